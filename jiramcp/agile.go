@@ -0,0 +1,190 @@
+package jiramcp
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/andygrunwald/go-jira"
+)
+
+// storyPointsNames are the names Jira Software gives the story points field
+// in company-managed and team-managed projects.
+var storyPointsNames = []string{"Story Points", "Story point estimate"}
+
+// sprint is a sprint of the agile API.
+type sprint struct {
+	ID           int    `json:"id"`
+	Name         string `json:"name"`
+	State        string `json:"state"`
+	Goal         string `json:"goal"`
+	StartDate    string `json:"startDate"`
+	EndDate      string `json:"endDate"`
+	CompleteDate string `json:"completeDate"`
+}
+
+// board is a board of the agile API.
+type board struct {
+	ID       int    `json:"id"`
+	Name     string `json:"name"`
+	Type     string `json:"type"`
+	Location struct {
+		ProjectKey string `json:"projectKey"`
+	} `json:"location"`
+}
+
+// projectVersion is a version of a project.
+type projectVersion struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	Released    bool   `json:"released"`
+	StartDate   string `json:"startDate"`
+	ReleaseDate string `json:"releaseDate"`
+}
+
+type fieldInfo struct {
+	ID     string `json:"id"`
+	Name   string `json:"name"`
+	Custom bool   `json:"custom"`
+	Schema struct {
+		Type   string `json:"type"`
+		Custom string `json:"custom"`
+	} `json:"schema"`
+}
+
+// getSprint fetches a sprint by ID.
+func (j *JiraMCPServer) getSprint(ctx context.Context, id int) (*sprint, error) {
+	var s sprint
+	if _, err := j.jiraDo(ctx, "GET", fmt.Sprintf("rest/agile/1.0/sprint/%d", id), nil, &s); err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+// projectBoards lists the boards of a project.
+func (j *JiraMCPServer) projectBoards(ctx context.Context, projectKey string) ([]board, error) {
+	var page struct {
+		Values []board `json:"values"`
+	}
+	if _, err := j.jiraDo(ctx, "GET", "rest/agile/1.0/board?maxResults=50&projectKeyOrId="+projectKey, nil, &page); err != nil {
+		return nil, err
+	}
+	return page.Values, nil
+}
+
+// findBoard returns the board with the given ID, or the only board of a
+// project when boardID is 0.
+func (j *JiraMCPServer) findBoard(ctx context.Context, projectKey string, boardID int) (*board, error) {
+	if boardID > 0 {
+		var b board
+		if _, err := j.jiraDo(ctx, "GET", fmt.Sprintf("rest/agile/1.0/board/%d", boardID), nil, &b); err != nil {
+			return nil, err
+		}
+		return &b, nil
+	}
+	boards, err := j.projectBoards(ctx, projectKey)
+	if err != nil {
+		return nil, err
+	}
+	switch len(boards) {
+	case 0:
+		return nil, fmt.Errorf("project %s has no board", projectKey)
+	case 1:
+		return &boards[0], nil
+	}
+	names := make([]string, 0, len(boards))
+	for _, b := range boards {
+		names = append(names, fmt.Sprintf("%d (%s)", b.ID, b.Name))
+	}
+	return nil, fmt.Errorf("project %s has several boards, pass boardId: %s", projectKey, strings.Join(names, ", "))
+}
+
+// findVersion looks up a version of a project by name.
+func (j *JiraMCPServer) findVersion(ctx context.Context, projectKey, name string) (*projectVersion, error) {
+	var versions []projectVersion
+	if _, err := j.jiraDo(ctx, "GET", fmt.Sprintf("rest/api/2/project/%s/versions", projectKey), nil, &versions); err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(versions))
+	for i, v := range versions {
+		if strings.EqualFold(v.Name, name) {
+			return &versions[i], nil
+		}
+		names = append(names, v.Name)
+	}
+	return nil, fmt.Errorf("%s", unknownNameProblem("version", name, "does not exist in "+projectKey, names, nil))
+}
+
+// jiraFields returns the system and custom fields of the instance.
+func (j *JiraMCPServer) jiraFields(ctx context.Context) ([]fieldInfo, error) {
+	return cached(ctx, j.cache, "metadata", "fields", func() ([]fieldInfo, error) {
+		var fields []fieldInfo
+		_, err := j.jiraDo(ctx, "GET", "rest/api/2/field", nil, &fields)
+		return fields, err
+	})
+}
+
+// storyPointsField returns the ID and name of the story points field:
+// JIRA_MCP_STORY_POINTS_FIELD (an ID or name) when set, otherwise the field
+// Jira Software creates. It returns empty strings when there is none.
+func (j *JiraMCPServer) storyPointsField(ctx context.Context) (id, name string, err error) {
+	fields, err := j.jiraFields(ctx)
+	if err != nil {
+		return "", "", err
+	}
+	wanted := storyPointsNames
+	if j.config.StoryPointsField != "" {
+		wanted = []string{j.config.StoryPointsField}
+	}
+	for _, w := range wanted {
+		for _, f := range fields {
+			if strings.EqualFold(f.ID, w) || strings.EqualFold(f.Name, w) {
+				return f.ID, f.Name, nil
+			}
+		}
+	}
+	if j.config.StoryPointsField != "" {
+		return "", "", fmt.Errorf("JIRA_MCP_STORY_POINTS_FIELD %q matches no field", j.config.StoryPointsField)
+	}
+	return "", "", nil
+}
+
+// issuePoints returns the story points of an issue, or 0 when unestimated.
+func issuePoints(issue jira.Issue, fieldID string) float64 {
+	if fieldID == "" || issue.Fields == nil {
+		return 0
+	}
+	points, _ := issue.Fields.Unknowns[fieldID].(float64)
+	return points
+}
+
+// parsePoints parses a story points value from a changelog entry.
+func parsePoints(s string) float64 {
+	points, _ := strconv.ParseFloat(strings.TrimSpace(s), 64)
+	return points
+}
+
+// parseAgileTime parses the timestamps of the agile API, which may or may not
+// carry milliseconds.
+func parseAgileTime(s string) (time.Time, bool) {
+	for _, layout := range []string{time.RFC3339, jiraTimeLayout, "2006-01-02"} {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// idListContains reports whether a comma-separated list of IDs, as the
+// changelog records sprint membership, contains id.
+func idListContains(list interface{}, id string) bool {
+	s, _ := list.(string)
+	for _, item := range strings.Split(s, ",") {
+		if strings.TrimSpace(item) == id {
+			return true
+		}
+	}
+	return false
+}
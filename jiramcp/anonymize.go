@@ -0,0 +1,297 @@
+package jiramcp
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// emailPattern matches email addresses appearing anywhere in output text, including
+// addresses mentioned in descriptions and comments.
+var emailPattern = regexp.MustCompile(`[A-Za-z0-9._%+\-]+@[A-Za-z0-9.\-]+\.[A-Za-z]{2,}`)
+
+// pseudonymizer replaces the names, email addresses, usernames, and account IDs
+// of Jira users with stable pseudonyms. Identities are learned from every Jira API response; pseudonyms
+// are assigned per MCP session in order of first appearance, so "Person 1" is
+// the same human for the whole session.
+type pseudonymizer struct {
+	mu         sync.Mutex
+	identities map[string]bool              // display names seen in Jira responses
+	emails     map[string]string            // email -> display name, when known
+	handles    map[string]string            // username, user key, or account ID -> display name, when known
+	sessions   map[string]map[string]string // session ID -> real value -> pseudonym
+	counters   map[string]int               // session ID -> pseudonyms assigned
+}
+
+func newPseudonymizer() *pseudonymizer {
+	return &pseudonymizer{
+		identities: make(map[string]bool),
+		emails:     make(map[string]string),
+		handles:    make(map[string]string),
+		sessions:   make(map[string]map[string]string),
+		counters:   make(map[string]int),
+	}
+}
+
+// forget drops the pseudonyms of the sessions matching match.
+func (p *pseudonymizer) forget(match func(session string) bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for id := range p.sessions {
+		if match(id) {
+			delete(p.sessions, id)
+			delete(p.counters, id)
+		}
+	}
+}
+
+// learn records the identity fields of a Jira user object. Handles are the
+// username, user key, and account ID that wiki markup such as [~username]
+// refers to the user by.
+func (p *pseudonymizer) learn(displayName, email string, handles ...string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if displayName != "" {
+		p.identities[displayName] = true
+	}
+	if email != "" {
+		p.emails[strings.ToLower(email)] = displayName
+	}
+	for _, h := range handles {
+		if known, ok := p.handles[h]; h != "" && (!ok || known == "") {
+			p.handles[h] = displayName
+		}
+	}
+}
+
+// userChangelogFields are the changelog fields whose from and to values are
+// usernames or account IDs, with display names as fromString and toString.
+var userChangelogFields = map[string]bool{"assignee": true, "reporter": true, "creator": true}
+
+// learnFromJSON walks a decoded JSON document and learns every object that looks
+// like a Jira user (has a displayName alongside an accountId, name, or email),
+// and the users that changelog items of user fields change between.
+func (p *pseudonymizer) learnFromJSON(v interface{}) {
+	switch t := v.(type) {
+	case map[string]interface{}:
+		displayName, _ := t["displayName"].(string)
+		email, _ := t["emailAddress"].(string)
+		accountID, hasAccount := t["accountId"].(string)
+		name, hasName := t["name"].(string)
+		if displayName != "" && (hasAccount || hasName || email != "") {
+			key, _ := t["key"].(string)
+			p.learn(displayName, email, name, key, accountID)
+		}
+		if field, _ := t["field"].(string); userChangelogFields[strings.ToLower(field)] {
+			for _, end := range []string{"from", "to"} {
+				handle, _ := t[end].(string)
+				display, _ := t[end+"String"].(string)
+				if handle != "" || display != "" {
+					p.learn(display, "", handle)
+				}
+			}
+		}
+		for _, child := range t {
+			p.learnFromJSON(child)
+		}
+	case []interface{}:
+		for _, child := range t {
+			p.learnFromJSON(child)
+		}
+	}
+}
+
+// pseudonymFor returns the session's pseudonym for a person, assigning the next
+// one if needed. The caller must hold p.mu.
+func (p *pseudonymizer) pseudonymFor(session, displayName string) string {
+	names := p.sessions[session]
+	if names == nil {
+		names = make(map[string]string)
+		p.sessions[session] = names
+	}
+	if alias, ok := names[displayName]; ok {
+		return alias
+	}
+	p.counters[session]++
+	alias := fmt.Sprintf("Person %d", p.counters[session])
+	names[displayName] = alias
+	return alias
+}
+
+// rewrite replaces every known name, username, and account ID and any email
+// address in text with the session's pseudonyms.
+func (p *pseudonymizer) rewrite(session, text string) string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	text = emailPattern.ReplaceAllStringFunc(text, func(email string) string {
+		owner := p.emails[strings.ToLower(email)]
+		if owner == "" {
+			owner = strings.ToLower(email)
+		}
+		return handleAlias(p.pseudonymFor(session, owner)) + "@example.invalid"
+	})
+
+	// Replace longer names first so "Ann Lee-Smith" wins over "Ann Lee".
+	names := make([]string, 0, len(p.identities))
+	for name := range p.identities {
+		if strings.Contains(text, name) {
+			names = append(names, name)
+		}
+	}
+	sort.Slice(names, func(a, b int) bool { return len(names[a]) > len(names[b]) })
+	for _, name := range names {
+		text = strings.ReplaceAll(text, name, p.pseudonymFor(session, name))
+	}
+
+	// Handles are replaced as whole words only, as a username such as "ann"
+	// is also part of other words.
+	handles := make([]string, 0, len(p.handles))
+	for handle := range p.handles {
+		if strings.Contains(text, handle) {
+			handles = append(handles, handle)
+		}
+	}
+	sort.Slice(handles, func(a, b int) bool { return len(handles[a]) > len(handles[b]) })
+	for _, handle := range handles {
+		owner := p.handles[handle]
+		if owner == "" {
+			owner = handle
+		}
+		text = replaceWord(text, handle, handleAlias(p.pseudonymFor(session, owner)))
+	}
+	return text
+}
+
+// rewriteData returns a copy of notification data with every string in it,
+// map keys included, rewritten as by rewrite.
+func (p *pseudonymizer) rewriteData(session string, data interface{}) interface{} {
+	b, err := json.Marshal(data)
+	if err != nil {
+		return nil
+	}
+	var doc interface{}
+	if err := json.Unmarshal(b, &doc); err != nil {
+		return nil
+	}
+	var walk func(v interface{}) interface{}
+	walk = func(v interface{}) interface{} {
+		switch v := v.(type) {
+		case string:
+			return p.rewrite(session, v)
+		case []interface{}:
+			for i := range v {
+				v[i] = walk(v[i])
+			}
+		case map[string]interface{}:
+			rewritten := make(map[string]interface{}, len(v))
+			for k, e := range v {
+				rewritten[p.rewrite(session, k)] = walk(e)
+			}
+			return rewritten
+		}
+		return v
+	}
+	return walk(doc)
+}
+
+// handleAlias turns a pseudonym such as "Person 1" into the form used in
+// place of usernames and email local parts, "person1".
+func handleAlias(alias string) string {
+	return strings.ToLower(strings.ReplaceAll(alias, " ", ""))
+}
+
+// replaceWord replaces the occurrences of word in s that are not part of a
+// longer word.
+func replaceWord(s, word, repl string) string {
+	var sb strings.Builder
+	for {
+		i := strings.Index(s, word)
+		if i < 0 {
+			break
+		}
+		end := i + len(word)
+		before, _ := utf8.DecodeLastRuneInString(s[:i])
+		after, _ := utf8.DecodeRuneInString(s[end:])
+		if (i == 0 || !isWordRune(before)) && (end == len(s) || !isWordRune(after)) {
+			sb.WriteString(s[:i])
+			sb.WriteString(repl)
+		} else {
+			sb.WriteString(s[:end])
+		}
+		s = s[end:]
+	}
+	sb.WriteString(s)
+	return sb.String()
+}
+
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_' || r == '-'
+}
+
+// identityCollector is an http.RoundTripper that inspects JSON responses from
+// Jira to learn user identities before handing the unchanged body back.
+type identityCollector struct {
+	base http.RoundTripper
+	p    *pseudonymizer
+}
+
+func (c *identityCollector) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := c.base.RoundTrip(req)
+	if err != nil || !strings.Contains(resp.Header.Get("Content-Type"), "json") {
+		return resp, err
+	}
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	var doc interface{}
+	if json.Unmarshal(body, &doc) == nil {
+		c.p.learnFromJSON(doc)
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	return resp, nil
+}
+
+// anonymizeMiddleware rewrites the text of tool results and resource contents
+// so no real user names or email addresses leave the server. Notifications
+// are rewritten as they are sent, see broadcastLog.
+func (j *JiraMCPServer) anonymizeMiddleware(next mcp.MethodHandler) mcp.MethodHandler {
+	return func(ctx context.Context, method string, req mcp.Request) (mcp.Result, error) {
+		result, err := next(ctx, method, req)
+		if err != nil || result == nil {
+			return result, err
+		}
+		session := requestSession(ctx)
+		switch r := result.(type) {
+		case *mcp.CallToolResult:
+			for _, c := range r.Content {
+				switch c := c.(type) {
+				case *mcp.TextContent:
+					c.Text = j.pseudonyms.rewrite(session, c.Text)
+				case *mcp.EmbeddedResource:
+					if c.Resource != nil {
+						c.Resource.Text = j.pseudonyms.rewrite(session, c.Resource.Text)
+					}
+				}
+			}
+		case *mcp.ReadResourceResult:
+			for _, c := range r.Contents {
+				c.Text = j.pseudonyms.rewrite(session, c.Text)
+			}
+		}
+		return result, nil
+	}
+}
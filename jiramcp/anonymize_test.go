@@ -0,0 +1,106 @@
+package jiramcp
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+func TestPseudonymizerRewrite(t *testing.T) {
+	p := newPseudonymizer()
+	p.learnFromJSON(map[string]interface{}{
+		"assignee": map[string]interface{}{
+			"displayName":  "Ann Lee",
+			"emailAddress": "Ann.Lee@corp.example.com",
+			"name":         "alee",
+			"key":          "JIRAUSER10100",
+			"accountId":    "5b10ac8d82e05b22cc7d4ef5",
+		},
+		"changelog": map[string]interface{}{"items": []interface{}{
+			map[string]interface{}{"field": "assignee", "from": "bstone", "fromString": "Bob Stone", "to": "alee", "toString": "Ann Lee"},
+		}},
+	})
+
+	tests := []struct {
+		in, want string
+	}{
+		{"Ann Lee (ann.lee@corp.example.com)", "Person 1 (person1@example.invalid)"},
+		{"[~alee] and [~accountid:5b10ac8d82e05b22cc7d4ef5] and JIRAUSER10100", "[~person1] and [~accountid:person1] and person1"},
+		{"Bob Stone is bstone; calee and alee2 are not", "Person 2 is person2; calee and alee2 are not"},
+		{"mail unknown@else.example.org", "mail person3@example.invalid"},
+	}
+	for _, tt := range tests {
+		if got := p.rewrite("s1", tt.in); got != tt.want {
+			t.Errorf("rewrite(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+	// Pseudonyms are per session.
+	if got, want := p.rewrite("s2", "Bob Stone and Ann Lee"), "Person 1 and Person 2"; got != want {
+		t.Errorf("second session: rewrite = %q, want %q", got, want)
+	}
+}
+
+func TestPseudonymizerRewriteData(t *testing.T) {
+	p := newPseudonymizer()
+	p.learn("Ann Lee", "", "alee")
+	got := p.rewriteData("s1", map[string]interface{}{
+		"message": "Ann Lee updated SMS-1",
+		"counts":  map[string]int{"alee": 2},
+		"list":    []string{"[~alee]"},
+	})
+	b, _ := json.Marshal(got)
+	if want := `{"counts":{"person1":2},"list":["[~person1]"],"message":"Person 1 updated SMS-1"}`; string(b) != want {
+		t.Errorf("rewriteData = %s, want %s", b, want)
+	}
+}
+
+func TestWebhookNotificationsAreAnonymized(t *testing.T) {
+	j := newTestServer(t, &MockJiraService{DoFunc: jiraRoutes{}.do}, func(c *JiraConfig) {
+		c.Anonymize = true
+		c.WebhookSecret = "hook"
+	})
+	messages := make(chan string, 1)
+	session := connectWith(t, context.Background(), j, &mcp.ClientOptions{
+		LoggingMessageHandler: func(_ context.Context, req *mcp.LoggingMessageRequest) {
+			b, _ := json.Marshal(req.Params.Data)
+			messages <- string(b)
+		},
+	})
+	if err := session.SetLoggingLevel(context.Background(), &mcp.SetLoggingLevelParams{Level: "info"}); err != nil {
+		t.Fatal(err)
+	}
+
+	body := `{
+		"webhookEvent": "jira:issue_updated",
+		"user": {"displayName": "Ann Lee", "name": "alee", "emailAddress": "ann@corp.example.com"},
+		"issue": {"key": "SMS-1", "fields": {"summary": "Ask [~bstone]"}},
+		"changelog": {"items": [{"field": "assignee", "from": "bstone", "fromString": "Bob Stone", "to": "alee", "toString": "Ann Lee"}]}
+	}`
+	req := httptest.NewRequest(http.MethodPost, "/webhook", strings.NewReader(body))
+	req.Header.Set("X-Hub-Signature", signWebhook("hook", body))
+	rec := httptest.NewRecorder()
+	j.webhookHandler(rec, req)
+	if rec.Code != http.StatusNoContent {
+		t.Fatalf("webhook status = %d, want %d", rec.Code, http.StatusNoContent)
+	}
+
+	select {
+	case msg := <-messages:
+		for _, real := range []string{"Ann Lee", "Bob Stone", "alee", "bstone", "ann@"} {
+			if strings.Contains(msg, real) {
+				t.Errorf("notification %s contains %q", msg, real)
+			}
+		}
+		if !strings.Contains(msg, `Person 2 updated SMS-1 (assignee: \"Person 1\" → \"Person 2\")`) {
+			t.Errorf("notification = %s, want the change described with pseudonyms", msg)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("no notification was sent")
+	}
+}
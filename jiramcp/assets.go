@@ -0,0 +1,196 @@
+package jiramcp
+
+import (
+	"context"
+	"embed"
+	"fmt"
+	"html/template"
+	"io/fs"
+	"log/slog"
+	"net/http"
+	"os"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// embeddedAssets holds the default issue templates, canned responses, and
+// status page so a single binary carries everything it needs.
+//
+//go:embed assets
+var embeddedAssets embed.FS
+
+// overlayFS serves files from an operator-provided directory when present and
+// falls back to the embedded defaults otherwise.
+type overlayFS struct {
+	override fs.FS
+	base     fs.FS
+}
+
+// newAssetFS returns the asset filesystem, layering overrideDir (if set) on top
+// of the embedded assets.
+func newAssetFS(overrideDir string) fs.FS {
+	base, err := fs.Sub(embeddedAssets, "assets")
+	if err != nil {
+		// The embed directive guarantees the directory exists.
+		panic(err)
+	}
+	o := overlayFS{base: base}
+	if overrideDir != "" {
+		o.override = os.DirFS(overrideDir)
+	}
+	return o
+}
+
+func (o overlayFS) Open(name string) (fs.File, error) {
+	if o.override != nil {
+		if f, err := o.override.Open(name); err == nil {
+			return f, nil
+		}
+	}
+	return o.base.Open(name)
+}
+
+// ReadDir merges the entries of both layers, with overrides shadowing defaults
+// of the same name.
+func (o overlayFS) ReadDir(name string) ([]fs.DirEntry, error) {
+	entries := make(map[string]fs.DirEntry)
+	baseEntries, baseErr := fs.ReadDir(o.base, name)
+	for _, e := range baseEntries {
+		entries[e.Name()] = e
+	}
+	var overrideErr error = fs.ErrNotExist
+	if o.override != nil {
+		var overrideEntries []fs.DirEntry
+		overrideEntries, overrideErr = fs.ReadDir(o.override, name)
+		for _, e := range overrideEntries {
+			entries[e.Name()] = e
+		}
+	}
+	if baseErr != nil && overrideErr != nil {
+		return nil, baseErr
+	}
+
+	merged := make([]fs.DirEntry, 0, len(entries))
+	for _, e := range entries {
+		merged = append(merged, e)
+	}
+	sort.Slice(merged, func(a, b int) bool { return merged[a].Name() < merged[b].Name() })
+	return merged, nil
+}
+
+// listAssets returns the names (without extension) of the files in dir that
+// have the given extension.
+func (j *JiraMCPServer) listAssets(dir, ext string) ([]string, error) {
+	entries, err := fs.ReadDir(j.assets, dir)
+	if err != nil {
+		return nil, err
+	}
+	var names []string
+	for _, e := range entries {
+		if !e.IsDir() && path.Ext(e.Name()) == ext {
+			names = append(names, strings.TrimSuffix(e.Name(), ext))
+		}
+	}
+	return names, nil
+}
+
+func (j *JiraMCPServer) addAssetResources() {
+	j.server.AddResource(&mcp.Resource{
+		Name:        "issue-templates",
+		URI:         "jira://templates",
+		Description: "Names of the issue templates bundled with the server or provided by the operator",
+		MIMEType:    "text/plain",
+	}, j.TemplateIndex)
+	j.server.AddResourceTemplate(&mcp.ResourceTemplate{
+		Name:        "issue-template",
+		URITemplate: "jira://templates/{name}",
+		Description: "Definition of an issue template (issue type, summary pattern, description skeleton, labels, components, custom fields)",
+		MIMEType:    "application/json",
+	}, j.TemplateResource)
+	j.server.AddResource(&mcp.Resource{
+		Name:        "canned-responses",
+		URI:         "jira://canned-responses",
+		Description: "Names of the canned comment responses available for replying in tickets",
+		MIMEType:    "text/plain",
+	}, j.assetIndex("responses", ".md"))
+	j.server.AddResourceTemplate(&mcp.ResourceTemplate{
+		Name:        "canned-response",
+		URITemplate: "jira://canned-responses/{name}",
+		Description: "Text of a canned comment response",
+		MIMEType:    "text/markdown",
+	}, j.assetFile("jira://canned-responses/", "responses", ".md", "text/markdown"))
+}
+
+// assetIndex returns a resource handler listing the assets in dir.
+func (j *JiraMCPServer) assetIndex(dir, ext string) mcp.ResourceHandler {
+	return func(ctx context.Context, req *mcp.ReadResourceRequest) (*mcp.ReadResourceResult, error) {
+		names, err := j.listAssets(dir, ext)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list %s: %w", dir, err)
+		}
+		return &mcp.ReadResourceResult{
+			Contents: []*mcp.ResourceContents{
+				{URI: req.Params.URI, MIMEType: "text/plain", Text: strings.Join(names, "\n")},
+			},
+		}, nil
+	}
+}
+
+// assetFile returns a resource handler serving the asset named by the part of
+// the URI following prefix.
+func (j *JiraMCPServer) assetFile(prefix, dir, ext, mimeType string) mcp.ResourceHandler {
+	return func(ctx context.Context, req *mcp.ReadResourceRequest) (*mcp.ReadResourceResult, error) {
+		name := strings.TrimPrefix(req.Params.URI, prefix)
+		if name == "" || strings.ContainsAny(name, "/\\") {
+			return nil, mcp.ResourceNotFoundError(req.Params.URI)
+		}
+		body, err := fs.ReadFile(j.assets, path.Join(dir, name+ext))
+		if err != nil {
+			return nil, mcp.ResourceNotFoundError(req.Params.URI)
+		}
+		return &mcp.ReadResourceResult{
+			Contents: []*mcp.ResourceContents{
+				{URI: req.Params.URI, MIMEType: mimeType, Text: string(body)},
+			},
+		}, nil
+	}
+}
+
+// statusPageData is rendered by status/index.html.
+type statusPageData struct {
+	Name       string
+	Version    string
+	BaseURL    string
+	ProjectKey string
+	DryRun     bool
+	Started    time.Time
+	Uptime     time.Duration
+}
+
+// statusHandler renders the status page. The template is parsed on each request
+// so edits in the override directory show up without a restart.
+func (j *JiraMCPServer) statusHandler(w http.ResponseWriter, r *http.Request) {
+	tmpl, err := template.ParseFS(j.assets, "status/index.html")
+	if err != nil {
+		slog.Error("Failed to load status page template", "error", err)
+		http.Error(w, "status page unavailable", http.StatusInternalServerError)
+		return
+	}
+	data := statusPageData{
+		Name:       ServerName,
+		Version:    ServerVersion,
+		BaseURL:    j.config.BaseURL,
+		ProjectKey: j.config.ProjectKey,
+		DryRun:     j.config.DryRun,
+		Started:    j.started,
+		Uptime:     time.Since(j.started).Round(time.Second),
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	if err := tmpl.Execute(w, data); err != nil {
+		slog.Error("Failed to render status page", "error", err)
+	}
+}
@@ -0,0 +1,143 @@
+package jiramcp
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// defaultAuditRecordLimit is the page size used when no limit is supplied.
+const defaultAuditRecordLimit = 50
+
+type GetAuditRecordsParams struct {
+	// From and To bound the record creation time (YYYY-MM-DD or RFC 3339).
+	From string `json:"from,omitempty"`
+	To   string `json:"to,omitempty"`
+	// Category keeps only records whose category matches, e.g. "user management".
+	Category string `json:"category,omitempty"`
+	// User is a free-text filter matched by Jira against the author and record text.
+	User   string `json:"user,omitempty"`
+	Offset int    `json:"offset,omitempty"`
+	Limit  int    `json:"limit,omitempty"`
+	// Verbosity "minimal" omits changed values; "full" adds record descriptions.
+	Verbosity string `json:"verbosity,omitempty"`
+}
+
+type auditRecordPage struct {
+	Offset  int           `json:"offset"`
+	Limit   int           `json:"limit"`
+	Total   int           `json:"total"`
+	Records []auditRecord `json:"records"`
+}
+
+type auditRecord struct {
+	ID              int64  `json:"id"`
+	Summary         string `json:"summary"`
+	RemoteAddress   string `json:"remoteAddress"`
+	AuthorKey       string `json:"authorKey"`
+	AuthorAccountID string `json:"authorAccountId"`
+	Created         string `json:"created"`
+	Category        string `json:"category"`
+	EventSource     string `json:"eventSource"`
+	Description     string `json:"description"`
+	ObjectItem      struct {
+		Name     string `json:"name"`
+		TypeName string `json:"typeName"`
+	} `json:"objectItem"`
+	ChangedValues []struct {
+		FieldName   string `json:"fieldName"`
+		ChangedFrom string `json:"changedFrom"`
+		ChangedTo   string `json:"changedTo"`
+	} `json:"changedValues"`
+}
+
+// parseAuditTime accepts a plain date or an RFC 3339 timestamp and returns the
+// timestamp format expected by the audit records API.
+func parseAuditTime(value string) (string, error) {
+	if t, err := time.Parse("2006-01-02", value); err == nil {
+		return t.Format(jiraTimeLayout), nil
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return "", fmt.Errorf("invalid date %q: expected YYYY-MM-DD or RFC 3339", value)
+	}
+	return t.Format(jiraTimeLayout), nil
+}
+
+// GetAuditRecords queries Jira's audit log. It requires site administrator
+// permission.
+func (j *JiraMCPServer) GetAuditRecords(ctx context.Context, req *mcp.CallToolRequest, params *GetAuditRecordsParams) (*mcp.CallToolResult, any, error) {
+	limit := params.Limit
+	if limit <= 0 {
+		limit = defaultAuditRecordLimit
+	}
+
+	query := url.Values{}
+	query.Set("offset", strconv.Itoa(params.Offset))
+	query.Set("limit", strconv.Itoa(limit))
+	if params.User != "" {
+		query.Set("filter", params.User)
+	}
+	for name, value := range map[string]string{"from": params.From, "to": params.To} {
+		if value == "" {
+			continue
+		}
+		ts, err := parseAuditTime(value)
+		if err != nil {
+			return textResult("Invalid '%s' parameter: %v", name, err), nil, nil
+		}
+		query.Set(name, ts)
+	}
+
+	var page auditRecordPage
+	if _, err := j.jiraDo(ctx, "GET", "rest/api/2/auditing/record?"+query.Encode(), nil, &page); err != nil {
+		return textResult("Failed to get audit records (this requires Jira administrator access): %v", err), nil, nil
+	}
+
+	verbosity := j.verbosity(params.Verbosity)
+	var sb strings.Builder
+	shown := 0
+	for _, r := range page.Records {
+		if params.Category != "" && !strings.EqualFold(r.Category, params.Category) {
+			continue
+		}
+		shown++
+		author := r.AuthorAccountID
+		if author == "" {
+			author = r.AuthorKey
+		}
+		fmt.Fprintf(&sb, "[%s] %s — %s", formatJiraTimestamp(r.Created), r.Category, r.Summary)
+		if r.ObjectItem.Name != "" {
+			fmt.Fprintf(&sb, " (%s: %s)", r.ObjectItem.TypeName, r.ObjectItem.Name)
+		}
+		fmt.Fprintf(&sb, " by %s", author)
+		if verbosity == VerbosityMinimal {
+			sb.WriteString("\n")
+			continue
+		}
+		if r.RemoteAddress != "" {
+			fmt.Fprintf(&sb, " from %s", r.RemoteAddress)
+		}
+		sb.WriteString("\n")
+		if verbosity == VerbosityFull && r.Description != "" {
+			fmt.Fprintf(&sb, "    %s\n", normalizeWhitespace(r.Description))
+		}
+		for _, cv := range r.ChangedValues {
+			fmt.Fprintf(&sb, "    %s: %q → %q\n", cv.FieldName, cv.ChangedFrom, cv.ChangedTo)
+		}
+	}
+
+	header := fmt.Sprintf("Audit records %d-%d of %d", page.Offset+1, page.Offset+len(page.Records), page.Total)
+	if params.Category != "" {
+		header += fmt.Sprintf(", %d in category %q", shown, params.Category)
+	}
+	if next := page.Offset + len(page.Records); next < page.Total {
+		header += fmt.Sprintf(" (next offset: %d)", next)
+	}
+	return textResult("%s\n%s", header, sb.String()), nil, nil
+}
@@ -0,0 +1,344 @@
+package jiramcp
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/andygrunwald/go-jira"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// maxBreakdownIssues bounds the issues create-work-breakdown creates in one
+// call.
+const maxBreakdownIssues = 100
+
+// epicLinkType is the schema of the Epic Link field of Jira Software on
+// Server and Data Center, and of older Cloud projects.
+const epicLinkType = "com.pyxis.greenhopper.jira:gh-epic-link"
+
+// BreakdownItem is an issue of a work breakdown. IssueType defaults to Epic,
+// Story, and the project's sub-task type at the three levels.
+type BreakdownItem struct {
+	Summary     string   `json:"summary"`
+	Description string   `json:"description,omitempty"`
+	IssueType   string   `json:"issueType,omitempty"`
+	Priority    string   `json:"priority,omitempty"`
+	Labels      []string `json:"labels,omitempty"`
+	Components  []string `json:"components,omitempty"`
+	// Assignee is an email address or name to look up.
+	Assignee string `json:"assignee,omitempty"`
+}
+
+type BreakdownStory struct {
+	Summary     string          `json:"summary"`
+	Description string          `json:"description,omitempty"`
+	IssueType   string          `json:"issueType,omitempty"`
+	Priority    string          `json:"priority,omitempty"`
+	Labels      []string        `json:"labels,omitempty"`
+	Components  []string        `json:"components,omitempty"`
+	Assignee    string          `json:"assignee,omitempty"`
+	Subtasks    []BreakdownItem `json:"subtasks,omitempty"`
+}
+
+type CreateWorkBreakdownParams struct {
+	ProjectKey string `json:"projectKey,omitempty"`
+	// Epic is created with Stories as its children; EpicKey instead adds the
+	// stories to an existing epic.
+	Epic    *BreakdownItem   `json:"epic,omitempty"`
+	EpicKey string           `json:"epicKey,omitempty"`
+	Stories []BreakdownStory `json:"stories,omitempty"`
+	// KeepPartial keeps the issues created before a failure and goes on with
+	// the other stories. By default a failure deletes the issues this call
+	// created, so that no half-built tree is left behind.
+	KeepPartial     bool `json:"keepPartial,omitempty"`
+	LiteralMentions bool `json:"literalMentions,omitempty"`
+	DryRun          bool `json:"dryRun,omitempty"`
+}
+
+// breakdownNode is an issue to create, with its place in the tree.
+type breakdownNode struct {
+	item  BreakdownItem
+	level int // 0 epic, 1 story, 2 sub-task
+	// parent is the index of the parent node, -1 for none.
+	parent  int
+	key     string
+	err     error
+	skipped bool
+}
+
+// breakdownNodes flattens a work breakdown in creation order, parents first.
+func breakdownNodes(params *CreateWorkBreakdownParams) []*breakdownNode {
+	var nodes []*breakdownNode
+	epic := -1
+	if params.Epic != nil {
+		nodes = append(nodes, &breakdownNode{item: *params.Epic, parent: -1})
+		epic = 0
+	}
+	for _, s := range params.Stories {
+		story := len(nodes)
+		nodes = append(nodes, &breakdownNode{
+			item: BreakdownItem{
+				Summary: s.Summary, Description: s.Description, IssueType: s.IssueType, Priority: s.Priority,
+				Labels: s.Labels, Components: s.Components, Assignee: s.Assignee,
+			},
+			level:  1,
+			parent: epic,
+		})
+		for _, sub := range s.Subtasks {
+			nodes = append(nodes, &breakdownNode{item: sub, level: 2, parent: story})
+		}
+	}
+	return nodes
+}
+
+// subtaskType returns the name of a project's sub-task issue type.
+func subtaskType(project *jira.Project) string {
+	for _, t := range project.IssueTypes {
+		if t.Subtask {
+			return t.Name
+		}
+	}
+	return "Sub-task"
+}
+
+// epicLinkField returns the ID of the Epic Link field, or "" when the
+// instance has none.
+func (j *JiraMCPServer) epicLinkField(ctx context.Context) string {
+	fields, err := j.jiraFields(ctx)
+	if err != nil {
+		return ""
+	}
+	for _, f := range fields {
+		if f.Schema.Custom == epicLinkType {
+			return f.ID
+		}
+	}
+	return ""
+}
+
+// breakdownFields builds the create fields of a node. parentKey is the key
+// of the parent issue, or a placeholder in a dry run.
+func (j *JiraMCPServer) breakdownFields(ctx context.Context, n *breakdownNode, projectKey, parentKey, epicLink string, literalMentions bool) (map[string]interface{}, error) {
+	description, _ := j.resolveMentions(ctx, n.item.Description, literalMentions)
+	fields := map[string]interface{}{
+		"project":   map[string]string{"key": projectKey},
+		"summary":   n.item.Summary,
+		"issuetype": map[string]string{"name": n.item.IssueType},
+	}
+	if description != "" {
+		fields["description"] = description
+	}
+	if n.item.Priority != "" {
+		fields["priority"] = map[string]string{"name": n.item.Priority}
+	}
+	if len(n.item.Labels) > 0 {
+		fields["labels"] = n.item.Labels
+	}
+	if len(n.item.Components) > 0 {
+		fields["components"] = componentRefs(n.item.Components)
+	}
+	if n.item.Assignee != "" {
+		assignee, err := j.assigneeValue(ctx, n.item.Assignee)
+		if err != nil {
+			return nil, fmt.Errorf("assignee: %w", err)
+		}
+		fields["assignee"] = assignee
+	}
+	switch {
+	case parentKey == "":
+	case n.level == 1 && epicLink != "":
+		// Stories join their epic through the Epic Link field where Jira
+		// has one; sub-tasks and Cloud stories use parent.
+		fields[epicLink] = parentKey
+	default:
+		fields["parent"] = map[string]string{"key": parentKey}
+	}
+	return fields, nil
+}
+
+// createBreakdownIssue creates the issue of a node and returns its key.
+func (j *JiraMCPServer) createBreakdownIssue(ctx context.Context, n *breakdownNode, projectKey, parentKey, epicLink string, literalMentions bool) (string, error) {
+	fields, err := j.breakdownFields(ctx, n, projectKey, parentKey, epicLink, literalMentions)
+	if err != nil {
+		return "", err
+	}
+	var result struct {
+		Key string `json:"key"`
+	}
+	if _, err := j.jiraDo(ctx, "POST", "rest/api/2/issue", map[string]interface{}{"fields": fields}, &result); err != nil {
+		return "", err
+	}
+	return result.Key, nil
+}
+
+// CreateWorkBreakdown creates an epic, its stories, and their sub-tasks in
+// one call, linking each issue to its parent. The tree is checked before
+// anything is created; if creating an issue still fails, the issues created
+// so far are deleted again unless keepPartial is set.
+func (j *JiraMCPServer) CreateWorkBreakdown(ctx context.Context, req *mcp.CallToolRequest, params *CreateWorkBreakdownParams) (*mcp.CallToolResult, any, error) {
+	if params.Epic != nil && params.EpicKey != "" {
+		return textResult("Give either epic or epicKey, not both"), nil, nil
+	}
+	projectKey := j.projectKeyOrDefault(params.ProjectKey)
+	epicKey := strings.ToUpper(strings.TrimSpace(params.EpicKey))
+	nodes := breakdownNodes(params)
+	if len(nodes) == 0 {
+		return textResult("Nothing to create: give an epic or stories"), nil, nil
+	}
+	if len(nodes) > maxBreakdownIssues {
+		return textResult("The breakdown has %d issues; at most %d are created in one call", len(nodes), maxBreakdownIssues), nil, nil
+	}
+
+	// Everything that can be checked up front is, so that a mistake is
+	// caught before the first issue exists.
+	var problems []string
+	if problem := j.projectKeyProblem(ctx, projectKey); problem != "" {
+		return textResult("Failed to create the work breakdown: %s", problem), nil, nil
+	}
+	project, err := j.getProject(ctx, projectKey)
+	if err != nil {
+		return textResult("Failed to get project %s: %v", projectKey, err), nil, nil
+	}
+	defaults := []string{"Epic", "Story", subtaskType(project)}
+	for i, n := range nodes {
+		label := fmt.Sprintf("issue %d (%q)", i+1, n.item.Summary)
+		if strings.TrimSpace(n.item.Summary) == "" {
+			problems = append(problems, label+": summary is required")
+		}
+		if n.item.IssueType == "" {
+			n.item.IssueType = defaults[n.level]
+		}
+		if problem := issueTypeProblem(project, n.item.IssueType); problem != "" {
+			problems = append(problems, label+": "+problem)
+		}
+		if n.item.Priority != "" {
+			if problem, err := j.priorityProblem(ctx, projectKey, n.item.Priority); err == nil && problem != "" {
+				problems = append(problems, label+": "+problem)
+			}
+		}
+		for _, problem := range componentProblems(project, componentRefs(n.item.Components)) {
+			problems = append(problems, label+": "+problem)
+		}
+	}
+	if epicKey != "" {
+		if issueProblems := j.issueProblems(ctx, epicKey); len(issueProblems) > 0 {
+			problems = append(problems, issueProblems...)
+		}
+	}
+	epicLink := j.epicLinkField(ctx)
+
+	if j.dryRun(params.DryRun) {
+		var payloads []map[string]interface{}
+		for i, n := range nodes {
+			parentKey := epicKey
+			if n.parent >= 0 {
+				parentKey = fmt.Sprintf("<key of issue %d>", n.parent+1)
+			}
+			fields, err := j.breakdownFields(ctx, n, projectKey, parentKey, epicLink, params.LiteralMentions)
+			if err != nil {
+				problems = append(problems, fmt.Sprintf("issue %d (%q): %v", i+1, n.item.Summary, err))
+				continue
+			}
+			payloads = append(payloads, map[string]interface{}{"fields": fields})
+		}
+		return dryRunResult("POST", "rest/api/2/issue (once per issue, in order)", payloads, problems), nil, nil
+	}
+	if len(problems) > 0 {
+		return textResult("Failed to create the work breakdown; nothing was created:\n- %s", strings.Join(problems, "\n- ")), nil, nil
+	}
+
+	// The call itself counted once towards the mutation limit; the other
+	// issues are charged before any is created.
+	if len(nodes) > 1 {
+		client := requestClient(ctx)
+		if ok, limit, _ := j.mutations.allow(client, len(nodes)-1, j.config.MaxMutationsPerHour, time.Now()); !ok {
+			return textResult("Failed to create the work breakdown; nothing was created: its %d issues would exceed the client's limit of %d changes per hour. "+
+				"Split it up, or ask an operator to raise the limit with override-mutation-limit for client %q.", len(nodes), limit, client), nil, nil
+		}
+	}
+	var created []*breakdownNode
+	failed := false
+	for _, n := range nodes {
+		parentKey := epicKey
+		if n.parent >= 0 {
+			parent := nodes[n.parent]
+			if parent.key == "" {
+				n.skipped = true
+				continue
+			}
+			parentKey = parent.key
+		}
+		n.key, err = j.createBreakdownIssue(ctx, n, projectKey, parentKey, epicLink, params.LiteralMentions)
+		if err != nil && n.level == 1 && parentKey != "" && epicLink != "" {
+			// Some projects keep the Epic Link field off their create screens
+			// and take the epic as parent instead.
+			n.key, err = j.createBreakdownIssue(ctx, n, projectKey, parentKey, "", params.LiteralMentions)
+			if err == nil {
+				epicLink = ""
+			}
+		}
+		if err != nil {
+			n.err = err
+			failed = true
+			if !params.KeepPartial {
+				break
+			}
+			continue
+		}
+		created = append(created, n)
+		logger(ctx).Info("Created work breakdown issue", "issueKey", n.key, "level", n.level)
+	}
+
+	var sb strings.Builder
+	if failed && !params.KeepPartial {
+		// Sub-tasks are deleted before the stories holding them.
+		var kept []string
+		for k := len(created) - 1; k >= 0; k-- {
+			if _, err := j.jiraDo(ctx, "DELETE", fmt.Sprintf("rest/api/2/issue/%s", created[k].key), nil, nil); err != nil {
+				kept = append(kept, fmt.Sprintf("%s (%v)", created[k].key, err))
+			}
+		}
+		for _, n := range nodes {
+			if n.err != nil {
+				fmt.Fprintf(&sb, "Failed to create %s %q: %v\n", n.item.IssueType, n.item.Summary, n.err)
+			}
+		}
+		if len(kept) > 0 {
+			fmt.Fprintf(&sb, "Deleted %d of the %d issues created before the failure; these could not be deleted and remain: %s\n",
+				len(created)-len(kept), len(created), strings.Join(kept, ", "))
+		} else {
+			fmt.Fprintf(&sb, "The %d issues created before the failure were deleted again; nothing remains.\n", len(created))
+		}
+		return textResult("%s", sb.String()), nil, nil
+	}
+
+	keys := make([]string, len(created))
+	for i, n := range created {
+		keys[i] = n.key
+	}
+	j.journalCreate(ctx, "create-work-breakdown", keys...)
+	fmt.Fprintf(&sb, "Created %d of %d issues in %s", len(created), len(nodes), projectKey)
+	if epicKey != "" {
+		fmt.Fprintf(&sb, " under %s", epicKey)
+	}
+	sb.WriteString(":\n")
+	for _, n := range nodes {
+		indent := strings.Repeat("  ", n.level)
+		if params.Epic == nil {
+			indent = strings.Repeat("  ", n.level-1)
+		}
+		switch {
+		case n.key != "":
+			fmt.Fprintf(&sb, "%s- %s %s: %s\n", indent, n.key, n.item.IssueType, n.item.Summary)
+		case n.err != nil:
+			fmt.Fprintf(&sb, "%s- FAILED %s: %s: %v\n", indent, n.item.IssueType, n.item.Summary, n.err)
+		case n.skipped:
+			fmt.Fprintf(&sb, "%s- SKIPPED %s: %s (its parent was not created)\n", indent, n.item.IssueType, n.item.Summary)
+		default:
+			fmt.Fprintf(&sb, "%s- NOT CREATED %s: %s\n", indent, n.item.IssueType, n.item.Summary)
+		}
+	}
+	return textResult("%s", sb.String()), nil, nil
+}
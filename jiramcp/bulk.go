@@ -0,0 +1,241 @@
+package jiramcp
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+const (
+	// defaultBulkIssues and maxBulkIssues bound the issues a bulk tool
+	// changes in one call.
+	defaultBulkIssues = 50
+	maxBulkIssues     = 200
+	// bulkPreviewKeys is how many affected keys a confirmation lists.
+	bulkPreviewKeys = 20
+)
+
+// The bulk tools select issues with either JQL (up to MaxIssues, default 50,
+// max 200) or IssueKeys. ConfirmationPhrase confirms a change to more issues
+// than JIRA_MCP_BULK_CONFIRM_THRESHOLD for clients that cannot show a
+// confirmation prompt; it must be "<count> issues".
+
+type BulkTransitionParams struct {
+	JQL       string   `json:"jql,omitempty"`
+	IssueKeys []string `json:"issueKeys,omitempty"`
+	MaxIssues int      `json:"maxIssues,omitempty"`
+	// Transition is the name of the transition or of the target status.
+	Transition         string `json:"transition"`
+	Resolution         string `json:"resolution,omitempty"`
+	Comment            string `json:"comment,omitempty"`
+	LiteralMentions    bool   `json:"literalMentions,omitempty"`
+	ConfirmationPhrase string `json:"confirmationPhrase,omitempty"`
+	DryRun             bool   `json:"dryRun,omitempty"`
+}
+
+type BulkUpdateParams struct {
+	JQL       string   `json:"jql,omitempty"`
+	IssueKeys []string `json:"issueKeys,omitempty"`
+	MaxIssues int      `json:"maxIssues,omitempty"`
+	Priority  string   `json:"priority,omitempty"`
+	// Assignee is an email address or name to look up, or "none" to unassign.
+	Assignee           string   `json:"assignee,omitempty"`
+	DueDate            string   `json:"dueDate,omitempty"`
+	AddLabels          []string `json:"addLabels,omitempty"`
+	RemoveLabels       []string `json:"removeLabels,omitempty"`
+	AddComponents      []string `json:"addComponents,omitempty"`
+	RemoveComponents   []string `json:"removeComponents,omitempty"`
+	NotifyUsers        *bool    `json:"notifyUsers,omitempty"`
+	ConfirmationPhrase string   `json:"confirmationPhrase,omitempty"`
+	DryRun             bool     `json:"dryRun,omitempty"`
+}
+
+// bulkIssueKeys resolves the issues a bulk tool acts on: the issues of a
+// JQL query, up to limit, or the given keys.
+func (j *JiraMCPServer) bulkIssueKeys(ctx context.Context, jql string, issueKeys []string, limit int) ([]string, error) {
+	if (jql == "") == (len(issueKeys) == 0) {
+		return nil, fmt.Errorf("set either jql or issueKeys")
+	}
+	if limit <= 0 {
+		limit = defaultBulkIssues
+	}
+	if limit > maxBulkIssues {
+		return nil, fmt.Errorf("maxIssues must be at most %d", maxBulkIssues)
+	}
+	if len(issueKeys) > 0 {
+		if len(issueKeys) > maxBulkIssues {
+			return nil, fmt.Errorf("issueKeys must list at most %d issues", maxBulkIssues)
+		}
+		keys := make([]string, 0, len(issueKeys))
+		for _, k := range issueKeys {
+			keys = append(keys, strings.ToUpper(strings.TrimSpace(k)))
+		}
+		return keys, nil
+	}
+	issues, err := j.searchIssues(ctx, jql, []string{"summary"}, "", limit)
+	if err != nil {
+		return nil, err
+	}
+	keys := make([]string, 0, len(issues))
+	for _, issue := range issues {
+		keys = append(keys, issue.Key)
+	}
+	return keys, nil
+}
+
+// previewKeys lists the first affected keys and how many more there are.
+func previewKeys(keys []string) string {
+	if len(keys) <= bulkPreviewKeys {
+		return strings.Join(keys, ", ")
+	}
+	return fmt.Sprintf("%s and %d more", strings.Join(keys[:bulkPreviewKeys], ", "), len(keys)-bulkPreviewKeys)
+}
+
+// confirmBulk asks the user to confirm an action on more issues than
+// JIRA_MCP_BULK_CONFIRM_THRESHOLD. The user is asked through MCP elicitation
+// when the client supports it; otherwise the caller must repeat the call
+// with confirmationPhrase set to "<count> issues". It returns a message for
+// the caller when the action must not proceed, and "" when it may.
+func (j *JiraMCPServer) confirmBulk(ctx context.Context, req *mcp.CallToolRequest, action string, keys []string, phrase string) string {
+	threshold := j.config.BulkConfirmThreshold
+	if threshold < 0 || len(keys) <= threshold {
+		return ""
+	}
+	expected := fmt.Sprintf("%d issues", len(keys))
+	if strings.EqualFold(strings.TrimSpace(phrase), expected) {
+		return ""
+	}
+	if req != nil && req.Session != nil {
+		result, err := req.Session.Elicit(ctx, &mcp.ElicitParams{
+			Message: fmt.Sprintf("%s will change %d issues: %s. Proceed?", action, len(keys), previewKeys(keys)),
+			RequestedSchema: &jsonschema.Schema{
+				Type: "object",
+				Properties: map[string]*jsonschema.Schema{
+					"confirm": {Type: "boolean", Title: "Confirm", Description: fmt.Sprintf("Change all %d issues", len(keys))},
+				},
+				Required: []string{"confirm"},
+			},
+		})
+		if err == nil {
+			if result.Action == "accept" && result.Content["confirm"] == true {
+				logger(ctx).Info("Bulk action confirmed by the user", "action", action, "issues", len(keys))
+				return ""
+			}
+			return fmt.Sprintf("%s was not confirmed by the user (%s); nothing was changed.", action, result.Action)
+		}
+		// Clients without elicitation support make Elicit fail.
+		logger(ctx).Debug("Could not ask for confirmation", "error", err)
+	}
+	if phrase != "" {
+		return fmt.Sprintf("%s would change %d issues and confirmationPhrase %q does not match %q; nothing was changed.", action, len(keys), phrase, expected)
+	}
+	return fmt.Sprintf("%s would change %d issues: %s. Confirm with the user, then call the tool again with confirmationPhrase set to %q.",
+		action, len(keys), previewKeys(keys), expected)
+}
+
+// runBulk applies apply to every key and summarizes the results. Unless
+// dryRun is set, each issue after the first is charged to the client's
+// mutation limit, which counted the call itself once, before any issue is
+// changed, so a change that would exceed the limit changes nothing.
+func (j *JiraMCPServer) runBulk(ctx context.Context, req *mcp.CallToolRequest, action string, keys []string, dryRun bool, apply func(key string) *mcp.CallToolResult) *mcp.CallToolResult {
+	if !dryRun && len(keys) > 1 {
+		client := requestClient(ctx)
+		if ok, limit, _ := j.mutations.allow(client, len(keys)-1, j.config.MaxMutationsPerHour, time.Now()); !ok {
+			return textResult("%s: changing %d issues would exceed the client's limit of %d changes per hour; nothing was changed. "+
+				"Select fewer issues, or ask an operator to raise the limit with override-mutation-limit for client %q.", action, len(keys), limit, client)
+		}
+	}
+	var sb strings.Builder
+	succeeded, failed := 0, 0
+	for _, key := range keys {
+		result := apply(key)
+		if toolFailed(result) {
+			failed++
+		} else {
+			succeeded++
+		}
+		for _, c := range result.Content {
+			if tc, ok := c.(*mcp.TextContent); ok {
+				fmt.Fprintf(&sb, "- %s\n", strings.ReplaceAll(tc.Text, "\n", "\n  "))
+			}
+		}
+	}
+	return textResult("%s: %d of %d issues succeeded, %d failed.\n%s", action, succeeded, len(keys), failed, sb.String())
+}
+
+// BulkTransitionIssues moves every selected issue through the matching
+// transition, after the user confirmed when many issues are affected.
+func (j *JiraMCPServer) BulkTransitionIssues(ctx context.Context, req *mcp.CallToolRequest, params *BulkTransitionParams) (*mcp.CallToolResult, any, error) {
+	if j.requiresConfirmation(params.Transition) {
+		return textResult("Moving issues to %s cannot be undone, so it is done one issue at a time with transition-jira-issue.", params.Transition), nil, nil
+	}
+	keys, err := j.bulkIssueKeys(ctx, params.JQL, params.IssueKeys, params.MaxIssues)
+	if err != nil {
+		return textResult("Failed to select issues: %v", err), nil, nil
+	}
+	if len(keys) == 0 {
+		return textResult("No issues match; nothing was changed."), nil, nil
+	}
+	action := fmt.Sprintf("Moving issues to %q", params.Transition)
+	// A dry run checks the transition on every issue and changes nothing,
+	// so it needs no confirmation.
+	dryRun := j.dryRun(params.DryRun)
+	if !dryRun {
+		if problem := j.confirmBulk(ctx, req, action, keys, params.ConfirmationPhrase); problem != "" {
+			return textResult("%s", problem), nil, nil
+		}
+	}
+	return j.runBulk(ctx, req, action, keys, dryRun, func(key string) *mcp.CallToolResult {
+		result, _, _ := j.TransitionJiraIssue(ctx, req, &TransitionIssueParams{
+			IssueKey:        key,
+			Transition:      params.Transition,
+			Resolution:      params.Resolution,
+			Comment:         params.Comment,
+			LiteralMentions: params.LiteralMentions,
+			DryRun:          dryRun,
+		})
+		return result
+	}), nil, nil
+}
+
+// BulkUpdateIssues applies the same field edits to every selected issue,
+// after the user confirmed when many issues are affected.
+func (j *JiraMCPServer) BulkUpdateIssues(ctx context.Context, req *mcp.CallToolRequest, params *BulkUpdateParams) (*mcp.CallToolResult, any, error) {
+	edit := UpdateIssueArgs{
+		Priority:         params.Priority,
+		Assignee:         params.Assignee,
+		DueDate:          params.DueDate,
+		AddLabels:        params.AddLabels,
+		RemoveLabels:     params.RemoveLabels,
+		AddComponents:    params.AddComponents,
+		RemoveComponents: params.RemoveComponents,
+		NotifyUsers:      params.NotifyUsers,
+	}
+	if edit.Priority == "" && edit.Assignee == "" && edit.DueDate == "" && len(edit.AddLabels)+len(edit.RemoveLabels)+len(edit.AddComponents)+len(edit.RemoveComponents) == 0 {
+		return textResult("Nothing to update: set priority, assignee, dueDate, or labels or components to add or remove"), nil, nil
+	}
+	keys, err := j.bulkIssueKeys(ctx, params.JQL, params.IssueKeys, params.MaxIssues)
+	if err != nil {
+		return textResult("Failed to select issues: %v", err), nil, nil
+	}
+	if len(keys) == 0 {
+		return textResult("No issues match; nothing was changed."), nil, nil
+	}
+	dryRun := j.dryRun(params.DryRun)
+	if !dryRun {
+		if problem := j.confirmBulk(ctx, req, "Updating issues", keys, params.ConfirmationPhrase); problem != "" {
+			return textResult("%s", problem), nil, nil
+		}
+	}
+	return j.runBulk(ctx, req, "Updating issues", keys, dryRun, func(key string) *mcp.CallToolResult {
+		args := edit
+		args.IssueKey = key
+		args.DryRun = dryRun
+		result, _, _ := j.UpdateJiraIssue(ctx, req, &args)
+		return result
+	}), nil, nil
+}
@@ -0,0 +1,243 @@
+package jiramcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/andygrunwald/go-jira"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// maxBurnupDays bounds the length of a burnup series.
+const maxBurnupDays = 366
+
+type BurnupParams struct {
+	// SprintID selects a sprint; otherwise FixVersion selects a version of
+	// ProjectKey.
+	SprintID   int    `json:"sprintId,omitempty"`
+	ProjectKey string `json:"projectKey,omitempty"`
+	FixVersion string `json:"fixVersion,omitempty"`
+	MaxIssues  int    `json:"maxIssues,omitempty"`
+}
+
+// burnupData is the result of the burnup tool: one value per date in each
+// series, ready to be charted.
+type burnupData struct {
+	Scope           string    `json:"scope"`
+	StoryPoints     string    `json:"storyPointsField,omitempty"`
+	Dates           []string  `json:"dates"`
+	ScopePoints     []float64 `json:"scopePoints"`
+	CompletedPoints []float64 `json:"completedPoints"`
+	ScopeIssues     []int     `json:"scopeIssues"`
+	CompletedIssues []int     `json:"completedIssues"`
+	IssuesScanned   int       `json:"issuesScanned"`
+}
+
+// burnupState is an issue's state at one point in time.
+type burnupState struct {
+	inScope bool
+	done    bool
+	points  float64
+}
+
+// burnupScope says which changelog entries move an issue in or out of the
+// scope being charted.
+type burnupScope struct {
+	sprintID  string
+	versionID string
+}
+
+// revert undoes a changelog item on state, turning the state after the
+// change into the state before it.
+func (s burnupScope) revert(state *burnupState, item jira.ChangelogItems, pointsName string, categories map[string]string) {
+	switch {
+	case item.Field == "status":
+		state.done = categories[strings.ToLower(item.FromString)] == "done"
+	case pointsName != "" && strings.EqualFold(item.Field, pointsName):
+		state.points = parsePoints(item.FromString)
+	case s.sprintID != "" && item.Field == "Sprint":
+		state.inScope = idListContains(item.From, s.sprintID)
+	case s.versionID != "" && item.Field == "Fix Version":
+		if fmt.Sprint(item.To) == s.versionID {
+			state.inScope = false
+		}
+		if fmt.Sprint(item.From) == s.versionID {
+			state.inScope = true
+		}
+	}
+}
+
+// stateAt replays the changelog of issue backwards from its current state to
+// its state at the given time.
+func (s burnupScope) stateAt(issue jira.Issue, at time.Time, pointsID, pointsName string, categories map[string]string) burnupState {
+	state := burnupState{
+		inScope: true,
+		done:    categories[strings.ToLower(statusName(&issue))] == "done",
+		points:  issuePoints(issue, pointsID),
+	}
+	histories := changelogHistories(issue)
+	for i := len(histories) - 1; i >= 0; i-- {
+		if t, err := time.Parse(jiraTimeLayout, histories[i].Created); err == nil && t.Before(at) {
+			break
+		}
+		for _, item := range histories[i].Items {
+			s.revert(&state, item, pointsName, categories)
+		}
+	}
+	if !time.Time(issue.Fields.Created).Before(at) {
+		state.inScope = false
+	}
+	return state
+}
+
+// burnupSeries replays the changelogs of issues backwards from their current
+// state to compute scope and completion at the end of each day.
+func burnupSeries(issues []jira.Issue, days []time.Time, scope burnupScope, pointsID, pointsName string, categories map[string]string) *burnupData {
+	data := &burnupData{
+		ScopePoints:     make([]float64, len(days)),
+		CompletedPoints: make([]float64, len(days)),
+		ScopeIssues:     make([]int, len(days)),
+		CompletedIssues: make([]int, len(days)),
+		IssuesScanned:   len(issues),
+	}
+	for _, day := range days {
+		data.Dates = append(data.Dates, day.Format("2006-01-02"))
+	}
+	for _, issue := range issues {
+		state := burnupState{
+			inScope: true,
+			done:    categories[strings.ToLower(statusName(&issue))] == "done",
+			points:  issuePoints(issue, pointsID),
+		}
+		created := time.Time(issue.Fields.Created)
+		histories := changelogHistories(issue)
+		next := len(histories) - 1
+		for d := len(days) - 1; d >= 0; d-- {
+			dayEnd := days[d].AddDate(0, 0, 1)
+			for ; next >= 0; next-- {
+				at, err := time.Parse(jiraTimeLayout, histories[next].Created)
+				if err == nil && at.Before(dayEnd) {
+					break
+				}
+				for _, item := range histories[next].Items {
+					scope.revert(&state, item, pointsName, categories)
+				}
+			}
+			if !state.inScope || !created.Before(dayEnd) {
+				continue
+			}
+			data.ScopePoints[d] += state.points
+			data.ScopeIssues[d]++
+			if state.done {
+				data.CompletedPoints[d] += state.points
+				data.CompletedIssues[d]++
+			}
+		}
+	}
+	return data
+}
+
+// Burnup returns daily scope and completed story points (and issue counts)
+// of a sprint or fix version, computed from the issues' changelogs.
+func (j *JiraMCPServer) Burnup(ctx context.Context, req *mcp.CallToolRequest, params *BurnupParams) (*mcp.CallToolResult, any, error) {
+	maxIssues := params.MaxIssues
+	if maxIssues <= 0 {
+		maxIssues = 500
+	}
+	now := time.Now().UTC()
+
+	var jql, label string
+	var scope burnupScope
+	var start, end time.Time
+	switch {
+	case params.SprintID > 0:
+		s, err := j.getSprint(ctx, params.SprintID)
+		if err != nil {
+			return textResult("Failed to get sprint %d: %v", params.SprintID, err), nil, nil
+		}
+		var ok bool
+		if start, ok = parseAgileTime(s.StartDate); !ok {
+			return textResult("Sprint %d (%s) has not started", s.ID, s.Name), nil, nil
+		}
+		end = now
+		if t, ok := parseAgileTime(s.CompleteDate); ok {
+			end = t
+		} else if t, ok := parseAgileTime(s.EndDate); ok && t.Before(now) {
+			end = t
+		}
+		jql = fmt.Sprintf("sprint = %d", s.ID)
+		label = fmt.Sprintf("sprint %d (%s)", s.ID, s.Name)
+		scope.sprintID = strconv.Itoa(s.ID)
+	case params.FixVersion != "":
+		projectKey := j.projectKeyOrDefault(params.ProjectKey)
+		v, err := j.findVersion(ctx, projectKey, params.FixVersion)
+		if err != nil {
+			return textResult("Failed to find version: %v", err), nil, nil
+		}
+		start, _ = parseAgileTime(v.StartDate)
+		end = now
+		if t, ok := parseAgileTime(v.ReleaseDate); ok && v.Released && t.Before(now) {
+			end = t
+		}
+		jql = fmt.Sprintf("project = %s AND fixVersion = %s", projectKey, v.ID)
+		label = fmt.Sprintf("%s version %s", projectKey, v.Name)
+		scope.versionID = v.ID
+	default:
+		return textResult("sprintId or fixVersion is required"), nil, nil
+	}
+
+	pointsID, pointsName, err := j.storyPointsField(ctx)
+	if err != nil {
+		return textResult("Failed to find the story points field: %v", err), nil, nil
+	}
+	fields := []string{"created", "status"}
+	if pointsID != "" {
+		fields = append(fields, pointsID)
+	}
+	issues, err := j.searchIssues(ctx, jql, fields, "changelog", maxIssues)
+	if err != nil {
+		return textResult("Failed to load issues of %s: %v", label, err), nil, nil
+	}
+	categories, err := j.statusCategories(ctx)
+	if err != nil {
+		return textResult("Failed to load status categories: %v", err), nil, nil
+	}
+
+	// A version without a start date is charted from its first issue.
+	if start.IsZero() {
+		start = end
+		for _, issue := range issues {
+			if created := time.Time(issue.Fields.Created); created.Before(start) {
+				start = created
+			}
+		}
+	}
+	first := start.UTC().Truncate(24 * time.Hour)
+	var days []time.Time
+	for day := first; !day.After(end) && len(days) < maxBurnupDays; day = day.AddDate(0, 0, 1) {
+		days = append(days, day)
+	}
+
+	data := burnupSeries(issues, days, scope, pointsID, pointsName, categories)
+	data.Scope = label
+	data.StoryPoints = pointsName
+	b, err := json.MarshalIndent(data, "", "  ")
+	if err != nil {
+		return textResult("Failed to encode burnup data: %v", err), nil, nil
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "Burnup of %s from %s to %s", label, first.Format("2006-01-02"), end.Format("2006-01-02"))
+	if pointsName == "" {
+		sb.WriteString(" (no story points field found, so point series are zero; use the issue counts)")
+	}
+	if len(issues) == maxIssues {
+		fmt.Fprintf(&sb, " (only the first %d issues; raise maxIssues)", maxIssues)
+	}
+	fmt.Fprintf(&sb, ":\n%s\n", b)
+	return textResult("%s", sb.String()), nil, nil
+}
@@ -0,0 +1,231 @@
+package jiramcp
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"golang.org/x/time/rate"
+)
+
+// metadataCacheTTL is how long project and user lookups are cached.
+const metadataCacheTTL = 10 * time.Minute
+
+// redisKeyPrefix namespaces the server's keys in a shared Redis.
+const redisKeyPrefix = "jira-mcp:"
+
+// Cache is a TTL cache for Jira metadata such as projects and users. The
+// in-memory cache is per process; the Redis cache is shared by every replica
+// pointed at the same Redis.
+type Cache interface {
+	// Get decodes the cached value for key into v and reports whether it was
+	// found.
+	Get(ctx context.Context, key string, v interface{}) (bool, error)
+	Set(ctx context.Context, key string, v interface{}, ttl time.Duration) error
+}
+
+// Limiter paces requests to Jira to stay within a request budget.
+type Limiter interface {
+	// Wait blocks until a request may be sent. It reports whether it had to
+	// wait for budget.
+	Wait(ctx context.Context) (bool, error)
+}
+
+// newCacheAndLimiter builds the cache and, when JIRA_MCP_JIRA_RATE_LIMIT is
+// set, the limiter, backed by Redis when JIRA_MCP_REDIS_URL is set.
+func newCacheAndLimiter(config *JiraConfig) (Cache, Limiter, error) {
+	service := jiraCredentials{Username: config.Username, Token: config.APIToken}
+	if config.RedisURL == "" {
+		var limiter Limiter
+		if config.JiraRateLimit > 0 {
+			limiter = &localLimiter{rate.NewLimiter(rate.Limit(config.JiraRateLimit), config.JiraRateLimit)}
+		}
+		return &scopedCache{Cache: &memoryCache{}, site: config.BaseURL, service: service.hash()}, limiter, nil
+	}
+
+	opts, err := redis.ParseURL(config.RedisURL)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid JIRA_MCP_REDIS_URL: %w", err)
+	}
+	client := redis.NewClient(opts)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := client.Ping(ctx).Err(); err != nil {
+		return nil, nil, fmt.Errorf("failed to connect to Redis: %w", err)
+	}
+	var limiter Limiter
+	if config.JiraRateLimit > 0 {
+		limiter = &redisLimiter{client: client, perSecond: int64(config.JiraRateLimit)}
+	}
+	return &scopedCache{Cache: &redisCache{client: client}, site: config.BaseURL, service: service.hash()}, limiter, nil
+}
+
+// jiraAccountKey carries the hash of the Jira credentials a request acts
+// with, when they are not the service account's.
+type jiraAccountKey struct{}
+
+// scopedCache keys entries by Jira site and by the account that fetched
+// them, so that what one account may see is never served to another, and
+// servers pointed at different sites never share entries in Redis.
+type scopedCache struct {
+	Cache
+	site    string
+	service string
+}
+
+func (c *scopedCache) key(ctx context.Context, key string) string {
+	account, ok := ctx.Value(jiraAccountKey{}).(string)
+	if !ok {
+		account = c.service
+	}
+	sum := sha256.Sum256([]byte(c.site + "\x00" + account))
+	return hex.EncodeToString(sum[:8]) + ":" + key
+}
+
+func (c *scopedCache) Get(ctx context.Context, key string, v interface{}) (bool, error) {
+	return c.Cache.Get(ctx, c.key(ctx, key), v)
+}
+
+func (c *scopedCache) Set(ctx context.Context, key string, v interface{}, ttl time.Duration) error {
+	return c.Cache.Set(ctx, c.key(ctx, key), v, ttl)
+}
+
+// cached returns the value cached under key, or calls fetch and caches its
+// result. Cache errors are logged and treated as misses.
+func cached[T any](ctx context.Context, c Cache, name, key string, fetch func() (T, error)) (T, error) {
+	var v T
+	found, err := c.Get(ctx, key, &v)
+	if err != nil {
+		slog.Warn("Cache read failed", "cache", name, "error", err)
+	}
+	recordCache(name, found)
+	if found {
+		return v, nil
+	}
+	v, err = fetch()
+	if err != nil {
+		return v, err
+	}
+	if err := c.Set(ctx, key, v, metadataCacheTTL); err != nil {
+		slog.Warn("Cache write failed", "cache", name, "error", err)
+	}
+	return v, nil
+}
+
+// memoryCache is the default, process-local cache.
+type memoryCache struct {
+	mu      sync.Mutex
+	entries map[string]memoryEntry
+}
+
+type memoryEntry struct {
+	value   []byte
+	expires time.Time
+}
+
+func (c *memoryCache) Get(ctx context.Context, key string, v interface{}) (bool, error) {
+	c.mu.Lock()
+	e, ok := c.entries[key]
+	c.mu.Unlock()
+	if !ok || time.Now().After(e.expires) {
+		return false, nil
+	}
+	return true, json.Unmarshal(e.value, v)
+}
+
+func (c *memoryCache) Set(ctx context.Context, key string, v interface{}, ttl time.Duration) error {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.entries == nil {
+		c.entries = make(map[string]memoryEntry)
+	}
+	for k, e := range c.entries {
+		if now.After(e.expires) {
+			delete(c.entries, k)
+		}
+	}
+	c.entries[key] = memoryEntry{value: b, expires: now.Add(ttl)}
+	return nil
+}
+
+// redisCache shares cached metadata between replicas.
+type redisCache struct {
+	client *redis.Client
+}
+
+func (c *redisCache) Get(ctx context.Context, key string, v interface{}) (bool, error) {
+	b, err := c.client.Get(ctx, redisKeyPrefix+"cache:"+key).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, json.Unmarshal(b, v)
+}
+
+func (c *redisCache) Set(ctx context.Context, key string, v interface{}, ttl time.Duration) error {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	return c.client.Set(ctx, redisKeyPrefix+"cache:"+key, b, ttl).Err()
+}
+
+// localLimiter is a token bucket for a single process.
+type localLimiter struct {
+	limiter *rate.Limiter
+}
+
+func (l *localLimiter) Wait(ctx context.Context) (bool, error) {
+	if l.limiter.Allow() {
+		return false, nil
+	}
+	return true, l.limiter.Wait(ctx)
+}
+
+// redisLimiter enforces a combined requests-per-second budget across replicas
+// with one counter per second in Redis.
+type redisLimiter struct {
+	client    *redis.Client
+	perSecond int64
+}
+
+func (l *redisLimiter) Wait(ctx context.Context) (bool, error) {
+	waited := false
+	for {
+		now := time.Now()
+		key := redisKeyPrefix + "rate:" + strconv.FormatInt(now.Unix(), 10)
+		n, err := l.client.Incr(ctx, key).Result()
+		if err != nil {
+			// Do not stop talking to Jira because Redis is unavailable.
+			slog.Warn("Rate limiter unavailable", "error", err)
+			return waited, nil
+		}
+		if n == 1 {
+			l.client.Expire(ctx, key, 2*time.Second)
+		}
+		if n <= l.perSecond {
+			return waited, nil
+		}
+		waited = true
+		select {
+		case <-ctx.Done():
+			return waited, ctx.Err()
+		case <-time.After(now.Truncate(time.Second).Add(time.Second).Sub(now)):
+		}
+	}
+}
@@ -0,0 +1,208 @@
+package jiramcp
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// interaction is one recorded Jira request and its response. Requests are
+// recorded relative to the base URL and without headers, so cassettes hold no
+// credentials and replay against any base URL.
+type interaction struct {
+	Method       string `json:"method"`
+	URI          string `json:"uri"`
+	RequestBody  string `json:"requestBody,omitempty"`
+	Status       int    `json:"status"`
+	ContentType  string `json:"contentType,omitempty"`
+	ResponseBody string `json:"responseBody"`
+}
+
+// cassette is a file of recorded interactions, written by JIRA_MCP_RECORD_FILE
+// and served back by JIRA_MCP_REPLAY_FILE.
+type cassette struct {
+	mu           sync.Mutex
+	path         string
+	RecordedAt   time.Time     `json:"recordedAt"`
+	BaseURL      string        `json:"baseUrl"`
+	Interactions []interaction `json:"interactions"`
+	// used marks the interactions already replayed.
+	used []bool
+}
+
+// loadCassette reads a cassette for replay.
+func loadCassette(path string) (*cassette, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read cassette: %w", err)
+	}
+	c := &cassette{path: path}
+	if err := json.Unmarshal(b, c); err != nil {
+		return nil, fmt.Errorf("failed to parse cassette %s: %w", path, err)
+	}
+	c.used = make([]bool, len(c.Interactions))
+	return c, nil
+}
+
+// save writes the cassette, replacing the file atomically so an interrupted
+// session leaves the last complete recording behind.
+func (c *cassette) save() error {
+	b, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(c.path), ".cassette-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(b); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), c.path)
+}
+
+// requestKey returns the method, relative URI, and body identifying a request.
+func requestKey(req *http.Request, baseURL string) (method, uri, body string, err error) {
+	uri = req.URL.RequestURI()
+	if base, err := req.URL.Parse(baseURL); err == nil {
+		uri = strings.TrimPrefix(uri, strings.TrimSuffix(base.Path, "/"))
+	}
+	if req.Body != nil {
+		b, err := io.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return "", "", "", err
+		}
+		req.Body = io.NopCloser(bytes.NewReader(b))
+		body = string(b)
+	}
+	return req.Method, uri, body, nil
+}
+
+// recordingTransport passes requests on to Jira and appends each exchange to
+// a cassette.
+type recordingTransport struct {
+	base     http.RoundTripper
+	cassette *cassette
+}
+
+func (t *recordingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	method, uri, body, err := requestKey(req, t.cassette.BaseURL)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := t.base.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	b, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(b))
+
+	c := t.cassette
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.Interactions = append(c.Interactions, interaction{
+		Method:       method,
+		URI:          uri,
+		RequestBody:  body,
+		Status:       resp.StatusCode,
+		ContentType:  resp.Header.Get("Content-Type"),
+		ResponseBody: string(b),
+	})
+	if err := c.save(); err != nil {
+		return nil, fmt.Errorf("failed to write cassette %s: %w", c.path, err)
+	}
+	return resp, nil
+}
+
+// replayTransport answers requests from a cassette without contacting Jira.
+// Each request gets the first unused interaction with the same method, URI,
+// and body, so repeated requests replay in recorded order; once those are
+// used up, the last of them is served again.
+type replayTransport struct {
+	cassette *cassette
+	baseURL  string
+}
+
+func (t *replayTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	method, uri, body, err := requestKey(req, t.baseURL)
+	if err != nil {
+		return nil, err
+	}
+	c := t.cassette
+	c.mu.Lock()
+	match := -1
+	for i, in := range c.Interactions {
+		if in.Method != method || in.URI != uri || in.RequestBody != body {
+			continue
+		}
+		match = i
+		if !c.used[i] {
+			break
+		}
+	}
+	if match >= 0 {
+		c.used[match] = true
+	}
+	c.mu.Unlock()
+	if match < 0 {
+		return nil, fmt.Errorf("replay: %s %s was not recorded in %s", method, uri, c.path)
+	}
+
+	in := c.Interactions[match]
+	header := make(http.Header)
+	if in.ContentType != "" {
+		header.Set("Content-Type", in.ContentType)
+	}
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", in.Status, http.StatusText(in.Status)),
+		StatusCode:    in.Status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(strings.NewReader(in.ResponseBody)),
+		ContentLength: int64(len(in.ResponseBody)),
+		Request:       req,
+	}, nil
+}
+
+// jiraTransport returns the transport Jira requests are sent with: the
+// network, a recorder in front of it, or a cassette instead of it.
+func jiraTransport(config *JiraConfig) (http.RoundTripper, error) {
+	if config.ReplayFile != "" {
+		c, err := loadCassette(config.ReplayFile)
+		if err != nil {
+			return nil, err
+		}
+		return &replayTransport{cassette: c, baseURL: config.BaseURL}, nil
+	}
+	network, err := networkTransport(config)
+	if err != nil {
+		return nil, err
+	}
+	if config.RecordFile != "" {
+		c := &cassette{path: config.RecordFile, RecordedAt: time.Now().UTC(), BaseURL: config.BaseURL}
+		if err := c.save(); err != nil {
+			return nil, fmt.Errorf("failed to create cassette %s: %w", config.RecordFile, err)
+		}
+		return &recordingTransport{base: network, cassette: c}, nil
+	}
+	return network, nil
+}
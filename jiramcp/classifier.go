@@ -0,0 +1,102 @@
+package jiramcp
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// Urgency tags a classifier may return, from most to least urgent. Unknown
+// tags sort after these.
+var urgencyRank = map[string]int{"critical": 0, "high": 1, "medium": 2, "low": 3}
+
+// classifierComment is a comment sent to the classifier.
+type classifierComment struct {
+	ID       string `json:"id"`
+	IssueKey string `json:"issueKey"`
+	Author   string `json:"author,omitempty"`
+	Public   bool   `json:"public"`
+	Body     string `json:"body"`
+}
+
+// commentTags is the classifier's verdict on one comment.
+type commentTags struct {
+	ID        string   `json:"id"`
+	Urgency   string   `json:"urgency,omitempty"`
+	Sentiment string   `json:"sentiment,omitempty"`
+	Tags      []string `json:"tags,omitempty"`
+}
+
+// String renders the tags for tool results, e.g. "[urgency: high, sentiment:
+// negative, refund]".
+func (t commentTags) String() string {
+	var parts []string
+	if t.Urgency != "" {
+		parts = append(parts, "urgency: "+t.Urgency)
+	}
+	if t.Sentiment != "" {
+		parts = append(parts, "sentiment: "+t.Sentiment)
+	}
+	parts = append(parts, t.Tags...)
+	if len(parts) == 0 {
+		return ""
+	}
+	return "[" + strings.Join(parts, ", ") + "]"
+}
+
+// rank orders tags for triage: more urgent first, then negative sentiment.
+func (t commentTags) rank() int {
+	r, ok := urgencyRank[strings.ToLower(t.Urgency)]
+	if !ok {
+		r = len(urgencyRank)
+	}
+	r *= 2
+	if !strings.EqualFold(t.Sentiment, "negative") {
+		r++
+	}
+	return r
+}
+
+// classifyComments sends comments to JIRA_MCP_CLASSIFIER_URL as
+// {"comments": [...]} and returns the tags of each by comment ID. The
+// classifier answers {"results": [{"id", "urgency", "sentiment", "tags"}]}.
+// It returns nil without error when no classifier is configured.
+func (j *JiraMCPServer) classifyComments(ctx context.Context, comments []classifierComment) (map[string]commentTags, error) {
+	if j.config.ClassifierURL == "" || len(comments) == 0 {
+		return nil, nil
+	}
+	body, err := json.Marshal(map[string]interface{}{"comments": comments})
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, j.config.ClassifierURL, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if j.config.ClassifierToken != "" {
+		req.Header.Set("Authorization", "Bearer "+j.config.ClassifierToken)
+	}
+	resp, err := outboundHTTPClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("classifier returned %s", resp.Status)
+	}
+	var result struct {
+		Results []commentTags `json:"results"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("failed to decode classifier response: %w", err)
+	}
+	tags := make(map[string]commentTags, len(result.Results))
+	for _, t := range result.Results {
+		tags[t.ID] = t
+	}
+	return tags, nil
+}
@@ -0,0 +1,247 @@
+package jiramcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/andygrunwald/go-jira"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// crossProjectCloneFields are copied by default when cloning into another
+// project. Custom fields and versions are left out because their IDs are
+// usually specific to the source project's configuration.
+var crossProjectCloneFields = []string{"summary", "description", "priority", "labels", "components", "environment", "duedate"}
+
+// nameReferencedFields hold project-scoped objects that are matched by name
+// when an issue is cloned into a different project.
+var nameReferencedFields = map[string]bool{
+	"components":  true,
+	"fixVersions": true,
+	"versions":    true,
+}
+
+type CloneIssueParams struct {
+	IssueKey string `json:"issueKey"`
+	// ProjectKey is the project to clone into; defaults to the source issue's project.
+	ProjectKey string `json:"projectKey,omitempty"`
+	// IssueType overrides the issue type of the clone.
+	IssueType string `json:"issueType,omitempty"`
+	// Summary overrides the summary of the clone; defaults to "CLONE - <original summary>".
+	Summary string `json:"summary,omitempty"`
+	// IncludeFields restricts the copied fields to this list of field IDs.
+	IncludeFields []string `json:"includeFields,omitempty"`
+	// ExcludeFields lists field IDs that are never copied.
+	ExcludeFields   []string `json:"excludeFields,omitempty"`
+	CopyAttachments bool     `json:"copyAttachments,omitempty"`
+	CopyLinks       bool     `json:"copyLinks,omitempty"`
+	// LinkToOriginal creates a "Cloners" link from the clone to the original (default true).
+	LinkToOriginal *bool `json:"linkToOriginal,omitempty"`
+	DryRun         bool  `json:"dryRun,omitempty"`
+}
+
+type createdIssue struct {
+	ID   string `json:"id"`
+	Key  string `json:"key"`
+	Self string `json:"self"`
+}
+
+// cloneFields builds the create payload fields for a clone of issue.
+func (j *JiraMCPServer) cloneFields(ctx context.Context, issue *rawIssue, sourceProject, targetProject string, params *CloneIssueParams) (map[string]interface{}, error) {
+	var candidates []string
+	switch {
+	case len(params.IncludeFields) > 0:
+		candidates = params.IncludeFields
+	case sourceProject != targetProject:
+		candidates = crossProjectCloneFields
+	default:
+		editable, err := j.editableFields(ctx, issue.Key)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get editable fields of %s: %w", issue.Key, err)
+		}
+		for id := range editable {
+			if !nonRestorableFields[id] {
+				candidates = append(candidates, id)
+			}
+		}
+	}
+	excluded := make(map[string]bool, len(params.ExcludeFields))
+	for _, id := range params.ExcludeFields {
+		excluded[id] = true
+	}
+
+	fields := make(map[string]interface{})
+	for _, id := range candidates {
+		raw, ok := issue.Fields[id]
+		if !ok || excluded[id] || nonRestorableFields[id] || string(raw) == "null" {
+			continue
+		}
+		if sourceProject != targetProject && nameReferencedFields[id] {
+			fields[id] = nameReferences(raw)
+		} else {
+			fields[id] = restorableValue(raw)
+		}
+	}
+
+	var summary string
+	var issueType struct {
+		ID   string `json:"id"`
+		Name string `json:"name"`
+	}
+	json.Unmarshal(issue.Fields["summary"], &summary)
+	json.Unmarshal(issue.Fields["issuetype"], &issueType)
+
+	fields["project"] = map[string]string{"key": targetProject}
+	fields["summary"] = "CLONE - " + summary
+	if params.Summary != "" {
+		fields["summary"] = params.Summary
+	}
+	switch {
+	case params.IssueType != "":
+		fields["issuetype"] = map[string]string{"name": params.IssueType}
+	case sourceProject != targetProject:
+		fields["issuetype"] = map[string]string{"name": issueType.Name}
+	default:
+		fields["issuetype"] = map[string]string{"id": issueType.ID}
+	}
+	return fields, nil
+}
+
+// nameReferences converts a list of objects into references by name.
+func nameReferences(raw json.RawMessage) []map[string]interface{} {
+	var items []struct {
+		Name string `json:"name"`
+	}
+	json.Unmarshal(raw, &items)
+	refs := make([]map[string]interface{}, 0, len(items))
+	for _, item := range items {
+		refs = append(refs, map[string]interface{}{"name": item.Name})
+	}
+	return refs
+}
+
+// CloneJiraIssue creates a copy of an issue, optionally in another project,
+// with its attachments and links.
+func (j *JiraMCPServer) CloneJiraIssue(ctx context.Context, req *mcp.CallToolRequest, params *CloneIssueParams) (*mcp.CallToolResult, any, error) {
+	var issue rawIssue
+	if _, err := j.jiraDo(ctx, "GET", fmt.Sprintf("rest/api/2/issue/%s?fields=*all", params.IssueKey), nil, &issue); err != nil {
+		return textResult("Failed to get JIRA issue %s: %v", params.IssueKey, err), nil, nil
+	}
+	var project struct {
+		Key string `json:"key"`
+	}
+	json.Unmarshal(issue.Fields["project"], &project)
+	targetProject := project.Key
+	if params.ProjectKey != "" {
+		targetProject = strings.ToUpper(params.ProjectKey)
+	}
+
+	fields, err := j.cloneFields(ctx, &issue, project.Key, targetProject, params)
+	if err != nil {
+		return textResult("Failed to clone %s: %v", issue.Key, err), nil, nil
+	}
+	payload := map[string]interface{}{"fields": fields}
+
+	if j.dryRun(params.DryRun) {
+		var problems []string
+		target, err := j.getProject(ctx, targetProject)
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("project %s does not exist or is not accessible: %v", targetProject, err))
+		} else if ref, ok := fields["issuetype"].(map[string]string); ok && ref["name"] != "" {
+			found := false
+			for _, t := range target.IssueTypes {
+				found = found || strings.EqualFold(t.Name, ref["name"])
+			}
+			if !found {
+				problems = append(problems, fmt.Sprintf("issue type %q does not exist in %s", ref["name"], targetProject))
+			}
+		}
+		return dryRunResult("POST", "rest/api/2/issue", payload, problems), nil, nil
+	}
+
+	var clone createdIssue
+	if _, err := j.jiraDo(ctx, "POST", "rest/api/2/issue", payload, &clone); err != nil {
+		return textResult("Failed to create clone of %s in %s: %v", issue.Key, targetProject, err), nil, nil
+	}
+	logger(ctx).Info("Cloned issue", "clone", clone.Key)
+	j.journalCreate(ctx, "clone-jira-issue", clone.Key)
+
+	// Attachments and links are copied after the clone exists; failures are
+	// reported but do not undo the clone.
+	var warnings []string
+	if params.LinkToOriginal == nil || *params.LinkToOriginal {
+		if err := j.linkIssues(ctx, "Cloners", clone.Key, issue.Key); err != nil {
+			warnings = append(warnings, fmt.Sprintf("could not link %s to %s: %v", clone.Key, issue.Key, err))
+		}
+	}
+	copied := 0
+	if params.CopyLinks {
+		var links []jira.IssueLink
+		json.Unmarshal(issue.Fields["issuelinks"], &links)
+		for _, l := range links {
+			inward, outward := clone.Key, ""
+			if l.OutwardIssue != nil {
+				outward = l.OutwardIssue.Key
+			} else if l.InwardIssue != nil {
+				inward, outward = l.InwardIssue.Key, clone.Key
+			}
+			if err := j.linkIssues(ctx, l.Type.Name, inward, outward); err != nil {
+				warnings = append(warnings, fmt.Sprintf("could not copy %q link: %v", l.Type.Name, err))
+				continue
+			}
+			copied++
+		}
+	}
+	attached := 0
+	if params.CopyAttachments {
+		var attachments []jira.Attachment
+		json.Unmarshal(issue.Fields["attachment"], &attachments)
+		for _, a := range attachments {
+			if err := j.copyAttachment(ctx, &a, clone.Key); err != nil {
+				warnings = append(warnings, fmt.Sprintf("could not copy attachment %s: %v", a.Filename, err))
+				continue
+			}
+			attached++
+		}
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "Cloned %s to %s: %s/browse/%s\n", issue.Key, clone.Key, j.config.BaseURL, clone.Key)
+	fmt.Fprintf(&sb, "Copied %d field(s)", len(fields))
+	if params.CopyLinks {
+		fmt.Fprintf(&sb, ", %d link(s)", copied)
+	}
+	if params.CopyAttachments {
+		fmt.Fprintf(&sb, ", %d attachment(s)", attached)
+	}
+	sb.WriteString("\n")
+	for _, w := range warnings {
+		fmt.Fprintf(&sb, "Warning: %s\n", w)
+	}
+	return textResult("%s", sb.String()), nil, nil
+}
+
+// linkIssues creates an issue link. As in the links returned on an issue,
+// the inward issue is the one the link type's outward description applies
+// to, e.g. "inward clones outward".
+func (j *JiraMCPServer) linkIssues(ctx context.Context, linkType, inward, outward string) error {
+	_, err := j.client(ctx).AddLink(ctx, &jira.IssueLink{
+		Type:         jira.IssueLinkType{Name: linkType},
+		InwardIssue:  &jira.Issue{Key: inward},
+		OutwardIssue: &jira.Issue{Key: outward},
+	})
+	return err
+}
+
+// copyAttachment downloads an attachment and uploads it to another issue.
+func (j *JiraMCPServer) copyAttachment(ctx context.Context, a *jira.Attachment, issueKey string) error {
+	resp, err := j.client(ctx).DownloadAttachment(ctx, a.ID)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	_, _, err = j.client(ctx).PostAttachment(ctx, issueKey, resp.Body, a.Filename)
+	return err
+}
@@ -0,0 +1,54 @@
+package jiramcp
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/andygrunwald/go-jira"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+type AddCommentParams struct {
+	IssueKey string `json:"issueKey"`
+	Body     string `json:"body"`
+	// VisibilityRole or VisibilityGroup restrict the comment to members of a
+	// project role or group.
+	VisibilityRole  string `json:"visibilityRole,omitempty"`
+	VisibilityGroup string `json:"visibilityGroup,omitempty"`
+	// LiteralMentions keeps @name and @email text as typed instead of
+	// turning it into mentions that notify the users.
+	LiteralMentions bool `json:"literalMentions,omitempty"`
+	DryRun          bool `json:"dryRun,omitempty"`
+}
+
+// AddComment posts a comment on an issue.
+func (j *JiraMCPServer) AddComment(ctx context.Context, req *mcp.CallToolRequest, params *AddCommentParams) (*mcp.CallToolResult, any, error) {
+	if strings.TrimSpace(params.Body) == "" {
+		return textResult("Comment body is required"), nil, nil
+	}
+	if params.VisibilityRole != "" && params.VisibilityGroup != "" {
+		return textResult("Specify at most one of visibilityRole and visibilityGroup"), nil, nil
+	}
+
+	body, mentioned := j.resolveMentions(ctx, params.Body, params.LiteralMentions)
+	comment := &jira.Comment{Body: body}
+	switch {
+	case params.VisibilityRole != "":
+		comment.Visibility = jira.CommentVisibility{Type: "role", Value: params.VisibilityRole}
+	case params.VisibilityGroup != "":
+		comment.Visibility = jira.CommentVisibility{Type: "group", Value: params.VisibilityGroup}
+	}
+
+	if j.dryRun(params.DryRun) {
+		return dryRunResult("POST", fmt.Sprintf("rest/api/2/issue/%s/comment", params.IssueKey), comment, j.issueProblems(ctx, params.IssueKey)), nil, nil
+	}
+
+	created, _, err := j.client(ctx).AddComment(ctx, params.IssueKey, comment)
+	if err != nil {
+		return textResult("Failed to add comment to %s: %v", params.IssueKey, err), nil, nil
+	}
+	logger(ctx).Info("Added comment", "commentId", created.ID)
+
+	return textResult("Added comment %s to %s/browse/%s%s", created.ID, j.config.BaseURL, params.IssueKey, mentioned.note()), nil, nil
+}
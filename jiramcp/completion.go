@@ -0,0 +1,217 @@
+package jiramcp
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/andygrunwald/go-jira"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// maxCompletions is the most values returned for one completion request, the
+// limit set by the MCP specification.
+const maxCompletions = 100
+
+const (
+	// recentIssueCapacity bounds the issue keys remembered for completion.
+	recentIssueCapacity = 500
+	// completionLookupInterval is the minimum time between Jira lookups made
+	// for completions; clients ask on every keystroke.
+	completionLookupInterval = 500 * time.Millisecond
+	// completionCacheTTL is how long lookup results are reused.
+	completionCacheTTL = time.Minute
+	// minLookupPrefix is the shortest value looked up in Jira.
+	minLookupPrefix = 2
+)
+
+// recentIssues remembers the keys of issues the server has returned, most
+// recent first, so issue keys can be completed without calling Jira.
+type recentIssues struct {
+	mu   sync.Mutex
+	keys []string
+}
+
+func (r *recentIssues) add(issues ...jira.Issue) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, issue := range issues {
+		if issue.Key == "" {
+			continue
+		}
+		if i := slices.Index(r.keys, issue.Key); i >= 0 {
+			r.keys = slices.Delete(r.keys, i, i+1)
+		}
+		r.keys = slices.Insert(r.keys, 0, issue.Key)
+	}
+	if len(r.keys) > recentIssueCapacity {
+		r.keys = r.keys[:recentIssueCapacity]
+	}
+}
+
+func (r *recentIssues) matching(prefix string) []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var values []string
+	for _, key := range r.keys {
+		if strings.HasPrefix(key, prefix) {
+			values = append(values, key)
+		}
+	}
+	return values
+}
+
+// completionLookups throttles and caches the Jira lookups behind completions.
+// The throttle is per replica; results go to the shared metadata cache.
+type completionLookups struct {
+	mu    sync.Mutex
+	last  time.Time
+	cache Cache
+}
+
+// lookup returns the cached values for key, or calls fetch when the cache has
+// none and the throttle allows it. A throttled lookup returns nothing; the
+// client asks again as the user keeps typing.
+func (c *completionLookups) lookup(ctx context.Context, key string, fetch func() ([]string, error)) []string {
+	key = "completion:" + key
+	var values []string
+	found, err := c.cache.Get(ctx, key, &values)
+	if err != nil {
+		slog.Warn("Cache read failed", "cache", "completion", "error", err)
+	}
+	recordCache("completion", found)
+	if found {
+		return values
+	}
+
+	c.mu.Lock()
+	now := time.Now()
+	if now.Sub(c.last) < completionLookupInterval {
+		c.mu.Unlock()
+		return nil
+	}
+	c.last = now
+	c.mu.Unlock()
+
+	values, err = fetch()
+	if err != nil {
+		slog.Warn("Completion lookup failed", "lookup", key, "error", err)
+		return nil
+	}
+	if err := c.cache.Set(ctx, key, values, completionCacheTTL); err != nil {
+		slog.Warn("Cache write failed", "cache", "completion", "error", err)
+	}
+	return values
+}
+
+// complete serves completion/complete for prompt and resource template
+// arguments. Arguments are completed by name, using the same names as the
+// tools (projectKey, issueKey, user), so clients that build forms from them
+// can autocomplete those too.
+func (j *JiraMCPServer) complete(ctx context.Context, req *mcp.CompleteRequest) (*mcp.CompleteResult, error) {
+	arg := req.Params.Argument
+	var values []string
+	switch arg.Name {
+	case "projectKey", "project":
+		values = j.completeProjectKeys(arg.Value)
+	case "issueKey", "key":
+		values = j.completeIssueKeys(ctx, arg.Value)
+	case "user", "assignee", "reporter", "watcher":
+		values = j.completeUsers(ctx, arg.Value)
+	}
+	return completionResult(values), nil
+}
+
+// completeProjectKeys offers the configured project and the allowed projects
+// that start with prefix.
+func (j *JiraMCPServer) completeProjectKeys(prefix string) []string {
+	candidates := append([]string{j.config.ProjectKey}, j.currentSettings().AllowedProjects...)
+	var values []string
+	for _, key := range candidates {
+		key = strings.ToUpper(key)
+		if strings.HasPrefix(key, strings.ToUpper(prefix)) && !slices.Contains(values, key) {
+			values = append(values, key)
+		}
+	}
+	return values
+}
+
+// completeIssueKeys offers the keys of recently returned issues that start
+// with prefix, topped up from Jira's issue picker, which also knows the
+// caller's recently viewed issues.
+func (j *JiraMCPServer) completeIssueKeys(ctx context.Context, prefix string) []string {
+	prefix = strings.ToUpper(strings.TrimSpace(prefix))
+	var values []string
+	for _, key := range j.recentIssues.matching(prefix) {
+		if j.projectInScope(ctx, projectOfIssue(key)) {
+			values = append(values, key)
+		}
+	}
+	if len(values) >= 10 || len(prefix) < minLookupPrefix {
+		return values
+	}
+	picked := j.completions.lookup(ctx, "issue:"+prefix, func() ([]string, error) {
+		var result struct {
+			Sections []struct {
+				Issues []struct {
+					Key string `json:"key"`
+				} `json:"issues"`
+			} `json:"sections"`
+		}
+		path := "rest/api/2/issue/picker?showSubTasks=true&query=" + url.QueryEscape(prefix)
+		if _, err := j.jiraDo(ctx, "GET", path, nil, &result); err != nil {
+			return nil, err
+		}
+		var keys []string
+		for _, section := range result.Sections {
+			for _, issue := range section.Issues {
+				keys = append(keys, issue.Key)
+			}
+		}
+		return keys, nil
+	})
+	for _, key := range picked {
+		if strings.HasPrefix(key, prefix) && !slices.Contains(values, key) && j.projectInScope(ctx, projectOfIssue(key)) {
+			values = append(values, key)
+		}
+	}
+	return values
+}
+
+// completeUsers offers the display names of users matching prefix.
+func (j *JiraMCPServer) completeUsers(ctx context.Context, prefix string) []string {
+	prefix = strings.TrimSpace(prefix)
+	if len(prefix) < minLookupPrefix {
+		return nil
+	}
+	return j.completions.lookup(ctx, "user:"+strings.ToLower(prefix), func() ([]string, error) {
+		users, _, err := j.client(ctx).FindUsers(ctx, prefix)
+		if err != nil {
+			return nil, fmt.Errorf("user search failed: %w", err)
+		}
+		var names []string
+		for _, u := range users {
+			if u.Active && u.DisplayName != "" && !slices.Contains(names, u.DisplayName) {
+				names = append(names, u.DisplayName)
+			}
+		}
+		return names, nil
+	})
+}
+
+func completionResult(values []string) *mcp.CompleteResult {
+	result := &mcp.CompleteResult{Completion: mcp.CompletionResultDetails{Values: values, Total: len(values)}}
+	if len(values) > maxCompletions {
+		result.Completion.Values = values[:maxCompletions]
+		result.Completion.HasMore = true
+	}
+	if result.Completion.Values == nil {
+		result.Completion.Values = []string{}
+	}
+	return result
+}
@@ -0,0 +1,111 @@
+package jiramcp
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/andygrunwald/go-jira"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+type ListComponentsParams struct {
+	ProjectKey string `json:"projectKey,omitempty"`
+	Verbosity  string `json:"verbosity,omitempty"`
+}
+
+type CreateComponentParams struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	ProjectKey  string `json:"projectKey,omitempty"`
+	// LeadAccountID optionally sets the component lead. On Server and Data
+	// Center it is the lead's username.
+	LeadAccountID string `json:"leadAccountId,omitempty"`
+	DryRun        bool   `json:"dryRun,omitempty"`
+}
+
+// projectKeyOrDefault returns key if set, otherwise the configured project key.
+func (j *JiraMCPServer) projectKeyOrDefault(key string) string {
+	if key != "" {
+		return strings.ToUpper(key)
+	}
+	return j.config.ProjectKey
+}
+
+// ListComponents returns the components defined on a project, one per line.
+func (j *JiraMCPServer) ListComponents(ctx context.Context, req *mcp.CallToolRequest, params *ListComponentsParams) (*mcp.CallToolResult, any, error) {
+	projectKey := j.projectKeyOrDefault(params.ProjectKey)
+
+	var components []jira.ProjectComponent
+	if _, err := j.jiraDo(ctx, "GET", fmt.Sprintf("rest/api/2/project/%s/components", projectKey), nil, &components); err != nil {
+		return textResult("Failed to list components for project %s: %v", projectKey, err), nil, nil
+	}
+	if len(components) == 0 {
+		return textResult("Project %s has no components", projectKey), nil, nil
+	}
+
+	verbosity := j.verbosity(params.Verbosity)
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "Components in %s:\n", projectKey)
+	for _, c := range components {
+		if verbosity == VerbosityMinimal {
+			fmt.Fprintf(&sb, "- %s\n", c.Name)
+			continue
+		}
+		fmt.Fprintf(&sb, "- %s (id %s)", c.Name, c.ID)
+		if c.Lead.DisplayName != "" {
+			fmt.Fprintf(&sb, ", lead: %s", c.Lead.DisplayName)
+		}
+		if c.Description != "" {
+			fmt.Fprintf(&sb, " — %s", c.Description)
+		}
+		if verbosity == VerbosityFull && c.AssigneeType != "" {
+			fmt.Fprintf(&sb, "\n    default assignee: %s", c.AssigneeType)
+			if c.RealAssignee.DisplayName != "" {
+				fmt.Fprintf(&sb, " (%s)", c.RealAssignee.DisplayName)
+			}
+		}
+		sb.WriteString("\n")
+	}
+	return textResult("%s", sb.String()), nil, nil
+}
+
+// CreateComponent creates a new component in a project.
+func (j *JiraMCPServer) CreateComponent(ctx context.Context, req *mcp.CallToolRequest, params *CreateComponentParams) (*mcp.CallToolResult, any, error) {
+	if strings.TrimSpace(params.Name) == "" {
+		return textResult("Component name is required"), nil, nil
+	}
+	projectKey := j.projectKeyOrDefault(params.ProjectKey)
+
+	options := &jira.CreateComponentOptions{
+		Name:        params.Name,
+		Description: params.Description,
+		Project:     projectKey,
+	}
+	if params.LeadAccountID != "" {
+		j.setComponentLead(options, params.LeadAccountID)
+	}
+
+	if j.dryRun(params.DryRun) {
+		var problems []string
+		project, err := j.getProject(ctx, projectKey)
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("project %s does not exist or is not accessible: %v", projectKey, err))
+		} else {
+			for _, c := range project.Components {
+				if strings.EqualFold(c.Name, params.Name) {
+					problems = append(problems, fmt.Sprintf("component %q already exists in %s", c.Name, projectKey))
+				}
+			}
+		}
+		return dryRunResult("POST", "rest/api/2/component", options, problems), nil, nil
+	}
+
+	component, _, err := j.client(ctx).CreateComponent(ctx, options)
+	if err != nil {
+		return textResult("Failed to create component %q in project %s: %v", params.Name, projectKey, err), nil, nil
+	}
+	logger(ctx).Info("Created component", "component", component.Name, "componentId", component.ID, "project", projectKey)
+
+	return textResult("Created component %s (id %s) in project %s", component.Name, component.ID, projectKey), nil, nil
+}
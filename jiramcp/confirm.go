@@ -0,0 +1,30 @@
+package jiramcp
+
+import (
+	"fmt"
+	"strings"
+)
+
+// requiresConfirmation reports whether a transition into status needs a
+// confirmation phrase because the service account cannot reverse it.
+func (j *JiraMCPServer) requiresConfirmation(status string) bool {
+	for _, s := range j.config.ConfirmStatuses {
+		if strings.EqualFold(s, status) {
+			return true
+		}
+	}
+	return false
+}
+
+// confirmationProblem checks the confirmation phrase of an irreversible
+// action against the issue key. It returns a message for the caller when the
+// phrase is missing or does not match, and "" when the action may proceed.
+func confirmationProblem(action, issueKey, phrase string) string {
+	if strings.EqualFold(strings.TrimSpace(phrase), issueKey) {
+		return ""
+	}
+	if phrase == "" {
+		return fmt.Sprintf("%s cannot be undone. To proceed, call the tool again with confirmationPhrase set to %q.", action, issueKey)
+	}
+	return fmt.Sprintf("%s cannot be undone and confirmationPhrase %q does not match the issue key %s; nothing was changed.", action, phrase, issueKey)
+}
@@ -0,0 +1,259 @@
+package jiramcp
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/andygrunwald/go-jira"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// Content formats of get-issue-content.
+const (
+	ContentMarkdown = "markdown"
+	ContentHTML     = "html"
+	ContentBoth     = "both"
+)
+
+type GetIssueContentParams struct {
+	IssueKey string `json:"issueKey"`
+	// Format is "markdown" (default), "html" for sanitized HTML rendered by
+	// Jira, or "both".
+	Format string `json:"format,omitempty"`
+	// MaxComments keeps the most recent comments (default 20); -1 leaves
+	// comments out.
+	MaxComments int `json:"maxComments,omitempty"`
+}
+
+// issueContent is an issue's description and comments, as stored and as
+// rendered to HTML by Jira.
+type issueContent struct {
+	Key    string `json:"key"`
+	Fields struct {
+		Summary     string `json:"summary"`
+		Description string `json:"description"`
+		Comment     struct {
+			Total    int            `json:"total"`
+			Comments []jira.Comment `json:"comments"`
+		} `json:"comment"`
+	} `json:"fields"`
+	RenderedFields struct {
+		Description string `json:"description"`
+		Comment     struct {
+			Comments []struct {
+				ID   string `json:"id"`
+				Body string `json:"body"`
+			} `json:"comments"`
+		} `json:"comment"`
+	} `json:"renderedFields"`
+}
+
+var (
+	wikiHeading   = regexp.MustCompile(`^h([1-6])\.\s+`)
+	wikiList      = regexp.MustCompile(`^([*#-]+)\s+`)
+	wikiMono      = regexp.MustCompile(`\{\{(.+?)\}\}`)
+	wikiBold      = regexp.MustCompile(`(^|[\s(])\*(\S(?:[^*\n]*\S)?)\*($|[\s).,:;!?])`)
+	wikiItalic    = regexp.MustCompile(`(^|[\s(])_(\S(?:[^_\n]*\S)?)_($|[\s).,:;!?])`)
+	wikiStrike    = regexp.MustCompile(`(^|\s)-(\S(?:[^-\n]*\S)?)-($|[\s.,:;!?])`)
+	wikiLink      = regexp.MustCompile(`\[([^|\]\n]+)\|([^\]\n]+)\]`)
+	wikiMention   = regexp.MustCompile(`\[~(?:accountid:)?([^\]\n]+)\]`)
+	wikiBareLink  = regexp.MustCompile(`\[((?:https?|mailto):[^\]\n]+)\]`)
+	wikiImage     = regexp.MustCompile(`!([^!\s|]+)(?:\|[^!\n]*)?!`)
+	wikiColor     = regexp.MustCompile(`\{color(?::[^}]*)?\}`)
+	wikiCodeBlock = regexp.MustCompile(`^\{(code|noformat)(?::([^}|]*))?[^}]*\}(.*)$`)
+)
+
+// wikiToMarkdown converts the common constructs of Jira wiki markup, the
+// format of descriptions and comments in the v2 API, to Markdown. Anything
+// it does not know is left as it is.
+func wikiToMarkdown(s string) string {
+	var out []string
+	inCode, inQuote, inTable := "", false, false
+	for _, line := range strings.Split(strings.ReplaceAll(s, "\r\n", "\n"), "\n") {
+		trimmed := strings.TrimSpace(line)
+		if inCode != "" {
+			if i := strings.Index(line, "{"+inCode+"}"); i >= 0 {
+				if strings.TrimSpace(line[:i]) != "" {
+					out = append(out, line[:i])
+				}
+				out = append(out, "```")
+				inCode = ""
+				continue
+			}
+			out = append(out, line)
+			continue
+		}
+		if m := wikiCodeBlock.FindStringSubmatch(trimmed); m != nil {
+			inCode = m[1]
+			out = append(out, "```"+strings.TrimSpace(m[2]))
+			if rest := m[3]; rest != "" {
+				if i := strings.Index(rest, "{"+inCode+"}"); i >= 0 {
+					out = append(out, rest[:i], "```")
+					inCode = ""
+				} else {
+					out = append(out, rest)
+				}
+			}
+			continue
+		}
+		if trimmed == "{quote}" {
+			inQuote = !inQuote
+			continue
+		}
+
+		var md string
+		switch {
+		case strings.HasPrefix(trimmed, "||") || (strings.HasPrefix(trimmed, "|") && strings.HasSuffix(trimmed, "|") && len(trimmed) > 1):
+			header := strings.HasPrefix(trimmed, "||")
+			cells := strings.FieldsFunc(trimmed, func(r rune) bool { return r == '|' })
+			for k := range cells {
+				cells[k] = wikiInline(strings.TrimSpace(cells[k]))
+			}
+			md = "| " + strings.Join(cells, " | ") + " |"
+			if header && !inTable {
+				md += "\n|" + strings.Repeat(" --- |", len(cells))
+			}
+			inTable = true
+			out = append(out, md)
+			continue
+		case wikiHeading.MatchString(trimmed):
+			m := wikiHeading.FindStringSubmatch(trimmed)
+			md = strings.Repeat("#", int(m[1][0]-'0')) + " " + wikiInline(trimmed[len(m[0]):])
+		case strings.HasPrefix(trimmed, "bq. "):
+			md = "> " + wikiInline(trimmed[4:])
+		case trimmed == "----":
+			md = "---"
+		case wikiList.MatchString(trimmed):
+			m := wikiList.FindStringSubmatch(trimmed)
+			marker := "- "
+			if strings.HasSuffix(m[1], "#") {
+				marker = "1. "
+			}
+			md = strings.Repeat("  ", len(m[1])-1) + marker + wikiInline(trimmed[len(m[0]):])
+		default:
+			md = wikiInline(line)
+		}
+		inTable = false
+		if inQuote {
+			md = "> " + md
+		}
+		out = append(out, md)
+	}
+	if inCode != "" {
+		out = append(out, "```")
+	}
+	return strings.Join(out, "\n")
+}
+
+// wikiInline converts the inline markup of a line, leaving monospaced text
+// alone.
+func wikiInline(s string) string {
+	var sb strings.Builder
+	last := 0
+	for _, m := range wikiMono.FindAllStringSubmatchIndex(s, -1) {
+		sb.WriteString(wikiInlineText(s[last:m[0]]))
+		sb.WriteString("`" + s[m[2]:m[3]] + "`")
+		last = m[1]
+	}
+	sb.WriteString(wikiInlineText(s[last:]))
+	return sb.String()
+}
+
+func wikiInlineText(s string) string {
+	s = wikiColor.ReplaceAllString(s, "")
+	s = wikiImage.ReplaceAllString(s, "![$1]($1)")
+	s = wikiMention.ReplaceAllString(s, "@$1")
+	s = wikiLink.ReplaceAllString(s, "[$1]($2)")
+	s = wikiBareLink.ReplaceAllString(s, "<$1>")
+	s = wikiBold.ReplaceAllString(s, "$1**$2**$3")
+	s = wikiItalic.ReplaceAllString(s, "$1*$2*$3")
+	s = wikiStrike.ReplaceAllString(s, "$1~~$2~~$3")
+	return s
+}
+
+// GetIssueContent returns an issue's description and recent comments as
+// Markdown, as sanitized HTML for clients that render it, or both. The HTML
+// is Jira's own rendering, reduced by sanitizeHTML to plain formatting.
+func (j *JiraMCPServer) GetIssueContent(ctx context.Context, req *mcp.CallToolRequest, params *GetIssueContentParams) (*mcp.CallToolResult, any, error) {
+	issueKey := strings.ToUpper(params.IssueKey)
+	format := strings.ToLower(params.Format)
+	if format == "" {
+		format = ContentMarkdown
+	}
+	if format != ContentMarkdown && format != ContentHTML && format != ContentBoth {
+		return textResult("format must be %q, %q, or %q", ContentMarkdown, ContentHTML, ContentBoth), nil, nil
+	}
+	maxComments := params.MaxComments
+	if maxComments == 0 {
+		maxComments = 20
+	}
+
+	fields := "summary,description"
+	if maxComments > 0 {
+		fields += ",comment"
+	}
+	path := fmt.Sprintf("rest/api/2/issue/%s?fields=%s", issueKey, fields)
+	if format != ContentMarkdown {
+		path += "&expand=renderedFields"
+	}
+	var issue issueContent
+	if _, err := j.jiraDo(ctx, "GET", path, nil, &issue); err != nil {
+		return textResult("Failed to get issue %s: %v", issueKey, err), nil, nil
+	}
+	comments := issue.Fields.Comment.Comments
+	if maxComments > 0 && len(comments) > maxComments {
+		comments = comments[len(comments)-maxComments:]
+	}
+	total := max(issue.Fields.Comment.Total, len(issue.Fields.Comment.Comments))
+
+	var content []mcp.Content
+	if format != ContentHTML {
+		var sb strings.Builder
+		fmt.Fprintf(&sb, "# %s: %s\n\n", issue.Key, issue.Fields.Summary)
+		if desc := strings.TrimSpace(issue.Fields.Description); desc != "" {
+			sb.WriteString(wikiToMarkdown(desc) + "\n")
+		} else {
+			sb.WriteString("_No description._\n")
+		}
+		if maxComments > 0 {
+			fmt.Fprintf(&sb, "\n## Comments (%d of %d)\n", len(comments), total)
+			for k := range comments {
+				c := &comments[k]
+				fmt.Fprintf(&sb, "\n**%s**, %s\n\n%s\n", commentAuthor(c), formatJiraTimestamp(c.Created), wikiToMarkdown(c.Body))
+			}
+		}
+		content = append(content, &mcp.TextContent{Text: sb.String()})
+	}
+	if format != ContentMarkdown {
+		base, _ := url.Parse(j.config.BaseURL + "/")
+		rendered := make(map[string]string, len(issue.RenderedFields.Comment.Comments))
+		for _, c := range issue.RenderedFields.Comment.Comments {
+			rendered[c.ID] = c.Body
+		}
+		var sb strings.Builder
+		fmt.Fprintf(&sb, "<h1>%s: %s</h1>\n", html.EscapeString(issue.Key), html.EscapeString(issue.Fields.Summary))
+		fmt.Fprintf(&sb, "<div>%s</div>\n", sanitizeHTML(issue.RenderedFields.Description, base))
+		if maxComments > 0 {
+			fmt.Fprintf(&sb, "<h2>Comments (%d of %d)</h2>\n", len(comments), total)
+			for k := range comments {
+				c := &comments[k]
+				body, ok := rendered[c.ID]
+				if !ok {
+					body = "<pre>" + html.EscapeString(c.Body) + "</pre>"
+				}
+				fmt.Fprintf(&sb, "<div><p><strong>%s</strong>, %s</p>%s</div>\n",
+					html.EscapeString(commentAuthor(c)), html.EscapeString(formatJiraTimestamp(c.Created)), sanitizeHTML(body, base))
+			}
+		}
+		content = append(content, &mcp.EmbeddedResource{Resource: &mcp.ResourceContents{
+			URI:      fmt.Sprintf("%s/browse/%s", j.config.BaseURL, issue.Key),
+			MIMEType: "text/html",
+			Text:     sb.String(),
+		}})
+	}
+	return &mcp.CallToolResult{Content: content}, nil, nil
+}
@@ -0,0 +1,183 @@
+package jiramcp
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"log/slog"
+	"net/http"
+
+	"github.com/andygrunwald/go-jira"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// Headers with which HTTP clients supply their own Jira credentials when
+// JIRA_MCP_SESSION_CREDENTIALS is enabled. A token without a username is sent
+// as a bearer personal access token (Server and Data Center); with a username
+// it is used for basic auth, as with the service account.
+const (
+	jiraUsernameHeader = "X-Jira-Username"
+	jiraTokenHeader    = "X-Jira-Token"
+)
+
+// jiraCredentials identifies the Jira account a client acts as.
+type jiraCredentials struct {
+	Username string
+	Token    string
+}
+
+// hash identifies creds without revealing them.
+func (c jiraCredentials) hash() string {
+	sum := sha256.Sum256([]byte(c.Username + "\x00" + c.Token))
+	return hex.EncodeToString(sum[:])
+}
+
+type (
+	credentialsKey struct{}
+	jiraClientKey  struct{}
+)
+
+// sessionClient is the Jira client built for a session's credentials, and
+// the identity of the client that sent them.
+type sessionClient struct {
+	creds  jiraCredentials
+	owner  string
+	client JiraService
+}
+
+// newJiraClient creates a Jira client for creds. Requests are paced by limiter
+// when it is non-nil, measured, and retried when rate limited, and when
+// anonymization is on, responses are also fed to the pseudonymizer.
+func newJiraClient(baseURL string, creds jiraCredentials, p *pseudonymizer, limiter Limiter, transport http.RoundTripper) (*jira.Client, error) {
+	var base http.RoundTripper = &instrumentedTransport{base: transport, limiter: limiter}
+	if p != nil {
+		base = &identityCollector{base: base, p: p}
+	}
+	var httpClient *http.Client
+	if creds.Username == "" {
+		tp := jira.PATAuthTransport{Token: creds.Token, Transport: base}
+		httpClient = tp.Client()
+	} else {
+		tp := jira.BasicAuthTransport{Username: creds.Username, Password: creds.Token, Transport: base}
+		httpClient = tp.Client()
+	}
+	return jira.NewClient(httpClient, baseURL)
+}
+
+// credentialsFromHeader extracts Jira credentials from request headers.
+func credentialsFromHeader(h http.Header) (jiraCredentials, bool) {
+	creds := jiraCredentials{
+		Username: h.Get(jiraUsernameHeader),
+		Token:    h.Get(jiraTokenHeader),
+	}
+	return creds, creds.Token != ""
+}
+
+// withRequestCredentials stores the Jira credentials sent with an HTTP request
+// in its context. The SSE transport does not pass headers on to MCP handlers,
+// so this is how credentials sent when a client connects reach the session.
+func withRequestCredentials(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if creds, ok := credentialsFromHeader(r.Header); ok {
+			r = r.WithContext(context.WithValue(r.Context(), credentialsKey{}, creds))
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// requestCredentials returns the Jira credentials sent with an MCP request,
+// from its own headers when the transport forwards them, or from the HTTP
+// request that opened the session.
+func requestCredentials(ctx context.Context, req mcp.Request) (jiraCredentials, bool) {
+	if extra := req.GetExtra(); extra != nil && extra.Header != nil {
+		if creds, ok := credentialsFromHeader(extra.Header); ok {
+			return creds, true
+		}
+	}
+	creds, ok := ctx.Value(credentialsKey{}).(jiraCredentials)
+	return creds, ok
+}
+
+// client returns the Jira client for the current request: the calling
+// session's own client when it supplied credentials, otherwise the service
+// account's.
+func (j *JiraMCPServer) client(ctx context.Context) JiraService {
+	if c, ok := ctx.Value(jiraClientKey{}).(JiraService); ok {
+		return c
+	}
+	return j.service
+}
+
+// sessionCredentialsMiddleware attaches the session's own Jira client to the
+// context of each request. Credentials are taken from the request's headers
+// when the transport forwards them, or from the HTTP request that opened the
+// session, and are remembered for the rest of the session.
+func (j *JiraMCPServer) sessionCredentialsMiddleware(next mcp.MethodHandler) mcp.MethodHandler {
+	return func(ctx context.Context, method string, req mcp.Request) (mcp.Result, error) {
+		creds, ok := requestCredentials(ctx, req)
+		if !ok && j.oidc != nil {
+			creds, ok = j.oidc.credentialsFor(j.requestPrincipal(ctx, req))
+		}
+		session := ""
+		if s := req.GetSession(); s != nil {
+			session = s.ID()
+		}
+
+		sc, err := j.sessionClient(session, principalID(j.requestPrincipal(ctx, req)), creds, ok)
+		if err != nil {
+			return nil, err
+		}
+		if sc == nil {
+			if j.config.RequireSessionCredentials && (method == "tools/call" || method == "resources/read") {
+				return nil, fmt.Errorf("this server requires your own Jira credentials: send the %s and %s headers", jiraUsernameHeader, jiraTokenHeader)
+			}
+			return next(ctx, method, req)
+		}
+		ctx = context.WithValue(ctx, jiraClientKey{}, sc.client)
+		ctx = context.WithValue(ctx, jiraAccountKey{}, sc.creds.hash())
+		return next(ctx, method, req)
+	}
+}
+
+// sessionClient returns the client cached for session, replacing it when the
+// session presents different credentials. A request without credentials only
+// gets the cached client when it comes from the authenticated client that
+// supplied them, so a session ID alone does not lend anyone else's Jira
+// account. It returns nil when there is no such client.
+func (j *JiraMCPServer) sessionClient(session, owner string, creds jiraCredentials, supplied bool) (*sessionClient, error) {
+	j.sessionClientsMu.Lock()
+	defer j.sessionClientsMu.Unlock()
+	cached, ok := j.sessionClients[session]
+	if !supplied {
+		if ok && owner != "" && cached.owner == owner {
+			return cached, nil
+		}
+		return nil, nil
+	}
+	if ok && cached.creds == creds {
+		return cached, nil
+	}
+
+	client, err := newJiraClient(j.config.BaseURL, creds, j.pseudonyms, j.limiter, j.transport)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Jira client for session: %w", err)
+	}
+	// Forget the clients of sessions that have disconnected.
+	live := make(map[string]bool)
+	for s := range j.server.Sessions() {
+		live[s.ID()] = true
+	}
+	for id := range j.sessionClients {
+		if !live[id] {
+			delete(j.sessionClients, id)
+		}
+	}
+	j.sessionClients[session] = &sessionClient{creds: creds, owner: owner, client: NewGoJiraService(client)}
+	who := creds.Username
+	if who == "" {
+		who = "a personal access token"
+	}
+	slog.Info("Session uses its own Jira credentials", "session", session, "user", who)
+	return j.sessionClients[session], nil
+}
@@ -0,0 +1,215 @@
+package jiramcp
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/andygrunwald/go-jira"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// dateUnits maps the units of relative dates to days and months.
+var dateUnits = map[string]struct{ days, months int }{
+	"day":   {1, 0},
+	"week":  {7, 0},
+	"month": {0, 1},
+	"year":  {0, 12},
+}
+
+// numberWords are the spelled-out counts accepted in relative dates.
+var numberWords = map[string]int{
+	"a": 1, "an": 1, "one": 1, "two": 2, "three": 3, "four": 4, "five": 5, "six": 6,
+	"seven": 7, "eight": 8, "nine": 9, "ten": 10, "eleven": 11, "twelve": 12,
+}
+
+// dateFormats lists the forms resolveDate accepts, for error messages.
+const dateFormats = `YYYY-MM-DD, "today", "tomorrow", "in 3 days", "in 2 weeks", "+1 month", "friday", "next friday", "next week", "end of week", "end of month", "next month"`
+
+// parseWeekday parses an English day name or its three-letter abbreviation.
+func parseWeekday(s string) (time.Weekday, bool) {
+	for d := time.Sunday; d <= time.Saturday; d++ {
+		name := strings.ToLower(d.String())
+		if s == name || s == name[:3] {
+			return d, true
+		}
+	}
+	return 0, false
+}
+
+// resolveDate turns an ISO date or a relative expression into a date,
+// counting from today. A bare or "next" weekday is its next occurrence after
+// today; "this" weekday may be today. Weeks start on Monday.
+func resolveDate(expr string, now time.Time) (time.Time, error) {
+	s := strings.ToLower(strings.Join(strings.Fields(expr), " "))
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	if t, err := time.ParseInLocation("2006-01-02", s, now.Location()); err == nil {
+		return t, nil
+	}
+	daysUntil := func(d time.Weekday, allowToday bool) time.Time {
+		n := (int(d) - int(today.Weekday()) + 7) % 7
+		if n == 0 && !allowToday {
+			n = 7
+		}
+		return today.AddDate(0, 0, n)
+	}
+	switch s {
+	case "today":
+		return today, nil
+	case "tomorrow":
+		return today.AddDate(0, 0, 1), nil
+	case "yesterday":
+		return today.AddDate(0, 0, -1), nil
+	case "next week":
+		return daysUntil(time.Monday, false), nil
+	case "end of week", "end of the week":
+		return daysUntil(time.Friday, true), nil
+	case "next month":
+		return time.Date(today.Year(), today.Month()+1, 1, 0, 0, 0, 0, today.Location()), nil
+	case "end of month", "end of the month":
+		return time.Date(today.Year(), today.Month()+1, 0, 0, 0, 0, 0, today.Location()), nil
+	}
+
+	words := strings.Fields(s)
+	if d, ok := parseWeekday(s); ok {
+		return daysUntil(d, false), nil
+	}
+	if len(words) == 2 && (words[0] == "next" || words[0] == "this") {
+		if d, ok := parseWeekday(words[1]); ok {
+			return daysUntil(d, words[0] == "this"), nil
+		}
+	}
+
+	// Offsets: "in 2 weeks", "+2 weeks", "2 weeks from now", "3 days ago", "+3d".
+	sign := 1
+	switch {
+	case len(words) > 0 && words[0] == "in":
+		words = words[1:]
+	case len(words) == 4 && words[2] == "from" && words[3] == "now":
+		words = words[:2]
+	case len(words) == 3 && words[2] == "ago":
+		words, sign = words[:2], -1
+	case strings.HasPrefix(s, "+") || strings.HasPrefix(s, "-"):
+		if strings.HasPrefix(s, "-") {
+			sign = -1
+		}
+		rest := strings.TrimSpace(s[1:])
+		if i := strings.IndexFunc(rest, func(r rune) bool { return r < '0' || r > '9' }); i > 0 && !strings.Contains(rest, " ") {
+			rest = rest[:i] + " " + rest[i:]
+		}
+		words = strings.Fields(rest)
+	default:
+		words = nil
+	}
+	if len(words) == 2 {
+		n, err := strconv.Atoi(words[0])
+		if err != nil {
+			var ok bool
+			if n, ok = numberWords[words[0]]; !ok {
+				return time.Time{}, fmt.Errorf("cannot read date %q; use %s", expr, dateFormats)
+			}
+		}
+		unit := strings.TrimSuffix(words[1], "s")
+		switch unit {
+		case "d":
+			unit = "day"
+		case "w", "wk":
+			unit = "week"
+		case "m", "mo":
+			unit = "month"
+		case "y", "yr":
+			unit = "year"
+		}
+		if u, ok := dateUnits[unit]; ok {
+			return today.AddDate(0, sign*n*u.months, sign*n*u.days), nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("cannot read date %q; use %s", expr, dateFormats)
+}
+
+// dueDateNote describes the due date of a created issue, so callers see
+// what a relative date resolved to.
+func dueDateNote(f *jira.IssueFields) string {
+	if time.Time(f.Duedate).IsZero() {
+		return ""
+	}
+	return fmt.Sprintf(" (due %s)", time.Time(f.Duedate).Format("2006-01-02"))
+}
+
+type ListOverdueIssuesParams struct {
+	ProjectKey string `json:"projectKey,omitempty"`
+	// Assignee narrows the list to an email address or name, or "me".
+	Assignee string `json:"assignee,omitempty"`
+	// DueWithinDays also lists unresolved issues due in the next days.
+	DueWithinDays int `json:"dueWithinDays,omitempty"`
+	MaxResults    int `json:"maxResults,omitempty"`
+}
+
+// ListOverdueIssues lists the unresolved issues of a project whose due date
+// has passed, most overdue first.
+func (j *JiraMCPServer) ListOverdueIssues(ctx context.Context, req *mcp.CallToolRequest, params *ListOverdueIssuesParams) (*mcp.CallToolResult, any, error) {
+	projectKey := j.projectKeyOrDefault(params.ProjectKey)
+	maxResults := params.MaxResults
+	if maxResults <= 0 {
+		maxResults = 50
+	}
+	if params.DueWithinDays < 0 {
+		return textResult("dueWithinDays must not be negative"), nil, nil
+	}
+	// Due dates are days, so "within N days" runs to the start of day N+1.
+	horizon := 0
+	if params.DueWithinDays > 0 {
+		horizon = params.DueWithinDays + 1
+	}
+	jql := fmt.Sprintf(`project = %s AND resolution IS EMPTY AND duedate < startOfDay("+%dd")`, projectKey, horizon)
+	switch {
+	case strings.EqualFold(params.Assignee, "me"):
+		jql += " AND assignee = currentUser()"
+	case params.Assignee != "":
+		user, err := j.findJiraUser(ctx, params.Assignee)
+		if err != nil {
+			return textResult("Failed to find assignee %q: %v", params.Assignee, err), nil, nil
+		}
+		jql += fmt.Sprintf(" AND assignee = %q", j.userID(user))
+	}
+	jql += " ORDER BY duedate ASC, priority DESC"
+
+	issues, err := j.searchIssues(ctx, jql, append(slices.Clone(defaultSearchFields), "duedate"), "", maxResults)
+	if err != nil {
+		return textResult("Failed to search overdue issues: %v", err), nil, nil
+	}
+	if len(issues) == 0 {
+		return textResult("No overdue issues in %s", projectKey), nil, nil
+	}
+
+	now := time.Now()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.Local)
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "%d unresolved issue(s) in %s past their due date", len(issues), projectKey)
+	if params.DueWithinDays > 0 {
+		fmt.Fprintf(&sb, " or due within %d days", params.DueWithinDays)
+	}
+	sb.WriteString(":\n")
+	for i := range issues {
+		due := time.Time(issues[i].Fields.Duedate)
+		due = time.Date(due.Year(), due.Month(), due.Day(), 0, 0, 0, 0, time.Local)
+		days := int(today.Sub(due).Hours() / 24)
+		var when string
+		switch {
+		case days > 0:
+			when = fmt.Sprintf("%d day(s) overdue", days)
+		case days == 0:
+			when = "due today"
+		default:
+			when = fmt.Sprintf("due in %d day(s)", -days)
+		}
+		fmt.Fprintf(&sb, "- %s (due %s, %s)\n", formatIssueLine(&issues[i]), due.Format("2006-01-02"), when)
+	}
+	if len(issues) == maxResults {
+		fmt.Fprintf(&sb, "Only the first %d issues are listed; raise maxResults.\n", maxResults)
+	}
+	return textResult("%s", sb.String()), nil, nil
+}
@@ -0,0 +1,130 @@
+package jiramcp
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/andygrunwald/go-jira"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+type DeleteIssueParams struct {
+	IssueKey string `json:"issueKey"`
+	// DeleteSubtasks must be set to delete an issue that has subtasks.
+	DeleteSubtasks bool `json:"deleteSubtasks,omitempty"`
+	// Confirm must be true; it guards against accidental calls.
+	Confirm bool `json:"confirm"`
+	// ConfirmationPhrase must repeat the issue key.
+	ConfirmationPhrase string `json:"confirmationPhrase"`
+	// BulkConfirmationPhrase confirms deleting more issues, subtasks
+	// included, than JIRA_MCP_BULK_CONFIRM_THRESHOLD when the client cannot
+	// show a confirmation prompt; it must be "<count> issues".
+	BulkConfirmationPhrase string `json:"bulkConfirmationPhrase,omitempty"`
+	DryRun                 bool   `json:"dryRun,omitempty"`
+}
+
+type ArchiveIssueParams struct {
+	IssueKey string `json:"issueKey"`
+	// Confirm must be true; it guards against accidental calls.
+	Confirm bool `json:"confirm"`
+	DryRun  bool `json:"dryRun,omitempty"`
+}
+
+type archiveRequest struct {
+	IssueIdsOrKeys []string `json:"issueIdsOrKeys"`
+}
+
+type archiveResponse struct {
+	NumberOfIssuesUpdated int `json:"numberOfIssuesUpdated"`
+	Errors                map[string]struct {
+		Message        string   `json:"message"`
+		IssueIdsOrKeys []string `json:"issueIdsOrKeys"`
+	} `json:"errors"`
+}
+
+// DeleteJiraIssue permanently deletes an issue. The tool is only registered
+// when JIRA_MCP_ALLOW_DELETE is enabled and each call must be confirmed.
+func (j *JiraMCPServer) DeleteJiraIssue(ctx context.Context, req *mcp.CallToolRequest, params *DeleteIssueParams) (*mcp.CallToolResult, any, error) {
+	issueKey := strings.ToUpper(params.IssueKey)
+	if !params.Confirm {
+		return textResult("Deleting %s is permanent. Set confirm to true to proceed.", issueKey), nil, nil
+	}
+	confirmation := confirmationProblem("Deleting "+issueKey, issueKey, params.ConfirmationPhrase)
+
+	path := fmt.Sprintf("rest/api/2/issue/%s?deleteSubtasks=%t", issueKey, params.DeleteSubtasks)
+	if j.dryRun(params.DryRun) {
+		var problems []string
+		if confirmation != "" {
+			problems = append(problems, confirmation)
+		}
+		issue, _, err := j.client(ctx).GetIssue(ctx, issueKey, &jira.GetQueryOptions{Fields: "subtasks"})
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("issue %s does not exist or is not accessible: %v", issueKey, err))
+		} else if n := len(issue.Fields.Subtasks); n > 0 && !params.DeleteSubtasks {
+			problems = append(problems, fmt.Sprintf("%s has %d subtask(s); set deleteSubtasks to delete them too", issueKey, n))
+		}
+		return dryRunResult("DELETE", path, nil, problems), nil, nil
+	}
+	if confirmation != "" {
+		return textResult("%s", confirmation), nil, nil
+	}
+	if params.DeleteSubtasks {
+		issue, _, err := j.client(ctx).GetIssue(ctx, issueKey, &jira.GetQueryOptions{Fields: "subtasks"})
+		if err != nil {
+			return textResult("Failed to get the subtasks of %s: %v", issueKey, err), nil, nil
+		}
+		keys := []string{issueKey}
+		for _, sub := range issue.Fields.Subtasks {
+			keys = append(keys, sub.Key)
+		}
+		if problem := j.confirmBulk(ctx, req, "Deleting "+issueKey+" with its subtasks", keys, params.BulkConfirmationPhrase); problem != "" {
+			return textResult("%s", problem), nil, nil
+		}
+	}
+
+	if _, err := j.jiraDo(ctx, "DELETE", path, nil, nil); err != nil {
+		return textResult("Failed to delete %s: %v", issueKey, err), nil, nil
+	}
+	logger(ctx).Info("Deleted issue", "deleteSubtasks", params.DeleteSubtasks)
+
+	return textResult("Deleted %s", issueKey), nil, nil
+}
+
+// ArchiveJiraIssue archives an issue, which hides it from search and boards
+// but keeps it restorable. Archiving requires Jira Cloud Premium/Enterprise or
+// Jira Data Center.
+func (j *JiraMCPServer) ArchiveJiraIssue(ctx context.Context, req *mcp.CallToolRequest, params *ArchiveIssueParams) (*mcp.CallToolResult, any, error) {
+	issueKey := strings.ToUpper(params.IssueKey)
+	if !params.Confirm {
+		return textResult("Archiving %s hides it from search and boards. Set confirm to true to proceed.", issueKey), nil, nil
+	}
+
+	payload := archiveRequest{IssueIdsOrKeys: []string{issueKey}}
+	if j.dryRun(params.DryRun) {
+		return dryRunResult("PUT", "rest/api/2/issue/archive", payload, j.issueProblems(ctx, issueKey)), nil, nil
+	}
+
+	// Jira Cloud archives in bulk; Data Center has a per-issue endpoint.
+	var result archiveResponse
+	resp, err := j.jiraDo(ctx, "PUT", "rest/api/2/issue/archive", payload, &result)
+	if err != nil && resp != nil && resp.StatusCode == http.StatusNotFound {
+		if _, err := j.jiraDo(ctx, "PUT", fmt.Sprintf("rest/api/2/issue/%s/archive", issueKey), nil, nil); err != nil {
+			return textResult("Failed to archive %s (archiving needs Jira Data Center or Cloud Premium): %v", issueKey, err), nil, nil
+		}
+		result.NumberOfIssuesUpdated = 1
+	} else if err != nil {
+		return textResult("Failed to archive %s (archiving needs Jira Data Center or Cloud Premium): %v", issueKey, err), nil, nil
+	}
+	if result.NumberOfIssuesUpdated == 0 {
+		var reasons []string
+		for _, e := range result.Errors {
+			reasons = append(reasons, e.Message)
+		}
+		return textResult("%s was not archived: %s", issueKey, strings.Join(reasons, "; ")), nil, nil
+	}
+	logger(ctx).Info("Archived issue")
+
+	return textResult("Archived %s", issueKey), nil, nil
+}
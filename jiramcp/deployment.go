@@ -0,0 +1,145 @@
+package jiramcp
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"strings"
+
+	"github.com/andygrunwald/go-jira"
+)
+
+// Deployment types of a Jira instance. Server and Data Center share an API
+// and are both DeploymentServer.
+const (
+	DeploymentCloud  = "cloud"
+	DeploymentServer = "server"
+)
+
+// serverInfo is the response of GET rest/api/2/serverInfo.
+type serverInfo struct {
+	BaseURL        string `json:"baseUrl"`
+	Version        string `json:"version"`
+	VersionNumbers []int  `json:"versionNumbers"`
+	DeploymentType string `json:"deploymentType"`
+	BuildNumber    int    `json:"buildNumber"`
+	ServerTitle    string `json:"serverTitle"`
+}
+
+// jiraInstance describes the Jira the server talks to. Jira Cloud identifies
+// users by accountId only and searches them with query, while Server and Data
+// Center use usernames throughout. Both are reached through REST API v2 with
+// wiki markup: Cloud still serves v2 and converts wiki markup to and from ADF,
+// and Server and Data Center have no v3.
+type jiraInstance struct {
+	Deployment string `json:"deployment"`
+	DataCenter bool   `json:"dataCenter,omitempty"`
+	Version    string `json:"version,omitempty"`
+	// Source tells how the deployment was determined: "serverInfo",
+	// "JIRA_MCP_DEPLOYMENT", or "url" when serverInfo could not be read.
+	Source string `json:"source"`
+}
+
+// guessDeployment tells Cloud sites from their host name.
+func guessDeployment(baseURL string) string {
+	u, err := url.Parse(baseURL)
+	if err != nil {
+		return DeploymentCloud
+	}
+	host := strings.ToLower(u.Hostname())
+	if strings.HasSuffix(host, ".atlassian.net") || strings.HasSuffix(host, ".jira.com") || strings.HasSuffix(host, ".jira-dev.com") {
+		return DeploymentCloud
+	}
+	return DeploymentServer
+}
+
+// detectInstance determines the deployment type and version of the Jira
+// instance. JIRA_MCP_DEPLOYMENT overrides serverInfo; when neither is
+// available the deployment is guessed from JIRA_BASE_URL.
+func (j *JiraMCPServer) detectInstance(ctx context.Context) jiraInstance {
+	if j.config.Deployment != "" {
+		return jiraInstance{Deployment: j.config.Deployment, Source: "JIRA_MCP_DEPLOYMENT"}
+	}
+	probeCtx, cancel := context.WithTimeout(ctx, probeTimeout)
+	defer cancel()
+	var info serverInfo
+	if _, err := j.jiraDo(probeCtx, "GET", "rest/api/2/serverInfo", nil, &info); err != nil || info.DeploymentType == "" {
+		deployment := guessDeployment(j.config.BaseURL)
+		slog.Warn("Could not read the Jira server info; guessing the deployment from JIRA_BASE_URL", "deployment", deployment, "error", err)
+		return jiraInstance{Deployment: deployment, Source: "url"}
+	}
+	instance := jiraInstance{Deployment: DeploymentServer, Version: info.Version, Source: "serverInfo"}
+	switch strings.ToLower(info.DeploymentType) {
+	case "cloud":
+		instance.Deployment = DeploymentCloud
+	case "datacenter":
+		instance.DataCenter = true
+	}
+	slog.Info("Detected Jira instance", "instance", instance.String())
+	return instance
+}
+
+// isCloud reports whether the server talks to Jira Cloud.
+func (j *JiraMCPServer) isCloud() bool {
+	return j.instance.Deployment == DeploymentCloud
+}
+
+// userID returns what the instance identifies a user by: the accountId on
+// Cloud and the username on Server and Data Center.
+func (j *JiraMCPServer) userID(u *jira.User) string {
+	if j.isCloud() {
+		return u.AccountID
+	}
+	return u.Name
+}
+
+// userRef returns the reference to a user that fields and the assignee
+// endpoint take, given the user's ID as userID returns it.
+func (j *JiraMCPServer) userRef(id string) map[string]string {
+	if j.isCloud() {
+		return map[string]string{"accountId": id}
+	}
+	return map[string]string{"name": id}
+}
+
+// assignableUser returns a user reduced to the reference the instance takes
+// in issue fields, or nil when u does not identify a user on it.
+func (j *JiraMCPServer) assignableUser(u *jira.User) *jira.User {
+	if u == nil {
+		return nil
+	}
+	switch {
+	case j.isCloud() && u.AccountID != "":
+		return &jira.User{AccountID: u.AccountID}
+	case !j.isCloud() && u.Name != "":
+		return &jira.User{Name: u.Name}
+	}
+	return nil
+}
+
+// setComponentLead sets the lead of a component to create, given the lead's
+// ID as userID returns it. Server and Data Center take the lead as a
+// username.
+func (j *JiraMCPServer) setComponentLead(options *jira.CreateComponentOptions, id string) {
+	if j.isCloud() {
+		options.Lead = &jira.User{AccountID: id}
+	} else {
+		options.LeadUserName = id
+	}
+}
+
+// String renders the instance for logs and tool results.
+func (i jiraInstance) String() string {
+	s := "Jira Cloud"
+	if i.Deployment == DeploymentServer {
+		s = "Jira Server"
+		if i.DataCenter {
+			s = "Jira Data Center"
+		}
+	}
+	if i.Version != "" {
+		s += " " + i.Version
+	}
+	return fmt.Sprintf("%s (from %s)", s, i.Source)
+}
@@ -0,0 +1,217 @@
+package jiramcp
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/andygrunwald/go-jira"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// digestBucket holds the most recent digest so it survives restarts.
+const (
+	digestBucket = "digest"
+	digestKey    = "latest"
+	digestURI    = "jira://digest/latest"
+)
+
+// digestSectionLimit caps the issues listed in each digest section.
+const digestSectionLimit = 25
+
+// digest is a compiled daily digest.
+type digest struct {
+	GeneratedAt time.Time `json:"generatedAt"`
+	Text        string    `json:"text"`
+}
+
+type GetDailyDigestParams struct {
+	// Refresh compiles a new digest instead of returning the last one.
+	Refresh bool `json:"refresh,omitempty"`
+}
+
+// digestProjects returns the projects the digest covers.
+func (j *JiraMCPServer) digestProjects() []string {
+	if len(j.config.DigestProjects) > 0 {
+		return j.config.DigestProjects
+	}
+	return []string{j.config.ProjectKey}
+}
+
+// digestInScope reports whether the request may see the digest, which
+// covers every digest project.
+func (j *JiraMCPServer) digestInScope(ctx context.Context) bool {
+	for _, project := range j.digestProjects() {
+		if !j.projectInScope(ctx, project) {
+			return false
+		}
+	}
+	return true
+}
+
+// compileDigest gathers new issues, overdue issues, and, when an SLA field is
+// configured, issues whose SLA is breached or about to be.
+func (j *JiraMCPServer) compileDigest(ctx context.Context) (*digest, error) {
+	projects := j.digestProjects()
+	scope := fmt.Sprintf("project in (%s)", strings.Join(projects, ", "))
+	sections := []struct{ title, jql string }{
+		{"New in the last 24 hours", scope + " AND created >= -1d ORDER BY priority DESC, created DESC"},
+		{"Overdue", scope + " AND duedate < startOfDay() AND resolution IS EMPTY ORDER BY duedate ASC"},
+	}
+	if field := j.config.DigestSLAField; field != "" {
+		sections = append(sections, struct{ title, jql string }{
+			"SLA at risk",
+			fmt.Sprintf(`%s AND resolution IS EMPTY AND ("%[2]s" = breached() OR "%[2]s" < remaining("4h")) ORDER BY "%[2]s" ASC`, scope, field),
+		})
+	}
+
+	now := time.Now()
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "# Jira digest for %s, %s\n", strings.Join(projects, ", "), now.Format("Monday 2 January 2006"))
+	results := make([][]jira.Issue, len(sections))
+	err := forEachParallel(ctx, j.fetchConcurrency(), len(sections), func(ctx context.Context, i int) error {
+		issues, err := j.searchIssues(ctx, sections[i].jql, defaultSearchFields, "", digestSectionLimit)
+		if err != nil {
+			return fmt.Errorf("%s: %w", strings.ToLower(sections[i].title), err)
+		}
+		results[i] = issues
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	for i, section := range sections {
+		issues := results[i]
+		fmt.Fprintf(&sb, "\n## %s (%d)\n", section.title, len(issues))
+		if len(issues) == 0 {
+			sb.WriteString("Nothing.\n")
+		}
+		for _, issue := range issues {
+			fmt.Fprintf(&sb, "- %s\n", formatIssue(&issue, VerbosityStandard))
+		}
+	}
+	return &digest{GeneratedAt: now, Text: sb.String()}, nil
+}
+
+// publishDigest compiles and stores a digest, then tells connected clients
+// and the outbound webhook about it.
+func (j *JiraMCPServer) publishDigest(ctx context.Context) error {
+	d, err := j.compileDigest(ctx)
+	if err != nil {
+		return err
+	}
+	if err := j.store.Put(digestBucket, digestKey, d); err != nil {
+		return fmt.Errorf("failed to save digest: %w", err)
+	}
+	if err := j.server.ResourceUpdated(ctx, &mcp.ResourceUpdatedNotificationParams{URI: digestURI}); err != nil {
+		slog.Warn("Failed to send resource update", "uri", digestURI, "error", err)
+	}
+	j.broadcastLog(ctx, "info", "jira-digest", j.digestProjects(), map[string]interface{}{
+		"uri":    digestURI,
+		"digest": d.Text,
+	})
+	if j.config.DigestWebhookURL != "" {
+		if err := postDigest(ctx, j.config.DigestWebhookURL, d); err != nil {
+			slog.Warn("Failed to post digest to webhook", "error", err)
+		}
+	}
+	slog.Info("Published daily digest")
+	return nil
+}
+
+// postDigest sends the digest as {"text": ...}, which chat incoming webhooks
+// such as Slack's accept as is.
+func postDigest(ctx context.Context, url string, d *digest) error {
+	body, err := json.Marshal(map[string]string{"text": d.Text})
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := outboundHTTPClient.Do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook returned %s", resp.Status)
+	}
+	return nil
+}
+
+// nextDigestTime returns the first occurrence of the configured time of day
+// after now.
+func nextDigestTime(now time.Time, hour, minute int, loc *time.Location) time.Time {
+	now = now.In(loc)
+	next := time.Date(now.Year(), now.Month(), now.Day(), hour, minute, 0, 0, loc)
+	if !next.After(now) {
+		next = next.AddDate(0, 0, 1)
+	}
+	return next
+}
+
+// RunDigestScheduler publishes a digest every day at JIRA_MCP_DIGEST_TIME
+// until ctx is cancelled.
+func (j *JiraMCPServer) RunDigestScheduler(ctx context.Context) {
+	at, _ := time.Parse("15:04", j.config.DigestTime)
+	for {
+		next := nextDigestTime(time.Now(), at.Hour(), at.Minute(), j.config.DigestLocation)
+		slog.Info("Scheduled daily digest", "at", next.Format(time.RFC3339))
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(time.Until(next)):
+		}
+		if err := j.publishDigest(ctx); err != nil {
+			slog.Error("Failed to publish daily digest", "error", err)
+		}
+	}
+}
+
+// DigestResource serves the most recent digest at jira://digest/latest.
+func (j *JiraMCPServer) DigestResource(ctx context.Context, req *mcp.ReadResourceRequest) (*mcp.ReadResourceResult, error) {
+	if !j.digestInScope(ctx) {
+		return nil, fmt.Errorf("the digest covers projects you may not access")
+	}
+	var d digest
+	found, err := j.store.Get(digestBucket, digestKey, &d)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load digest: %w", err)
+	}
+	if !found {
+		return nil, mcp.ResourceNotFoundError(req.Params.URI)
+	}
+	return &mcp.ReadResourceResult{
+		Contents: []*mcp.ResourceContents{
+			{URI: req.Params.URI, MIMEType: "text/markdown", Text: d.Text},
+		},
+	}, nil
+}
+
+// GetDailyDigest returns the last published digest, or compiles a fresh one.
+func (j *JiraMCPServer) GetDailyDigest(ctx context.Context, req *mcp.CallToolRequest, params *GetDailyDigestParams) (*mcp.CallToolResult, any, error) {
+	if !j.digestInScope(ctx) {
+		return textResult("The digest covers projects you may not access"), nil, nil
+	}
+	var d digest
+	found, err := j.store.Get(digestBucket, digestKey, &d)
+	if err != nil {
+		return textResult("Failed to load digest: %v", err), nil, nil
+	}
+	if params.Refresh || !found {
+		fresh, err := j.compileDigest(ctx)
+		if err != nil {
+			return textResult("Failed to compile digest: %v", err), nil, nil
+		}
+		d = *fresh
+	}
+	return textResult("%s\n(generated %s)", d.Text, d.GeneratedAt.Format(time.RFC3339)), nil, nil
+}
@@ -0,0 +1,115 @@
+package jiramcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/andygrunwald/go-jira"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// dryRun reports whether a mutating call should be simulated, either because the
+// server was started with --dry-run or because the caller asked for it.
+func (j *JiraMCPServer) dryRun(requested bool) bool {
+	return j.config.DryRun || requested
+}
+
+// dryRunResult describes the request a mutating tool would have sent to Jira,
+// along with any validation problems found while preparing it.
+func dryRunResult(method, path string, payload interface{}, problems []string) *mcp.CallToolResult {
+	var sb strings.Builder
+	if len(problems) > 0 {
+		fmt.Fprintf(&sb, "Dry run: validation failed, the request would be rejected:\n")
+		for _, p := range problems {
+			fmt.Fprintf(&sb, "- %s\n", p)
+		}
+	} else {
+		sb.WriteString("Dry run: validation passed, nothing was written to Jira.\n")
+	}
+	fmt.Fprintf(&sb, "\nRequest: %s /%s\n", method, path)
+	if payload != nil {
+		body, err := json.MarshalIndent(payload, "", "  ")
+		if err != nil {
+			fmt.Fprintf(&sb, "Payload could not be encoded: %v\n", err)
+		} else {
+			fmt.Fprintf(&sb, "Payload:\n%s\n", body)
+		}
+	}
+	return textResult("%s", sb.String())
+}
+
+// getProject fetches a project with its issue types and components.
+func (j *JiraMCPServer) getProject(ctx context.Context, projectKey string) (*jira.Project, error) {
+	return cached(ctx, j.cache, "project", "project:"+strings.ToUpper(projectKey), func() (*jira.Project, error) {
+		var project jira.Project
+		if _, err := j.jiraDo(ctx, "GET", fmt.Sprintf("rest/api/2/project/%s", projectKey), nil, &project); err != nil {
+			return nil, err
+		}
+		return &project, nil
+	})
+}
+
+// validateIssueFields checks that the project, issue type, priority, and
+// components referenced by fields exist, returning a problem description for
+// every reference that cannot be resolved.
+func (j *JiraMCPServer) validateIssueFields(ctx context.Context, fields *jira.IssueFields) []string {
+	var problems []string
+	if strings.TrimSpace(fields.Summary) == "" {
+		problems = append(problems, "summary is required")
+	}
+
+	project, err := j.getProject(ctx, fields.Project.Key)
+	if err != nil {
+		if problem := j.projectKeyProblem(ctx, fields.Project.Key); problem != "" {
+			return append(problems, problem)
+		}
+		return append(problems, fmt.Sprintf("project %s does not exist or is not accessible: %v", fields.Project.Key, err))
+	}
+
+	if fields.Type.Name != "" {
+		if problem := issueTypeProblem(project, fields.Type.Name); problem != "" {
+			problems = append(problems, problem)
+		}
+	} else {
+		problems = append(problems, "issue type is required")
+	}
+
+	problems = append(problems, componentProblems(project, fields.Components)...)
+
+	if fields.Priority != nil && fields.Priority.Name != "" {
+		problem, err := j.priorityProblem(ctx, project.Key, fields.Priority.Name)
+		if err != nil {
+			problem = fmt.Sprintf("could not verify priority %q: %v", fields.Priority.Name, err)
+		}
+		if problem != "" {
+			problems = append(problems, problem)
+		}
+	}
+
+	return problems
+}
+
+// issueProblems reports whether the issue a mutation targets can be loaded.
+func (j *JiraMCPServer) issueProblems(ctx context.Context, issueKey string) []string {
+	if _, _, err := j.client(ctx).GetIssue(ctx, issueKey, &jira.GetQueryOptions{Fields: "summary"}); err != nil {
+		return []string{fmt.Sprintf("issue %s does not exist or is not accessible: %v", issueKey, err)}
+	}
+	return nil
+}
+
+// componentProblems reports components that are not defined on the project.
+func componentProblems(project *jira.Project, components []*jira.Component) []string {
+	var problems []string
+	for _, c := range components {
+		found := false
+		for _, pc := range project.Components {
+			found = found || strings.EqualFold(pc.Name, c.Name)
+		}
+		if !found {
+			problems = append(problems, fmt.Sprintf("component %q does not exist in %s", c.Name, project.Key))
+		}
+	}
+	return problems
+}
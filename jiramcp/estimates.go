@@ -0,0 +1,338 @@
+package jiramcp
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/andygrunwald/go-jira"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// defaultTShirtSizes is the size scale used when JIRA_MCP_TSHIRT_SIZES is
+// not set.
+const defaultTShirtSizes = "XS=1,S=2,M=3,L=5,XL=8"
+
+// TShirtSize is a T-shirt size and the story points it stands for.
+type TShirtSize struct {
+	Name   string  `json:"name"`
+	Points float64 `json:"points"`
+}
+
+// parseTShirtSizes parses a comma-separated list of SIZE=POINTS pairs, in
+// increasing size.
+func parseTShirtSizes(s string) ([]TShirtSize, error) {
+	var sizes []TShirtSize
+	for _, pair := range strings.Split(s, ",") {
+		if pair = strings.TrimSpace(pair); pair == "" {
+			continue
+		}
+		name, value, ok := strings.Cut(pair, "=")
+		points, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+		if !ok || err != nil || points < 0 || strings.TrimSpace(name) == "" {
+			return nil, fmt.Errorf("JIRA_MCP_TSHIRT_SIZES must be SIZE=POINTS pairs such as %q, got %q", defaultTShirtSizes, pair)
+		}
+		sizes = append(sizes, TShirtSize{Name: strings.TrimSpace(name), Points: points})
+	}
+	if len(sizes) == 0 {
+		return nil, fmt.Errorf("JIRA_MCP_TSHIRT_SIZES defines no sizes")
+	}
+	return sizes, nil
+}
+
+type SetEstimateParams struct {
+	IssueKey string `json:"issueKey"`
+	// Size is a T-shirt size; it sets the story points it maps to and, when
+	// JIRA_MCP_SIZE_FIELD is set, that field.
+	Size string `json:"size,omitempty"`
+	// Points sets the story points directly, instead of Size.
+	Points *float64 `json:"points,omitempty"`
+	// Confidence sets JIRA_MCP_CONFIDENCE_FIELD, e.g. "Low" or "High".
+	Confidence string `json:"confidence,omitempty"`
+	DryRun     bool   `json:"dryRun,omitempty"`
+}
+
+type GetEstimatesParams struct {
+	// IssueKeys or JQL select the issues; JQL is capped at MaxIssues
+	// (default 50).
+	IssueKeys []string `json:"issueKeys,omitempty"`
+	JQL       string   `json:"jql,omitempty"`
+	MaxIssues int      `json:"maxIssues,omitempty"`
+}
+
+// estimateFields are the fields an estimate is kept in. Empty IDs are fields
+// that do not exist or are not configured.
+type estimateFields struct {
+	points, pointsName         string
+	size, sizeType             string
+	confidence, confidenceType string
+}
+
+// configuredField looks up the field JIRA_MCP_<env> names by ID or name. It
+// returns nil when name is empty.
+func (j *JiraMCPServer) configuredField(ctx context.Context, env, name string) (*fieldInfo, error) {
+	if name == "" {
+		return nil, nil
+	}
+	fields, err := j.jiraFields(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for i, f := range fields {
+		if strings.EqualFold(f.ID, name) || strings.EqualFold(f.Name, name) {
+			return &fields[i], nil
+		}
+	}
+	return nil, fmt.Errorf("%s %q matches no field", env, name)
+}
+
+// estimateFields resolves the story points, size, and confidence fields.
+func (j *JiraMCPServer) estimateFields(ctx context.Context) (*estimateFields, error) {
+	var f estimateFields
+	var err error
+	if f.points, f.pointsName, err = j.storyPointsField(ctx); err != nil {
+		return nil, err
+	}
+	size, err := j.configuredField(ctx, "JIRA_MCP_SIZE_FIELD", j.config.SizeField)
+	if err != nil {
+		return nil, err
+	}
+	if size != nil {
+		f.size, f.sizeType = size.ID, size.Schema.Type
+	}
+	confidence, err := j.configuredField(ctx, "JIRA_MCP_CONFIDENCE_FIELD", j.config.ConfidenceField)
+	if err != nil {
+		return nil, err
+	}
+	if confidence != nil {
+		f.confidence, f.confidenceType = confidence.ID, confidence.Schema.Type
+	}
+	return &f, nil
+}
+
+// ids lists the fields to request for estimates.
+func (f *estimateFields) ids() []string {
+	var ids []string
+	for _, id := range []string{f.points, f.size, f.confidence} {
+		if id != "" {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+// fieldValue returns the value to write to a field of the given schema
+// type: an option for select lists, a number for number fields, and the
+// text otherwise.
+func fieldValue(schemaType, value string) (interface{}, error) {
+	switch schemaType {
+	case "option":
+		return map[string]string{"value": value}, nil
+	case "number":
+		n, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%q is not a number", value)
+		}
+		return n, nil
+	}
+	return value, nil
+}
+
+// fieldText renders a custom field value as returned by Jira: text, a
+// number, or an option or user object.
+func fieldText(v interface{}) string {
+	switch v := v.(type) {
+	case string:
+		return v
+	case float64:
+		return formatPoints(v)
+	case map[string]interface{}:
+		for _, key := range []string{"value", "name", "displayName"} {
+			if s, ok := v[key].(string); ok {
+				return s
+			}
+		}
+	}
+	return ""
+}
+
+// sizePoints returns the story points of a T-shirt size.
+func (j *JiraMCPServer) sizePoints(size string) (float64, bool) {
+	for _, s := range j.config.TShirtSizes {
+		if strings.EqualFold(s.Name, size) {
+			return s.Points, true
+		}
+	}
+	return 0, false
+}
+
+// nearestSize returns the T-shirt size whose points are closest to points,
+// the smaller one on a tie.
+func (j *JiraMCPServer) nearestSize(points float64) string {
+	best, bestDistance := "", math.Inf(1)
+	for _, s := range j.config.TShirtSizes {
+		if d := math.Abs(s.Points - points); d < bestDistance {
+			best, bestDistance = s.Name, d
+		}
+	}
+	return best
+}
+
+// sizeNames lists the configured sizes with their points.
+func (j *JiraMCPServer) sizeNames() string {
+	names := make([]string, 0, len(j.config.TShirtSizes))
+	for _, s := range j.config.TShirtSizes {
+		names = append(names, fmt.Sprintf("%s=%s", s.Name, formatPoints(s.Points)))
+	}
+	return strings.Join(names, ", ")
+}
+
+// estimatePoints returns the story points of an issue and whether they come
+// from its T-shirt size because the points field is empty.
+func (j *JiraMCPServer) estimatePoints(issue jira.Issue, f *estimateFields) (float64, bool) {
+	if issue.Fields == nil {
+		return 0, false
+	}
+	if points, ok := issue.Fields.Unknowns[f.points].(float64); ok && f.points != "" {
+		return points, false
+	}
+	if f.size != "" {
+		if points, ok := j.sizePoints(fieldText(issue.Fields.Unknowns[f.size])); ok {
+			return points, true
+		}
+	}
+	return 0, false
+}
+
+// SetEstimate sets an issue's estimate as a T-shirt size or story points,
+// and its confidence.
+func (j *JiraMCPServer) SetEstimate(ctx context.Context, req *mcp.CallToolRequest, params *SetEstimateParams) (*mcp.CallToolResult, any, error) {
+	issueKey := strings.ToUpper(params.IssueKey)
+	if params.Size != "" && params.Points != nil {
+		return textResult("Set either size or points, not both"), nil, nil
+	}
+	if params.Size == "" && params.Points == nil && params.Confidence == "" {
+		return textResult("Nothing to set: give size, points, or confidence"), nil, nil
+	}
+	f, err := j.estimateFields(ctx)
+	if err != nil {
+		return textResult("Failed to find the estimate fields: %v", err), nil, nil
+	}
+
+	fields := make(map[string]interface{})
+	var changes []string
+	points := params.Points
+	if params.Size != "" {
+		p, ok := j.sizePoints(params.Size)
+		if !ok {
+			return textResult("Unknown size %q (sizes: %s)", params.Size, j.sizeNames()), nil, nil
+		}
+		points = &p
+		if f.size != "" {
+			v, err := fieldValue(f.sizeType, j.nearestSize(p))
+			if err != nil {
+				return textResult("Failed to set the size: %v", err), nil, nil
+			}
+			fields[f.size] = v
+		}
+		changes = append(changes, fmt.Sprintf("size %s", strings.ToUpper(params.Size)))
+	}
+	if points != nil {
+		if f.points == "" {
+			return textResult("No story points field found; set JIRA_MCP_STORY_POINTS_FIELD"), nil, nil
+		}
+		fields[f.points] = *points
+		changes = append(changes, fmt.Sprintf("%s %s", f.pointsName, formatPoints(*points)))
+	}
+	if params.Confidence != "" {
+		if f.confidence == "" {
+			return textResult("No confidence field is configured; set JIRA_MCP_CONFIDENCE_FIELD"), nil, nil
+		}
+		v, err := fieldValue(f.confidenceType, params.Confidence)
+		if err != nil {
+			return textResult("Failed to set the confidence: %v", err), nil, nil
+		}
+		fields[f.confidence] = v
+		changes = append(changes, "confidence "+params.Confidence)
+	}
+
+	path := fmt.Sprintf("rest/api/2/issue/%s", issueKey)
+	payload := map[string]interface{}{"fields": fields}
+	if j.dryRun(params.DryRun) {
+		return dryRunResult("PUT", path, payload, j.issueProblems(ctx, issueKey)), nil, nil
+	}
+	before := j.journalBefore(ctx, issueKey, fields)
+	if _, err := j.jiraDo(ctx, "PUT", path, payload, nil); err != nil {
+		return textResult("Failed to set the estimate of %s: %v", issueKey, err), nil, nil
+	}
+	j.journalUpdate(ctx, req, "set-estimate", issueKey, before, "")
+	return textResult("Set %s on %s", strings.Join(changes, ", "), issueKey), nil, nil
+}
+
+// GetEstimates lists the size, story points, and confidence of issues.
+func (j *JiraMCPServer) GetEstimates(ctx context.Context, req *mcp.CallToolRequest, params *GetEstimatesParams) (*mcp.CallToolResult, any, error) {
+	maxIssues := params.MaxIssues
+	if maxIssues <= 0 {
+		maxIssues = 50
+	}
+	jql := params.JQL
+	if len(params.IssueKeys) > 0 {
+		if jql != "" {
+			return textResult("Set either issueKeys or jql, not both"), nil, nil
+		}
+		jql = fmt.Sprintf("key in (%s) ORDER BY key ASC", strings.Join(params.IssueKeys, ", "))
+		maxIssues = len(params.IssueKeys)
+	}
+	if jql == "" {
+		return textResult("Set issueKeys or jql"), nil, nil
+	}
+	f, err := j.estimateFields(ctx)
+	if err != nil {
+		return textResult("Failed to find the estimate fields: %v", err), nil, nil
+	}
+	issues, err := j.searchIssues(ctx, jql, append([]string{"summary", "status"}, f.ids()...), "", maxIssues)
+	if err != nil {
+		return textResult("Failed to search issues: %v", err), nil, nil
+	}
+	if len(issues) == 0 {
+		return textResult("No issues found"), nil, nil
+	}
+
+	var sb strings.Builder
+	var total float64
+	unestimated := 0
+	for _, issue := range issues {
+		points, fromSize := j.estimatePoints(issue, f)
+		size := ""
+		if f.size != "" {
+			size = fieldText(issue.Fields.Unknowns[f.size])
+		}
+		var parts []string
+		switch {
+		case size != "":
+			parts = append(parts, "size "+size)
+		case points > 0:
+			parts = append(parts, "≈ "+j.nearestSize(points))
+		}
+		switch {
+		case fromSize:
+			parts = append(parts, formatPoints(points)+" points from size")
+		case points > 0 || issue.Fields.Unknowns[f.points] != nil:
+			parts = append(parts, formatPoints(points)+" points")
+		default:
+			parts = append(parts, "unestimated")
+			unestimated++
+		}
+		if f.confidence != "" {
+			if c := fieldText(issue.Fields.Unknowns[f.confidence]); c != "" {
+				parts = append(parts, "confidence "+c)
+			}
+		}
+		total += points
+		fmt.Fprintf(&sb, "- %s: %s (%s)\n", issue.Key, issue.Fields.Summary, strings.Join(parts, ", "))
+	}
+	fmt.Fprintf(&sb, "Total: %s points over %d issues, %d unestimated. Sizes: %s\n", formatPoints(total), len(issues), unestimated, j.sizeNames())
+	return textResult("%s", sb.String()), nil, nil
+}
@@ -0,0 +1,145 @@
+package jiramcp
+
+import (
+	"archive/zip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/andygrunwald/go-jira"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+type ExportIssueBundleParams struct {
+	IssueKey string `json:"issueKey"`
+	// IncludeAttachments writes a zip archive with the bundle and the attachment
+	// files to the export directory. Only available over the stdio transport.
+	IncludeAttachments bool `json:"includeAttachments,omitempty"`
+}
+
+// issueBundle is the archival JSON document produced by export-issue-bundle.
+type issueBundle struct {
+	ExportedAt  time.Time                  `json:"exportedAt"`
+	BaseURL     string                     `json:"baseUrl"`
+	Key         string                     `json:"key"`
+	Fields      map[string]json.RawMessage `json:"fields"`
+	Comments    []*jira.Comment            `json:"comments"`
+	Changelog   []jira.ChangelogHistory    `json:"changelog"`
+	Attachments []jira.Attachment          `json:"attachments"`
+}
+
+// issueBundle collects an issue's fields, comments, changelog, and attachment
+// metadata.
+func (j *JiraMCPServer) issueBundle(ctx context.Context, issueKey string) (*issueBundle, error) {
+	var issue rawIssue
+	if _, err := j.jiraDo(ctx, "GET", fmt.Sprintf("rest/api/2/issue/%s?fields=*all", issueKey), nil, &issue); err != nil {
+		return nil, fmt.Errorf("failed to get JIRA issue %s: %w", issueKey, err)
+	}
+	bundle := &issueBundle{
+		ExportedAt: time.Now().UTC(),
+		BaseURL:    j.config.BaseURL,
+		Key:        issue.Key,
+		Fields:     issue.Fields,
+	}
+	json.Unmarshal(issue.Fields["attachment"], &bundle.Attachments)
+	// Comments and attachments are exported separately in full.
+	delete(bundle.Fields, "comment")
+	delete(bundle.Fields, "attachment")
+
+	comments, err := j.fetchAllComments(ctx, issue.Key)
+	if err != nil {
+		return nil, err
+	}
+	bundle.Comments = comments
+
+	for startAt := 0; ; {
+		page, err := j.changelogPage(ctx, issue.Key, startAt, 100)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get history of %s: %w", issue.Key, err)
+		}
+		bundle.Changelog = append(bundle.Changelog, page.Values...)
+		startAt += len(page.Values)
+		if page.IsLast || len(page.Values) == 0 || startAt >= page.Total {
+			break
+		}
+	}
+	return bundle, nil
+}
+
+// ExportIssueBundle packages an issue into a single JSON document for
+// record-keeping before deletion or migration.
+func (j *JiraMCPServer) ExportIssueBundle(ctx context.Context, req *mcp.CallToolRequest, params *ExportIssueBundleParams) (*mcp.CallToolResult, any, error) {
+	issueKey := strings.ToUpper(params.IssueKey)
+	if params.IncludeAttachments && j.config.Transport != "stdio" {
+		return textResult("includeAttachments writes a local archive and is only available when the server runs over stdio"), nil, nil
+	}
+
+	bundle, err := j.issueBundle(ctx, issueKey)
+	if err != nil {
+		return textResult("Failed to export %s: %v", issueKey, err), nil, nil
+	}
+	body, err := json.MarshalIndent(bundle, "", "  ")
+	if err != nil {
+		return textResult("Failed to encode export of %s: %v", issueKey, err), nil, nil
+	}
+	if !params.IncludeAttachments {
+		return textResult("%s", body), nil, nil
+	}
+
+	path, err := j.writeIssueArchive(ctx, bundle, body)
+	if err != nil {
+		return textResult("Failed to write archive of %s: %v", issueKey, err), nil, nil
+	}
+	logger(ctx).Info("Exported issue bundle", "path", path)
+	return textResult("Exported %s (%d comments, %d changelog entries, %d attachments) to %s",
+		bundle.Key, len(bundle.Comments), len(bundle.Changelog), len(bundle.Attachments), path), nil, nil
+}
+
+// writeIssueArchive writes issue.json and the attachment files of a bundle to
+// a zip archive in the export directory and returns its path.
+func (j *JiraMCPServer) writeIssueArchive(ctx context.Context, bundle *issueBundle, body []byte) (string, error) {
+	dir := filepath.Join(j.config.StateDir, "exports")
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return "", err
+	}
+	path := filepath.Join(dir, fmt.Sprintf("%s-%s.zip", bundle.Key, bundle.ExportedAt.Format("20060102T150405Z")))
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o600)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	zw := zip.NewWriter(f)
+	w, err := zw.Create("issue.json")
+	if err != nil {
+		return "", err
+	}
+	if _, err := w.Write(body); err != nil {
+		return "", err
+	}
+	for _, a := range bundle.Attachments {
+		// Attachment IDs keep entries unique when file names repeat.
+		w, err := zw.Create(fmt.Sprintf("attachments/%s-%s", a.ID, filepath.Base(a.Filename)))
+		if err != nil {
+			return "", err
+		}
+		resp, err := j.client(ctx).DownloadAttachment(ctx, a.ID)
+		if err != nil {
+			return "", fmt.Errorf("failed to download attachment %s: %w", a.Filename, err)
+		}
+		_, err = io.Copy(w, resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			return "", fmt.Errorf("failed to download attachment %s: %w", a.Filename, err)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		return "", err
+	}
+	return path, f.Close()
+}
@@ -0,0 +1,193 @@
+package jiramcp
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/andygrunwald/go-jira"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+type ListFiltersParams struct {
+	// Name filters by (partial) filter name.
+	Name       string `json:"name,omitempty"`
+	MaxResults int    `json:"maxResults,omitempty"`
+	Verbosity  string `json:"verbosity,omitempty"`
+}
+
+type GetFilterParams struct {
+	FilterID  string `json:"filterId"`
+	Verbosity string `json:"verbosity,omitempty"`
+}
+
+type RunFilterParams struct {
+	FilterID   string `json:"filterId"`
+	MaxResults int    `json:"maxResults,omitempty"`
+	PageToken  string `json:"pageToken,omitempty"`
+	Verbosity  string `json:"verbosity,omitempty"`
+}
+
+type CreateFilterParams struct {
+	Name        string `json:"name"`
+	JQL         string `json:"jql"`
+	Description string `json:"description,omitempty"`
+	Favourite   bool   `json:"favourite,omitempty"`
+	DryRun      bool   `json:"dryRun,omitempty"`
+}
+
+type filterSearchPage struct {
+	Total  int           `json:"total"`
+	IsLast bool          `json:"isLast"`
+	Values []jira.Filter `json:"values"`
+}
+
+type createFilterRequest struct {
+	Name        string `json:"name"`
+	JQL         string `json:"jql"`
+	Description string `json:"description,omitempty"`
+	Favourite   bool   `json:"favourite"`
+}
+
+// getFilter loads a saved filter by ID.
+func (j *JiraMCPServer) getFilter(ctx context.Context, id string) (*jira.Filter, error) {
+	if _, err := strconv.Atoi(id); err != nil {
+		return nil, fmt.Errorf("filter ID must be numeric, got %q", id)
+	}
+	var filter jira.Filter
+	if _, err := j.jiraDo(ctx, "GET", "rest/api/2/filter/"+id, nil, &filter); err != nil {
+		return nil, err
+	}
+	return &filter, nil
+}
+
+func formatFilter(f *jira.Filter, verbosity string) string {
+	if verbosity == VerbosityMinimal {
+		return fmt.Sprintf("%s: %s", f.ID, f.Name)
+	}
+	line := fmt.Sprintf("%s: %s (owner: %s)", f.ID, f.Name, f.Owner.DisplayName)
+	if f.Favourite {
+		line += " ★"
+	}
+	line += "\n    JQL: " + f.Jql
+	if f.Description != "" {
+		line += "\n    " + f.Description
+	}
+	if verbosity == VerbosityFull && f.ViewURL != "" {
+		line += "\n    " + f.ViewURL
+	}
+	return line
+}
+
+// ListFilters searches the saved filters visible to the user. Instances without
+// the filter search API (Server/Data Center) list the user's favourites instead.
+func (j *JiraMCPServer) ListFilters(ctx context.Context, req *mcp.CallToolRequest, params *ListFiltersParams) (*mcp.CallToolResult, any, error) {
+	maxResults := params.MaxResults
+	if maxResults <= 0 {
+		maxResults = 50
+	}
+
+	query := url.Values{}
+	query.Set("expand", "description,jql,owner,favourite")
+	query.Set("maxResults", strconv.Itoa(maxResults))
+	if params.Name != "" {
+		query.Set("filterName", params.Name)
+	}
+
+	var filters []jira.Filter
+	var page filterSearchPage
+	resp, err := j.jiraDo(ctx, "GET", "rest/api/2/filter/search?"+query.Encode(), nil, &page)
+	switch {
+	case err == nil:
+		filters = page.Values
+	case resp != nil && resp.StatusCode == http.StatusNotFound:
+		if _, err := j.jiraDo(ctx, "GET", "rest/api/2/filter/favourite", nil, &filters); err != nil {
+			return textResult("Failed to list favourite filters: %v", err), nil, nil
+		}
+		if params.Name != "" {
+			matched := filters[:0]
+			for _, f := range filters {
+				if strings.Contains(strings.ToLower(f.Name), strings.ToLower(params.Name)) {
+					matched = append(matched, f)
+				}
+			}
+			filters = matched
+		}
+		filters = filters[:min(len(filters), maxResults)]
+	default:
+		return textResult("Failed to list filters: %v", err), nil, nil
+	}
+
+	if len(filters) == 0 {
+		return textResult("No saved filters found"), nil, nil
+	}
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "%d saved filter(s):\n", len(filters))
+	for _, f := range filters {
+		sb.WriteString(formatFilter(&f, j.verbosity(params.Verbosity)))
+		sb.WriteString("\n")
+	}
+	return textResult("%s", sb.String()), nil, nil
+}
+
+// GetFilter returns the definition of a saved filter.
+func (j *JiraMCPServer) GetFilter(ctx context.Context, req *mcp.CallToolRequest, params *GetFilterParams) (*mcp.CallToolResult, any, error) {
+	filter, err := j.getFilter(ctx, params.FilterID)
+	if err != nil {
+		return textResult("Failed to get filter %s: %v", params.FilterID, err), nil, nil
+	}
+	return textResult("%s", formatFilter(filter, j.verbosity(params.Verbosity))), nil, nil
+}
+
+// RunFilter executes a saved filter's JQL and returns one page of results.
+func (j *JiraMCPServer) RunFilter(ctx context.Context, req *mcp.CallToolRequest, params *RunFilterParams) (*mcp.CallToolResult, any, error) {
+	filter, err := j.getFilter(ctx, params.FilterID)
+	if err != nil {
+		return textResult("Failed to get filter %s: %v", params.FilterID, err), nil, nil
+	}
+
+	result, out, err := j.SearchJiraIssues(ctx, req, &SearchIssuesParams{
+		JQL:        filter.Jql,
+		MaxResults: params.MaxResults,
+		PageToken:  params.PageToken,
+		Verbosity:  params.Verbosity,
+	})
+	if result != nil {
+		if tc, ok := result.Content[0].(*mcp.TextContent); ok {
+			tc.Text = fmt.Sprintf("Filter %s (%s):\n%s", filter.ID, filter.Name, tc.Text)
+		}
+	}
+	return result, out, err
+}
+
+// CreateFilter saves a new filter owned by the authenticated user.
+func (j *JiraMCPServer) CreateFilter(ctx context.Context, req *mcp.CallToolRequest, params *CreateFilterParams) (*mcp.CallToolResult, any, error) {
+	if strings.TrimSpace(params.Name) == "" || strings.TrimSpace(params.JQL) == "" {
+		return textResult("Both name and jql are required"), nil, nil
+	}
+	payload := createFilterRequest{
+		Name:        params.Name,
+		JQL:         params.JQL,
+		Description: params.Description,
+		Favourite:   params.Favourite,
+	}
+
+	if j.dryRun(params.DryRun) {
+		var problems []string
+		if _, err := j.searchPage(ctx, params.JQL, []string{"key"}, "", 1, ""); err != nil {
+			problems = append(problems, fmt.Sprintf("JQL is not valid: %v", err))
+		}
+		return dryRunResult("POST", "rest/api/2/filter", payload, problems), nil, nil
+	}
+
+	var filter jira.Filter
+	if _, err := j.jiraDo(ctx, "POST", "rest/api/2/filter", payload, &filter); err != nil {
+		return textResult("Failed to create filter %q: %v", params.Name, err), nil, nil
+	}
+	logger(ctx).Info("Created filter", "filter", filter.Name, "filterId", filter.ID)
+
+	return textResult("Created filter %s: %s\n%s", filter.ID, filter.Name, filter.ViewURL), nil, nil
+}
@@ -0,0 +1,133 @@
+package jiramcp
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// readinessCacheTTL is how long a readiness check result is reused, so probes
+// from several replicas or a short probe period do not hammer Jira.
+const readinessCacheTTL = 10 * time.Second
+
+// readiness caches the outcome of the last Jira connectivity check.
+type readiness struct {
+	mu      sync.Mutex
+	checked time.Time
+	err     error
+}
+
+// healthzHandler reports that the process is up.
+func (j *JiraMCPServer) healthzHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain")
+	fmt.Fprintln(w, "ok")
+}
+
+// readyzHandler reports whether the server can serve tool calls: it is not
+// shutting down, Jira is reachable, and the service account's credentials
+// are accepted.
+func (j *JiraMCPServer) readyzHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain")
+	if j.draining.Load() {
+		http.Error(w, "shutting down", http.StatusServiceUnavailable)
+		return
+	}
+	if err := j.checkJira(r.Context()); err != nil {
+		http.Error(w, "jira unavailable: "+err.Error(), http.StatusServiceUnavailable)
+		return
+	}
+	fmt.Fprintln(w, "ok")
+}
+
+func (j *JiraMCPServer) checkJira(ctx context.Context) error {
+	j.ready.mu.Lock()
+	defer j.ready.mu.Unlock()
+	if time.Since(j.ready.checked) < readinessCacheTTL {
+		return j.ready.err
+	}
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	_, resp, err := j.service.GetSelf(ctx)
+	switch {
+	case err != nil && resp != nil && (resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden):
+		err = fmt.Errorf("authentication failed (HTTP %d)", resp.StatusCode)
+	case err != nil:
+		err = fmt.Errorf("not reachable: %w", err)
+	}
+	if err != nil {
+		slog.Warn("Readiness check failed", "error", err)
+	}
+	j.ready.checked, j.ready.err = time.Now(), err
+	return err
+}
+
+// inFlightMiddleware tracks running tool calls so shutdown can wait for them,
+// and refuses new ones once shutdown has begun.
+func (j *JiraMCPServer) inFlightMiddleware(next mcp.MethodHandler) mcp.MethodHandler {
+	return func(ctx context.Context, method string, req mcp.Request) (mcp.Result, error) {
+		if method != "tools/call" {
+			return next(ctx, method, req)
+		}
+		if j.draining.Load() {
+			return nil, fmt.Errorf("the server is shutting down; retry on another instance")
+		}
+		j.inFlight.Add(1)
+		defer j.inFlight.Done()
+		return next(ctx, method, req)
+	}
+}
+
+// drain stops accepting tool calls and waits up to timeout for the running
+// ones to finish. It reports whether they all did.
+func (j *JiraMCPServer) drain(timeout time.Duration) bool {
+	j.draining.Store(true)
+	done := make(chan struct{})
+	go func() {
+		j.inFlight.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return true
+	case <-time.After(timeout):
+		return false
+	}
+}
+
+// serveHTTP runs srv until ctx is cancelled, then drains in-flight tool calls
+// and shuts the listener down. SSE streams stay open until the server closes
+// them, so connections are closed once the tool calls are done.
+func (j *JiraMCPServer) serveHTTP(ctx context.Context, srv *http.Server, certFile, keyFile string) error {
+	errc := make(chan error, 1)
+	go func() {
+		if srv.TLSConfig != nil {
+			errc <- srv.ListenAndServeTLS(certFile, keyFile)
+		} else {
+			errc <- srv.ListenAndServe()
+		}
+	}()
+
+	select {
+	case err := <-errc:
+		return err
+	case <-ctx.Done():
+	}
+
+	slog.Info("Shutting down, waiting for in-flight tool calls", "timeout", j.config.ShutdownTimeout)
+	if !j.drain(j.config.ShutdownTimeout) {
+		slog.Warn("Shutdown timeout reached with tool calls still running")
+	}
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		// Idle SSE streams keep their connections busy; close them.
+		srv.Close()
+	}
+	slog.Info("Server stopped")
+	return nil
+}
@@ -0,0 +1,177 @@
+package jiramcp
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/andygrunwald/go-jira"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// defaultHeatmapIssues bounds how many issues the heatmap scans by default.
+const defaultHeatmapIssues = 500
+
+type TransitionHeatmapParams struct {
+	ProjectKey string `json:"projectKey,omitempty"`
+	// JQL narrows the scanned issues further, e.g. "issuetype = Bug".
+	JQL string `json:"jql,omitempty"`
+	// Days is the size of the period to analyse, counted back from today (default 90).
+	Days      int `json:"days,omitempty"`
+	MaxIssues int `json:"maxIssues,omitempty"`
+	// Verbosity "minimal" lists transition counts only, without bars or dwell times.
+	Verbosity string `json:"verbosity,omitempty"`
+}
+
+type transitionStats struct {
+	from, to   string
+	count      int
+	totalDwell time.Duration
+	bounceBack bool
+}
+
+// statusCategories maps status names to their category key (new,
+// indeterminate, done).
+func (j *JiraMCPServer) statusCategories(ctx context.Context) (map[string]string, error) {
+	statuses, _, err := j.client(ctx).ListStatuses(ctx)
+	if err != nil {
+		return nil, err
+	}
+	categories := make(map[string]string, len(statuses))
+	for _, s := range statuses {
+		categories[strings.ToLower(s.Name)] = s.StatusCategory.Key
+	}
+	return categories, nil
+}
+
+// TransitionHeatmap reports how often each workflow transition was used over a
+// period, the average time spent in the source status before it, and which
+// transitions move work back out of a done status.
+func (j *JiraMCPServer) TransitionHeatmap(ctx context.Context, req *mcp.CallToolRequest, params *TransitionHeatmapParams) (*mcp.CallToolResult, any, error) {
+	projectKey := j.projectKeyOrDefault(params.ProjectKey)
+	days := params.Days
+	if days <= 0 {
+		days = 90
+	}
+	maxIssues := params.MaxIssues
+	if maxIssues <= 0 {
+		maxIssues = defaultHeatmapIssues
+	}
+	since := time.Now().AddDate(0, 0, -days)
+
+	jql := fmt.Sprintf("project = %s AND updated >= -%dd", projectKey, days)
+	if params.JQL != "" {
+		jql = fmt.Sprintf("%s AND (%s)", jql, params.JQL)
+	}
+	issues, err := j.searchIssues(ctx, jql+" ORDER BY updated DESC", []string{"created", "status"}, "changelog", maxIssues)
+	if err != nil {
+		return textResult("Failed to load issues for %s: %v", projectKey, err), nil, nil
+	}
+
+	categories, err := j.statusCategories(ctx)
+	if err != nil {
+		return textResult("Failed to load status categories: %v", err), nil, nil
+	}
+
+	stats := make(map[string]*transitionStats)
+	total := 0
+	for _, issue := range issues {
+		// Dwell time is measured from the previous status change, or from
+		// creation for the first transition of the issue.
+		enteredAt := time.Time(issue.Fields.Created)
+		for _, h := range changelogHistories(issue) {
+			created, err := time.Parse(jiraTimeLayout, h.Created)
+			if err != nil {
+				continue
+			}
+			for _, item := range h.Items {
+				if item.Field != "status" {
+					continue
+				}
+				if !created.Before(since) {
+					key := item.FromString + " → " + item.ToString
+					s := stats[key]
+					if s == nil {
+						s = &transitionStats{
+							from:       item.FromString,
+							to:         item.ToString,
+							bounceBack: categories[strings.ToLower(item.FromString)] == "done" && categories[strings.ToLower(item.ToString)] != "done",
+						}
+						stats[key] = s
+					}
+					s.count++
+					if !enteredAt.IsZero() {
+						s.totalDwell += created.Sub(enteredAt)
+					}
+					total++
+				}
+				enteredAt = created
+			}
+		}
+	}
+
+	if total == 0 {
+		return textResult("No status transitions found in %s over the last %d days (%d issues scanned)", projectKey, days, len(issues)), nil, nil
+	}
+
+	sorted := make([]*transitionStats, 0, len(stats))
+	for _, s := range stats {
+		sorted = append(sorted, s)
+	}
+	sort.Slice(sorted, func(a, b int) bool { return sorted[a].count > sorted[b].count })
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "Transition heatmap for %s, last %d days (%d transitions across %d issues):\n", projectKey, days, total, len(issues))
+	verbosity := j.verbosity(params.Verbosity)
+	var bounces []string
+	for _, s := range sorted {
+		if verbosity == VerbosityMinimal {
+			fmt.Fprintf(&sb, "%5d  %s → %s\n", s.count, s.from, s.to)
+			continue
+		}
+		avgDwell := s.totalDwell / time.Duration(s.count)
+		fmt.Fprintf(&sb, "%5d  %s %s → %s (avg %s in %s)\n", s.count, heatBar(s.count, sorted[0].count), s.from, s.to, formatDuration(avgDwell), s.from)
+		if s.bounceBack {
+			bounces = append(bounces, fmt.Sprintf("%s → %s: %d", s.from, s.to, s.count))
+		}
+	}
+	if len(bounces) > 0 {
+		sb.WriteString("\nBounce-backs (work reopened after reaching a done status):\n")
+		for _, b := range bounces {
+			fmt.Fprintf(&sb, "- %s\n", b)
+		}
+	}
+	if len(issues) == maxIssues {
+		fmt.Fprintf(&sb, "\nNote: scan stopped at %d issues; narrow the period or JQL for complete figures.\n", maxIssues)
+	}
+	return textResult("%s", sb.String()), nil, nil
+}
+
+// changelogHistories returns an issue's changelog in chronological order.
+func changelogHistories(issue jira.Issue) []jira.ChangelogHistory {
+	if issue.Changelog == nil {
+		return nil
+	}
+	histories := append([]jira.ChangelogHistory(nil), issue.Changelog.Histories...)
+	sort.SliceStable(histories, func(a, b int) bool { return histories[a].Created < histories[b].Created })
+	return histories
+}
+
+// heatBar renders a proportional bar of at most ten cells.
+func heatBar(count, peak int) string {
+	cells := count * 10 / peak
+	if cells == 0 {
+		cells = 1
+	}
+	return strings.Repeat("█", cells) + strings.Repeat("·", 10-cells)
+}
+
+// formatDuration renders a duration in days or hours, whichever reads better.
+func formatDuration(d time.Duration) string {
+	if d >= 48*time.Hour {
+		return fmt.Sprintf("%.1fd", d.Hours()/24)
+	}
+	return fmt.Sprintf("%.1fh", d.Hours())
+}
@@ -0,0 +1,154 @@
+package jiramcp
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/andygrunwald/go-jira"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// defaultHistoryResults bounds get-issue-history output when maxResults is
+// not given.
+const defaultHistoryResults = 50
+
+type GetIssueHistoryParams struct {
+	IssueKey string `json:"issueKey"`
+	// Field keeps only changes to this field (case-insensitive), e.g. "status".
+	Field string `json:"field,omitempty"`
+	// From and To bound the change date (YYYY-MM-DD, inclusive).
+	From string `json:"from,omitempty"`
+	To   string `json:"to,omitempty"`
+	// StartAt continues a previous call; pass the nextStartAt it returned.
+	StartAt    int    `json:"startAt,omitempty"`
+	MaxResults int    `json:"maxResults,omitempty"`
+	Verbosity  string `json:"verbosity,omitempty"`
+}
+
+type changelogPage struct {
+	StartAt    int                     `json:"startAt"`
+	MaxResults int                     `json:"maxResults"`
+	Total      int                     `json:"total"`
+	IsLast     bool                    `json:"isLast"`
+	Values     []jira.ChangelogHistory `json:"values"`
+}
+
+// changelogPage fetches one page of an issue's changelog, oldest first. Jira
+// Server and Data Center lack the paginated changelog endpoint, so there the
+// full changelog is expanded on the issue and sliced locally.
+func (j *JiraMCPServer) changelogPage(ctx context.Context, issueKey string, startAt, pageSize int) (*changelogPage, error) {
+	var page changelogPage
+	path := fmt.Sprintf("rest/api/2/issue/%s/changelog?startAt=%d&maxResults=%d", issueKey, startAt, pageSize)
+	resp, err := j.jiraDo(ctx, "GET", path, nil, &page)
+	if err == nil {
+		return &page, nil
+	}
+	if resp == nil || resp.StatusCode != http.StatusNotFound {
+		return nil, err
+	}
+
+	var issue jira.Issue
+	if _, err := j.jiraDo(ctx, "GET", fmt.Sprintf("rest/api/2/issue/%s?fields=created&expand=changelog", issueKey), nil, &issue); err != nil {
+		return nil, err
+	}
+	histories := changelogHistories(issue)
+	page = changelogPage{StartAt: startAt, MaxResults: pageSize, Total: len(histories)}
+	if startAt < len(histories) {
+		page.Values = histories[startAt:min(len(histories), startAt+pageSize)]
+	}
+	page.IsLast = startAt+len(page.Values) >= len(histories)
+	return &page, nil
+}
+
+// GetIssueHistory returns an issue's changelog, oldest first, optionally
+// filtered by field and date range.
+func (j *JiraMCPServer) GetIssueHistory(ctx context.Context, req *mcp.CallToolRequest, params *GetIssueHistoryParams) (*mcp.CallToolResult, any, error) {
+	issueKey := strings.ToUpper(params.IssueKey)
+	maxResults := params.MaxResults
+	if maxResults <= 0 {
+		maxResults = defaultHistoryResults
+	}
+	var from, to time.Time
+	var err error
+	if params.From != "" {
+		if from, err = time.Parse("2006-01-02", params.From); err != nil {
+			return textResult("Invalid 'from' parameter %q: expected YYYY-MM-DD", params.From), nil, nil
+		}
+	}
+	if params.To != "" {
+		if to, err = time.Parse("2006-01-02", params.To); err != nil {
+			return textResult("Invalid 'to' parameter %q: expected YYYY-MM-DD", params.To), nil, nil
+		}
+		to = to.AddDate(0, 0, 1)
+	}
+	verbosity := j.verbosity(params.Verbosity)
+
+	var lines []string
+	next, total := params.StartAt, 0
+	done := false
+	for !done && len(lines) < maxResults {
+		page, err := j.changelogPage(ctx, issueKey, next, 100)
+		if err != nil {
+			return textResult("Failed to get history of %s: %v", issueKey, err), nil, nil
+		}
+		total = page.Total
+		for _, h := range page.Values {
+			next++
+			created, err := time.Parse(jiraTimeLayout, h.Created)
+			if err == nil && ((!from.IsZero() && created.Before(from)) || (!to.IsZero() && !created.Before(to))) {
+				continue
+			}
+			for _, item := range h.Items {
+				if params.Field != "" && !strings.EqualFold(item.Field, params.Field) {
+					continue
+				}
+				lines = append(lines, formatChangelogItem(&h, &item, verbosity))
+			}
+			if len(lines) >= maxResults {
+				break
+			}
+		}
+		done = page.IsLast || len(page.Values) == 0 || next >= page.Total
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "History of %s (%d change(s) shown, changelog entries %d-%d of %d):\n", issueKey, len(lines), params.StartAt+1, next, total)
+	if len(lines) == 0 {
+		sb.WriteString("No matching changes.\n")
+	}
+	for _, line := range lines {
+		sb.WriteString(line)
+		sb.WriteString("\n")
+	}
+	if !done && next < total {
+		fmt.Fprintf(&sb, "\nMore history available. nextStartAt: %d\n", next)
+	}
+	return textResult("%s", sb.String()), nil, nil
+}
+
+// formatChangelogItem renders a single field change from a changelog entry.
+func formatChangelogItem(h *jira.ChangelogHistory, item *jira.ChangelogItems, verbosity string) string {
+	when := formatJiraTimestamp(h.Created)
+	if verbosity == VerbosityMinimal {
+		return fmt.Sprintf("[%s] %s → %q", when, item.Field, item.ToString)
+	}
+	author := h.Author.DisplayName
+	if author == "" {
+		author = "unknown"
+	}
+	line := fmt.Sprintf("[%s] %s changed %s: %q → %q", when, author, item.Field, item.FromString, item.ToString)
+	if verbosity == VerbosityFull && (item.From != nil || item.To != nil) {
+		line += fmt.Sprintf(" (ids %v → %v)", valueOrNone(item.From), valueOrNone(item.To))
+	}
+	return line
+}
+
+func valueOrNone(v interface{}) interface{} {
+	if v == nil {
+		return "none"
+	}
+	return v
+}
@@ -0,0 +1,75 @@
+package jiramcp
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+)
+
+// requireAuth rejects HTTP requests that do not carry one of the configured
+// API tokens or, with JIRA_MCP_OIDC_ISSUER, a valid OIDC token, either as
+// "Authorization: Bearer <token>" or in the X-API-Key header. It is a no-op
+// when neither is configured. The client's identity is stored in the request
+// context for role checks, along with the Jira credentials it impersonates.
+func (j *JiraMCPServer) requireAuth(next http.Handler) http.Handler {
+	if len(j.config.AuthTokens) == 0 && j.oidc == nil {
+		return next
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if p := j.authenticate(r.Context(), tokenFromHeader(r.Header)); p != nil {
+			ctx := context.WithValue(r.Context(), principalKey{}, p)
+			if creds, ok := j.oidc.credentialsFor(p); ok {
+				ctx = context.WithValue(ctx, credentialsKey{}, creds)
+			}
+			next.ServeHTTP(w, r.WithContext(ctx))
+			return
+		}
+		slog.Warn("Rejected unauthenticated request", "path", r.URL.Path, "remoteAddr", r.RemoteAddr)
+		w.Header().Set("WWW-Authenticate", `Bearer realm="`+ServerName+`"`)
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+	})
+}
+
+// apiTokenValid reports whether token is one of JIRA_MCP_AUTH_TOKENS.
+// Digests are compared so the comparison takes the same time whatever the
+// length of the presented token.
+func (j *JiraMCPServer) apiTokenValid(token string) bool {
+	got := sha256.Sum256([]byte(token))
+	valid := false
+	for _, t := range j.config.AuthTokens {
+		want := sha256.Sum256([]byte(t))
+		if subtle.ConstantTimeCompare(got[:], want[:]) == 1 {
+			valid = true
+		}
+	}
+	return valid
+}
+
+// tlsConfig returns the TLS settings for the HTTP listener, or nil when TLS is
+// not configured. With a client CA, clients must present a certificate it
+// signed (mutual TLS).
+func tlsConfig(c *JiraConfig) (*tls.Config, error) {
+	if c.TLSCertFile == "" {
+		return nil, nil
+	}
+	cfg := &tls.Config{MinVersion: tls.VersionTLS12}
+	if c.TLSClientCAFile != "" {
+		pem, err := os.ReadFile(c.TLSClientCAFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read client CA file: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificates found in client CA file %s", c.TLSClientCAFile)
+		}
+		cfg.ClientCAs = pool
+		cfg.ClientAuth = tls.RequireAndVerifyClientCert
+	}
+	return cfg, nil
+}
@@ -0,0 +1,107 @@
+package jiramcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// clearValue is the value of UpdateIssueArgs.Assignee and DueDate that clears
+// the field.
+const clearValue = "none"
+
+// nameOperations returns the update operations that set, add, and remove the
+// named values of a multi-value field such as components or fixVersions. A
+// present but empty set clears the field.
+func nameOperations(set []string, add, remove []string) []map[string]interface{} {
+	var ops []map[string]interface{}
+	if set != nil {
+		values := make([]map[string]string, 0, len(set))
+		for _, name := range set {
+			values = append(values, map[string]string{"name": name})
+		}
+		ops = append(ops, map[string]interface{}{"set": values})
+	}
+	for _, name := range add {
+		ops = append(ops, map[string]interface{}{"add": map[string]string{"name": name}})
+	}
+	for _, name := range remove {
+		ops = append(ops, map[string]interface{}{"remove": map[string]string{"name": name}})
+	}
+	return ops
+}
+
+// setOperation returns the update operation replacing a field's value.
+func setOperation(value interface{}) []map[string]interface{} {
+	return []map[string]interface{}{{"set": value}}
+}
+
+// editableFieldIDs returns the IDs of the fields the current user may edit on
+// the issue, keyed by lower-cased field name and by ID.
+func (j *JiraMCPServer) editableFieldIDs(ctx context.Context, issueKey string) (map[string]string, error) {
+	var meta editMeta
+	if _, err := j.jiraDo(ctx, "GET", fmt.Sprintf("rest/api/2/issue/%s/editmeta", issueKey), nil, &meta); err != nil {
+		return nil, err
+	}
+	ids := make(map[string]string, 2*len(meta.Fields))
+	for id, raw := range meta.Fields {
+		var field struct {
+			Name string `json:"name"`
+		}
+		json.Unmarshal(raw, &field)
+		if field.Name != "" {
+			ids[strings.ToLower(field.Name)] = id
+		}
+		ids[strings.ToLower(id)] = id
+	}
+	return ids, nil
+}
+
+// customFieldOperations resolves custom fields given by name or ID to set
+// operations. Fields that do not exist or cannot be edited on the issue are
+// reported as problems.
+func (j *JiraMCPServer) customFieldOperations(ctx context.Context, issueKey string, fields map[string]interface{}) (map[string]interface{}, []string, error) {
+	ids, err := j.editableFieldIDs(ctx, issueKey)
+	if err != nil {
+		return nil, nil, err
+	}
+	ops := make(map[string]interface{}, len(fields))
+	var problems []string
+	for name, value := range fields {
+		id, ok := ids[strings.ToLower(name)]
+		if !ok {
+			problems = append(problems, fmt.Sprintf("field %q does not exist or cannot be edited on %s", name, issueKey))
+			continue
+		}
+		ops[id] = setOperation(value)
+	}
+	return ops, problems, nil
+}
+
+// dueDateValue parses a due date argument: a date resolveDate understands,
+// or "none" to clear it.
+func dueDateValue(dueDate string) (interface{}, error) {
+	if strings.EqualFold(dueDate, clearValue) {
+		return nil, nil
+	}
+	t, err := resolveDate(dueDate, time.Now())
+	if err != nil {
+		return nil, fmt.Errorf("due date: %w", err)
+	}
+	return t.Format("2006-01-02"), nil
+}
+
+// assigneeValue resolves an assignee argument, an email address or name,
+// to the value Jira expects; "none" unassigns the issue.
+func (j *JiraMCPServer) assigneeValue(ctx context.Context, assignee string) (interface{}, error) {
+	if strings.EqualFold(assignee, clearValue) {
+		return nil, nil
+	}
+	user, err := j.findJiraUser(ctx, assignee)
+	if err != nil {
+		return nil, err
+	}
+	return j.userRef(j.userID(user)), nil
+}
@@ -0,0 +1,23 @@
+package jiramcp
+
+import (
+	"context"
+
+	"github.com/andygrunwald/go-jira"
+)
+
+// jiraDo issues a request against the Jira REST API relative to the configured
+// base URL and decodes the JSON response into v when v is non-nil.
+// Errors are wrapped with the messages Jira returned in the response body.
+func (j *JiraMCPServer) jiraDo(ctx context.Context, method, path string, body, v interface{}) (*jira.Response, error) {
+	req, err := j.client(ctx).NewRequestWithContext(ctx, method, path, body)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := j.client(ctx).Do(req, v)
+	if err != nil {
+		return resp, jira.NewJiraError(resp, err)
+	}
+	return resp, nil
+}
@@ -0,0 +1,98 @@
+package jiramcp
+
+import (
+	"context"
+	"io"
+	"net/http"
+
+	"github.com/andygrunwald/go-jira"
+)
+
+// JiraService is the part of the Jira API the server uses. NewGoJiraService
+// adapts a go-jira client to it; MockJiraService lets tests and embedding
+// programs run the handlers without a Jira instance.
+//
+// Endpoints without a typed method are reached through NewRequestWithContext
+// and Do, which take paths relative to the Jira base URL.
+type JiraService interface {
+	NewRequestWithContext(ctx context.Context, method, urlStr string, body interface{}) (*http.Request, error)
+	Do(req *http.Request, v interface{}) (*jira.Response, error)
+
+	GetIssue(ctx context.Context, issueKey string, options *jira.GetQueryOptions) (*jira.Issue, *jira.Response, error)
+	CreateIssue(ctx context.Context, issue *jira.Issue) (*jira.Issue, *jira.Response, error)
+	AddComment(ctx context.Context, issueKey string, comment *jira.Comment) (*jira.Comment, *jira.Response, error)
+	GetTransitions(ctx context.Context, issueKey string) ([]jira.Transition, *jira.Response, error)
+	DoTransitionWithPayload(ctx context.Context, issueKey string, payload interface{}) (*jira.Response, error)
+	AddLink(ctx context.Context, link *jira.IssueLink) (*jira.Response, error)
+	DownloadAttachment(ctx context.Context, attachmentID string) (*jira.Response, error)
+	PostAttachment(ctx context.Context, issueKey string, r io.Reader, filename string) (*[]jira.Attachment, *jira.Response, error)
+	CreateComponent(ctx context.Context, options *jira.CreateComponentOptions) (*jira.ProjectComponent, *jira.Response, error)
+
+	FindUsers(ctx context.Context, query string) ([]jira.User, *jira.Response, error)
+	GetSelf(ctx context.Context) (*jira.User, *jira.Response, error)
+	ListPriorities(ctx context.Context) ([]jira.Priority, *jira.Response, error)
+	ListStatuses(ctx context.Context) ([]jira.Status, *jira.Response, error)
+}
+
+// goJiraService implements JiraService with a go-jira client. The raw request
+// methods are the client's own.
+type goJiraService struct {
+	*jira.Client
+}
+
+// NewGoJiraService returns a JiraService backed by client.
+func NewGoJiraService(client *jira.Client) JiraService {
+	return goJiraService{client}
+}
+
+func (s goJiraService) GetIssue(ctx context.Context, issueKey string, options *jira.GetQueryOptions) (*jira.Issue, *jira.Response, error) {
+	return s.Issue.GetWithContext(ctx, issueKey, options)
+}
+
+func (s goJiraService) CreateIssue(ctx context.Context, issue *jira.Issue) (*jira.Issue, *jira.Response, error) {
+	return s.Issue.CreateWithContext(ctx, issue)
+}
+
+func (s goJiraService) AddComment(ctx context.Context, issueKey string, comment *jira.Comment) (*jira.Comment, *jira.Response, error) {
+	return s.Issue.AddCommentWithContext(ctx, issueKey, comment)
+}
+
+func (s goJiraService) GetTransitions(ctx context.Context, issueKey string) ([]jira.Transition, *jira.Response, error) {
+	return s.Issue.GetTransitionsWithContext(ctx, issueKey)
+}
+
+func (s goJiraService) DoTransitionWithPayload(ctx context.Context, issueKey string, payload interface{}) (*jira.Response, error) {
+	return s.Issue.DoTransitionWithPayloadWithContext(ctx, issueKey, payload)
+}
+
+func (s goJiraService) AddLink(ctx context.Context, link *jira.IssueLink) (*jira.Response, error) {
+	return s.Issue.AddLinkWithContext(ctx, link)
+}
+
+func (s goJiraService) DownloadAttachment(ctx context.Context, attachmentID string) (*jira.Response, error) {
+	return s.Issue.DownloadAttachmentWithContext(ctx, attachmentID)
+}
+
+func (s goJiraService) PostAttachment(ctx context.Context, issueKey string, r io.Reader, filename string) (*[]jira.Attachment, *jira.Response, error) {
+	return s.Issue.PostAttachmentWithContext(ctx, issueKey, r, filename)
+}
+
+func (s goJiraService) CreateComponent(ctx context.Context, options *jira.CreateComponentOptions) (*jira.ProjectComponent, *jira.Response, error) {
+	return s.Component.CreateWithContext(ctx, options)
+}
+
+func (s goJiraService) FindUsers(ctx context.Context, query string) ([]jira.User, *jira.Response, error) {
+	return s.User.FindWithContext(ctx, query)
+}
+
+func (s goJiraService) GetSelf(ctx context.Context) (*jira.User, *jira.Response, error) {
+	return s.User.GetSelfWithContext(ctx)
+}
+
+func (s goJiraService) ListPriorities(ctx context.Context) ([]jira.Priority, *jira.Response, error) {
+	return s.Priority.GetListWithContext(ctx)
+}
+
+func (s goJiraService) ListStatuses(ctx context.Context) ([]jira.Status, *jira.Response, error) {
+	return s.Status.GetAllStatusesWithContext(ctx)
+}
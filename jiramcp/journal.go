@@ -0,0 +1,365 @@
+package jiramcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// maxJournalEntries bounds the changes remembered for each session; older
+// ones can no longer be undone.
+const maxJournalEntries = 50
+
+// journalEntry is a change a tool call made to Jira: the issues it created,
+// or the values of the fields it edited before and after the edit, as Jira
+// returned them.
+type journalEntry struct {
+	Time time.Time `json:"time"`
+	Tool string    `json:"tool"`
+	// Created lists the issues the call created, parents first.
+	Created  []string                   `json:"created,omitempty"`
+	IssueKey string                     `json:"issueKey,omitempty"`
+	Before   map[string]json.RawMessage `json:"before,omitempty"`
+	After    map[string]json.RawMessage `json:"after,omitempty"`
+	// Note records what undoing the entry leaves in place, such as a
+	// status change.
+	Note string `json:"note,omitempty"`
+}
+
+// describe summarizes the entry for tool results.
+func (e journalEntry) describe() string {
+	at := e.Time.Local().Format("15:04:05")
+	if len(e.Created) > 0 {
+		return fmt.Sprintf("%s at %s created %s", e.Tool, at, strings.Join(e.Created, ", "))
+	}
+	return fmt.Sprintf("%s at %s changed %s of %s", e.Tool, at, strings.Join(sortedKeys(e.Before), ", "), e.IssueKey)
+}
+
+// operationJournal keeps the changes of each session, newest last, so that
+// undo-last-change can revert them.
+type operationJournal struct {
+	mu       sync.Mutex
+	sessions map[string][]journalEntry
+}
+
+// record appends an entry to the journal of session.
+func (o *operationJournal) record(session string, e journalEntry) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if o.sessions == nil {
+		o.sessions = make(map[string][]journalEntry)
+	}
+	entries := append(o.sessions[session], e)
+	if len(entries) > maxJournalEntries {
+		entries = entries[len(entries)-maxJournalEntries:]
+	}
+	o.sessions[session] = entries
+}
+
+// take removes and returns the newest entry of session, so that two undo
+// calls never revert the same change.
+func (o *operationJournal) take(session string) (journalEntry, bool) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	entries := o.sessions[session]
+	if len(entries) == 0 {
+		return journalEntry{}, false
+	}
+	e := entries[len(entries)-1]
+	o.sessions[session] = entries[:len(entries)-1]
+	return e, true
+}
+
+// entries returns a copy of the journal of session.
+func (o *operationJournal) entries(session string) []journalEntry {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return append([]journalEntry(nil), o.sessions[session]...)
+}
+
+// replace sets the journal of session, as restored from the store.
+func (o *operationJournal) replace(session string, entries []journalEntry) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if o.sessions == nil {
+		o.sessions = make(map[string][]journalEntry)
+	}
+	o.sessions[session] = append([]journalEntry(nil), entries...)
+}
+
+// forget drops the journals of the sessions matching match.
+func (o *operationJournal) forget(match func(session string) bool) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	for id := range o.sessions {
+		if match(id) {
+			delete(o.sessions, id)
+		}
+	}
+}
+
+// sortedKeys returns the keys of m in order.
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// issueFieldValues returns the raw values of fields of an issue, by ID.
+// Fields Jira leaves out of the response are empty, and so null.
+func (j *JiraMCPServer) issueFieldValues(ctx context.Context, issueKey string, ids []string) (map[string]json.RawMessage, error) {
+	var issue struct {
+		Fields map[string]json.RawMessage `json:"fields"`
+	}
+	path := fmt.Sprintf("rest/api/2/issue/%s?fields=%s", issueKey, strings.Join(ids, ","))
+	if _, err := j.jiraDo(ctx, "GET", path, nil, &issue); err != nil {
+		return nil, err
+	}
+	values := make(map[string]json.RawMessage, len(ids))
+	for _, id := range ids {
+		if v, ok := issue.Fields[id]; ok {
+			values[id] = v
+		} else {
+			values[id] = json.RawMessage("null")
+		}
+	}
+	return values, nil
+}
+
+// journalBefore reads the fields an edit is about to change, for
+// journalUpdate. It returns nil when they cannot be read, and the edit then
+// goes unjournaled.
+func (j *JiraMCPServer) journalBefore(ctx context.Context, issueKey string, fields map[string]interface{}) map[string]json.RawMessage {
+	before, err := j.issueFieldValues(ctx, issueKey, sortedKeys(fields))
+	if err != nil {
+		logger(ctx).Warn("Could not read fields before an edit; it cannot be undone", "issueKey", issueKey, "error", err)
+		return nil
+	}
+	return before
+}
+
+// journalUpdate records a field edit of issueKey made by tool, given the
+// values journalBefore read.
+func (j *JiraMCPServer) journalUpdate(ctx context.Context, req *mcp.CallToolRequest, tool, issueKey string, before map[string]json.RawMessage, note string) {
+	if before == nil {
+		return
+	}
+	after, err := j.issueFieldValues(ctx, issueKey, sortedKeys(before))
+	if err != nil {
+		logger(ctx).Warn("Could not read fields after an edit; it cannot be undone", "issueKey", issueKey, "error", err)
+		return
+	}
+	j.journal.record(requestSession(ctx), journalEntry{Time: time.Now(), Tool: tool, IssueKey: issueKey, Before: before, After: after, Note: note})
+}
+
+// journalCreate records the issues a tool call created.
+func (j *JiraMCPServer) journalCreate(ctx context.Context, tool string, keys ...string) {
+	if len(keys) == 0 {
+		return
+	}
+	j.journal.record(requestSession(ctx), journalEntry{Time: time.Now(), Tool: tool, Created: keys})
+}
+
+// settableValue turns a field value as Jira returns it into one it accepts
+// on edit: users by account ID or name, and other objects such as options,
+// versions, and components by ID.
+func settableValue(v interface{}) interface{} {
+	switch v := v.(type) {
+	case []interface{}:
+		values := make([]interface{}, len(v))
+		for i, e := range v {
+			values[i] = settableValue(e)
+		}
+		return values
+	case map[string]interface{}:
+		switch {
+		case v["accountId"] != nil:
+			return map[string]interface{}{"accountId": v["accountId"]}
+		case v["displayName"] != nil && v["name"] != nil:
+			return map[string]interface{}{"name": v["name"]}
+		case v["id"] != nil:
+			ref := map[string]interface{}{"id": v["id"]}
+			if child, ok := v["child"]; ok {
+				ref["child"] = settableValue(child)
+			}
+			return ref
+		case v["value"] != nil:
+			return map[string]interface{}{"value": v["value"]}
+		}
+	}
+	return v
+}
+
+// sameValue reports whether two raw field values are equal.
+func sameValue(a, b json.RawMessage) bool {
+	var va, vb interface{}
+	json.Unmarshal(a, &va)
+	json.Unmarshal(b, &vb)
+	return reflect.DeepEqual(va, vb)
+}
+
+type UndoLastChangeParams struct {
+	// Force reverts fields even if they changed again since the change
+	// being undone.
+	Force bool `json:"force,omitempty"`
+	// ConfirmationPhrase confirms deleting more created issues than
+	// JIRA_MCP_BULK_CONFIRM_THRESHOLD when the client cannot show a
+	// confirmation prompt; it must be "<count> issues".
+	ConfirmationPhrase string `json:"confirmationPhrase,omitempty"`
+	DryRun             bool   `json:"dryRun,omitempty"`
+}
+
+type ListSessionChangesParams struct{}
+
+// ListSessionChanges lists the changes of the session that undo-last-change
+// can revert, newest first.
+func (j *JiraMCPServer) ListSessionChanges(ctx context.Context, req *mcp.CallToolRequest, params *ListSessionChangesParams) (*mcp.CallToolResult, any, error) {
+	entries := j.journal.entries(requestSession(ctx))
+	if len(entries) == 0 {
+		return textResult("No changes recorded in this session"), nil, nil
+	}
+	var sb strings.Builder
+	sb.WriteString("Changes in this session, newest first:\n")
+	for i := len(entries) - 1; i >= 0; i-- {
+		fmt.Fprintf(&sb, "- %s", entries[i].describe())
+		if entries[i].Note != "" {
+			fmt.Fprintf(&sb, " (%s)", entries[i].Note)
+		}
+		sb.WriteString("\n")
+	}
+	return textResult("%s", sb.String()), nil, nil
+}
+
+// UndoLastChange reverts the newest change of the session: it deletes the
+// issues a call created, or sets the fields an edit changed back to their
+// earlier values. Fields that changed again since are left alone unless
+// force is set.
+func (j *JiraMCPServer) UndoLastChange(ctx context.Context, req *mcp.CallToolRequest, params *UndoLastChangeParams) (*mcp.CallToolResult, any, error) {
+	session := requestSession(ctx)
+	e, ok := j.journal.take(session)
+	if !ok {
+		return textResult("Nothing to undo: no changes are recorded in this session"), nil, nil
+	}
+	result, undone := j.undo(ctx, req, e, params)
+	if !undone {
+		// A dry run or a refusal leaves the entry for the next attempt.
+		j.journal.record(session, e)
+	}
+	return result, nil, nil
+}
+
+// undo reverts a journal entry, reporting whether it is done with.
+func (j *JiraMCPServer) undo(ctx context.Context, req *mcp.CallToolRequest, e journalEntry, params *UndoLastChangeParams) (*mcp.CallToolResult, bool) {
+	if len(e.Created) > 0 {
+		return j.undoCreate(ctx, req, e, params)
+	}
+
+	current, err := j.issueFieldValues(ctx, e.IssueKey, sortedKeys(e.Before))
+	if err != nil {
+		return textResult("Failed to read the fields of %s: %v", e.IssueKey, err), false
+	}
+	var changed []string
+	for id := range e.Before {
+		if !sameValue(current[id], e.After[id]) {
+			changed = append(changed, id)
+		}
+	}
+	sort.Strings(changed)
+	fields := make(map[string]interface{}, len(e.Before))
+	for id, raw := range e.Before {
+		var v interface{}
+		json.Unmarshal(raw, &v)
+		fields[id] = settableValue(v)
+	}
+	path := fmt.Sprintf("rest/api/2/issue/%s", e.IssueKey)
+	payload := map[string]interface{}{"fields": fields}
+	if j.dryRun(params.DryRun) {
+		var problems []string
+		if len(changed) > 0 && !params.Force {
+			problems = append(problems, fmt.Sprintf("%s changed again since: %s; set force to overwrite", e.IssueKey, strings.Join(changed, ", ")))
+		}
+		return dryRunResult("PUT", path, payload, problems), false
+	}
+	if len(changed) > 0 && !params.Force {
+		return textResult("Did not undo %s: %s changed again since (%s). Set force to revert anyway.", e.describe(), e.IssueKey, strings.Join(changed, ", ")), false
+	}
+	if _, err := j.jiraDo(ctx, "PUT", path, payload, nil); err != nil {
+		return textResult("Failed to undo %s: %v", e.describe(), err), false
+	}
+	logger(ctx).Info("Undid field edit", "issueKey", e.IssueKey, "tool", e.Tool)
+	text := fmt.Sprintf("Undid %s: restored %s", e.describe(), strings.Join(sortedKeys(e.Before), ", "))
+	if e.Note != "" {
+		text += "; " + e.Note
+	}
+	return textResult("%s", text), true
+}
+
+// undoCreate deletes the issues of a journal entry, children first. Issues
+// that could not be deleted stay in the journal. Deleting needs
+// JIRA_MCP_ALLOW_DELETE and a role that may use the delete tools; otherwise
+// the issues stay and the entry is dropped, so earlier changes can still be
+// undone.
+func (j *JiraMCPServer) undoCreate(ctx context.Context, req *mcp.CallToolRequest, e journalEntry, params *UndoLastChangeParams) (*mcp.CallToolResult, bool) {
+	keys := make([]string, 0, len(e.Created))
+	for i := len(e.Created) - 1; i >= 0; i-- {
+		keys = append(keys, e.Created[i])
+	}
+	if !j.config.AllowDelete || !j.mayUseTool(ctx, req, "delete-jira-issue") {
+		reason := "deleting issues is disabled on this server (JIRA_MCP_ALLOW_DELETE)"
+		if j.config.AllowDelete {
+			reason = "your role may not delete issues"
+		}
+		if j.dryRun(params.DryRun) {
+			return textResult("Would not undo %s: %s, so %s would stay in place.", e.Tool, reason, strings.Join(keys, ", ")), false
+		}
+		logger(ctx).Info("Left created issues in place on undo", "tool", e.Tool, "reason", reason)
+		return textResult("Did not undo %s: %s, so %s stay in place. Delete them by hand if they are not wanted; "+
+			"the change is dropped from the journal so earlier changes can be undone.", e.Tool, reason, strings.Join(keys, ", ")), true
+	}
+	if j.dryRun(params.DryRun) {
+		var problems []string
+		for _, key := range keys {
+			problems = append(problems, j.issueProblems(ctx, key)...)
+		}
+		return dryRunResult("DELETE", "rest/api/2/issue/{key} for "+strings.Join(keys, ", "), nil, problems), false
+	}
+	if problem := j.confirmBulk(ctx, req, "Undoing "+e.Tool, keys, params.ConfirmationPhrase); problem != "" {
+		return textResult("%s", problem), false
+	}
+
+	var deleted, kept, failures []string
+	for _, key := range keys {
+		// Sub-tasks added since by someone else make the delete fail rather
+		// than disappear with their parent.
+		resp, err := j.jiraDo(ctx, "DELETE", fmt.Sprintf("rest/api/2/issue/%s?deleteSubtasks=false", key), nil, nil)
+		if err != nil && (resp == nil || resp.StatusCode != http.StatusNotFound) {
+			kept = append([]string{key}, kept...)
+			failures = append(failures, fmt.Sprintf("%s (%v)", key, err))
+			continue
+		}
+		deleted = append(deleted, key)
+	}
+	logger(ctx).Info("Undid issue creation", "deleted", len(deleted), "tool", e.Tool)
+	if len(kept) > 0 {
+		e.Created = kept
+		j.journal.record(requestSession(ctx), e)
+		done := "nothing"
+		if len(deleted) > 0 {
+			done = strings.Join(deleted, ", ")
+		}
+		return textResult("Undid %s only in part: deleted %s; failed to delete %s, which undo-last-change can retry",
+			e.Tool, done, strings.Join(failures, "; ")), true
+	}
+	return textResult("Undid %s: deleted %s", e.Tool, strings.Join(deleted, ", ")), true
+}
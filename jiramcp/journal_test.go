@@ -0,0 +1,135 @@
+package jiramcp
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+// deleteRoutes serves the deletion of issues, failing for those in fail, and
+// records the order of the deletes.
+func deleteRoutes(deleted *[]string, keys []string, fail map[string]bool) jiraRoutes {
+	routes := jiraRoutes{}
+	for _, key := range keys {
+		key := key
+		routes["DELETE rest/api/2/issue/"+key] = func(*http.Request) (int, interface{}) {
+			if fail[key] {
+				return http.StatusBadRequest, map[string]interface{}{"errorMessages": []string{"has sub-tasks"}}
+			}
+			*deleted = append(*deleted, key)
+			return http.StatusNoContent, nil
+		}
+	}
+	return routes
+}
+
+func createdEntry(keys ...string) journalEntry {
+	return journalEntry{Time: time.Now(), Tool: "create-work-breakdown", Created: keys}
+}
+
+func TestUndoCreateDeletesChildrenFirst(t *testing.T) {
+	var deleted []string
+	mock := &MockJiraService{DoFunc: deleteRoutes(&deleted, []string{"SMS-1", "SMS-2"}, nil).do}
+	j := newTestServer(t, mock, func(c *JiraConfig) { c.AllowDelete = true })
+	j.journal.record("", createdEntry("SMS-1", "SMS-2"))
+
+	result, _, _ := j.UndoLastChange(context.Background(), nil, &UndoLastChangeParams{})
+	if got, want := resultText(result), "Undid create-work-breakdown: deleted SMS-2, SMS-1"; got != want {
+		t.Errorf("result = %q, want %q", got, want)
+	}
+	if strings.Join(deleted, ",") != "SMS-2,SMS-1" {
+		t.Errorf("deleted %v, want SMS-2 before its parent SMS-1", deleted)
+	}
+	if entries := j.journal.entries(""); len(entries) != 0 {
+		t.Errorf("journal = %+v, want it empty", entries)
+	}
+}
+
+func TestUndoCreateKeepsWhatFailedToDelete(t *testing.T) {
+	var deleted []string
+	mock := &MockJiraService{DoFunc: deleteRoutes(&deleted, []string{"SMS-1", "SMS-2", "SMS-3"}, map[string]bool{"SMS-1": true}).do}
+	j := newTestServer(t, mock, func(c *JiraConfig) { c.AllowDelete = true })
+	j.journal.record("", createdEntry("SMS-1", "SMS-2", "SMS-3"))
+
+	result, _, _ := j.UndoLastChange(context.Background(), nil, &UndoLastChangeParams{})
+	if got := resultText(result); !strings.HasPrefix(got, "Undid create-work-breakdown only in part: deleted SMS-3, SMS-2; failed to delete SMS-1") {
+		t.Errorf("result = %q, want a partial undo", got)
+	}
+	entries := j.journal.entries("")
+	if len(entries) != 1 || strings.Join(entries[0].Created, ",") != "SMS-1" {
+		t.Errorf("journal = %+v, want SMS-1 left to retry", entries)
+	}
+}
+
+func TestUndoCreateNeedsDeletePermission(t *testing.T) {
+	var deleted []string
+	routes := deleteRoutes(&deleted, []string{"SMS-1"}, nil)
+
+	// Without JIRA_MCP_ALLOW_DELETE nothing is deleted, and the entry is
+	// dropped so earlier changes can be undone.
+	j := newTestServer(t, &MockJiraService{DoFunc: routes.do}, nil)
+	j.journal.record("", createdEntry("SMS-1"))
+	result, _, _ := j.UndoLastChange(context.Background(), nil, &UndoLastChangeParams{})
+	if got := resultText(result); !strings.HasPrefix(got, "Did not undo create-work-breakdown: deleting issues is disabled on this server") {
+		t.Errorf("without deletes: result = %q, want a refusal", got)
+	}
+	if entries := j.journal.entries(""); len(entries) != 0 {
+		t.Errorf("without deletes: journal = %+v, want the entry dropped", entries)
+	}
+
+	// An editor may undo but not delete, so it cannot undo creations either.
+	j = newRBACServer(t, &MockJiraService{DoFunc: routes.do}, func(c *JiraConfig) { c.AllowDelete = true })
+	j.journal.record("", createdEntry("SMS-1"))
+	result = callTool(t, connectAs(t, j, "editor"), "undo-last-change", map[string]interface{}{})
+	if got := resultText(result); !strings.HasPrefix(got, "Did not undo create-work-breakdown: your role may not delete issues") {
+		t.Errorf("editor: result = %q, want a refusal", got)
+	}
+	if len(deleted) > 0 {
+		t.Errorf("deleted %v without permission", deleted)
+	}
+}
+
+func TestUndoFieldEdit(t *testing.T) {
+	current := `{"summary": "New"}`
+	var restored map[string]map[string]interface{}
+	routes := jiraRoutes{
+		"GET rest/api/2/issue/SMS-1": func(*http.Request) (int, interface{}) {
+			return http.StatusOK, map[string]json.RawMessage{"fields": json.RawMessage(current)}
+		},
+		"PUT rest/api/2/issue/SMS-1": func(r *http.Request) (int, interface{}) {
+			decodeBody(t, r, &restored)
+			return http.StatusNoContent, nil
+		},
+	}
+	j := newTestServer(t, &MockJiraService{DoFunc: routes.do}, nil)
+	edit := journalEntry{
+		Time:     time.Now(),
+		Tool:     "update-jira-issue",
+		IssueKey: "SMS-1",
+		Before:   map[string]json.RawMessage{"summary": json.RawMessage(`"Old"`)},
+		After:    map[string]json.RawMessage{"summary": json.RawMessage(`"New"`)},
+	}
+
+	// An issue edited again since is left alone unless forced.
+	current = `{"summary": "Newer"}`
+	j.journal.record("", edit)
+	result, _, _ := j.UndoLastChange(context.Background(), nil, &UndoLastChangeParams{})
+	if got := resultText(result); !strings.Contains(got, "changed again since (summary)") || restored != nil {
+		t.Fatalf("result = %q, restored %v; want a refusal", got, restored)
+	}
+	if len(j.journal.entries("")) != 1 {
+		t.Fatal("a refused undo dropped the entry")
+	}
+
+	result, _, _ = j.UndoLastChange(context.Background(), nil, &UndoLastChangeParams{Force: true})
+	if got := resultText(result); !strings.HasPrefix(got, "Undid update-jira-issue") || restored["fields"]["summary"] != "Old" {
+		t.Errorf("forced: result = %q, restored %v; want summary Old", got, restored)
+	}
+	result, _, _ = j.UndoLastChange(context.Background(), nil, &UndoLastChangeParams{})
+	if got := resultText(result); !strings.HasPrefix(got, "Nothing to undo") {
+		t.Errorf("second undo: result = %q, want nothing to undo", got)
+	}
+}
@@ -0,0 +1,508 @@
+package jiramcp
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// Tools for Jira Service Management, which models support work as customer
+// requests in service desks on top of ordinary issues. They use the
+// /rest/servicedeskapi endpoints.
+
+type ListServiceDesksParams struct{}
+
+type ListRequestTypesParams struct {
+	ServiceDeskID string `json:"serviceDeskId"`
+	// Query filters request types by name.
+	Query string `json:"query,omitempty"`
+}
+
+type CreateCustomerRequestParams struct {
+	ServiceDeskID string `json:"serviceDeskId"`
+	RequestTypeID string `json:"requestTypeId"`
+	Summary       string `json:"summary"`
+	Description   string `json:"description,omitempty"`
+	// Fields sets further request type fields by field ID.
+	Fields map[string]interface{} `json:"fields,omitempty"`
+	// RaiseOnBehalfOf is the email or accountId of the customer the request is for.
+	RaiseOnBehalfOf string `json:"raiseOnBehalfOf,omitempty"`
+	DryRun          bool   `json:"dryRun,omitempty"`
+}
+
+type AddRequestCommentParams struct {
+	IssueKey string `json:"issueKey"`
+	Body     string `json:"body"`
+	// Public makes the comment visible to customers; comments are internal by default.
+	Public bool `json:"public,omitempty"`
+	// LiteralMentions keeps @name and @email text as typed instead of
+	// turning it into mentions that notify the users.
+	LiteralMentions bool `json:"literalMentions,omitempty"`
+	DryRun          bool `json:"dryRun,omitempty"`
+}
+
+type GetRequestSLAParams struct {
+	IssueKey string `json:"issueKey"`
+}
+
+type GetRequestCommentsParams struct {
+	IssueKey string `json:"issueKey"`
+	// SinceHours limits the comments to those of the last hours; 0 returns all.
+	SinceHours int `json:"sinceHours,omitempty"`
+}
+
+type ListQueuesParams struct {
+	ServiceDeskID string `json:"serviceDeskId"`
+}
+
+type GetQueueCommentsParams struct {
+	ServiceDeskID string `json:"serviceDeskId"`
+	QueueID       string `json:"queueId"`
+	// SinceHours is how far back comments count as new (default 24).
+	SinceHours int `json:"sinceHours,omitempty"`
+	// MaxIssues bounds how many requests of the queue are read (default 25, at most 50).
+	MaxIssues int `json:"maxIssues,omitempty"`
+}
+
+type serviceDeskPage[T any] struct {
+	Start      int  `json:"start"`
+	Limit      int  `json:"limit"`
+	IsLastPage bool `json:"isLastPage"`
+	Values     []T  `json:"values"`
+}
+
+type serviceDesk struct {
+	ID          string `json:"id"`
+	ProjectID   string `json:"projectId"`
+	ProjectName string `json:"projectName"`
+	ProjectKey  string `json:"projectKey"`
+}
+
+type requestType struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	IssueTypeID string `json:"issueTypeId"`
+}
+
+type customerRequestPayload struct {
+	ServiceDeskID      string                 `json:"serviceDeskId"`
+	RequestTypeID      string                 `json:"requestTypeId"`
+	RequestFieldValues map[string]interface{} `json:"requestFieldValues"`
+	RaiseOnBehalfOf    string                 `json:"raiseOnBehalfOf,omitempty"`
+}
+
+type customerRequest struct {
+	IssueID  string `json:"issueId"`
+	IssueKey string `json:"issueKey"`
+	Links    struct {
+		Web string `json:"web"`
+	} `json:"_links"`
+}
+
+type requestComment struct {
+	ID      string           `json:"id,omitempty"`
+	Body    string           `json:"body"`
+	Public  bool             `json:"public"`
+	Author  *serviceDeskUser `json:"author,omitempty"`
+	Created *serviceDeskDate `json:"created,omitempty"`
+}
+
+type serviceDeskUser struct {
+	AccountID    string `json:"accountId"`
+	DisplayName  string `json:"displayName"`
+	EmailAddress string `json:"emailAddress"`
+}
+
+type serviceDeskDate struct {
+	EpochMillis int64  `json:"epochMillis"`
+	Friendly    string `json:"friendly"`
+}
+
+type serviceDeskQueue struct {
+	ID         string `json:"id"`
+	Name       string `json:"name"`
+	IssueCount int    `json:"issueCount"`
+}
+
+type queueIssue struct {
+	Key    string `json:"key"`
+	Fields struct {
+		Summary string `json:"summary"`
+	} `json:"fields"`
+}
+
+// requestThread is a request with its new comments, ranked for triage.
+type requestThread struct {
+	issue    queueIssue
+	comments []requestComment
+	rank     int
+}
+
+type slaDuration struct {
+	Millis   int64  `json:"millis"`
+	Friendly string `json:"friendly"`
+}
+
+type slaCycle struct {
+	Breached      bool         `json:"breached"`
+	Paused        bool         `json:"paused"`
+	GoalDuration  slaDuration  `json:"goalDuration"`
+	ElapsedTime   slaDuration  `json:"elapsedTime"`
+	RemainingTime slaDuration  `json:"remainingTime"`
+	BreachTime    *slaDateTime `json:"breachTime"`
+	StopTime      *slaDateTime `json:"stopTime"`
+}
+
+type slaDateTime struct {
+	Friendly string `json:"friendly"`
+}
+
+type slaMetric struct {
+	Name            string     `json:"name"`
+	OngoingCycle    *slaCycle  `json:"ongoingCycle"`
+	CompletedCycles []slaCycle `json:"completedCycles"`
+}
+
+// serviceDeskValues pages through a service desk API collection.
+func serviceDeskValues[T any](ctx context.Context, j *JiraMCPServer, path string) ([]T, error) {
+	var values []T
+	sep := "?"
+	if strings.Contains(path, "?") {
+		sep = "&"
+	}
+	for start := 0; ; {
+		var page serviceDeskPage[T]
+		if _, err := j.jiraDo(ctx, "GET", fmt.Sprintf("%s%sstart=%d&limit=50", path, sep, start), nil, &page); err != nil {
+			return nil, err
+		}
+		values = append(values, page.Values...)
+		start += len(page.Values)
+		if page.IsLastPage || len(page.Values) == 0 {
+			return values, nil
+		}
+	}
+}
+
+// ListServiceDesks lists the service desks the user can access.
+func (j *JiraMCPServer) ListServiceDesks(ctx context.Context, req *mcp.CallToolRequest, params *ListServiceDesksParams) (*mcp.CallToolResult, any, error) {
+	desks, err := serviceDeskValues[serviceDesk](ctx, j, "rest/servicedeskapi/servicedesk")
+	if err != nil {
+		return textResult("Failed to list service desks (is Jira Service Management installed?): %v", err), nil, nil
+	}
+	if len(desks) == 0 {
+		return textResult("No service desks found"), nil, nil
+	}
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "%d service desk(s):\n", len(desks))
+	for _, d := range desks {
+		fmt.Fprintf(&sb, "- %s (id %s, project %s)\n", d.ProjectName, d.ID, d.ProjectKey)
+	}
+	return textResult("%s", sb.String()), nil, nil
+}
+
+// ListRequestTypes lists the request types customers can raise in a service desk.
+func (j *JiraMCPServer) ListRequestTypes(ctx context.Context, req *mcp.CallToolRequest, params *ListRequestTypesParams) (*mcp.CallToolResult, any, error) {
+	if _, err := strconv.Atoi(params.ServiceDeskID); err != nil {
+		return textResult("serviceDeskId must be numeric; use list-service-desks to find it"), nil, nil
+	}
+	path := fmt.Sprintf("rest/servicedeskapi/servicedesk/%s/requesttype", params.ServiceDeskID)
+	if params.Query != "" {
+		path += "?searchQuery=" + url.QueryEscape(params.Query)
+	}
+	types, err := serviceDeskValues[requestType](ctx, j, path)
+	if err != nil {
+		return textResult("Failed to list request types of service desk %s: %v", params.ServiceDeskID, err), nil, nil
+	}
+	if len(types) == 0 {
+		return textResult("Service desk %s has no matching request types", params.ServiceDeskID), nil, nil
+	}
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "Request types in service desk %s:\n", params.ServiceDeskID)
+	for _, t := range types {
+		fmt.Fprintf(&sb, "- %s (id %s)", t.Name, t.ID)
+		if t.Description != "" {
+			fmt.Fprintf(&sb, " — %s", normalizeWhitespace(t.Description))
+		}
+		sb.WriteString("\n")
+	}
+	return textResult("%s", sb.String()), nil, nil
+}
+
+// CreateCustomerRequest raises a customer request in a service desk.
+func (j *JiraMCPServer) CreateCustomerRequest(ctx context.Context, req *mcp.CallToolRequest, params *CreateCustomerRequestParams) (*mcp.CallToolResult, any, error) {
+	if strings.TrimSpace(params.Summary) == "" {
+		return textResult("summary is required"), nil, nil
+	}
+	payload := customerRequestPayload{
+		ServiceDeskID:      params.ServiceDeskID,
+		RequestTypeID:      params.RequestTypeID,
+		RequestFieldValues: map[string]interface{}{"summary": params.Summary},
+		RaiseOnBehalfOf:    params.RaiseOnBehalfOf,
+	}
+	if params.Description != "" {
+		payload.RequestFieldValues["description"] = params.Description
+	}
+	for id, v := range params.Fields {
+		payload.RequestFieldValues[id] = v
+	}
+
+	if j.dryRun(params.DryRun) {
+		var problems []string
+		path := fmt.Sprintf("rest/servicedeskapi/servicedesk/%s/requesttype/%s", url.PathEscape(params.ServiceDeskID), url.PathEscape(params.RequestTypeID))
+		if _, err := j.jiraDo(ctx, "GET", path, nil, &requestType{}); err != nil {
+			problems = append(problems, fmt.Sprintf("request type %s does not exist in service desk %s: %v", params.RequestTypeID, params.ServiceDeskID, err))
+		}
+		return dryRunResult("POST", "rest/servicedeskapi/request", payload, problems), nil, nil
+	}
+
+	var created customerRequest
+	if _, err := j.jiraDo(ctx, "POST", "rest/servicedeskapi/request", payload, &created); err != nil {
+		return textResult("Failed to create customer request: %v", err), nil, nil
+	}
+	logger(ctx).Info("Created customer request", "request", created.IssueKey)
+
+	return textResult("Created customer request %s: %s", created.IssueKey, created.Links.Web), nil, nil
+}
+
+// AddRequestComment comments on a customer request, internally or visible to
+// the customer.
+func (j *JiraMCPServer) AddRequestComment(ctx context.Context, req *mcp.CallToolRequest, params *AddRequestCommentParams) (*mcp.CallToolResult, any, error) {
+	if strings.TrimSpace(params.Body) == "" {
+		return textResult("Comment body is required"), nil, nil
+	}
+	path := fmt.Sprintf("rest/servicedeskapi/request/%s/comment", params.IssueKey)
+	body, mentioned := j.resolveMentions(ctx, params.Body, params.LiteralMentions)
+	payload := requestComment{Body: body, Public: params.Public}
+	if j.dryRun(params.DryRun) {
+		return dryRunResult("POST", path, payload, j.issueProblems(ctx, params.IssueKey)), nil, nil
+	}
+
+	var created requestComment
+	if _, err := j.jiraDo(ctx, "POST", path, payload, &created); err != nil {
+		return textResult("Failed to comment on request %s: %v", params.IssueKey, err), nil, nil
+	}
+	visibility := "internal"
+	if created.Public {
+		visibility = "public"
+	}
+	logger(ctx).Info("Added request comment", "visibility", visibility, "commentId", created.ID)
+
+	return textResult("Added %s comment %s to request %s%s", visibility, created.ID, params.IssueKey, mentioned.note()), nil, nil
+}
+
+// GetRequestSLA reports the SLA metrics of a customer request.
+func (j *JiraMCPServer) GetRequestSLA(ctx context.Context, req *mcp.CallToolRequest, params *GetRequestSLAParams) (*mcp.CallToolResult, any, error) {
+	metrics, err := serviceDeskValues[slaMetric](ctx, j, fmt.Sprintf("rest/servicedeskapi/request/%s/sla", params.IssueKey))
+	if err != nil {
+		return textResult("Failed to get SLAs of request %s: %v", params.IssueKey, err), nil, nil
+	}
+	if len(metrics) == 0 {
+		return textResult("Request %s has no SLAs", params.IssueKey), nil, nil
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "SLAs of %s:\n", params.IssueKey)
+	for _, m := range metrics {
+		switch {
+		case m.OngoingCycle != nil:
+			c := m.OngoingCycle
+			state := "running"
+			if c.Paused {
+				state = "paused"
+			}
+			if c.Breached {
+				state = "BREACHED"
+			}
+			fmt.Fprintf(&sb, "- %s: %s, %s remaining of %s", m.Name, state, c.RemainingTime.Friendly, c.GoalDuration.Friendly)
+			if c.BreachTime != nil && !c.Breached {
+				fmt.Fprintf(&sb, " (breaches %s)", c.BreachTime.Friendly)
+			}
+			sb.WriteString("\n")
+		case len(m.CompletedCycles) > 0:
+			c := m.CompletedCycles[len(m.CompletedCycles)-1]
+			result := "met"
+			if c.Breached {
+				result = "BREACHED"
+			}
+			fmt.Fprintf(&sb, "- %s: completed, %s (%s of %s)\n", m.Name, result, c.ElapsedTime.Friendly, c.GoalDuration.Friendly)
+		default:
+			fmt.Fprintf(&sb, "- %s: not started\n", m.Name)
+		}
+	}
+	return textResult("%s", sb.String()), nil, nil
+}
+
+// requestComments returns the comments of a customer request created since
+// the given time; a zero time returns all.
+func (j *JiraMCPServer) requestComments(ctx context.Context, issueKey string, since time.Time) ([]requestComment, error) {
+	comments, err := serviceDeskValues[requestComment](ctx, j, fmt.Sprintf("rest/servicedeskapi/request/%s/comment", issueKey))
+	if err != nil || since.IsZero() {
+		return comments, err
+	}
+	recent := comments[:0]
+	for _, c := range comments {
+		if c.Created != nil && c.Created.EpochMillis >= since.UnixMilli() {
+			recent = append(recent, c)
+		}
+	}
+	return recent, nil
+}
+
+// tagComments runs the comments of the threads through the configured
+// classifier. When it fails, comments are returned untagged with a note.
+func (j *JiraMCPServer) tagComments(ctx context.Context, threads []*requestThread) (map[string]commentTags, string) {
+	var input []classifierComment
+	for _, t := range threads {
+		for _, c := range t.comments {
+			author := ""
+			if c.Author != nil {
+				author = c.Author.DisplayName
+			}
+			input = append(input, classifierComment{ID: c.ID, IssueKey: t.issue.Key, Author: author, Public: c.Public, Body: c.Body})
+		}
+	}
+	tags, err := j.classifyComments(ctx, input)
+	if err != nil {
+		logger(ctx).Warn("Comment classifier failed", "error", err)
+		return nil, fmt.Sprintf("Comments are untagged: the classifier failed (%v).\n", err)
+	}
+	return tags, ""
+}
+
+// writeRequestComment renders a comment with its classifier tags.
+func writeRequestComment(sb *strings.Builder, c requestComment, tags map[string]commentTags) {
+	visibility := "internal"
+	if c.Public {
+		visibility = "public"
+	}
+	author, when := "unknown", ""
+	if c.Author != nil {
+		author = c.Author.DisplayName
+	}
+	if c.Created != nil {
+		when = c.Created.Friendly + " "
+	}
+	fmt.Fprintf(sb, "- %s%s (%s)", when, author, visibility)
+	if t := tags[c.ID].String(); t != "" {
+		fmt.Fprintf(sb, " %s", t)
+	}
+	fmt.Fprintf(sb, ": %s\n", normalizeWhitespace(c.Body))
+}
+
+// GetRequestComments returns the comments of a customer request, tagged with
+// urgency and sentiment when a classifier is configured.
+func (j *JiraMCPServer) GetRequestComments(ctx context.Context, req *mcp.CallToolRequest, params *GetRequestCommentsParams) (*mcp.CallToolResult, any, error) {
+	var since time.Time
+	if params.SinceHours > 0 {
+		since = time.Now().Add(-time.Duration(params.SinceHours) * time.Hour)
+	}
+	comments, err := j.requestComments(ctx, params.IssueKey, since)
+	if err != nil {
+		return textResult("Failed to get comments of request %s: %v", params.IssueKey, err), nil, nil
+	}
+	if len(comments) == 0 {
+		return textResult("Request %s has no matching comments", params.IssueKey), nil, nil
+	}
+
+	thread := &requestThread{issue: queueIssue{Key: params.IssueKey}, comments: comments}
+	tags, note := j.tagComments(ctx, []*requestThread{thread})
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "%d comment(s) on %s:\n", len(comments), params.IssueKey)
+	sb.WriteString(note)
+	for _, c := range comments {
+		writeRequestComment(&sb, c, tags)
+	}
+	return textResult("%s", sb.String()), nil, nil
+}
+
+// ListQueues lists the queues of a service desk with their issue counts.
+func (j *JiraMCPServer) ListQueues(ctx context.Context, req *mcp.CallToolRequest, params *ListQueuesParams) (*mcp.CallToolResult, any, error) {
+	if _, err := strconv.Atoi(params.ServiceDeskID); err != nil {
+		return textResult("serviceDeskId must be numeric; use list-service-desks to find it"), nil, nil
+	}
+	queues, err := serviceDeskValues[serviceDeskQueue](ctx, j, fmt.Sprintf("rest/servicedeskapi/servicedesk/%s/queue?includeCount=true", params.ServiceDeskID))
+	if err != nil {
+		return textResult("Failed to list queues of service desk %s: %v", params.ServiceDeskID, err), nil, nil
+	}
+	if len(queues) == 0 {
+		return textResult("Service desk %s has no queues", params.ServiceDeskID), nil, nil
+	}
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "Queues in service desk %s:\n", params.ServiceDeskID)
+	for _, q := range queues {
+		fmt.Fprintf(&sb, "- %s (id %s, %d issues)\n", q.Name, q.ID, q.IssueCount)
+	}
+	return textResult("%s", sb.String()), nil, nil
+}
+
+// GetQueueComments collects the new comments on the requests of a queue. When
+// a classifier is configured, comments are tagged and the requests ordered so
+// the most urgent and most negative threads come first.
+func (j *JiraMCPServer) GetQueueComments(ctx context.Context, req *mcp.CallToolRequest, params *GetQueueCommentsParams) (*mcp.CallToolResult, any, error) {
+	if _, err := strconv.Atoi(params.ServiceDeskID); err != nil {
+		return textResult("serviceDeskId must be numeric; use list-service-desks to find it"), nil, nil
+	}
+	sinceHours := params.SinceHours
+	if sinceHours <= 0 {
+		sinceHours = 24
+	}
+	maxIssues := params.MaxIssues
+	if maxIssues <= 0 {
+		maxIssues = 25
+	}
+	maxIssues = min(maxIssues, 50)
+	since := time.Now().Add(-time.Duration(sinceHours) * time.Hour)
+
+	var page serviceDeskPage[queueIssue]
+	path := fmt.Sprintf("rest/servicedeskapi/servicedesk/%s/queue/%s/issue?limit=%d", params.ServiceDeskID, url.PathEscape(params.QueueID), maxIssues)
+	if _, err := j.jiraDo(ctx, "GET", path, nil, &page); err != nil {
+		return textResult("Failed to read queue %s of service desk %s: %v", params.QueueID, params.ServiceDeskID, err), nil, nil
+	}
+
+	var threads []*requestThread
+	for _, issue := range page.Values {
+		comments, err := j.requestComments(ctx, issue.Key, since)
+		if err != nil {
+			return textResult("Failed to get comments of request %s: %v", issue.Key, err), nil, nil
+		}
+		if len(comments) > 0 {
+			threads = append(threads, &requestThread{issue: issue, comments: comments})
+		}
+	}
+	if len(threads) == 0 {
+		return textResult("No new comments in the last %d hours on the %d request(s) read from queue %s", sinceHours, len(page.Values), params.QueueID), nil, nil
+	}
+
+	tags, note := j.tagComments(ctx, threads)
+	for _, t := range threads {
+		t.rank = commentTags{}.rank()
+		for _, c := range t.comments {
+			if tag, ok := tags[c.ID]; ok {
+				t.rank = min(t.rank, tag.rank())
+			}
+		}
+	}
+	sort.SliceStable(threads, func(a, b int) bool { return threads[a].rank < threads[b].rank })
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "New comments in the last %d hours on %d request(s) of queue %s", sinceHours, len(threads), params.QueueID)
+	if tags != nil {
+		sb.WriteString(", most urgent first")
+	}
+	sb.WriteString(":\n")
+	sb.WriteString(note)
+	for _, t := range threads {
+		fmt.Fprintf(&sb, "\n%s: %s\n", t.issue.Key, t.issue.Fields.Summary)
+		for _, c := range t.comments {
+			writeRequestComment(&sb, c, tags)
+		}
+	}
+	return textResult("%s", sb.String()), nil, nil
+}
@@ -0,0 +1,64 @@
+package jiramcp
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// defaultLabelResults caps list-labels output when maxResults is not given.
+const defaultLabelResults = 50
+
+type ListLabelsParams struct {
+	// Prefix filters labels case-insensitively, for autocomplete-style suggestions.
+	Prefix     string `json:"prefix,omitempty"`
+	MaxResults int    `json:"maxResults,omitempty"`
+}
+
+type labelPage struct {
+	StartAt    int      `json:"startAt"`
+	MaxResults int      `json:"maxResults"`
+	Total      int      `json:"total"`
+	IsLast     bool     `json:"isLast"`
+	Values     []string `json:"values"`
+}
+
+// ListLabels pages through the labels API and returns the labels matching the
+// optional prefix.
+func (j *JiraMCPServer) ListLabels(ctx context.Context, req *mcp.CallToolRequest, params *ListLabelsParams) (*mcp.CallToolResult, any, error) {
+	maxResults := params.MaxResults
+	if maxResults <= 0 {
+		maxResults = defaultLabelResults
+	}
+	prefix := strings.ToLower(params.Prefix)
+
+	var labels []string
+	for startAt := 0; len(labels) < maxResults; {
+		var page labelPage
+		if _, err := j.jiraDo(ctx, "GET", fmt.Sprintf("rest/api/2/label?startAt=%d&maxResults=1000", startAt), nil, &page); err != nil {
+			return textResult("Failed to list labels: %v", err), nil, nil
+		}
+		for _, label := range page.Values {
+			if strings.HasPrefix(strings.ToLower(label), prefix) {
+				labels = append(labels, label)
+			}
+		}
+		startAt += len(page.Values)
+		if page.IsLast || len(page.Values) == 0 {
+			break
+		}
+	}
+	if len(labels) > maxResults {
+		labels = labels[:maxResults]
+	}
+
+	if len(labels) == 0 {
+		if prefix != "" {
+			return textResult("No labels found starting with %q", params.Prefix), nil, nil
+		}
+		return textResult("No labels found"), nil, nil
+	}
+	return textResult("%s", strings.Join(labels, "\n")), nil, nil
+}
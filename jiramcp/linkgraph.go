@@ -0,0 +1,189 @@
+package jiramcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/andygrunwald/go-jira"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// Link graph limits: depth and node count are capped so a densely linked
+// project cannot blow up the response.
+const (
+	defaultLinkGraphDepth = 2
+	maxLinkGraphDepth     = 5
+	defaultLinkGraphNodes = 50
+	maxLinkGraphNodes     = 200
+)
+
+type linkGraphNode struct {
+	Key       string `json:"key"`
+	Summary   string `json:"summary"`
+	Status    string `json:"status"`
+	Category  string `json:"statusCategory,omitempty"`
+	IssueType string `json:"issueType"`
+	Depth     int    `json:"depth"`
+}
+
+type linkGraphEdge struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+	// Type is the link type name (e.g. "Blocks"); Label is its outward
+	// description as read from From to To (e.g. "blocks").
+	Type  string `json:"type"`
+	Label string `json:"label"`
+}
+
+type linkGraph struct {
+	Root      string          `json:"root"`
+	Depth     int             `json:"depth"`
+	Nodes     []linkGraphNode `json:"nodes"`
+	Edges     []linkGraphEdge `json:"edges"`
+	Truncated bool            `json:"truncated"`
+}
+
+var linkGraphFields = []string{"summary", "status", "issuetype", "issuelinks", "subtasks", "parent"}
+
+// LinkGraph serves jira://issue/{key}/link-graph, a nodes/edges JSON document of
+// the issues reachable from key through issue links, subtasks, and parents.
+func (j *JiraMCPServer) LinkGraph(ctx context.Context, req *mcp.ReadResourceRequest) (*mcp.ReadResourceResult, error) {
+	rootKey, query, err := parseIssueResourceURI(req.Params.URI, "link-graph")
+	if err != nil {
+		return nil, err
+	}
+	depth, err := boundedIntParam(query.Get("depth"), defaultLinkGraphDepth, maxLinkGraphDepth)
+	if err != nil {
+		return nil, fmt.Errorf("invalid 'depth' parameter: %w", err)
+	}
+	maxNodes, err := boundedIntParam(query.Get("maxNodes"), defaultLinkGraphNodes, maxLinkGraphNodes)
+	if err != nil {
+		return nil, fmt.Errorf("invalid 'maxNodes' parameter: %w", err)
+	}
+
+	graph, err := j.buildLinkGraph(ctx, rootKey, depth, maxNodes)
+	if err != nil {
+		return nil, err
+	}
+	body, err := json.MarshalIndent(graph, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return &mcp.ReadResourceResult{
+		Contents: []*mcp.ResourceContents{
+			{URI: req.Params.URI, MIMEType: "application/json", Text: string(body)},
+		},
+	}, nil
+}
+
+// boundedIntParam parses an optional positive integer query parameter, applying
+// a default and an upper bound.
+func boundedIntParam(value string, def, upper int) (int, error) {
+	if value == "" {
+		return def, nil
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil || n < 1 {
+		return 0, fmt.Errorf("%q is not a positive integer", value)
+	}
+	return min(n, upper), nil
+}
+
+// buildLinkGraph walks the link graph breadth first, fetching each level with a
+// single search so a graph costs one request per depth level.
+func (j *JiraMCPServer) buildLinkGraph(ctx context.Context, rootKey string, depth, maxNodes int) (*linkGraph, error) {
+	graph := &linkGraph{Root: rootKey, Depth: depth}
+	seen := map[string]bool{rootKey: true}
+	edgeSeen := make(map[string]bool)
+	frontier := []string{rootKey}
+
+	addEdge := func(from, to, linkType, label string) {
+		id := from + "|" + to + "|" + linkType
+		if !edgeSeen[id] {
+			edgeSeen[id] = true
+			graph.Edges = append(graph.Edges, linkGraphEdge{From: from, To: to, Type: linkType, Label: label})
+		}
+	}
+
+	for level := 0; level <= depth && len(frontier) > 0; level++ {
+		issues, err := j.searchIssues(ctx, fmt.Sprintf("key IN (%s)", strings.Join(frontier, ",")), linkGraphFields, "", len(frontier))
+		if err != nil {
+			if level == 0 {
+				return nil, fmt.Errorf("failed to load %s: %w", rootKey, err)
+			}
+			// Linked issues may live in projects we cannot browse; keep what we have.
+			graph.Truncated = true
+			break
+		}
+
+		var next []string
+		visit := func(key string) {
+			if seen[key] {
+				return
+			}
+			if len(seen) >= maxNodes {
+				graph.Truncated = true
+				return
+			}
+			seen[key] = true
+			next = append(next, key)
+		}
+
+		for _, issue := range issues {
+			graph.Nodes = append(graph.Nodes, newLinkGraphNode(&issue, level))
+			if level == depth {
+				continue
+			}
+			f := issue.Fields
+			for _, link := range f.IssueLinks {
+				if link.OutwardIssue != nil {
+					addEdge(issue.Key, link.OutwardIssue.Key, link.Type.Name, link.Type.Outward)
+					visit(link.OutwardIssue.Key)
+				}
+				if link.InwardIssue != nil {
+					addEdge(link.InwardIssue.Key, issue.Key, link.Type.Name, link.Type.Outward)
+					visit(link.InwardIssue.Key)
+				}
+			}
+			for _, sub := range f.Subtasks {
+				addEdge(issue.Key, sub.Key, "Subtask", "has subtask")
+				visit(sub.Key)
+			}
+			if f.Parent != nil && f.Parent.Key != "" {
+				addEdge(f.Parent.Key, issue.Key, "Subtask", "has subtask")
+				visit(f.Parent.Key)
+			}
+		}
+		frontier = next
+	}
+
+	// Drop edges that point at issues we never loaded (beyond the limits).
+	loaded := make(map[string]bool, len(graph.Nodes))
+	for _, n := range graph.Nodes {
+		loaded[n.Key] = true
+	}
+	edges := graph.Edges[:0]
+	for _, e := range graph.Edges {
+		if loaded[e.From] && loaded[e.To] {
+			edges = append(edges, e)
+		}
+	}
+	graph.Edges = edges
+	return graph, nil
+}
+
+func newLinkGraphNode(issue *jira.Issue, depth int) linkGraphNode {
+	node := linkGraphNode{Key: issue.Key, Depth: depth}
+	if f := issue.Fields; f != nil {
+		node.Summary = f.Summary
+		node.IssueType = f.Type.Name
+		if f.Status != nil {
+			node.Status = f.Status.Name
+			node.Category = f.Status.StatusCategory.Key
+		}
+	}
+	return node
+}
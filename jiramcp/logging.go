@@ -0,0 +1,115 @@
+package jiramcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// redacted replaces credentials in log output.
+const redacted = "[REDACTED]"
+
+// sensitiveKeys are attribute keys whose values are never logged.
+var sensitiveKeys = []string{"token", "password", "secret", "authorization", "apikey", "api_key", "dsn"}
+
+type loggerKey struct{}
+
+// SetupLogging installs the default slog logger. Logs always go to stderr:
+// in stdio mode stdout carries the MCP stream, and anything else written
+// there corrupts it. Values of sensitive attributes and every occurrence of
+// the configured secrets are redacted, whatever the attribute.
+func SetupLogging(w io.Writer, level, format string, secrets []string) error {
+	var lvl slog.Level
+	if err := lvl.UnmarshalText([]byte(level)); err != nil {
+		return fmt.Errorf("invalid log level %q: use debug, info, warn, or error", level)
+	}
+	var nonEmpty []string
+	for _, s := range secrets {
+		// Very short values would redact unrelated text.
+		if len(s) >= 4 {
+			nonEmpty = append(nonEmpty, s)
+		}
+	}
+	opts := &slog.HandlerOptions{Level: lvl, ReplaceAttr: redactAttr(nonEmpty)}
+
+	var h slog.Handler
+	switch strings.ToLower(format) {
+	case "json":
+		h = slog.NewJSONHandler(w, opts)
+	case "text", "":
+		h = slog.NewTextHandler(w, opts)
+	default:
+		return fmt.Errorf("invalid log format %q: use text or json", format)
+	}
+	slog.SetDefault(slog.New(h))
+	return nil
+}
+
+// redactAttr returns a ReplaceAttr function that hides sensitive attributes
+// and scrubs secrets from all other values, including errors.
+func redactAttr(secrets []string) func([]string, slog.Attr) slog.Attr {
+	scrub := func(s string) string {
+		for _, secret := range secrets {
+			s = strings.ReplaceAll(s, secret, redacted)
+		}
+		return s
+	}
+	return func(groups []string, a slog.Attr) slog.Attr {
+		key := strings.ToLower(a.Key)
+		for _, k := range sensitiveKeys {
+			if strings.Contains(key, k) {
+				return slog.String(a.Key, redacted)
+			}
+		}
+		switch a.Value.Kind() {
+		case slog.KindString:
+			return slog.String(a.Key, scrub(a.Value.String()))
+		case slog.KindAny:
+			if err, ok := a.Value.Any().(error); ok {
+				return slog.String(a.Key, scrub(err.Error()))
+			}
+		}
+		return a
+	}
+}
+
+// logger returns the request-scoped logger stored in ctx by
+// loggingMiddleware, or the default logger.
+func logger(ctx context.Context) *slog.Logger {
+	if l, ok := ctx.Value(loggerKey{}).(*slog.Logger); ok {
+		return l
+	}
+	return slog.Default()
+}
+
+// loggingMiddleware attaches a logger carrying the session, method, and, for
+// tool calls, the tool name and issue key, so handler logs can be correlated.
+func (j *JiraMCPServer) loggingMiddleware(next mcp.MethodHandler) mcp.MethodHandler {
+	return func(ctx context.Context, method string, req mcp.Request) (mcp.Result, error) {
+		l := slog.Default().With("method", method)
+		if s := req.GetSession(); s != nil && s.ID() != "" {
+			l = l.With("session", s.ID())
+		}
+		if params, ok := req.GetParams().(*mcp.CallToolParamsRaw); ok {
+			l = l.With("tool", params.Name)
+			var args struct {
+				IssueKey string `json:"issueKey"`
+			}
+			if json.Unmarshal(params.Arguments, &args) == nil && args.IssueKey != "" {
+				l = l.With("issueKey", strings.ToUpper(args.IssueKey))
+			}
+		}
+		ctx = context.WithValue(ctx, loggerKey{}, l)
+		l.Debug("Handling request")
+		result, err := next(ctx, method, req)
+		if err != nil {
+			l.Warn("Request failed", "error", err)
+		}
+		return result, err
+	}
+}
@@ -0,0 +1,149 @@
+package jiramcp
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/andygrunwald/go-jira"
+)
+
+// maxMentionLookups bounds the user searches one text may cause.
+const maxMentionLookups = 20
+
+// mentionPattern matches @"Display Name", @email, and @username or
+// @accountId, which on Cloud may contain a colon. A mention must not follow a
+// letter or digit, so email addresses in running text are left alone.
+var mentionPattern = regexp.MustCompile(`(^|[^\p{L}\p{N}_~@])@(?:"([^"\n]+)"|([\p{L}\p{N}._%+-]+@[\p{L}\p{N}.-]+\.\p{L}{2,})|([\p{L}\p{N}][\p{L}\p{N}._:-]*))`)
+
+// mentionSkipped matches the parts of wiki markup where @ is never a
+// mention: code and noformat blocks, monospaced text, and links.
+var mentionSkipped = regexp.MustCompile(`(?s)\{code(?::[^}]*)?\}.*?\{code\}|\{noformat(?::[^}]*)?\}.*?\{noformat\}|\{\{.*?\}\}|\[[^\]\n]*\]`)
+
+// mentions records what resolveMentions did with the mentions of a text.
+type mentions struct {
+	resolved   []string
+	unresolved []string
+}
+
+// note summarizes the mentions for a tool result, or returns "" when the
+// text had none.
+func (m *mentions) note() string {
+	var parts []string
+	if len(m.resolved) > 0 {
+		parts = append(parts, "Mentioned "+strings.Join(m.resolved, ", ")+".")
+	}
+	if len(m.unresolved) > 0 {
+		parts = append(parts, "Left as plain text: "+strings.Join(m.unresolved, "; ")+".")
+	}
+	if len(parts) == 0 {
+		return ""
+	}
+	return "\n" + strings.Join(parts, " ")
+}
+
+// resolveMentions rewrites the @-mentions of a wiki markup text, such as a
+// comment or description, to Jira's mention markup so that the users are
+// notified: [~accountid:ID] on Jira Cloud and [~username] on Server and Data
+// Center. A quoted name, written @"Jane Doe", is resolved as find-jira-user
+// would, by email, exact name, or the only search result; a bare @word must
+// be exactly a user's username, email, or account ID, since a word that
+// happens to follow an @ should not notify whoever Jira's search guesses.
+// Ambiguous and unknown mentions stay as they are and are reported. Nothing
+// is changed when literal is set.
+func (j *JiraMCPServer) resolveMentions(ctx context.Context, text string, literal bool) (string, *mentions) {
+	m := &mentions{}
+	if literal || !strings.Contains(text, "@") {
+		return text, m
+	}
+	markup := make(map[string]string)
+	lookups := 0
+	resolve := func(query string, quoted bool) string {
+		key := strings.ToLower(query)
+		if quoted {
+			key = `"` + key
+		}
+		if done, ok := markup[key]; ok {
+			return done
+		}
+		result := ""
+		if lookups < maxMentionLookups {
+			lookups++
+			find := j.findMentionedUser
+			if quoted {
+				find = j.findJiraUser
+			}
+			user, err := find(ctx, query)
+			switch {
+			case err != nil:
+				m.unresolved = append(m.unresolved, fmt.Sprintf("@%s (%v)", query, err))
+			case j.userID(user) == "":
+				m.unresolved = append(m.unresolved, fmt.Sprintf("@%s (no user ID)", query))
+			case j.isCloud():
+				result = "[~accountid:" + user.AccountID + "]"
+				m.resolved = append(m.resolved, user.DisplayName)
+			default:
+				result = "[~" + user.Name + "]"
+				m.resolved = append(m.resolved, user.DisplayName)
+			}
+		} else {
+			m.unresolved = append(m.unresolved, fmt.Sprintf("@%s (more than %d mentions)", query, maxMentionLookups))
+		}
+		markup[key] = result
+		return result
+	}
+	rewrite := func(s string) string {
+		return mentionPattern.ReplaceAllStringFunc(s, func(match string) string {
+			sub := mentionPattern.FindStringSubmatch(match)
+			query := sub[2] + sub[3] + sub[4]
+			trailing := ""
+			if sub[4] != "" {
+				// A sentence or clause may end right after a username.
+				query = strings.TrimRight(query, ".:-")
+				trailing = sub[4][len(query):]
+			}
+			if user := resolve(query, sub[2] != ""); user != "" {
+				return sub[1] + user + trailing
+			}
+			return match
+		})
+	}
+
+	var sb strings.Builder
+	last := 0
+	for _, loc := range mentionSkipped.FindAllStringIndex(text, -1) {
+		sb.WriteString(rewrite(text[last:loc[0]]))
+		sb.WriteString(text[loc[0]:loc[1]])
+		last = loc[1]
+	}
+	sb.WriteString(rewrite(text[last:]))
+	return sb.String(), m
+}
+
+// findMentionedUser resolves a bare @word to the one user whose username,
+// email, or account ID it is. Unlike findJiraUser, it never settles for a
+// partial match, even the only one.
+func (j *JiraMCPServer) findMentionedUser(ctx context.Context, query string) (*jira.User, error) {
+	users, err := j.searchUsers(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("error searching for user '%s': %w", query, err)
+	}
+	var matches []jira.User
+	for _, u := range users {
+		if strings.EqualFold(u.Name, query) || strings.EqualFold(u.EmailAddress, query) || (u.AccountID != "" && u.AccountID == query) {
+			matches = append(matches, u)
+		}
+	}
+	switch len(matches) {
+	case 0:
+		return nil, fmt.Errorf("no user has the username, email, or account ID '%s'; quote display names as @\"Jane Doe\"", query)
+	case 1:
+		return &matches[0], nil
+	}
+	names := make([]string, 0, len(matches))
+	for _, u := range matches {
+		names = append(names, describeUser(u))
+	}
+	return nil, fmt.Errorf("%d users match '%s': %s", len(matches), query, strings.Join(names, "; "))
+}
@@ -0,0 +1,88 @@
+package jiramcp
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/andygrunwald/go-jira"
+)
+
+// mentionUsers is the user directory of the mention tests.
+var mentionUsers = []jira.User{
+	{Name: "jdoe", AccountID: "557058:f58131cb", DisplayName: "Jane Doe", EmailAddress: "jane@corp.example.com"},
+	{Name: "jdoe2", AccountID: "557058:a1b2c3d4", DisplayName: "Jane Doe", EmailAddress: "jane.doe@corp.example.com"},
+	{Name: "bob", AccountID: "557058:0b0b0b0b", DisplayName: "Bob Stone", EmailAddress: "bob@corp.example.com"},
+	{Name: "bobby", AccountID: "557058:0b0b0b0c", DisplayName: "Bobby Tables", EmailAddress: "bobby@corp.example.com"},
+}
+
+// searchMentionUsers finds users the way Jira's user search does, by any
+// part of their username, display name, or email.
+func searchMentionUsers(query string) []jira.User {
+	query = strings.ToLower(query)
+	var users []jira.User
+	for _, u := range mentionUsers {
+		if strings.Contains(strings.ToLower(u.Name+" "+u.DisplayName+" "+u.EmailAddress+" "+u.AccountID), query) {
+			users = append(users, u)
+		}
+	}
+	return users
+}
+
+func TestResolveMentionsOnServer(t *testing.T) {
+	routes := jiraRoutes{
+		"GET rest/api/2/user/search": func(r *http.Request) (int, interface{}) {
+			return http.StatusOK, searchMentionUsers(r.URL.Query().Get("username"))
+		},
+	}
+	j := newTestServer(t, &MockJiraService{DoFunc: routes.do}, nil)
+
+	tests := []struct {
+		name, in, want string
+		unresolved     int
+	}{
+		{"username", "Hi @jdoe, please look.", "Hi [~jdoe], please look.", 0},
+		{"username ending a sentence", "Ask @bob.", "Ask [~bob].", 0},
+		{"email", "Ping @jane@corp.example.com.", "Ping [~jdoe].", 0},
+		{"quoted display name", `Thanks @"Bob Stone"!`, "Thanks [~bob]!", 0},
+		// A bare word must name a user exactly, even when the search finds
+		// only one user containing it.
+		{"partial username", "@bobb can you check", "@bobb can you check", 1},
+		{"several partial matches", "@bo can you check", "@bo can you check", 1},
+		{"ambiguous display name", `@"Jane Doe" and @jdoe2`, `@"Jane Doe" and [~jdoe2]`, 1},
+		{"email in running text", "mail jane@corp.example.com", "mail jane@corp.example.com", 0},
+		{"code and links", "{code}@jdoe{code} {{@jdoe}} [profile|https://x.example.com/@jdoe]", "{code}@jdoe{code} {{@jdoe}} [profile|https://x.example.com/@jdoe]", 0},
+		{"non-ASCII text around", "Grüße @bob — danke", "Grüße [~bob] — danke", 0},
+	}
+	for _, tt := range tests {
+		got, m := j.resolveMentions(context.Background(), tt.in, false)
+		if got != tt.want || len(m.unresolved) != tt.unresolved {
+			t.Errorf("%s: resolveMentions(%q) = %q with unresolved %q; want %q with %d unresolved", tt.name, tt.in, got, m.unresolved, tt.want, tt.unresolved)
+		}
+	}
+
+	if got, _ := j.resolveMentions(context.Background(), "Hi @jdoe", true); got != "Hi @jdoe" {
+		t.Errorf("literal text was rewritten to %q", got)
+	}
+}
+
+func TestResolveMentionsOnCloud(t *testing.T) {
+	mock := &MockJiraService{
+		DoFunc: jiraRoutes{}.do,
+		FindUsersFunc: func(_ context.Context, query string) ([]jira.User, *jira.Response, error) {
+			return searchMentionUsers(query), nil, nil
+		},
+	}
+	j := newTestServer(t, mock, func(c *JiraConfig) {
+		c.Deployment = DeploymentCloud
+	})
+
+	got, m := j.resolveMentions(context.Background(), "cc @557058:f58131cb and @bob@corp.example.com", false)
+	if want := "cc [~accountid:557058:f58131cb] and [~accountid:557058:0b0b0b0b]"; got != want {
+		t.Errorf("resolveMentions = %q, want %q", got, want)
+	}
+	if want := "\nMentioned Jane Doe, Bob Stone."; m.note() != want {
+		t.Errorf("note = %q, want %q", m.note(), want)
+	}
+}
@@ -0,0 +1,210 @@
+package jiramcp
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/andygrunwald/go-jira"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// priorityAliases maps shorthand that agents often use for priorities to the
+// names of Jira's default priorities. An alias is only suggested when the
+// instance has a priority of that name.
+var priorityAliases = map[string]string{
+	"p0": "Highest", "p1": "Highest", "blocker": "Highest", "critical": "Highest", "urgent": "Highest",
+	"p2": "High", "major": "High",
+	"p3": "Medium", "normal": "Medium",
+	"p4": "Low", "minor": "Low",
+	"p5": "Lowest", "trivial": "Lowest",
+}
+
+// issueTypeAliases does the same for issue types.
+var issueTypeAliases = map[string]string{
+	"defect":      "Bug",
+	"feature":     "New Feature",
+	"enhancement": "Improvement",
+	"user story":  "Story",
+	"ticket":      "Task",
+	"todo":        "Task",
+	"sub-task":    "Subtask",
+	"subtask":     "Sub-task",
+}
+
+type ListIssueTypesParams struct {
+	// ProjectKey limits the list to the issue types available in a project.
+	ProjectKey string `json:"projectKey,omitempty"`
+}
+
+type ListStatusesParams struct {
+	// ProjectKey lists the statuses of a project's workflows, by issue type.
+	ProjectKey string `json:"projectKey,omitempty"`
+}
+
+// projectStatuses is an issue type's statuses in a project's workflows.
+type projectStatuses struct {
+	Name     string        `json:"name"`
+	Subtask  bool          `json:"subtask"`
+	Statuses []jira.Status `json:"statuses"`
+}
+
+// didYouMean returns the name in names that input most likely meant: an alias
+// of it, a name it contains or that contains it, or a name within a few typos.
+// It returns "" when nothing is close.
+func didYouMean(input string, names []string, aliases map[string]string) string {
+	in := strings.ToLower(strings.TrimSpace(input))
+	if alias, ok := aliases[in]; ok {
+		for _, name := range names {
+			if strings.EqualFold(name, alias) {
+				return name
+			}
+		}
+	}
+
+	best := ""
+	for _, name := range names {
+		n := strings.ToLower(name)
+		if (strings.Contains(in, n) || strings.Contains(n, in)) && len(name) > len(best) {
+			best = name
+		}
+	}
+	if best != "" {
+		return best
+	}
+
+	bestDistance := len(in)/3 + 1
+	for _, name := range names {
+		if d := levenshtein(in, strings.ToLower(name)); d <= bestDistance && (best == "" || d < bestDistance) {
+			best, bestDistance = name, d
+		}
+	}
+	return best
+}
+
+// levenshtein returns the edit distance between a and b.
+func levenshtein(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	for i := range prev {
+		prev[i] = i
+	}
+	for i := 1; i <= len(ra); i++ {
+		cur := make([]int, len(rb)+1)
+		cur[0] = i
+		for k := 1; k <= len(rb); k++ {
+			cost := 1
+			if ra[i-1] == rb[k-1] {
+				cost = 0
+			}
+			cur[k] = min(prev[k]+1, cur[k-1]+1, prev[k-1]+cost)
+		}
+		prev = cur
+	}
+	return prev[len(rb)]
+}
+
+// unknownNameProblem describes a name that is not among the available ones,
+// suggesting the closest match.
+func unknownNameProblem(what, input, scope string, names []string, aliases map[string]string) string {
+	problem := fmt.Sprintf("%s %q %s", what, input, scope)
+	if guess := didYouMean(input, names, aliases); guess != "" {
+		problem += fmt.Sprintf(". Did you mean %q?", guess)
+	}
+	return problem + fmt.Sprintf(" (available: %s)", strings.Join(names, ", "))
+}
+
+// issueTypeProblem describes why an issue type cannot be used in project, or
+// returns "" when it can.
+func issueTypeProblem(project *jira.Project, issueType string) string {
+	names := make([]string, 0, len(project.IssueTypes))
+	for _, t := range project.IssueTypes {
+		if strings.EqualFold(t.Name, issueType) {
+			return ""
+		}
+		names = append(names, t.Name)
+	}
+	return unknownNameProblem("issue type", issueType, "is not valid in "+project.Key, names, issueTypeAliases)
+}
+
+// issueTypes returns every issue type of the instance.
+func (j *JiraMCPServer) issueTypes(ctx context.Context) ([]jira.IssueType, error) {
+	return cached(ctx, j.cache, "metadata", "issuetypes", func() ([]jira.IssueType, error) {
+		var types []jira.IssueType
+		_, err := j.jiraDo(ctx, "GET", "rest/api/2/issuetype", nil, &types)
+		return types, err
+	})
+}
+
+// ListIssueTypes lists the issue types of the instance or of a project.
+func (j *JiraMCPServer) ListIssueTypes(ctx context.Context, req *mcp.CallToolRequest, params *ListIssueTypesParams) (*mcp.CallToolResult, any, error) {
+	var types []jira.IssueType
+	header := "Issue types:"
+	if params.ProjectKey != "" {
+		projectKey := strings.ToUpper(params.ProjectKey)
+		project, err := j.getProject(ctx, projectKey)
+		if err != nil {
+			return textResult("Failed to get project %s: %v", projectKey, err), nil, nil
+		}
+		types = project.IssueTypes
+		header = fmt.Sprintf("Issue types available in %s:", projectKey)
+	} else {
+		var err error
+		if types, err = j.issueTypes(ctx); err != nil {
+			return textResult("Failed to list issue types: %v", err), nil, nil
+		}
+	}
+
+	var sb strings.Builder
+	sb.WriteString(header + "\n")
+	for _, t := range types {
+		fmt.Fprintf(&sb, "- %s (id %s)", t.Name, t.ID)
+		if t.Subtask {
+			sb.WriteString(" [sub-task]")
+		}
+		if t.Description != "" {
+			fmt.Fprintf(&sb, " — %s", t.Description)
+		}
+		sb.WriteString("\n")
+	}
+	return textResult("%s", sb.String()), nil, nil
+}
+
+// ListStatuses lists the statuses of the instance with their categories, or
+// the statuses of a project's workflows by issue type.
+func (j *JiraMCPServer) ListStatuses(ctx context.Context, req *mcp.CallToolRequest, params *ListStatusesParams) (*mcp.CallToolResult, any, error) {
+	var sb strings.Builder
+	if params.ProjectKey != "" {
+		projectKey := strings.ToUpper(params.ProjectKey)
+		byType, err := cached(ctx, j.cache, "metadata", "statuses:"+projectKey, func() ([]projectStatuses, error) {
+			var byType []projectStatuses
+			_, err := j.jiraDo(ctx, "GET", fmt.Sprintf("rest/api/2/project/%s/statuses", projectKey), nil, &byType)
+			return byType, err
+		})
+		if err != nil {
+			return textResult("Failed to list statuses of %s: %v", projectKey, err), nil, nil
+		}
+		fmt.Fprintf(&sb, "Statuses in %s by issue type:\n", projectKey)
+		for _, t := range byType {
+			names := make([]string, 0, len(t.Statuses))
+			for _, s := range t.Statuses {
+				names = append(names, fmt.Sprintf("%s (%s)", s.Name, s.StatusCategory.Name))
+			}
+			fmt.Fprintf(&sb, "- %s: %s\n", t.Name, strings.Join(names, ", "))
+		}
+		return textResult("%s", sb.String()), nil, nil
+	}
+
+	statuses, err := cached(ctx, j.cache, "metadata", "statuses", func() ([]jira.Status, error) {
+		statuses, _, err := j.client(ctx).ListStatuses(ctx)
+		return statuses, err
+	})
+	if err != nil {
+		return textResult("Failed to list statuses: %v", err), nil, nil
+	}
+	sb.WriteString("Statuses:\n")
+	for _, s := range statuses {
+		fmt.Fprintf(&sb, "- %s (id %s, category %s)\n", s.Name, s.ID, s.StatusCategory.Name)
+	}
+	return textResult("%s", sb.String()), nil, nil
+}
@@ -0,0 +1,165 @@
+package jiramcp
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// Retries of idempotent Jira requests that were rate limited or hit a
+// temporarily unavailable instance.
+const (
+	maxJiraRetries   = 3
+	maxJiraRetryWait = 30 * time.Second
+)
+
+// metricsRegistry holds the server's metrics; it is served at /metrics when
+// JIRA_MCP_METRICS is enabled.
+var metricsRegistry = prometheus.NewRegistry()
+
+var (
+	toolCalls = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "jira_mcp_tool_calls_total",
+		Help: "Tool invocations by tool name and outcome (ok, tool_error, error).",
+	}, []string{"tool", "outcome"})
+	toolDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "jira_mcp_tool_call_duration_seconds",
+		Help:    "Duration of tool calls by tool name.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"tool"})
+	jiraRequestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "jira_mcp_jira_request_duration_seconds",
+		Help:    "Latency of Jira API requests by HTTP method and status code.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"method", "code"})
+	jiraRetries = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "jira_mcp_jira_retries_total",
+		Help: "Jira API requests retried, by the status code that caused the retry.",
+	}, []string{"code"})
+	rateLimitHits = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "jira_mcp_rate_limit_hits_total",
+		Help: "Requests refused by a rate limit: jira for HTTP 429 from Jira, budget for requests delayed by JIRA_MCP_JIRA_RATE_LIMIT, mutations for the per-client mutation limit.",
+	}, []string{"limiter"})
+	cacheRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "jira_mcp_cache_requests_total",
+		Help: "Cache lookups by cache and result (hit or miss).",
+	}, []string{"cache", "result"})
+)
+
+func init() {
+	metricsRegistry.MustRegister(
+		toolCalls, toolDuration, jiraRequestDuration, jiraRetries, rateLimitHits, cacheRequests,
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+	)
+}
+
+// metricsHandler serves the metrics in the Prometheus exposition format.
+func metricsHandler() http.Handler {
+	return promhttp.HandlerFor(metricsRegistry, promhttp.HandlerOpts{})
+}
+
+// recordCache counts a cache lookup.
+func recordCache(cache string, hit bool) {
+	result := "miss"
+	if hit {
+		result = "hit"
+	}
+	cacheRequests.WithLabelValues(cache, result).Inc()
+}
+
+// metricsMiddleware counts tool calls and measures their duration, for the
+// Prometheus metrics and the usage report.
+func (j *JiraMCPServer) metricsMiddleware(next mcp.MethodHandler) mcp.MethodHandler {
+	return func(ctx context.Context, method string, req mcp.Request) (mcp.Result, error) {
+		params, ok := req.GetParams().(*mcp.CallToolParamsRaw)
+		if !ok {
+			return next(ctx, method, req)
+		}
+		start := time.Now()
+		result, err := next(ctx, method, req)
+		outcome := "ok"
+		if r, ok := result.(*mcp.CallToolResult); err != nil {
+			outcome = "error"
+		} else if ok && r.IsError {
+			outcome = "tool_error"
+		}
+		toolCalls.WithLabelValues(params.Name, outcome).Inc()
+		toolDuration.WithLabelValues(params.Name).Observe(time.Since(start).Seconds())
+		j.recordUsage(params.Name, result, err, time.Since(start))
+		return result, err
+	}
+}
+
+// instrumentedTransport measures Jira API requests and retries idempotent
+// ones that were rate limited (429) or found Jira unavailable (503), waiting
+// as long as Retry-After asks. With a limiter, every attempt waits for the
+// shared request budget first.
+type instrumentedTransport struct {
+	base    http.RoundTripper
+	limiter Limiter
+}
+
+func (t *instrumentedTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	for attempt := 0; ; attempt++ {
+		if t.limiter != nil {
+			waited, err := t.limiter.Wait(req.Context())
+			if waited {
+				rateLimitHits.WithLabelValues("budget").Inc()
+			}
+			if err != nil {
+				return nil, err
+			}
+		}
+		start := time.Now()
+		resp, err := t.base.RoundTrip(req)
+		code := "error"
+		if err == nil {
+			code = strconv.Itoa(resp.StatusCode)
+		}
+		jiraRequestDuration.WithLabelValues(req.Method, code).Observe(time.Since(start).Seconds())
+		if err != nil {
+			return resp, err
+		}
+		if resp.StatusCode == http.StatusTooManyRequests {
+			rateLimitHits.WithLabelValues("jira").Inc()
+		}
+		if resp.StatusCode != http.StatusTooManyRequests && resp.StatusCode != http.StatusServiceUnavailable {
+			return resp, nil
+		}
+		if attempt >= maxJiraRetries || (req.Method != http.MethodGet && req.Method != http.MethodHead) {
+			return resp, nil
+		}
+		wait := retryAfter(resp.Header.Get("Retry-After"), attempt)
+		if wait > maxJiraRetryWait {
+			return resp, nil
+		}
+		io.Copy(io.Discard, resp.Body)
+		resp.Body.Close()
+		jiraRetries.WithLabelValues(code).Inc()
+		select {
+		case <-req.Context().Done():
+			return nil, req.Context().Err()
+		case <-time.After(wait):
+		}
+	}
+}
+
+// retryAfter returns the wait requested by a Retry-After header, or an
+// exponential backoff starting at one second when there is none.
+func retryAfter(header string, attempt int) time.Duration {
+	if secs, err := strconv.Atoi(header); err == nil && secs >= 0 {
+		return time.Duration(secs) * time.Second
+	}
+	if t, err := http.ParseTime(header); err == nil {
+		return time.Until(t)
+	}
+	return time.Second << attempt
+}
@@ -0,0 +1,193 @@
+package jiramcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/andygrunwald/go-jira"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// defaultMigrationAttachmentMB is the default size limit of attachments
+// copied by migrate-issue.
+const defaultMigrationAttachmentMB = 10
+
+// migratedFields are copied by migrate-issue. Everything is referenced by
+// name or value, since IDs differ between sites.
+var migratedFields = []string{"summary", "description", "environment", "labels", "duedate"}
+
+type MigrateIssueParams struct {
+	IssueKey string `json:"issueKey"`
+	// SourceSite is the site the issue is on; defaults to the server's own.
+	SourceSite string `json:"sourceSite,omitempty"`
+	// TargetSite names a site of JIRA_MCP_SITES_FILE, or "default".
+	TargetSite       string `json:"targetSite"`
+	TargetProjectKey string `json:"targetProjectKey"`
+	// IssueType overrides the issue type, for when the target project lacks
+	// the source's.
+	IssueType string `json:"issueType,omitempty"`
+	// MaxAttachmentMB skips larger attachments (default 10; -1 skips all).
+	MaxAttachmentMB int  `json:"maxAttachmentMB,omitempty"`
+	DryRun          bool `json:"dryRun,omitempty"`
+}
+
+type migratedComment struct {
+	Author  jira.User `json:"author"`
+	Created string    `json:"created"`
+	Body    string    `json:"body"`
+}
+
+// migrationFields builds the create payload of the copy of issue.
+func migrationFields(issue *rawIssue, targetProject, issueType string) map[string]interface{} {
+	fields := map[string]interface{}{"project": map[string]string{"key": targetProject}}
+	for _, id := range migratedFields {
+		if raw, ok := issue.Fields[id]; ok && string(raw) != "null" {
+			var v interface{}
+			json.Unmarshal(raw, &v)
+			fields[id] = v
+		}
+	}
+	for _, id := range []string{"priority", "issuetype"} {
+		var ref struct {
+			Name string `json:"name"`
+		}
+		if json.Unmarshal(issue.Fields[id], &ref) == nil && ref.Name != "" {
+			fields[id] = map[string]string{"name": ref.Name}
+		}
+	}
+	if issueType != "" {
+		fields["issuetype"] = map[string]string{"name": issueType}
+	}
+	if raw, ok := issue.Fields["components"]; ok && string(raw) != "null" {
+		if refs := nameReferences(raw); len(refs) > 0 {
+			fields["components"] = refs
+		}
+	}
+	return fields
+}
+
+// breadcrumb leaves a remote link and a comment on an issue pointing to its
+// counterpart on the other site.
+func (j *JiraMCPServer) breadcrumb(ctx context.Context, issueKey, title, url, comment string) error {
+	link := &jira.RemoteLink{GlobalID: url, Object: &jira.RemoteLinkObject{URL: url, Title: title}}
+	if _, err := j.jiraDo(ctx, "POST", fmt.Sprintf("rest/api/2/issue/%s/remotelink", issueKey), link, nil); err != nil {
+		return err
+	}
+	_, err := j.jiraDo(ctx, "POST", fmt.Sprintf("rest/api/2/issue/%s/comment", issueKey), map[string]string{"body": comment}, nil)
+	return err
+}
+
+// MigrateIssue copies an issue to a project on another Jira site, with its
+// comments and attachments, and links the two copies to each other.
+func (j *JiraMCPServer) MigrateIssue(ctx context.Context, req *mcp.CallToolRequest, params *MigrateIssueParams) (*mcp.CallToolResult, any, error) {
+	issueKey := strings.ToUpper(params.IssueKey)
+	targetProject := strings.ToUpper(params.TargetProjectKey)
+	if params.TargetSite == "" || targetProject == "" {
+		return textResult("targetSite and targetProjectKey are required (sites: %s)", strings.Join(j.siteNames(), ", ")), nil, nil
+	}
+	sourceCtx, sourceURL, err := j.onSite(ctx, params.SourceSite)
+	if err != nil {
+		return textResult("%v", err), nil, nil
+	}
+	targetCtx, targetURL, err := j.onSite(ctx, params.TargetSite)
+	if err != nil {
+		return textResult("%v", err), nil, nil
+	}
+	if sourceURL == targetURL {
+		return textResult("Source and target are the same site; use clone-jira-issue to copy within a site"), nil, nil
+	}
+	maxBytes := int64(params.MaxAttachmentMB)
+	if maxBytes == 0 {
+		maxBytes = defaultMigrationAttachmentMB
+	}
+	maxBytes *= 1 << 20
+
+	var issue rawIssue
+	if _, err := j.jiraDo(sourceCtx, "GET", fmt.Sprintf("rest/api/2/issue/%s?fields=*all", issueKey), nil, &issue); err != nil {
+		return textResult("Failed to get JIRA issue %s: %v", issueKey, err), nil, nil
+	}
+	fields := migrationFields(&issue, targetProject, params.IssueType)
+	var comments struct {
+		Comments []migratedComment `json:"comments"`
+	}
+	json.Unmarshal(issue.Fields["comment"], &comments)
+	var attachments []jira.Attachment
+	json.Unmarshal(issue.Fields["attachment"], &attachments)
+
+	if j.dryRun(params.DryRun) {
+		var problems []string
+		var project jira.Project
+		if _, err := j.jiraDo(targetCtx, "GET", "rest/api/2/project/"+targetProject, nil, &project); err != nil {
+			problems = append(problems, fmt.Sprintf("project %s does not exist or is not accessible on %s: %v", targetProject, params.TargetSite, err))
+		} else if ref, ok := fields["issuetype"].(map[string]string); ok {
+			if problem := issueTypeProblem(&project, ref["name"]); problem != "" {
+				problems = append(problems, problem)
+			}
+		}
+		result := dryRunResult("POST", "rest/api/2/issue", map[string]interface{}{"fields": fields}, problems)
+		skipped := 0
+		for _, a := range attachments {
+			if maxBytes < 0 || int64(a.Size) > maxBytes {
+				skipped++
+			}
+		}
+		result.Content = append(result.Content, &mcp.TextContent{Text: fmt.Sprintf("Would also copy %d comment(s) and %d of %d attachment(s), and link both issues.", len(comments.Comments), len(attachments)-skipped, len(attachments))})
+		return result, nil, nil
+	}
+
+	var copy createdIssue
+	if _, err := j.jiraDo(targetCtx, "POST", "rest/api/2/issue", map[string]interface{}{"fields": fields}, &copy); err != nil {
+		return textResult("Failed to create %s in %s on %s: %v", issueKey, targetProject, params.TargetSite, err), nil, nil
+	}
+	logger(ctx).Info("Migrated issue", "target", copy.Key, "site", params.TargetSite)
+	sourceLink := fmt.Sprintf("%s/browse/%s", sourceURL, issueKey)
+	targetLink := fmt.Sprintf("%s/browse/%s", targetURL, copy.Key)
+
+	// Everything after the create is best effort: failures are reported but
+	// leave the copy in place.
+	var warnings []string
+	copiedComments := 0
+	for _, c := range comments.Comments {
+		body := fmt.Sprintf("_Originally posted by %s on %s in %s:_\n\n%s", c.Author.DisplayName, c.Created, issueKey, c.Body)
+		if _, err := j.jiraDo(targetCtx, "POST", fmt.Sprintf("rest/api/2/issue/%s/comment", copy.Key), map[string]string{"body": body}, nil); err != nil {
+			warnings = append(warnings, fmt.Sprintf("could not copy a comment by %s: %v", c.Author.DisplayName, err))
+			continue
+		}
+		copiedComments++
+	}
+	copiedAttachments := 0
+	for _, a := range attachments {
+		if maxBytes < 0 || int64(a.Size) > maxBytes {
+			warnings = append(warnings, fmt.Sprintf("skipped attachment %s (%d bytes) over the size limit", a.Filename, a.Size))
+			continue
+		}
+		resp, err := j.client(sourceCtx).DownloadAttachment(sourceCtx, a.ID)
+		if err != nil {
+			warnings = append(warnings, fmt.Sprintf("could not download attachment %s: %v", a.Filename, err))
+			continue
+		}
+		_, _, err = j.client(targetCtx).PostAttachment(targetCtx, copy.Key, resp.Body, a.Filename)
+		resp.Body.Close()
+		if err != nil {
+			warnings = append(warnings, fmt.Sprintf("could not upload attachment %s: %v", a.Filename, err))
+			continue
+		}
+		copiedAttachments++
+	}
+	if err := j.breadcrumb(targetCtx, copy.Key, "Migrated from "+issueKey, sourceLink, fmt.Sprintf("Migrated from %s: %s", issueKey, sourceLink)); err != nil {
+		warnings = append(warnings, fmt.Sprintf("could not link %s back to the original: %v", copy.Key, err))
+	}
+	if err := j.breadcrumb(sourceCtx, issueKey, "Migrated to "+copy.Key, targetLink, fmt.Sprintf("Migrated to %s: %s", copy.Key, targetLink)); err != nil {
+		warnings = append(warnings, fmt.Sprintf("could not link %s to the copy: %v", issueKey, err))
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "Migrated %s to %s: %s\n", issueKey, copy.Key, targetLink)
+	fmt.Fprintf(&sb, "Copied %d field(s), %d of %d comment(s), %d of %d attachment(s)\n", len(fields), copiedComments, len(comments.Comments), copiedAttachments, len(attachments))
+	for _, w := range warnings {
+		fmt.Fprintf(&sb, "Warning: %s\n", w)
+	}
+	return textResult("%s", sb.String()), nil, nil
+}
@@ -0,0 +1,188 @@
+package jiramcp
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/andygrunwald/go-jira"
+)
+
+// mockBaseURL is the base URL of requests built by MockJiraService.
+const mockBaseURL = "https://jira.mock.invalid/"
+
+// MockJiraService is a JiraService for tests and demos. Each method calls the
+// function of the same name with a Func suffix, and fails when that function
+// is not set. Raw requests are built against a placeholder base URL, so DoFunc
+// can dispatch on req.Method and req.URL.Path. Calls records the name of every
+// method called, in order.
+type MockJiraService struct {
+	NewRequestFunc              func(ctx context.Context, method, urlStr string, body interface{}) (*http.Request, error)
+	DoFunc                      func(req *http.Request, v interface{}) (*jira.Response, error)
+	GetIssueFunc                func(ctx context.Context, issueKey string, options *jira.GetQueryOptions) (*jira.Issue, *jira.Response, error)
+	CreateIssueFunc             func(ctx context.Context, issue *jira.Issue) (*jira.Issue, *jira.Response, error)
+	AddCommentFunc              func(ctx context.Context, issueKey string, comment *jira.Comment) (*jira.Comment, *jira.Response, error)
+	GetTransitionsFunc          func(ctx context.Context, issueKey string) ([]jira.Transition, *jira.Response, error)
+	DoTransitionWithPayloadFunc func(ctx context.Context, issueKey string, payload interface{}) (*jira.Response, error)
+	AddLinkFunc                 func(ctx context.Context, link *jira.IssueLink) (*jira.Response, error)
+	DownloadAttachmentFunc      func(ctx context.Context, attachmentID string) (*jira.Response, error)
+	PostAttachmentFunc          func(ctx context.Context, issueKey string, r io.Reader, filename string) (*[]jira.Attachment, *jira.Response, error)
+	CreateComponentFunc         func(ctx context.Context, options *jira.CreateComponentOptions) (*jira.ProjectComponent, *jira.Response, error)
+	FindUsersFunc               func(ctx context.Context, query string) ([]jira.User, *jira.Response, error)
+	GetSelfFunc                 func(ctx context.Context) (*jira.User, *jira.Response, error)
+	ListPrioritiesFunc          func(ctx context.Context) ([]jira.Priority, *jira.Response, error)
+	ListStatusesFunc            func(ctx context.Context) ([]jira.Status, *jira.Response, error)
+
+	mu    sync.Mutex
+	Calls []string
+}
+
+var _ JiraService = (*MockJiraService)(nil)
+
+func (m *MockJiraService) record(name string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.Calls = append(m.Calls, name)
+}
+
+func notMocked(name string) error {
+	return fmt.Errorf("MockJiraService: %s is not mocked", name)
+}
+
+func (m *MockJiraService) NewRequestWithContext(ctx context.Context, method, urlStr string, body interface{}) (*http.Request, error) {
+	m.record("NewRequestWithContext")
+	if m.NewRequestFunc != nil {
+		return m.NewRequestFunc(ctx, method, urlStr, body)
+	}
+	var r io.Reader
+	if body != nil {
+		b, err := json.Marshal(body)
+		if err != nil {
+			return nil, err
+		}
+		r = bytes.NewReader(b)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, mockBaseURL+strings.TrimPrefix(urlStr, "/"), r)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	return req, nil
+}
+
+func (m *MockJiraService) Do(req *http.Request, v interface{}) (*jira.Response, error) {
+	m.record("Do")
+	if m.DoFunc == nil {
+		return nil, notMocked("Do")
+	}
+	return m.DoFunc(req, v)
+}
+
+func (m *MockJiraService) GetIssue(ctx context.Context, issueKey string, options *jira.GetQueryOptions) (*jira.Issue, *jira.Response, error) {
+	m.record("GetIssue")
+	if m.GetIssueFunc == nil {
+		return nil, nil, notMocked("GetIssue")
+	}
+	return m.GetIssueFunc(ctx, issueKey, options)
+}
+
+func (m *MockJiraService) CreateIssue(ctx context.Context, issue *jira.Issue) (*jira.Issue, *jira.Response, error) {
+	m.record("CreateIssue")
+	if m.CreateIssueFunc == nil {
+		return nil, nil, notMocked("CreateIssue")
+	}
+	return m.CreateIssueFunc(ctx, issue)
+}
+
+func (m *MockJiraService) AddComment(ctx context.Context, issueKey string, comment *jira.Comment) (*jira.Comment, *jira.Response, error) {
+	m.record("AddComment")
+	if m.AddCommentFunc == nil {
+		return nil, nil, notMocked("AddComment")
+	}
+	return m.AddCommentFunc(ctx, issueKey, comment)
+}
+
+func (m *MockJiraService) GetTransitions(ctx context.Context, issueKey string) ([]jira.Transition, *jira.Response, error) {
+	m.record("GetTransitions")
+	if m.GetTransitionsFunc == nil {
+		return nil, nil, notMocked("GetTransitions")
+	}
+	return m.GetTransitionsFunc(ctx, issueKey)
+}
+
+func (m *MockJiraService) DoTransitionWithPayload(ctx context.Context, issueKey string, payload interface{}) (*jira.Response, error) {
+	m.record("DoTransitionWithPayload")
+	if m.DoTransitionWithPayloadFunc == nil {
+		return nil, notMocked("DoTransitionWithPayload")
+	}
+	return m.DoTransitionWithPayloadFunc(ctx, issueKey, payload)
+}
+
+func (m *MockJiraService) AddLink(ctx context.Context, link *jira.IssueLink) (*jira.Response, error) {
+	m.record("AddLink")
+	if m.AddLinkFunc == nil {
+		return nil, notMocked("AddLink")
+	}
+	return m.AddLinkFunc(ctx, link)
+}
+
+func (m *MockJiraService) DownloadAttachment(ctx context.Context, attachmentID string) (*jira.Response, error) {
+	m.record("DownloadAttachment")
+	if m.DownloadAttachmentFunc == nil {
+		return nil, notMocked("DownloadAttachment")
+	}
+	return m.DownloadAttachmentFunc(ctx, attachmentID)
+}
+
+func (m *MockJiraService) PostAttachment(ctx context.Context, issueKey string, r io.Reader, filename string) (*[]jira.Attachment, *jira.Response, error) {
+	m.record("PostAttachment")
+	if m.PostAttachmentFunc == nil {
+		return nil, nil, notMocked("PostAttachment")
+	}
+	return m.PostAttachmentFunc(ctx, issueKey, r, filename)
+}
+
+func (m *MockJiraService) CreateComponent(ctx context.Context, options *jira.CreateComponentOptions) (*jira.ProjectComponent, *jira.Response, error) {
+	m.record("CreateComponent")
+	if m.CreateComponentFunc == nil {
+		return nil, nil, notMocked("CreateComponent")
+	}
+	return m.CreateComponentFunc(ctx, options)
+}
+
+func (m *MockJiraService) FindUsers(ctx context.Context, query string) ([]jira.User, *jira.Response, error) {
+	m.record("FindUsers")
+	if m.FindUsersFunc == nil {
+		return nil, nil, notMocked("FindUsers")
+	}
+	return m.FindUsersFunc(ctx, query)
+}
+
+func (m *MockJiraService) GetSelf(ctx context.Context) (*jira.User, *jira.Response, error) {
+	m.record("GetSelf")
+	if m.GetSelfFunc == nil {
+		return nil, nil, notMocked("GetSelf")
+	}
+	return m.GetSelfFunc(ctx)
+}
+
+func (m *MockJiraService) ListPriorities(ctx context.Context) ([]jira.Priority, *jira.Response, error) {
+	m.record("ListPriorities")
+	if m.ListPrioritiesFunc == nil {
+		return nil, nil, notMocked("ListPriorities")
+	}
+	return m.ListPrioritiesFunc(ctx)
+}
+
+func (m *MockJiraService) ListStatuses(ctx context.Context) ([]jira.Status, *jira.Response, error) {
+	m.record("ListStatuses")
+	if m.ListStatusesFunc == nil {
+		return nil, nil, notMocked("ListStatuses")
+	}
+	return m.ListStatusesFunc(ctx)
+}
@@ -0,0 +1,127 @@
+package jiramcp
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/andygrunwald/go-jira"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// defaultMyWorkResults bounds the my-work tools when maxResults is not given.
+const defaultMyWorkResults = 50
+
+type MyIssuesParams struct {
+	// ProjectKey optionally restricts results to one project.
+	ProjectKey string `json:"projectKey,omitempty"`
+	// IncludeResolved also returns issues in a done status.
+	IncludeResolved bool `json:"includeResolved,omitempty"`
+	MaxResults      int  `json:"maxResults,omitempty"`
+	// Verbosity is "minimal", "standard", or "full"; defaults to the server setting.
+	Verbosity string `json:"verbosity,omitempty"`
+}
+
+type RecentIssuesParams struct {
+	ProjectKey string `json:"projectKey,omitempty"`
+	MaxResults int    `json:"maxResults,omitempty"`
+	// Verbosity is "minimal", "standard", or "full"; defaults to the server setting.
+	Verbosity string `json:"verbosity,omitempty"`
+}
+
+// myWorkJQL builds the JQL for a my-work query from its base clause.
+func myWorkJQL(base, projectKey string, includeResolved bool, orderBy string) string {
+	clauses := []string{base}
+	if projectKey != "" {
+		clauses = append(clauses, fmt.Sprintf("project = %s", strings.ToUpper(projectKey)))
+	}
+	if !includeResolved {
+		clauses = append(clauses, "statusCategory != Done")
+	}
+	return strings.Join(clauses, " AND ") + " ORDER BY " + orderBy
+}
+
+func maxResultsOrDefault(n int) int {
+	if n <= 0 {
+		return defaultMyWorkResults
+	}
+	return n
+}
+
+// GetMyIssues lists the issues assigned to the authenticated user, grouped by status.
+func (j *JiraMCPServer) GetMyIssues(ctx context.Context, req *mcp.CallToolRequest, params *MyIssuesParams) (*mcp.CallToolResult, any, error) {
+	jql := myWorkJQL("assignee = currentUser()", params.ProjectKey, params.IncludeResolved, "priority DESC, updated DESC")
+	verbosity := j.verbosity(params.Verbosity)
+	issues, err := j.searchIssues(ctx, jql, issueFieldsFor(verbosity), "", maxResultsOrDefault(params.MaxResults))
+	if err != nil {
+		return textResult("Failed to get your assigned issues: %v", err), nil, nil
+	}
+	if len(issues) == 0 {
+		return textResult("You have no assigned issues matching: %s", jql), nil, nil
+	}
+	return textResult("%d issue(s) assigned to you:\n%s", len(issues), groupIssuesByStatus(issues, verbosity)), nil, nil
+}
+
+// GetMyReportedIssues lists the issues reported by the authenticated user,
+// grouped by status.
+func (j *JiraMCPServer) GetMyReportedIssues(ctx context.Context, req *mcp.CallToolRequest, params *MyIssuesParams) (*mcp.CallToolResult, any, error) {
+	jql := myWorkJQL("reporter = currentUser()", params.ProjectKey, params.IncludeResolved, "updated DESC")
+	verbosity := j.verbosity(params.Verbosity)
+	issues, err := j.searchIssues(ctx, jql, issueFieldsFor(verbosity), "", maxResultsOrDefault(params.MaxResults))
+	if err != nil {
+		return textResult("Failed to get your reported issues: %v", err), nil, nil
+	}
+	if len(issues) == 0 {
+		return textResult("You have no reported issues matching: %s", jql), nil, nil
+	}
+	return textResult("%d issue(s) reported by you:\n%s", len(issues), groupIssuesByStatus(issues, verbosity)), nil, nil
+}
+
+// GetRecentIssues lists the issues the authenticated user viewed most recently.
+func (j *JiraMCPServer) GetRecentIssues(ctx context.Context, req *mcp.CallToolRequest, params *RecentIssuesParams) (*mcp.CallToolResult, any, error) {
+	jql := myWorkJQL("issuekey IN issueHistory()", params.ProjectKey, true, "lastViewed DESC")
+	verbosity := j.verbosity(params.Verbosity)
+	issues, err := j.searchIssues(ctx, jql, issueFieldsFor(verbosity), "", maxResultsOrDefault(params.MaxResults))
+	if err != nil {
+		return textResult("Failed to get your recently viewed issues: %v", err), nil, nil
+	}
+	if len(issues) == 0 {
+		return textResult("You have not viewed any issues recently"), nil, nil
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "%d recently viewed issue(s), most recent first:\n", len(issues))
+	for _, issue := range issues {
+		sb.WriteString(formatIssue(&issue, verbosity))
+		sb.WriteString("\n")
+	}
+	return textResult("%s", sb.String()), nil, nil
+}
+
+// groupIssuesByStatus renders issues under a heading per status, keeping the
+// order in which statuses first appear in the search results.
+func groupIssuesByStatus(issues []jira.Issue, verbosity string) string {
+	var order []string
+	groups := make(map[string][]jira.Issue)
+	for _, issue := range issues {
+		status := "Unknown"
+		if issue.Fields != nil && issue.Fields.Status != nil {
+			status = issue.Fields.Status.Name
+		}
+		if _, ok := groups[status]; !ok {
+			order = append(order, status)
+		}
+		groups[status] = append(groups[status], issue)
+	}
+
+	var sb strings.Builder
+	for _, status := range order {
+		fmt.Fprintf(&sb, "\n%s (%d):\n", status, len(groups[status]))
+		for _, issue := range groups[status] {
+			sb.WriteString("  ")
+			sb.WriteString(formatIssue(&issue, verbosity))
+			sb.WriteString("\n")
+		}
+	}
+	return sb.String()
+}
@@ -0,0 +1,91 @@
+package jiramcp
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"time"
+)
+
+// networkTransport returns the transport that reaches Jira over the network,
+// configured with the proxy, CA bundle, and timeouts of config.
+func networkTransport(config *JiraConfig) (*http.Transport, error) {
+	t := http.DefaultTransport.(*http.Transport).Clone()
+	if config.ConnectTimeout > 0 {
+		t.DialContext = (&net.Dialer{Timeout: config.ConnectTimeout, KeepAlive: 30 * time.Second}).DialContext
+		t.TLSHandshakeTimeout = config.ConnectTimeout
+	}
+	t.ResponseHeaderTimeout = config.ResponseTimeout
+	// Keep enough idle connections for tools reading in parallel.
+	t.MaxIdleConnsPerHost = max(2*config.FetchConcurrency, http.DefaultMaxIdleConnsPerHost)
+
+	// Without ProxyURL the standard HTTPS_PROXY, HTTP_PROXY, and NO_PROXY
+	// variables apply, as they do for the default transport.
+	if config.ProxyURL != "" {
+		proxy, err := parseProxyURL(config.ProxyURL)
+		if err != nil {
+			return nil, err
+		}
+		t.Proxy = http.ProxyURL(proxy)
+	}
+
+	if config.CAFile == "" && !config.InsecureSkipVerify {
+		return t, nil
+	}
+	tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12}
+	if config.CAFile != "" {
+		pem, err := os.ReadFile(config.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CA file: %w", err)
+		}
+		// The private CA is trusted in addition to the system roots, so the
+		// same server can also reach public sites such as on-call providers.
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("CA file %s holds no PEM certificates", config.CAFile)
+		}
+		tlsConfig.RootCAs = pool
+	}
+	if config.InsecureSkipVerify {
+		slog.Warn("JIRA_MCP_INSECURE_SKIP_VERIFY is set: TLS certificates of Jira are NOT verified, so anyone on the network path can read and alter Jira traffic, including credentials. Use JIRA_MCP_CA_FILE instead.")
+		tlsConfig.InsecureSkipVerify = true
+	}
+	t.TLSClientConfig = tlsConfig
+	return t, nil
+}
+
+// parseProxyURL parses JIRA_MCP_PROXY_URL, which may omit the scheme.
+func parseProxyURL(raw string) (*url.URL, error) {
+	proxy, err := url.Parse(raw)
+	if err != nil || proxy.Host == "" {
+		if proxy, err = url.Parse("http://" + raw); err != nil || proxy.Host == "" {
+			return nil, fmt.Errorf("JIRA_MCP_PROXY_URL %q is not a valid URL", raw)
+		}
+	}
+	switch proxy.Scheme {
+	case "http", "https", "socks5":
+	default:
+		return nil, fmt.Errorf("JIRA_MCP_PROXY_URL must use http, https, or socks5, got %q", proxy.Scheme)
+	}
+	return proxy, nil
+}
+
+// proxyForDisplay returns the proxy URL without its password.
+func proxyForDisplay(raw string) string {
+	if raw == "" {
+		return ""
+	}
+	proxy, err := parseProxyURL(raw)
+	if err != nil {
+		return "(invalid)"
+	}
+	return proxy.Redacted()
+}
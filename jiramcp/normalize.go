@@ -0,0 +1,189 @@
+package jiramcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/andygrunwald/go-jira"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// defaultNormalizationIssues bounds how many issues the normalization report
+// scans by default.
+const defaultNormalizationIssues = 1000
+
+type FieldNormalizationParams struct {
+	ProjectKey string `json:"projectKey,omitempty"`
+	MaxIssues  int    `json:"maxIssues,omitempty"`
+}
+
+// planStep is one tool call of a normalization plan.
+type planStep struct {
+	Tool      string          `json:"tool"`
+	Arguments UpdateIssueArgs `json:"arguments"`
+}
+
+// labelVariants groups the spellings of a label that differ only by case,
+// with the number of scanned issues using each.
+type labelVariants struct {
+	canonical string
+	counts    map[string]int
+}
+
+// caseVariantLabels returns the labels used in more than one spelling, keyed
+// by lower-cased label. The most used spelling is canonical; ties go to the
+// alphabetically first.
+func caseVariantLabels(issues []jira.Issue) map[string]*labelVariants {
+	groups := make(map[string]*labelVariants)
+	for _, issue := range issues {
+		for _, label := range issue.Fields.Labels {
+			key := strings.ToLower(label)
+			if groups[key] == nil {
+				groups[key] = &labelVariants{counts: make(map[string]int)}
+			}
+			groups[key].counts[label]++
+		}
+	}
+	for key, g := range groups {
+		if len(g.counts) < 2 {
+			delete(groups, key)
+			continue
+		}
+		for label, n := range g.counts {
+			if best := g.counts[g.canonical]; g.canonical == "" || n > best || (n == best && label < g.canonical) {
+				g.canonical = label
+			}
+		}
+	}
+	return groups
+}
+
+// FieldNormalizationReport scans a project for inconsistent field usage and
+// proposes update-jira-issue calls that clean it up.
+func (j *JiraMCPServer) FieldNormalizationReport(ctx context.Context, req *mcp.CallToolRequest, params *FieldNormalizationParams) (*mcp.CallToolResult, any, error) {
+	projectKey := j.projectKeyOrDefault(params.ProjectKey)
+	maxIssues := params.MaxIssues
+	if maxIssues <= 0 {
+		maxIssues = defaultNormalizationIssues
+	}
+
+	project, err := j.getProject(ctx, projectKey)
+	if err != nil {
+		return textResult("Failed to get project %s: %v", projectKey, err), nil, nil
+	}
+	issues, err := j.searchIssues(ctx, fmt.Sprintf("project = %s ORDER BY key ASC", projectKey), []string{"labels", "components", "priority"}, "", maxIssues)
+	if err != nil {
+		return textResult("Failed to load issues for %s: %v", projectKey, err), nil, nil
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "Field normalization report for %s (%d issues scanned)\n", projectKey, len(issues))
+	if len(issues) == maxIssues {
+		fmt.Fprintf(&sb, "Only the first %d issues were scanned; raise maxIssues to cover the whole project.\n", maxIssues)
+	}
+	var plan []planStep
+
+	// Labels differing only by case.
+	variants := caseVariantLabels(issues)
+	sb.WriteString("\nLabels differing only by case:\n")
+	if len(variants) == 0 {
+		sb.WriteString("- none\n")
+	}
+	keys := make([]string, 0, len(variants))
+	for key := range variants {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		g := variants[key]
+		spellings := make([]string, 0, len(g.counts))
+		for label, n := range g.counts {
+			spellings = append(spellings, fmt.Sprintf("%q ×%d", label, n))
+		}
+		sort.Strings(spellings)
+		fmt.Fprintf(&sb, "- %s → %q\n", strings.Join(spellings, ", "), g.canonical)
+	}
+	for _, issue := range issues {
+		args := UpdateIssueArgs{IssueKey: issue.Key}
+		for _, label := range issue.Fields.Labels {
+			if g := variants[strings.ToLower(label)]; g != nil && label != g.canonical {
+				args.RemoveLabels = append(args.RemoveLabels, label)
+				if !slices.Contains(issue.Fields.Labels, g.canonical) && !slices.Contains(args.AddLabels, g.canonical) {
+					args.AddLabels = append(args.AddLabels, g.canonical)
+				}
+			}
+		}
+		if len(args.RemoveLabels) > 0 {
+			plan = append(plan, planStep{Tool: "update-jira-issue", Arguments: args})
+		}
+	}
+
+	// Components no scanned issue uses.
+	used := make(map[string]bool)
+	for _, issue := range issues {
+		for _, c := range issue.Fields.Components {
+			used[c.Name] = true
+		}
+	}
+	sb.WriteString("\nUnused components (remove or archive them in the project settings):\n")
+	unused := 0
+	for _, c := range project.Components {
+		if !used[c.Name] {
+			fmt.Fprintf(&sb, "- %s\n", c.Name)
+			unused++
+		}
+	}
+	if unused == 0 {
+		sb.WriteString("- none\n")
+	}
+
+	// Priorities: issues without one, and priorities nobody uses.
+	priorityCounts := make(map[string]int)
+	var unprioritized []string
+	for _, issue := range issues {
+		if issue.Fields.Priority == nil || issue.Fields.Priority.Name == "" {
+			unprioritized = append(unprioritized, issue.Key)
+			continue
+		}
+		priorityCounts[issue.Fields.Priority.Name]++
+	}
+	common := ""
+	for name, n := range priorityCounts {
+		if best := priorityCounts[common]; common == "" || n > best || (n == best && name < common) {
+			common = name
+		}
+	}
+	fmt.Fprintf(&sb, "\nIssues without a priority: %d\n", len(unprioritized))
+	if len(unprioritized) > 0 && common != "" {
+		fmt.Fprintf(&sb, "The plan sets them to %q, the priority most used in %s; review before applying.\n", common, projectKey)
+		for _, key := range unprioritized {
+			plan = append(plan, planStep{Tool: "update-jira-issue", Arguments: UpdateIssueArgs{IssueKey: key, Priority: common}})
+		}
+	}
+	if priorities, _, err := j.projectPriorities(ctx, projectKey); err == nil {
+		var never []string
+		for _, p := range priorities {
+			if priorityCounts[p.Name] == 0 {
+				never = append(never, p.Name)
+			}
+		}
+		if len(never) > 0 {
+			fmt.Fprintf(&sb, "Priorities never set on a scanned issue: %s\n", strings.Join(never, ", "))
+		}
+	}
+
+	if len(plan) == 0 {
+		sb.WriteString("\nNothing to normalize on the scanned issues.\n")
+		return textResult("%s", sb.String()), nil, nil
+	}
+	b, err := json.MarshalIndent(plan, "", "  ")
+	if err != nil {
+		return textResult("Failed to encode the normalization plan: %v", err), nil, nil
+	}
+	fmt.Fprintf(&sb, "\nNormalization plan (%d update-jira-issue calls; add \"dryRun\": true to preview each):\n%s\n", len(plan), b)
+	return textResult("%s", sb.String()), nil, nil
+}
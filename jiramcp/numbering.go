@@ -0,0 +1,137 @@
+package jiramcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// maxNumberingIssues bounds the issues counted for creation rates per project.
+const maxNumberingIssues = 10000
+
+type IssueNumberingReportParams struct {
+	// ProjectKeys defaults to the configured project.
+	ProjectKeys []string `json:"projectKeys,omitempty"`
+	// Weeks is the number of 7-day periods the creation rate covers (default 8).
+	Weeks int `json:"weeks,omitempty"`
+}
+
+// projectNumbering is the numbering report of one project.
+type projectNumbering struct {
+	Project       string `json:"project"`
+	LatestKey     string `json:"latestKey,omitempty"`
+	LatestNumber  int    `json:"latestNumber"`
+	LatestCreated string `json:"latestCreated,omitempty"`
+	// Weekly counts the issues created in each 7-day period ending now,
+	// oldest first, starting on the dates in WeekStarts.
+	WeekStarts    []string `json:"weekStarts"`
+	Weekly        []int    `json:"weekly"`
+	PerWeek       float64  `json:"perWeek"`
+	RecentPerWeek float64  `json:"recentPerWeek"`
+	// ProjectedNumber is the issue number expected a week from now at the
+	// recent rate.
+	ProjectedNumber int    `json:"projectedNumber"`
+	Error           string `json:"error,omitempty"`
+}
+
+// issueNumber returns the number of an issue key such as ABC-123.
+func issueNumber(key string) int {
+	n, _ := strconv.Atoi(key[strings.LastIndex(key, "-")+1:])
+	return n
+}
+
+// projectNumbering builds the numbering report of a project.
+func (j *JiraMCPServer) projectNumbering(ctx context.Context, projectKey string, weeks int, now time.Time) projectNumbering {
+	report := projectNumbering{Project: projectKey, Weekly: make([]int, weeks)}
+	latest, err := j.searchPage(ctx, fmt.Sprintf("project = %s ORDER BY key DESC", projectKey), []string{"created"}, "", 1, "")
+	if err != nil {
+		report.Error = err.Error()
+		return report
+	}
+	if len(latest.Issues) == 0 {
+		return report
+	}
+	report.LatestKey = latest.Issues[0].Key
+	report.LatestNumber = issueNumber(report.LatestKey)
+	report.LatestCreated = time.Time(latest.Issues[0].Fields.Created).UTC().Format(time.RFC3339)
+
+	start := now.AddDate(0, 0, -7*weeks)
+	for w := 0; w < weeks; w++ {
+		report.WeekStarts = append(report.WeekStarts, start.AddDate(0, 0, 7*w).Format("2006-01-02"))
+	}
+	jql := fmt.Sprintf(`project = %s AND created >= "%s"`, projectKey, start.Format("2006-01-02 15:04"))
+	issues, err := j.searchIssues(ctx, jql, []string{"created"}, "", maxNumberingIssues)
+	if err != nil {
+		report.Error = err.Error()
+		return report
+	}
+	for _, issue := range issues {
+		created := time.Time(issue.Fields.Created)
+		if w := int(created.Sub(start) / (7 * 24 * time.Hour)); w >= 0 && w < weeks {
+			report.Weekly[w]++
+		}
+	}
+	total := 0
+	for _, n := range report.Weekly {
+		total += n
+	}
+	recent := min(4, weeks)
+	recentTotal := 0
+	for _, n := range report.Weekly[weeks-recent:] {
+		recentTotal += n
+	}
+	report.PerWeek = math.Round(10*float64(total)/float64(weeks)) / 10
+	report.RecentPerWeek = math.Round(10*float64(recentTotal)/float64(recent)) / 10
+	report.ProjectedNumber = report.LatestNumber + int(math.Ceil(report.RecentPerWeek))
+	if len(issues) == maxNumberingIssues {
+		report.Error = fmt.Sprintf("only the first %d issues were counted", maxNumberingIssues)
+	}
+	return report
+}
+
+// IssueNumberingReport reports the latest issue number of projects and how
+// fast new numbers are being used, so references can be allocated ahead.
+func (j *JiraMCPServer) IssueNumberingReport(ctx context.Context, req *mcp.CallToolRequest, params *IssueNumberingReportParams) (*mcp.CallToolResult, any, error) {
+	weeks := params.Weeks
+	if weeks <= 0 {
+		weeks = 8
+	}
+	if weeks > 52 {
+		return textResult("weeks must be at most 52"), nil, nil
+	}
+	keys := params.ProjectKeys
+	if len(keys) == 0 {
+		keys = []string{j.config.ProjectKey}
+	}
+	now := time.Now().UTC()
+	reports := make([]projectNumbering, len(keys))
+	// Failures are reported per project, so the pool never stops early.
+	forEachParallel(ctx, j.fetchConcurrency(), len(keys), func(ctx context.Context, i int) error {
+		reports[i] = j.projectNumbering(ctx, strings.ToUpper(keys[i]), weeks, now)
+		return nil
+	})
+	var lines []string
+	for _, r := range reports {
+		switch {
+		case r.LatestKey == "" && r.Error != "":
+			lines = append(lines, fmt.Sprintf("- %s: failed: %s", r.Project, r.Error))
+		case r.LatestKey == "":
+			lines = append(lines, fmt.Sprintf("- %s: no issues yet", r.Project))
+		default:
+			lines = append(lines, fmt.Sprintf("- %s: latest %s, %.1f new issues a week recently (%.1f over %d weeks), about %s-%d a week from now",
+				r.Project, r.LatestKey, r.RecentPerWeek, r.PerWeek, weeks, r.Project, r.ProjectedNumber))
+		}
+	}
+	b, err := json.MarshalIndent(reports, "", "  ")
+	if err != nil {
+		return textResult("Failed to encode the numbering report: %v", err), nil, nil
+	}
+	return textResult("Issue numbering as of %s:\n%s\nNumbers of deleted issues are not reused, so the next key may be higher than latest + 1.\n%s",
+		now.Format(time.RFC3339), strings.Join(lines, "\n"), b), nil, nil
+}
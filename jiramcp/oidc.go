@@ -0,0 +1,123 @@
+package jiramcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/coreos/go-oidc/v3/oidc"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// oidcAuth verifies OIDC bearer tokens presented by HTTP clients.
+type oidcAuth struct {
+	verifier *oidc.IDTokenVerifier
+	// userClaim names the claim identifying the user for impersonation.
+	userClaim string
+	// impersonation maps values of userClaim to Jira credentials.
+	impersonation map[string]jiraCredentials
+}
+
+// impersonationEntry is one user in JIRA_MCP_OIDC_IMPERSONATION_FILE.
+type impersonationEntry struct {
+	Username string `json:"username,omitempty"`
+	Token    string `json:"token"`
+}
+
+// newOIDCAuth sets up token verification for JIRA_MCP_OIDC_ISSUER. Signing
+// keys come from JIRA_MCP_OIDC_JWKS_URL when set, and otherwise from the
+// issuer's discovery document.
+func newOIDCAuth(ctx context.Context, config *JiraConfig) (*oidcAuth, error) {
+	verifierConfig := &oidc.Config{ClientID: config.OIDCAudience}
+	var verifier *oidc.IDTokenVerifier
+	if config.OIDCJWKSURL != "" {
+		verifier = oidc.NewVerifier(config.OIDCIssuer, oidc.NewRemoteKeySet(ctx, config.OIDCJWKSURL), verifierConfig)
+	} else {
+		provider, err := oidc.NewProvider(ctx, config.OIDCIssuer)
+		if err != nil {
+			return nil, fmt.Errorf("failed to discover OIDC issuer %s: %w", config.OIDCIssuer, err)
+		}
+		verifier = provider.Verifier(verifierConfig)
+	}
+
+	a := &oidcAuth{verifier: verifier, userClaim: config.OIDCUserClaim}
+	if config.OIDCImpersonationFile != "" {
+		b, err := os.ReadFile(config.OIDCImpersonationFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read impersonation file: %w", err)
+		}
+		var entries map[string]impersonationEntry
+		if err := json.Unmarshal(b, &entries); err != nil {
+			return nil, fmt.Errorf("failed to parse impersonation file %s: %w", config.OIDCImpersonationFile, err)
+		}
+		a.impersonation = make(map[string]jiraCredentials, len(entries))
+		for user, e := range entries {
+			if e.Token == "" {
+				return nil, fmt.Errorf("impersonation file %s: %s has no token", config.OIDCImpersonationFile, user)
+			}
+			a.impersonation[strings.ToLower(user)] = jiraCredentials{Username: e.Username, Token: e.Token}
+		}
+	}
+	return a, nil
+}
+
+// verify checks a bearer token's signature, issuer, audience, and expiry and
+// returns the client it identifies.
+func (a *oidcAuth) verify(ctx context.Context, raw string) (*principal, error) {
+	token, err := a.verifier.Verify(ctx, raw)
+	if err != nil {
+		return nil, err
+	}
+	var claims map[string]interface{}
+	if err := token.Claims(&claims); err != nil {
+		return nil, fmt.Errorf("failed to decode claims: %w", err)
+	}
+	return &principal{Subject: token.Subject, Claims: claims}, nil
+}
+
+// credentialsFor returns the Jira credentials p acts with, when impersonation
+// is configured for the user.
+func (a *oidcAuth) credentialsFor(p *principal) (jiraCredentials, bool) {
+	if a == nil || p == nil || a.impersonation == nil {
+		return jiraCredentials{}, false
+	}
+	user, _ := p.Claims[a.userClaim].(string)
+	creds, ok := a.impersonation[strings.ToLower(user)]
+	return creds, ok
+}
+
+// authenticate returns the client identified by a bearer token: one of the
+// configured API tokens or a valid OIDC token. It returns nil for anything
+// else.
+func (j *JiraMCPServer) authenticate(ctx context.Context, token string) *principal {
+	if token == "" {
+		return nil
+	}
+	if j.apiTokenValid(token) {
+		return tokenPrincipal(token)
+	}
+	if j.oidc != nil {
+		p, err := j.oidc.verify(ctx, token)
+		if err != nil {
+			logger(ctx).Debug("Rejected OIDC token", "error", err)
+			return nil
+		}
+		return p
+	}
+	return nil
+}
+
+// requestPrincipal identifies the client behind an MCP request, from the HTTP
+// request that opened the session or from the request's own headers when the
+// transport forwards them.
+func (j *JiraMCPServer) requestPrincipal(ctx context.Context, req mcp.Request) *principal {
+	if p, ok := ctx.Value(principalKey{}).(*principal); ok {
+		return p
+	}
+	if extra := req.GetExtra(); extra != nil && extra.Header != nil {
+		return j.authenticate(ctx, tokenFromHeader(extra.Header))
+	}
+	return nil
+}
@@ -0,0 +1,189 @@
+package jiramcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/andygrunwald/go-jira"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// On-call providers selectable with JIRA_MCP_ONCALL_PROVIDER.
+const (
+	OnCallOpsgenie  = "opsgenie"
+	OnCallPagerDuty = "pagerduty"
+	OnCallWebhook   = "webhook"
+)
+
+// outboundHTTPClient is used for requests to services other than Jira, such
+// as on-call providers and outbound webhooks.
+var outboundHTTPClient = &http.Client{Timeout: 10 * time.Second}
+
+// onCallPerson identifies whoever is currently on call. Providers report an
+// email address; the webhook provider may also supply a Jira accountId.
+type onCallPerson struct {
+	Name      string `json:"name"`
+	Email     string `json:"email"`
+	AccountID string `json:"accountId"`
+}
+
+type AssignToOnCallParams struct {
+	IssueKey string `json:"issueKey"`
+	// Schedule overrides the configured on-call schedule (name for Opsgenie, ID for PagerDuty).
+	Schedule string `json:"schedule,omitempty"`
+	DryRun   bool   `json:"dryRun,omitempty"`
+}
+
+// currentOnCall asks the configured provider who is on call for schedule, or
+// for the default schedule when it is empty.
+func (j *JiraMCPServer) currentOnCall(ctx context.Context, schedule string) (*onCallPerson, error) {
+	c := j.config
+	if schedule == "" {
+		schedule = c.OnCallSchedule
+	}
+	var (
+		endpoint string
+		header   = http.Header{}
+	)
+	switch c.OnCallProvider {
+	case "":
+		return nil, fmt.Errorf("no on-call provider is configured (set JIRA_MCP_ONCALL_PROVIDER)")
+	case OnCallOpsgenie:
+		base := c.OnCallURL
+		if base == "" {
+			base = "https://api.opsgenie.com"
+		}
+		endpoint = fmt.Sprintf("%s/v2/schedules/%s/on-calls?scheduleIdentifierType=name&flat=true", strings.TrimSuffix(base, "/"), url.PathEscape(schedule))
+		header.Set("Authorization", "GenieKey "+c.OnCallToken)
+	case OnCallPagerDuty:
+		base := c.OnCallURL
+		if base == "" {
+			base = "https://api.pagerduty.com"
+		}
+		endpoint = fmt.Sprintf("%s/oncalls?schedule_ids[]=%s&include[]=users&earliest=true", strings.TrimSuffix(base, "/"), url.QueryEscape(schedule))
+		header.Set("Authorization", "Token token="+c.OnCallToken)
+		header.Set("Accept", "application/vnd.pagerduty+json;version=2")
+	case OnCallWebhook:
+		endpoint = c.OnCallURL
+		if schedule != "" {
+			sep := "?"
+			if strings.Contains(endpoint, "?") {
+				sep = "&"
+			}
+			endpoint += sep + "schedule=" + url.QueryEscape(schedule)
+		}
+		if c.OnCallToken != "" {
+			header.Set("Authorization", "Bearer "+c.OnCallToken)
+		}
+	default:
+		return nil, fmt.Errorf("unknown on-call provider %q", c.OnCallProvider)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header = header
+	resp, err := outboundHTTPClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("on-call lookup failed: %w", err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return nil, fmt.Errorf("on-call lookup failed: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("on-call lookup failed: %s: %s", resp.Status, strings.TrimSpace(string(body)))
+	}
+
+	var person onCallPerson
+	switch c.OnCallProvider {
+	case OnCallOpsgenie:
+		var result struct {
+			Data struct {
+				OnCallRecipients []string `json:"onCallRecipients"`
+			} `json:"data"`
+		}
+		if err := json.Unmarshal(body, &result); err != nil {
+			return nil, fmt.Errorf("invalid Opsgenie response: %w", err)
+		}
+		if len(result.Data.OnCallRecipients) > 0 {
+			person.Email = result.Data.OnCallRecipients[0]
+		}
+	case OnCallPagerDuty:
+		var result struct {
+			OnCalls []struct {
+				EscalationLevel int `json:"escalation_level"`
+				User            struct {
+					Name  string `json:"name"`
+					Email string `json:"email"`
+				} `json:"user"`
+			} `json:"oncalls"`
+		}
+		if err := json.Unmarshal(body, &result); err != nil {
+			return nil, fmt.Errorf("invalid PagerDuty response: %w", err)
+		}
+		// The first escalation level is the primary on-call.
+		for _, oc := range result.OnCalls {
+			if person.Email == "" || oc.EscalationLevel == 1 {
+				person.Name, person.Email = oc.User.Name, oc.User.Email
+			}
+			if oc.EscalationLevel == 1 {
+				break
+			}
+		}
+	case OnCallWebhook:
+		if err := json.Unmarshal(body, &person); err != nil {
+			return nil, fmt.Errorf("invalid on-call webhook response: %w", err)
+		}
+	}
+	if person.Email == "" && person.AccountID == "" {
+		return nil, fmt.Errorf("nobody is on call for schedule %q", schedule)
+	}
+	return &person, nil
+}
+
+// onCallAssignee resolves the current on-call person to a Jira user.
+func (j *JiraMCPServer) onCallAssignee(ctx context.Context, schedule string) (*jira.User, error) {
+	person, err := j.currentOnCall(ctx, schedule)
+	if err != nil {
+		return nil, err
+	}
+	// Server and Data Center have no accountIds, so the email is looked up
+	// there.
+	if person.AccountID != "" && j.isCloud() {
+		return &jira.User{AccountID: person.AccountID, DisplayName: person.Name}, nil
+	}
+	user, err := j.findJiraUser(ctx, person.Email)
+	if err != nil {
+		return nil, fmt.Errorf("on-call %s has no matching Jira user: %w", person.Email, err)
+	}
+	return user, nil
+}
+
+// AssignToOnCall assigns an issue to whoever is currently on call.
+func (j *JiraMCPServer) AssignToOnCall(ctx context.Context, req *mcp.CallToolRequest, params *AssignToOnCallParams) (*mcp.CallToolResult, any, error) {
+	user, err := j.onCallAssignee(ctx, params.Schedule)
+	if err != nil {
+		return textResult("Failed to find the current on-call: %v", err), nil, nil
+	}
+
+	path := fmt.Sprintf("rest/api/2/issue/%s/assignee", params.IssueKey)
+	payload := j.userRef(j.userID(user))
+	if j.dryRun(params.DryRun) {
+		return dryRunResult("PUT", path, payload, j.issueProblems(ctx, params.IssueKey)), nil, nil
+	}
+	if _, err := j.jiraDo(ctx, "PUT", path, payload, nil); err != nil {
+		return textResult("Failed to assign %s to %s: %v", params.IssueKey, user.DisplayName, err), nil, nil
+	}
+	logger(ctx).Info("Assigned issue to on-call", "assignee", user.DisplayName)
+
+	return textResult("Assigned %s to %s, who is currently on call", params.IssueKey, user.DisplayName), nil, nil
+}
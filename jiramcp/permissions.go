@@ -0,0 +1,320 @@
+package jiramcp
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+type AuditProjectPermissionsParams struct {
+	ProjectKey string `json:"projectKey,omitempty"`
+	// IncludeRoles also lists the members (users and groups) of every project role.
+	IncludeRoles bool `json:"includeRoles,omitempty"`
+	// Verbosity "minimal" reports risky grants only; "full" implies includeRoles.
+	Verbosity string `json:"verbosity,omitempty"`
+}
+
+type permissionScheme struct {
+	ID          int64             `json:"id"`
+	Name        string            `json:"name"`
+	Description string            `json:"description"`
+	Permissions []permissionGrant `json:"permissions"`
+}
+
+type permissionGrant struct {
+	ID         int64            `json:"id"`
+	Permission string           `json:"permission"`
+	Holder     permissionHolder `json:"holder"`
+}
+
+type permissionHolder struct {
+	Type      string `json:"type"`
+	Parameter string `json:"parameter"`
+	Value     string `json:"value"`
+	Group     *struct {
+		Name string `json:"name"`
+	} `json:"group,omitempty"`
+	User *struct {
+		DisplayName string `json:"displayName"`
+	} `json:"user,omitempty"`
+	ProjectRole *struct {
+		Name string `json:"name"`
+	} `json:"projectRole,omitempty"`
+}
+
+type projectRole struct {
+	Name   string `json:"name"`
+	Actors []struct {
+		DisplayName string `json:"displayName"`
+		Type        string `json:"type"`
+	} `json:"actors"`
+}
+
+// sensitivePermissions are grants that warrant review whenever they are given
+// to a broad audience.
+var sensitivePermissions = map[string]bool{
+	"ADMINISTER_PROJECTS":    true,
+	"DELETE_ISSUES":          true,
+	"DELETE_ALL_COMMENTS":    true,
+	"DELETE_ALL_ATTACHMENTS": true,
+	"DELETE_ALL_WORKLOGS":    true,
+	"EDIT_ALL_COMMENTS":      true,
+	"EDIT_ALL_WORKLOGS":      true,
+	"MODIFY_REPORTER":        true,
+	"SET_ISSUE_SECURITY":     true,
+	"MANAGE_WATCHERS":        true,
+}
+
+// broadGroups are default groups that typically contain every licensed user.
+var broadGroups = map[string]bool{
+	"jira-users":                 true,
+	"jira-software-users":        true,
+	"jira-servicedesk-users":     true,
+	"jira-core-users":            true,
+	"users":                      true,
+	"jira-work-management-users": true,
+}
+
+// describe renders a permission holder in a human readable form.
+func (h permissionHolder) describe() string {
+	switch h.Type {
+	case "anyone":
+		return "Anyone (including anonymous users)"
+	case "applicationRole":
+		if h.Parameter == "" {
+			return "Any logged in user"
+		}
+		return "Application access: " + h.Parameter
+	case "group":
+		if h.Group != nil && h.Group.Name != "" {
+			return "Group: " + h.Group.Name
+		}
+		if h.Parameter == "" {
+			return "Any logged in user"
+		}
+		return "Group: " + h.Parameter
+	case "projectRole":
+		if h.ProjectRole != nil {
+			return "Project role: " + h.ProjectRole.Name
+		}
+		return "Project role: " + h.Parameter
+	case "user":
+		if h.User != nil {
+			return "User: " + h.User.DisplayName
+		}
+		return "User: " + h.Parameter
+	case "reporter":
+		return "Reporter"
+	case "assignee":
+		return "Current assignee"
+	case "projectLead":
+		return "Project lead"
+	default:
+		if h.Parameter != "" {
+			return h.Type + ": " + h.Parameter
+		}
+		return h.Type
+	}
+}
+
+// riskOf returns a non-empty explanation if granting permission to the holder
+// is considered risky.
+func riskOf(permission string, h permissionHolder) string {
+	broad := false
+	switch h.Type {
+	case "anyone":
+		return "granted to anonymous users"
+	case "applicationRole":
+		broad = true
+	case "group":
+		name := h.Parameter
+		if h.Group != nil && h.Group.Name != "" {
+			name = h.Group.Name
+		}
+		broad = name == "" || broadGroups[strings.ToLower(name)]
+	}
+	if broad && sensitivePermissions[permission] {
+		return "sensitive permission granted to all users"
+	}
+	return ""
+}
+
+// AuditProjectPermissions reports the grants of a project's permission scheme,
+// grouped by permission, and flags grants that are risky.
+func (j *JiraMCPServer) AuditProjectPermissions(ctx context.Context, req *mcp.CallToolRequest, params *AuditProjectPermissionsParams) (*mcp.CallToolResult, any, error) {
+	projectKey := j.projectKeyOrDefault(params.ProjectKey)
+
+	var scheme permissionScheme
+	path := fmt.Sprintf("rest/api/2/project/%s/permissionscheme?expand=permissions,user,group,projectRole", projectKey)
+	if _, err := j.jiraDo(ctx, "GET", path, nil, &scheme); err != nil {
+		return textResult("Failed to get permission scheme for project %s (this requires Jira administrator access): %v", projectKey, err), nil, nil
+	}
+
+	grants := make(map[string][]string)
+	var risks []string
+	for _, g := range scheme.Permissions {
+		holder := g.Holder.describe()
+		grants[g.Permission] = append(grants[g.Permission], holder)
+		if risk := riskOf(g.Permission, g.Holder); risk != "" {
+			risks = append(risks, fmt.Sprintf("%s → %s: %s", g.Permission, holder, risk))
+		}
+	}
+	permissions := make([]string, 0, len(grants))
+	for p := range grants {
+		permissions = append(permissions, p)
+	}
+	sort.Strings(permissions)
+	sort.Strings(risks)
+
+	verbosity := j.verbosity(params.Verbosity)
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "Permission scheme for %s: %s (id %d)\n", projectKey, scheme.Name, scheme.ID)
+	if len(risks) > 0 {
+		fmt.Fprintf(&sb, "\nRisky grants (%d):\n", len(risks))
+		for _, r := range risks {
+			fmt.Fprintf(&sb, "  ! %s\n", r)
+		}
+	} else {
+		sb.WriteString("\nNo risky grants found.\n")
+	}
+	if verbosity == VerbosityMinimal {
+		return textResult("%s", sb.String()), nil, nil
+	}
+	sb.WriteString("\nGrants:\n")
+	for _, p := range permissions {
+		fmt.Fprintf(&sb, "- %s: %s\n", p, strings.Join(grants[p], "; "))
+	}
+
+	if params.IncludeRoles || verbosity == VerbosityFull {
+		var roles map[string]string
+		if _, err := j.jiraDo(ctx, "GET", fmt.Sprintf("rest/api/2/project/%s/role", projectKey), nil, &roles); err != nil {
+			fmt.Fprintf(&sb, "\nFailed to list project roles: %v\n", err)
+		} else {
+			names := make([]string, 0, len(roles))
+			for name := range roles {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+			sb.WriteString("\nProject roles:\n")
+			lines := make([]string, len(names))
+			forEachParallel(ctx, j.fetchConcurrency(), len(names), func(ctx context.Context, i int) error {
+				var role projectRole
+				// The role map holds absolute URLs, so only the path suffix is used.
+				roleURL := roles[names[i]]
+				if k := strings.Index(roleURL, "rest/api/"); k >= 0 {
+					roleURL = roleURL[k:]
+				}
+				if _, err := j.jiraDo(ctx, "GET", roleURL, nil, &role); err != nil {
+					lines[i] = fmt.Sprintf("- %s: failed to load members: %v\n", names[i], err)
+					return nil
+				}
+				members := make([]string, 0, len(role.Actors))
+				for _, a := range role.Actors {
+					members = append(members, a.DisplayName)
+				}
+				if len(members) == 0 {
+					members = append(members, "(no members)")
+				}
+				lines[i] = fmt.Sprintf("- %s: %s\n", names[i], strings.Join(members, ", "))
+				return nil
+			})
+			for _, line := range lines {
+				sb.WriteString(line)
+			}
+		}
+	}
+
+	return textResult("%s", sb.String()), nil, nil
+}
+
+type CheckMyPermissionsParams struct {
+	// ProjectKey or IssueKey scopes the check; issue-level security and
+	// workflow conditions only apply when an issue is given.
+	ProjectKey string `json:"projectKey,omitempty"`
+	IssueKey   string `json:"issueKey,omitempty"`
+}
+
+// checkedOperations are the operations check-my-permissions reports, with
+// the Jira permission each needs.
+var checkedOperations = []struct {
+	operation, permission string
+}{
+	{"browse", "BROWSE_PROJECTS"},
+	{"create", "CREATE_ISSUES"},
+	{"edit", "EDIT_ISSUES"},
+	{"transition", "TRANSITION_ISSUES"},
+	{"assign", "ASSIGN_ISSUES"},
+	{"comment", "ADD_COMMENTS"},
+	{"link", "LINK_ISSUES"},
+	{"attach", "CREATE_ATTACHMENTS"},
+	{"manage watchers", "MANAGE_WATCHERS"},
+	{"delete", "DELETE_ISSUES"},
+}
+
+type myPermissions struct {
+	Permissions map[string]struct {
+		Name           string `json:"name"`
+		HavePermission bool   `json:"havePermission"`
+	} `json:"permissions"`
+}
+
+// CheckMyPermissions reports which operations the account the server acts as
+// may perform in a project or on an issue, so agents can stop early instead
+// of running into 403s halfway through a workflow.
+func (j *JiraMCPServer) CheckMyPermissions(ctx context.Context, req *mcp.CallToolRequest, params *CheckMyPermissionsParams) (*mcp.CallToolResult, any, error) {
+	keys := make([]string, 0, len(checkedOperations))
+	for _, op := range checkedOperations {
+		keys = append(keys, op.permission)
+	}
+	scope := ""
+	query := "permissions=" + strings.Join(keys, ",")
+	if params.IssueKey != "" {
+		scope = strings.ToUpper(params.IssueKey)
+		query += "&issueKey=" + scope
+	} else {
+		scope = j.projectKeyOrDefault(params.ProjectKey)
+		query += "&projectKey=" + scope
+	}
+
+	var perms myPermissions
+	if _, err := j.jiraDo(ctx, "GET", "rest/api/2/mypermissions?"+query, nil, &perms); err != nil {
+		return textResult("Failed to check permissions on %s: %v", scope, err), nil, nil
+	}
+
+	var allowed, denied []string
+	for _, op := range checkedOperations {
+		p, ok := perms.Permissions[op.permission]
+		switch {
+		case !ok:
+			denied = append(denied, fmt.Sprintf("%s (%s not reported by Jira)", op.operation, op.permission))
+		case p.HavePermission:
+			allowed = append(allowed, op.operation)
+		default:
+			denied = append(denied, fmt.Sprintf("%s (needs %s)", op.operation, op.permission))
+		}
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "Permissions on %s:\n", scope)
+	if len(allowed) > 0 {
+		fmt.Fprintf(&sb, "Allowed: %s\n", strings.Join(allowed, ", "))
+	}
+	if len(denied) > 0 {
+		fmt.Fprintf(&sb, "Denied: %s\n", strings.Join(denied, ", "))
+	}
+	// Jira's answer is not the whole story: the server may hold back changes
+	// it would permit.
+	switch {
+	case j.config.ReadOnly:
+		sb.WriteString("Note: this server is read-only, so no changes can be made through it regardless.\n")
+	case j.config.Mode == ModeCommenter:
+		sb.WriteString("Note: this server is in commenter mode, so only comments can be added through it.\n")
+	}
+	if !j.config.AllowDelete {
+		sb.WriteString("Note: deleting is disabled on this server.\n")
+	}
+	return textResult("%s", sb.String()), nil, nil
+}
@@ -0,0 +1,97 @@
+package jiramcp
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	_ "github.com/jackc/pgx/v5/stdlib"
+)
+
+// pgQueryTimeout bounds each query against the Postgres store.
+const pgQueryTimeout = 10 * time.Second
+
+// postgresStore keeps state in a Postgres table so several replicas of the
+// server share snapshots, settings, and rotation positions.
+type postgresStore struct {
+	db *sql.DB
+}
+
+const pgSchema = `CREATE TABLE IF NOT EXISTS jira_mcp_state (
+	bucket     TEXT NOT NULL,
+	key        TEXT NOT NULL,
+	value      JSONB NOT NULL,
+	updated_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+	PRIMARY KEY (bucket, key)
+)`
+
+func openPostgresStore(dsn string) (*postgresStore, error) {
+	db, err := sql.Open("pgx", dsn)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open Postgres store: %w", err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), pgQueryTimeout)
+	defer cancel()
+	if _, err := db.ExecContext(ctx, pgSchema); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to prepare Postgres store: %w", err)
+	}
+	return &postgresStore{db: db}, nil
+}
+
+func (s *postgresStore) Put(bucket, key string, v interface{}) error {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), pgQueryTimeout)
+	defer cancel()
+	_, err = s.db.ExecContext(ctx, `INSERT INTO jira_mcp_state (bucket, key, value) VALUES ($1, $2, $3)
+		ON CONFLICT (bucket, key) DO UPDATE SET value = EXCLUDED.value, updated_at = now()`, bucket, key, b)
+	return err
+}
+
+func (s *postgresStore) Get(bucket, key string, v interface{}) (bool, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), pgQueryTimeout)
+	defer cancel()
+	var b []byte
+	err := s.db.QueryRowContext(ctx, `SELECT value FROM jira_mcp_state WHERE bucket = $1 AND key = $2`, bucket, key).Scan(&b)
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, json.Unmarshal(b, v)
+}
+
+func (s *postgresStore) Delete(bucket, key string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), pgQueryTimeout)
+	defer cancel()
+	_, err := s.db.ExecContext(ctx, `DELETE FROM jira_mcp_state WHERE bucket = $1 AND key = $2`, bucket, key)
+	return err
+}
+
+func (s *postgresStore) Keys(bucket string) ([]string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), pgQueryTimeout)
+	defer cancel()
+	rows, err := s.db.QueryContext(ctx, `SELECT key FROM jira_mcp_state WHERE bucket = $1 ORDER BY key`, bucket)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var keys []string
+	for rows.Next() {
+		var k string
+		if err := rows.Scan(&k); err != nil {
+			return nil, err
+		}
+		keys = append(keys, k)
+	}
+	return keys, rows.Err()
+}
+
+func (s *postgresStore) Close() error { return s.db.Close() }
@@ -0,0 +1,160 @@
+package jiramcp
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+const (
+	// maxPolledIssues bounds the issues one poll reads.
+	maxPolledIssues = 500
+	// pollOverlap is added to each poll's window, since JQL compares
+	// update times by the minute.
+	pollOverlap = time.Minute
+	// maxPollWindow bounds the window after a long run of failed polls.
+	maxPollWindow = 24 * time.Hour
+)
+
+// polledIssue is what the poller last saw of an issue.
+type polledIssue struct {
+	updated time.Time
+	status  string
+}
+
+// pollProjects returns the projects the poller watches: JIRA_MCP_POLL_PROJECTS,
+// otherwise the allowed projects, otherwise the configured project.
+func (j *JiraMCPServer) pollProjects() []string {
+	if len(j.config.PollProjects) > 0 {
+		return j.config.PollProjects
+	}
+	if allowed := j.currentSettings().AllowedProjects; len(allowed) > 0 {
+		return allowed
+	}
+	return []string{j.config.ProjectKey}
+}
+
+// nextPollInterval adapts the interval to the last poll: back to the
+// configured interval when issues changed, slowly longer (up to four times)
+// while nothing does, and twice as long after a failure, which is usually
+// Jira rate limiting or being unavailable.
+func nextPollInterval(current, base time.Duration, changed int, err error) time.Duration {
+	switch {
+	case err != nil:
+		return min(2*current, 16*base)
+	case changed > 0:
+		return base
+	default:
+		return min(current*3/2, 4*base)
+	}
+}
+
+// RunPoller polls Jira for recently updated issues every
+// JIRA_MCP_POLL_INTERVAL until ctx is cancelled, and notifies clients of the
+// changes as the webhook endpoint does. It is meant for deployments where
+// Jira cannot reach the server with webhooks.
+func (j *JiraMCPServer) RunPoller(ctx context.Context) {
+	base := j.config.PollInterval
+	interval := base
+	seen := make(map[string]polledIssue)
+	since := time.Now()
+	// The first poll only learns the current state, so that issues updated
+	// shortly before startup are not reported as changes.
+	baseline := true
+	slog.Info("Polling Jira for issue updates", "interval", base, "projects", j.pollProjects())
+	for {
+		changed, next, err := j.pollUpdates(ctx, since, seen, !baseline)
+		if err != nil {
+			slog.Warn("Polling Jira for updates failed", "error", err)
+		} else {
+			since, baseline = next, false
+		}
+		if next := nextPollInterval(interval, base, changed, err); next != interval {
+			slog.Debug("Adjusted Jira poll interval", "interval", next)
+			interval = next
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(interval):
+		}
+	}
+}
+
+// pollUpdates searches for issues updated since the last successful poll
+// and reports those whose update time moved past what was last seen. It
+// returns the number of changed issues and where the next poll starts.
+func (j *JiraMCPServer) pollUpdates(ctx context.Context, since time.Time, seen map[string]polledIssue, notify bool) (int, time.Time, error) {
+	next := time.Now()
+	window := min(time.Since(since)+pollOverlap, maxPollWindow)
+	minutes := int(math.Ceil(window.Minutes()))
+	jql := fmt.Sprintf("project in (%s) AND updated >= -%dm ORDER BY updated ASC", strings.Join(j.pollProjects(), ", "), minutes)
+	issues, err := j.searchIssues(ctx, jql, []string{"summary", "status", "created", "updated"}, "", maxPolledIssues)
+	if err != nil {
+		return 0, since, err
+	}
+	if len(issues) == maxPolledIssues && issues[len(issues)-1].Fields != nil {
+		// Issues are read oldest update first, so the next poll picks up
+		// where this one stopped.
+		slog.Warn("Poll found more updated issues than it reads; the rest are reported by the next poll", "max", maxPolledIssues)
+		next = time.Time(issues[len(issues)-1].Fields.Updated)
+	}
+
+	changed := 0
+	for i := range issues {
+		issue := &issues[i]
+		f := issue.Fields
+		if f == nil {
+			continue
+		}
+		updated := time.Time(f.Updated)
+		status := ""
+		if f.Status != nil {
+			status = f.Status.Name
+		}
+		prev, known := seen[issue.Key]
+		if known && !updated.After(prev.updated) {
+			continue
+		}
+		seen[issue.Key] = polledIssue{updated: updated, status: status}
+		changed++
+		if !notify {
+			continue
+		}
+
+		var message string
+		switch {
+		case !known && !time.Time(f.Created).Before(since.Add(-pollOverlap)):
+			message = fmt.Sprintf("%s created: %s", issue.Key, f.Summary)
+		case known && prev.status != status:
+			message = fmt.Sprintf("%s moved from %s to %s: %s", issue.Key, prev.status, status, f.Summary)
+		default:
+			message = fmt.Sprintf("%s updated: %s", issue.Key, f.Summary)
+		}
+		slog.Info("Jira poll", "issueKey", issue.Key, "message", message)
+		// A search cannot tell comments from other edits, so subscribers of
+		// the discussion are told about every update.
+		uri := fmt.Sprintf("jira://issue/%s/discussion-summary", issue.Key)
+		if err := j.server.ResourceUpdated(ctx, &mcp.ResourceUpdatedNotificationParams{URI: uri}); err != nil {
+			slog.Warn("Failed to send resource update", "uri", uri, "error", err)
+		}
+		j.broadcastLog(ctx, "info", "jira-poller", []string{projectOfIssue(issue.Key)}, map[string]interface{}{
+			"issueKey": issue.Key,
+			"status":   status,
+			"message":  message,
+		})
+	}
+
+	// Issues that left the window can no longer be reported twice.
+	for key, issue := range seen {
+		if issue.updated.Before(since.Add(-maxPollWindow)) {
+			delete(seen, key)
+		}
+	}
+	return changed, next, nil
+}
@@ -0,0 +1,145 @@
+package jiramcp
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/andygrunwald/go-jira"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+type ListPrioritiesParams struct {
+	// ProjectKey limits the list to the priorities of the project's priority scheme.
+	ProjectKey string `json:"projectKey,omitempty"`
+}
+
+// projectPriorityScheme is the Server/Data Center representation of the
+// priority scheme associated with a project.
+type projectPriorityScheme struct {
+	ID              int64    `json:"id"`
+	Name            string   `json:"name"`
+	OptionIDs       []string `json:"optionIds"`
+	DefaultOptionID string   `json:"defaultOptionId"`
+}
+
+// cloudPrioritySchemes is the Jira Cloud priority scheme search response.
+type cloudPrioritySchemes struct {
+	Values []struct {
+		ID         string `json:"id"`
+		Name       string `json:"name"`
+		Priorities struct {
+			Values []jira.Priority `json:"values"`
+		} `json:"priorities"`
+	} `json:"values"`
+}
+
+// projectPriorities returns the priorities available in a project and the name
+// of its priority scheme. Server/Data Center expose the scheme on the project
+// and Jira Cloud through the priority scheme search; when neither can be read
+// (for example without project admin permission) every priority of the
+// instance is returned with an empty scheme name.
+func (j *JiraMCPServer) projectPriorities(ctx context.Context, projectKey string) ([]jira.Priority, string, error) {
+	type schemePriorities struct {
+		Priorities []jira.Priority
+		Scheme     string
+	}
+	sp, err := cached(ctx, j.cache, "metadata", "priorities:"+strings.ToUpper(projectKey), func() (schemePriorities, error) {
+		priorities, scheme, err := j.lookupProjectPriorities(ctx, projectKey)
+		return schemePriorities{priorities, scheme}, err
+	})
+	return sp.Priorities, sp.Scheme, err
+}
+
+// instancePriorities returns every priority of the instance.
+func (j *JiraMCPServer) instancePriorities(ctx context.Context) ([]jira.Priority, error) {
+	return cached(ctx, j.cache, "metadata", "priorities", func() ([]jira.Priority, error) {
+		priorities, _, err := j.client(ctx).ListPriorities(ctx)
+		return priorities, err
+	})
+}
+
+func (j *JiraMCPServer) lookupProjectPriorities(ctx context.Context, projectKey string) ([]jira.Priority, string, error) {
+	all, err := j.instancePriorities(ctx)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to list priorities: %w", err)
+	}
+
+	var scheme projectPriorityScheme
+	if _, err := j.jiraDo(ctx, "GET", fmt.Sprintf("rest/api/2/project/%s/priorityscheme", projectKey), nil, &scheme); err == nil {
+		byID := make(map[string]jira.Priority, len(all))
+		for _, p := range all {
+			byID[p.ID] = p
+		}
+		priorities := make([]jira.Priority, 0, len(scheme.OptionIDs))
+		for _, id := range scheme.OptionIDs {
+			if p, ok := byID[id]; ok {
+				priorities = append(priorities, p)
+			}
+		}
+		return priorities, scheme.Name, nil
+	}
+
+	if project, err := j.getProject(ctx, projectKey); err == nil {
+		var schemes cloudPrioritySchemes
+		path := fmt.Sprintf("rest/api/2/priorityscheme?projectId=%s&expand=priorities", project.ID)
+		if _, err := j.jiraDo(ctx, "GET", path, nil, &schemes); err == nil && len(schemes.Values) > 0 {
+			return schemes.Values[0].Priorities.Values, schemes.Values[0].Name, nil
+		}
+	}
+	return all, "", nil
+}
+
+// priorityProblem describes why priority cannot be used in a project, listing
+// the priorities available instead. It returns "" if the priority is valid and
+// an error if the priorities could not be loaded.
+func (j *JiraMCPServer) priorityProblem(ctx context.Context, projectKey, priority string) (string, error) {
+	priorities, scheme, err := j.projectPriorities(ctx, projectKey)
+	if err != nil {
+		return "", err
+	}
+	names := make([]string, 0, len(priorities))
+	for _, p := range priorities {
+		if strings.EqualFold(p.Name, priority) {
+			return "", nil
+		}
+		names = append(names, p.Name)
+	}
+	if scheme != "" {
+		return unknownNameProblem("priority", priority, fmt.Sprintf("is not in %s's priority scheme %q", projectKey, scheme), names, priorityAliases), nil
+	}
+	return unknownNameProblem("priority", priority, "does not exist", names, priorityAliases), nil
+}
+
+// ListPriorities lists the priorities of the instance or of a project's
+// priority scheme.
+func (j *JiraMCPServer) ListPriorities(ctx context.Context, req *mcp.CallToolRequest, params *ListPrioritiesParams) (*mcp.CallToolResult, any, error) {
+	var priorities []jira.Priority
+	var err error
+	header := "Priorities:"
+	if params.ProjectKey != "" {
+		projectKey := strings.ToUpper(params.ProjectKey)
+		var scheme string
+		priorities, scheme, err = j.projectPriorities(ctx, projectKey)
+		header = fmt.Sprintf("Priorities available in %s:", projectKey)
+		if scheme != "" {
+			header = fmt.Sprintf("Priorities available in %s (scheme %q):", projectKey, scheme)
+		}
+	} else {
+		priorities, err = j.instancePriorities(ctx)
+	}
+	if err != nil {
+		return textResult("Failed to list priorities: %v", err), nil, nil
+	}
+
+	var sb strings.Builder
+	sb.WriteString(header + "\n")
+	for _, p := range priorities {
+		fmt.Fprintf(&sb, "- %s (id %s)", p.Name, p.ID)
+		if p.Description != "" {
+			fmt.Fprintf(&sb, " — %s", p.Description)
+		}
+		sb.WriteString("\n")
+	}
+	return textResult("%s", sb.String()), nil, nil
+}
@@ -0,0 +1,98 @@
+package jiramcp
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+)
+
+// probeTimeout bounds each startup probe.
+const probeTimeout = 10 * time.Second
+
+// optionalAPI is a Jira API that not every instance or account can use. Its
+// tools are hidden when the probe shows the configured credentials cannot
+// reach it.
+type optionalAPI struct {
+	name  string
+	tools []string
+	// probe returns a reason when the API is out of reach, and an error when
+	// that could not be determined.
+	probe func(ctx context.Context, j *JiraMCPServer) (string, error)
+}
+
+// optionalAPIs are probed at startup when JIRA_MCP_PROBE_APIS is enabled.
+var optionalAPIs = []optionalAPI{
+	{
+		name:  "Jira Software (Agile)",
+		tools: []string{"list-boards", "get-backlog", "rank-issue", "reorder-backlog", "sprint-summary"},
+		probe: probeEndpoint("rest/agile/1.0/board?maxResults=1"),
+	},
+	{
+		name: "Jira Service Management",
+		tools: []string{"list-service-desks", "list-request-types", "create-customer-request", "add-request-comment",
+			"get-request-sla", "get-request-comments", "list-queues", "get-queue-comments"},
+		probe: probeEndpoint("rest/servicedeskapi/servicedesk?limit=1"),
+	},
+	{
+		name:  "Jira administration",
+		tools: []string{"get-audit-records", "audit-project-permissions"},
+		probe: probeAdmin,
+	},
+}
+
+// probeEndpoint probes an API by requesting path. Only answers that will not
+// change on retry (401, 403, 404) make the API unavailable.
+func probeEndpoint(path string) func(context.Context, *JiraMCPServer) (string, error) {
+	return func(ctx context.Context, j *JiraMCPServer) (string, error) {
+		resp, err := j.jiraDo(ctx, "GET", path, nil, nil)
+		if resp != nil {
+			defer resp.Body.Close()
+			switch resp.StatusCode {
+			case http.StatusUnauthorized, http.StatusForbidden, http.StatusNotFound:
+				return fmt.Sprintf("GET /%s returned %d", path, resp.StatusCode), nil
+			}
+		}
+		return "", err
+	}
+}
+
+// probeAdmin checks for the Administer Jira global permission.
+func probeAdmin(ctx context.Context, j *JiraMCPServer) (string, error) {
+	var perms struct {
+		Permissions map[string]struct {
+			HavePermission bool `json:"havePermission"`
+		} `json:"permissions"`
+	}
+	if _, err := j.jiraDo(ctx, "GET", "rest/api/2/mypermissions?permissions=ADMINISTER", nil, &perms); err != nil {
+		return "", err
+	}
+	if !perms.Permissions["ADMINISTER"].HavePermission {
+		return fmt.Sprintf("%s lacks the Administer Jira permission", j.config.Username), nil
+	}
+	return "", nil
+}
+
+// probeAPIs records the tools of the optional APIs the service account cannot
+// reach in j.unavailableTools, so addTools leaves them out. When a probe
+// fails for another reason, such as a timeout, the tools are kept.
+func (j *JiraMCPServer) probeAPIs(ctx context.Context) {
+	j.unavailableTools = make(map[string]string)
+	for _, api := range optionalAPIs {
+		probeCtx, cancel := context.WithTimeout(ctx, probeTimeout)
+		reason, err := api.probe(probeCtx, j)
+		cancel()
+		if err != nil {
+			slog.Warn("Could not probe an optional Jira API; its tools stay available", "api", api.name, "error", err)
+			continue
+		}
+		if reason == "" {
+			continue
+		}
+		slog.Info("Optional Jira API is unavailable; hiding its tools", "api", api.name, "reason", reason, "tools", api.tools)
+		for _, tool := range api.tools {
+			j.unavailableTools[tool] = fmt.Sprintf("%s unavailable: %s", api.name, reason)
+		}
+	}
+}
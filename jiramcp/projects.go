@@ -0,0 +1,97 @@
+package jiramcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/andygrunwald/go-jira"
+)
+
+// ProjectDefaults are the values create-jira-issue uses for a project when
+// the request leaves them out, configured in JIRA_MCP_PROJECTS_FILE.
+type ProjectDefaults struct {
+	IssueType  string   `json:"issueType,omitempty"`
+	Labels     []string `json:"labels,omitempty"`
+	Components []string `json:"components,omitempty"`
+	// SecurityLevel is the issue security level new issues get, so that a
+	// project's issues are restricted unless a request names another level.
+	SecurityLevel string `json:"securityLevel,omitempty"`
+}
+
+// loadProjects reads the projects file, a JSON object of defaults by project
+// key.
+func loadProjects(path string) (map[string]ProjectDefaults, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read projects file: %w", err)
+	}
+	var raw map[string]ProjectDefaults
+	if err := json.Unmarshal(b, &raw); err != nil {
+		return nil, fmt.Errorf("failed to parse projects file %s: %w", path, err)
+	}
+	projects := make(map[string]ProjectDefaults, len(raw))
+	for key, d := range raw {
+		if strings.TrimSpace(key) == "" {
+			return nil, fmt.Errorf("projects file %s: a project has an empty key", path)
+		}
+		projects[strings.ToUpper(key)] = d
+	}
+	return projects, nil
+}
+
+// projectDefaults returns the configured defaults of a project.
+func (j *JiraMCPServer) projectDefaults(projectKey string) ProjectDefaults {
+	return j.config.Projects[strings.ToUpper(projectKey)]
+}
+
+// accessibleProjects lists the projects the service account can browse.
+func (j *JiraMCPServer) accessibleProjects(ctx context.Context) ([]jira.Project, error) {
+	return cached(ctx, j.cache, "project", "projects", func() ([]jira.Project, error) {
+		var projects []jira.Project
+		_, err := j.jiraDo(ctx, "GET", "rest/api/2/project", nil, &projects)
+		return projects, err
+	})
+}
+
+// projectKeyProblem describes why projectKey names no accessible project,
+// suggesting the project it most likely meant, or returns "" when it is
+// fine or the projects cannot be listed.
+func (j *JiraMCPServer) projectKeyProblem(ctx context.Context, projectKey string) string {
+	projects, err := j.accessibleProjects(ctx)
+	if err != nil {
+		logger(ctx).Warn("Could not list projects to check a project key", "error", err)
+		return ""
+	}
+	keys := make([]string, 0, len(projects))
+	guess := ""
+	for _, p := range projects {
+		if strings.EqualFold(p.Key, projectKey) {
+			return ""
+		}
+		// Agents often pass the project's name instead of its key.
+		if strings.EqualFold(p.Name, projectKey) {
+			guess = p.Key
+		}
+		keys = append(keys, p.Key)
+	}
+	if guess == "" {
+		guess = didYouMean(projectKey, keys, nil)
+	}
+	problem := fmt.Sprintf("project %q does not exist or is not accessible", projectKey)
+	if guess != "" {
+		problem += fmt.Sprintf(". Did you mean %q?", guess)
+	}
+	if len(j.config.Projects) > 0 {
+		known := make([]string, 0, len(j.config.Projects))
+		for key := range j.config.Projects {
+			known = append(known, key)
+		}
+		sort.Strings(known)
+		problem += fmt.Sprintf(" (configured projects: %s)", strings.Join(known, ", "))
+	}
+	return problem
+}
@@ -0,0 +1,356 @@
+package jiramcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/andygrunwald/go-jira"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// projectPlaceholder stands for the project key in the names and JQL of
+// filters in a project setup.
+const projectPlaceholder = "{{project}}"
+
+// setupName restricts the names of project setup files.
+var setupName = regexp.MustCompile(`^[A-Za-z0-9_.-]+$`)
+
+// projectSetup is the portable configuration of a project written by
+// snapshot-project-setup and read by apply-project-setup.
+type projectSetup struct {
+	SourceProject string           `json:"sourceProject"`
+	CapturedAt    time.Time        `json:"capturedAt"`
+	Components    []setupComponent `json:"components,omitempty"`
+	Versions      []jira.Version   `json:"versions,omitempty"`
+	Filters       []setupFilter    `json:"filters,omitempty"`
+	Templates     []issueTemplate  `json:"templates,omitempty"`
+}
+
+type setupComponent struct {
+	Name          string `json:"name"`
+	Description   string `json:"description,omitempty"`
+	AssigneeType  string `json:"assigneeType,omitempty"`
+	LeadAccountID string `json:"leadAccountId,omitempty"`
+}
+
+type setupFilter struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	JQL         string `json:"jql"`
+}
+
+type SnapshotProjectSetupParams struct {
+	ProjectKey string `json:"projectKey,omitempty"`
+	// Name names the setup file; defaults to the project key.
+	Name string `json:"name,omitempty"`
+}
+
+type ApplyProjectSetupParams struct {
+	ProjectKey string `json:"projectKey"`
+	// Name selects a setup saved by snapshot-project-setup; Setup passes the
+	// JSON of one instead, e.g. from another server.
+	Name   string `json:"name,omitempty"`
+	Setup  string `json:"setup,omitempty"`
+	DryRun bool   `json:"dryRun,omitempty"`
+}
+
+// setupDir is where project setups are saved.
+func (j *JiraMCPServer) setupDir() string {
+	return filepath.Join(j.config.StateDir, "project-setups")
+}
+
+// projectKeyPattern matches a project key as a word, along with the number
+// of an issue key it starts.
+func projectKeyPattern(key string) *regexp.Regexp {
+	return regexp.MustCompile(`\b` + regexp.QuoteMeta(key) + `\b(-\d+)?`)
+}
+
+// templatizeProject replaces the project key in s with projectPlaceholder.
+// Issue keys are left alone, since the issues do not exist in other projects.
+func templatizeProject(s, key string) string {
+	return projectKeyPattern(key).ReplaceAllStringFunc(s, func(m string) string {
+		if strings.Contains(m, "-") {
+			return m
+		}
+		return projectPlaceholder
+	})
+}
+
+// projectFilters returns the filters visible to the user whose JQL refers to
+// the project.
+func (j *JiraMCPServer) projectFilters(ctx context.Context, projectKey string) ([]setupFilter, error) {
+	pattern := projectKeyPattern(projectKey)
+	var filters []setupFilter
+	for startAt := 0; ; {
+		var page filterSearchPage
+		path := fmt.Sprintf("rest/api/2/filter/search?expand=jql,description&maxResults=100&startAt=%d", startAt)
+		if _, err := j.jiraDo(ctx, "GET", path, nil, &page); err != nil {
+			return nil, err
+		}
+		for _, f := range page.Values {
+			for _, m := range pattern.FindAllString(f.Jql, -1) {
+				if !strings.Contains(m, "-") {
+					filters = append(filters, setupFilter{
+						Name:        templatizeProject(f.Name, projectKey),
+						Description: f.Description,
+						JQL:         templatizeProject(f.Jql, projectKey),
+					})
+					break
+				}
+			}
+		}
+		startAt += len(page.Values)
+		if page.IsLast || len(page.Values) == 0 || startAt >= page.Total {
+			return filters, nil
+		}
+	}
+}
+
+// SnapshotProjectSetup saves a project's components, versions, and filters,
+// along with the server's issue templates, as a project setup that
+// apply-project-setup can reproduce in other projects.
+func (j *JiraMCPServer) SnapshotProjectSetup(ctx context.Context, req *mcp.CallToolRequest, params *SnapshotProjectSetupParams) (*mcp.CallToolResult, any, error) {
+	projectKey := j.projectKeyOrDefault(params.ProjectKey)
+	name := params.Name
+	if name == "" {
+		name = projectKey
+	}
+	if !setupName.MatchString(name) {
+		return textResult("name may only contain letters, digits, '.', '_', and '-'"), nil, nil
+	}
+
+	setup := projectSetup{SourceProject: projectKey, CapturedAt: time.Now().UTC()}
+	var components []jira.ProjectComponent
+	if _, err := j.jiraDo(ctx, "GET", fmt.Sprintf("rest/api/2/project/%s/components", projectKey), nil, &components); err != nil {
+		return textResult("Failed to list components of %s: %v", projectKey, err), nil, nil
+	}
+	for _, c := range components {
+		setup.Components = append(setup.Components, setupComponent{
+			Name:          c.Name,
+			Description:   c.Description,
+			AssigneeType:  c.AssigneeType,
+			LeadAccountID: j.userID(&c.Lead),
+		})
+	}
+	var versions []jira.Version
+	if _, err := j.jiraDo(ctx, "GET", fmt.Sprintf("rest/api/2/project/%s/versions", projectKey), nil, &versions); err != nil {
+		return textResult("Failed to list versions of %s: %v", projectKey, err), nil, nil
+	}
+	for _, v := range versions {
+		setup.Versions = append(setup.Versions, jira.Version{
+			Name:        v.Name,
+			Description: v.Description,
+			Archived:    v.Archived,
+			Released:    v.Released,
+			StartDate:   v.StartDate,
+			ReleaseDate: v.ReleaseDate,
+		})
+	}
+	filters, err := j.projectFilters(ctx, projectKey)
+	if err != nil {
+		return textResult("Failed to list filters of %s: %v", projectKey, err), nil, nil
+	}
+	setup.Filters = filters
+	templates, err := j.issueTemplates()
+	if err != nil {
+		return textResult("Failed to load issue templates: %v", err), nil, nil
+	}
+	for _, t := range templates {
+		setup.Templates = append(setup.Templates, *t)
+	}
+	sortTemplates(setup.Templates)
+
+	b, err := json.MarshalIndent(setup, "", "  ")
+	if err != nil {
+		return textResult("Failed to encode the setup of %s: %v", projectKey, err), nil, nil
+	}
+	if err := os.MkdirAll(j.setupDir(), 0o700); err != nil {
+		return textResult("Failed to create the setup directory: %v", err), nil, nil
+	}
+	path := filepath.Join(j.setupDir(), name+".json")
+	if err := os.WriteFile(path, b, 0o600); err != nil {
+		return textResult("Failed to save the setup of %s: %v", projectKey, err), nil, nil
+	}
+	logger(ctx).Info("Saved project setup", "project", projectKey, "path", path)
+	return textResult("Saved the setup of %s as %q (%d components, %d versions, %d filters, %d templates) to %s:\n%s",
+		projectKey, name, len(setup.Components), len(setup.Versions), len(setup.Filters), len(setup.Templates), path, b), nil, nil
+}
+
+// ApplyProjectSetup creates the components, versions, and filters of a
+// project setup that a project lacks, and adds the setup's issue templates
+// the server does not have. Existing items, matched by name, are left alone.
+func (j *JiraMCPServer) ApplyProjectSetup(ctx context.Context, req *mcp.CallToolRequest, params *ApplyProjectSetupParams) (*mcp.CallToolResult, any, error) {
+	projectKey := strings.ToUpper(params.ProjectKey)
+	if projectKey == "" {
+		return textResult("projectKey is required"), nil, nil
+	}
+	raw := []byte(params.Setup)
+	switch {
+	case params.Name != "" && params.Setup != "":
+		return textResult("Set name or setup, not both"), nil, nil
+	case params.Name != "":
+		if !setupName.MatchString(params.Name) {
+			return textResult("Unknown project setup %q", params.Name), nil, nil
+		}
+		b, err := os.ReadFile(filepath.Join(j.setupDir(), params.Name+".json"))
+		if err != nil {
+			return textResult("Failed to read project setup %q: %v", params.Name, err), nil, nil
+		}
+		raw = b
+	case params.Setup == "":
+		return textResult("name or setup is required"), nil, nil
+	}
+	var setup projectSetup
+	if err := json.Unmarshal(raw, &setup); err != nil {
+		return textResult("Invalid project setup: %v", err), nil, nil
+	}
+
+	var components []jira.ProjectComponent
+	if _, err := j.jiraDo(ctx, "GET", fmt.Sprintf("rest/api/2/project/%s/components", projectKey), nil, &components); err != nil {
+		return textResult("Failed to list components of %s: %v", projectKey, err), nil, nil
+	}
+	var versions []jira.Version
+	if _, err := j.jiraDo(ctx, "GET", fmt.Sprintf("rest/api/2/project/%s/versions", projectKey), nil, &versions); err != nil {
+		return textResult("Failed to list versions of %s: %v", projectKey, err), nil, nil
+	}
+	project, err := j.getProject(ctx, projectKey)
+	if err != nil {
+		return textResult("Failed to get project %s: %v", projectKey, err), nil, nil
+	}
+	templates, err := j.issueTemplates()
+	if err != nil {
+		return textResult("Failed to load issue templates: %v", err), nil, nil
+	}
+
+	var newComponents []setupComponent
+	for _, c := range setup.Components {
+		exists := false
+		for _, e := range components {
+			exists = exists || strings.EqualFold(e.Name, c.Name)
+		}
+		if !exists {
+			newComponents = append(newComponents, c)
+		}
+	}
+	var newVersions []jira.Version
+	for _, v := range setup.Versions {
+		exists := false
+		for _, e := range versions {
+			exists = exists || strings.EqualFold(e.Name, v.Name)
+		}
+		if !exists {
+			v.ProjectID, _ = strconv.Atoi(project.ID)
+			newVersions = append(newVersions, v)
+		}
+	}
+	var newFilters []createFilterRequest
+	for _, f := range setup.Filters {
+		newFilters = append(newFilters, createFilterRequest{
+			Name:        strings.ReplaceAll(f.Name, projectPlaceholder, projectKey),
+			JQL:         strings.ReplaceAll(f.JQL, projectPlaceholder, projectKey),
+			Description: f.Description,
+		})
+	}
+	var newTemplates []issueTemplate
+	for _, t := range setup.Templates {
+		if templates[t.Name] == nil {
+			newTemplates = append(newTemplates, t)
+		}
+	}
+
+	var sb strings.Builder
+	if j.dryRun(params.DryRun) {
+		fmt.Fprintf(&sb, "Dry run: nothing was written. Applying the setup of %s to %s would create:\n", setup.SourceProject, projectKey)
+		for _, c := range newComponents {
+			fmt.Fprintf(&sb, "- component %s\n", c.Name)
+		}
+		for _, v := range newVersions {
+			fmt.Fprintf(&sb, "- version %s\n", v.Name)
+		}
+		for _, f := range newFilters {
+			fmt.Fprintf(&sb, "- filter %q (unless one of that name exists): %s\n", f.Name, f.JQL)
+		}
+		for _, t := range newTemplates {
+			fmt.Fprintf(&sb, "- issue template %s\n", t.Name)
+		}
+		fmt.Fprintf(&sb, "%d components and %d versions already exist.\n", len(setup.Components)-len(newComponents), len(setup.Versions)-len(newVersions))
+		return textResult("%s", sb.String()), nil, nil
+	}
+
+	// Items are applied one by one; a failure is reported and the rest are
+	// still attempted, so the setup can be re-applied after fixing it.
+	var created, failed []string
+	for _, c := range newComponents {
+		options := &jira.CreateComponentOptions{Name: c.Name, Description: c.Description, AssigneeType: c.AssigneeType, Project: projectKey}
+		if c.LeadAccountID != "" {
+			j.setComponentLead(options, c.LeadAccountID)
+		}
+		if _, _, err := j.client(ctx).CreateComponent(ctx, options); err != nil {
+			failed = append(failed, fmt.Sprintf("component %s: %v", c.Name, err))
+			continue
+		}
+		created = append(created, "component "+c.Name)
+	}
+	for _, v := range newVersions {
+		if _, err := j.jiraDo(ctx, "POST", "rest/api/2/version", v, nil); err != nil {
+			failed = append(failed, fmt.Sprintf("version %s: %v", v.Name, err))
+			continue
+		}
+		created = append(created, "version "+v.Name)
+	}
+	if len(newFilters) > 0 {
+		// Filter names are unique per owner, so existing ones are those of the
+		// authenticated user.
+		var mine []jira.Filter
+		if _, err := j.jiraDo(ctx, "GET", "rest/api/2/filter/my", nil, &mine); err != nil {
+			failed = append(failed, fmt.Sprintf("filters: %v", err))
+			newFilters = nil
+		}
+		for _, f := range newFilters {
+			exists := false
+			for _, m := range mine {
+				exists = exists || strings.EqualFold(m.Name, f.Name)
+			}
+			if exists {
+				continue
+			}
+			if _, err := j.jiraDo(ctx, "POST", "rest/api/2/filter", f, nil); err != nil {
+				failed = append(failed, fmt.Sprintf("filter %s: %v", f.Name, err))
+				continue
+			}
+			created = append(created, "filter "+f.Name)
+		}
+	}
+	if len(newTemplates) > 0 {
+		if !j.config.AllowConfigUpdates {
+			failed = append(failed, fmt.Sprintf("%d issue template(s): adding templates needs JIRA_MCP_ALLOW_CONFIG_UPDATES", len(newTemplates)))
+		} else if err := j.addRuntimeTemplates(newTemplates); err != nil {
+			failed = append(failed, fmt.Sprintf("issue templates: %v", err))
+		} else {
+			for _, t := range newTemplates {
+				created = append(created, "issue template "+t.Name)
+			}
+		}
+	}
+	logger(ctx).Info("Applied project setup", "project", projectKey, "source", setup.SourceProject, "created", len(created), "failed", len(failed))
+
+	fmt.Fprintf(&sb, "Applied the setup of %s to %s: %d item(s) created", setup.SourceProject, projectKey, len(created))
+	if len(failed) > 0 {
+		fmt.Fprintf(&sb, ", %d failed", len(failed))
+	}
+	sb.WriteString("\n")
+	for _, c := range created {
+		fmt.Fprintf(&sb, "- created %s\n", c)
+	}
+	for _, f := range failed {
+		fmt.Fprintf(&sb, "- failed %s\n", f)
+	}
+	return textResult("%s", sb.String()), nil, nil
+}
@@ -0,0 +1,109 @@
+package jiramcp
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// jiraPrompt is a prompt that turns a few arguments into instructions for the
+// model. requires names the tools the instructions rely on; the prompt is
+// not offered when the tool policy removed any of them.
+type jiraPrompt struct {
+	prompt   *mcp.Prompt
+	requires []string
+	render   func(j *JiraMCPServer, args map[string]string) string
+}
+
+var jiraPrompts = []jiraPrompt{
+	{
+		prompt: &mcp.Prompt{
+			Name:        "triage-issue",
+			Title:       "Triage an issue",
+			Description: "Review an issue and propose its priority, components, labels, and assignee",
+			Arguments: []*mcp.PromptArgument{
+				{Name: "issueKey", Description: "Key of the issue to triage, e.g. PROJ-123", Required: true},
+			},
+		},
+		requires: []string{"search-jira-issues", "list-components", "list-priorities"},
+		render: func(j *JiraMCPServer, args map[string]string) string {
+			key := strings.ToUpper(args["issueKey"])
+			return fmt.Sprintf("Triage Jira issue %[1]s. Read it with search-jira-issues (jql: key = %[1]s, verbosity: full) and look for likely duplicates among open issues in the same project. "+
+				"Then propose a priority (list-priorities), components (list-components), labels, and an assignee, explaining each choice in one sentence. "+
+				"Do not change the issue until I confirm.", key)
+		},
+	},
+	{
+		prompt: &mcp.Prompt{
+			Name:        "project-status",
+			Title:       "Project status report",
+			Description: "Summarize the open work of a project: what moved recently, what is blocked, and what is at risk",
+			Arguments: []*mcp.PromptArgument{
+				{Name: "projectKey", Description: "Project key; defaults to the server's project"},
+			},
+		},
+		requires: []string{"search-jira-issues"},
+		render: func(j *JiraMCPServer, args map[string]string) string {
+			project := strings.ToUpper(args["projectKey"])
+			if project == "" {
+				project = j.config.ProjectKey
+			}
+			return fmt.Sprintf("Write a short status report for Jira project %[1]s. Use search-jira-issues to find issues updated in the last 7 days (project = %[1]s AND updated >= -7d) "+
+				"and open issues that are blocked or flagged. Group the report into Done, In progress, Blocked, and At risk, one line per issue with its key.", project)
+		},
+	},
+	{
+		prompt: &mcp.Prompt{
+			Name:        "standup",
+			Title:       "Stand-up notes",
+			Description: "Draft stand-up notes from a person's Jira activity since the last working day",
+			Arguments: []*mcp.PromptArgument{
+				{Name: "user", Description: "Name or email of the person; defaults to you"},
+			},
+		},
+		requires: []string{"search-jira-issues"},
+		render: func(j *JiraMCPServer, args map[string]string) string {
+			who := "currentUser()"
+			if user := args["user"]; user != "" {
+				who = fmt.Sprintf("%q", user)
+			}
+			return fmt.Sprintf("Draft stand-up notes for %s. Use search-jira-issues to find issues assigned to them that were updated since the last working day "+
+				"(assignee = %s AND updated >= -1d, or -3d on a Monday) and their open issues in progress. "+
+				"Answer in three short sections: Yesterday, Today, Blockers.", who, who)
+		},
+	},
+}
+
+// addPrompts registers the prompts whose tools are all available.
+func (j *JiraMCPServer) addPrompts() {
+	for _, p := range jiraPrompts {
+		available := true
+		for _, tool := range p.requires {
+			if !j.registeredTools[tool] {
+				available = false
+			}
+		}
+		if !available {
+			continue
+		}
+		j.server.AddPrompt(p.prompt, j.promptHandler(p))
+	}
+}
+
+func (j *JiraMCPServer) promptHandler(p jiraPrompt) mcp.PromptHandler {
+	return func(ctx context.Context, req *mcp.GetPromptRequest) (*mcp.GetPromptResult, error) {
+		for _, arg := range p.prompt.Arguments {
+			if arg.Required && strings.TrimSpace(req.Params.Arguments[arg.Name]) == "" {
+				return nil, fmt.Errorf("argument %q is required", arg.Name)
+			}
+		}
+		return &mcp.GetPromptResult{
+			Description: p.prompt.Description,
+			Messages: []*mcp.PromptMessage{
+				{Role: "user", Content: &mcp.TextContent{Text: p.render(j, req.Params.Arguments)}},
+			},
+		}, nil
+	}
+}
@@ -0,0 +1,241 @@
+package jiramcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/andygrunwald/go-jira"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// maxRankIssues is the most issues the rank endpoint moves in one request.
+const maxRankIssues = 50
+
+// rankPath is the Agile endpoint that ranks issues.
+const rankPath = "rest/agile/1.0/issue/rank"
+
+type ListBoardsParams struct {
+	ProjectKey string `json:"projectKey,omitempty"`
+}
+
+type GetBacklogParams struct {
+	ProjectKey string `json:"projectKey,omitempty"`
+	// BoardID selects the board when the project has several.
+	BoardID    int `json:"boardId,omitempty"`
+	MaxResults int `json:"maxResults,omitempty"`
+}
+
+type RankIssueParams struct {
+	IssueKey string `json:"issueKey"`
+	// Above or Below names the issue to rank the issue next to; set one.
+	Above  string `json:"above,omitempty"`
+	Below  string `json:"below,omitempty"`
+	DryRun bool   `json:"dryRun,omitempty"`
+}
+
+type ReorderBacklogParams struct {
+	// IssueKeys are moved as a block, in this order.
+	IssueKeys  []string `json:"issueKeys"`
+	ProjectKey string   `json:"projectKey,omitempty"`
+	BoardID    int      `json:"boardId,omitempty"`
+	// Position is "top" (default) or "bottom" of the backlog.
+	Position string `json:"position,omitempty"`
+	DryRun   bool   `json:"dryRun,omitempty"`
+}
+
+// rankRequest is the body of the rank endpoint.
+type rankRequest struct {
+	Issues          []string `json:"issues"`
+	RankBeforeIssue string   `json:"rankBeforeIssue,omitempty"`
+	RankAfterIssue  string   `json:"rankAfterIssue,omitempty"`
+}
+
+// rankIssues sends a rank request. Jira answers 207 when some issues could
+// not be ranked, listing them with their errors.
+func (j *JiraMCPServer) rankIssues(ctx context.Context, r rankRequest) error {
+	resp, err := j.jiraDo(ctx, "PUT", rankPath, r, nil)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusMultiStatus {
+		return nil
+	}
+	var result struct {
+		Entries []struct {
+			IssueKey string   `json:"issueKey"`
+			Status   int      `json:"status"`
+			Errors   []string `json:"errors"`
+		} `json:"entries"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return fmt.Errorf("some issues were not ranked")
+	}
+	var failed []string
+	for _, e := range result.Entries {
+		if e.Status >= 300 {
+			failed = append(failed, fmt.Sprintf("%s (%s)", e.IssueKey, strings.Join(e.Errors, "; ")))
+		}
+	}
+	if len(failed) == 0 {
+		return nil
+	}
+	return fmt.Errorf("not ranked: %s", strings.Join(failed, ", "))
+}
+
+// backlog returns the first page of a board's backlog, in rank order.
+func (j *JiraMCPServer) backlog(ctx context.Context, boardID, maxResults int) ([]jira.Issue, int, error) {
+	var page struct {
+		Total  int          `json:"total"`
+		Issues []jira.Issue `json:"issues"`
+	}
+	path := fmt.Sprintf("rest/agile/1.0/board/%d/backlog?fields=summary,status,issuetype,priority,assignee&maxResults=%d", boardID, maxResults)
+	if _, err := j.jiraDo(ctx, "GET", path, nil, &page); err != nil {
+		return nil, 0, err
+	}
+	return page.Issues, page.Total, nil
+}
+
+// ListBoards lists the boards of a project.
+func (j *JiraMCPServer) ListBoards(ctx context.Context, req *mcp.CallToolRequest, params *ListBoardsParams) (*mcp.CallToolResult, any, error) {
+	projectKey := j.projectKeyOrDefault(params.ProjectKey)
+	boards, err := j.projectBoards(ctx, projectKey)
+	if err != nil {
+		return textResult("Failed to list boards of %s: %v", projectKey, err), nil, nil
+	}
+	if len(boards) == 0 {
+		return textResult("Project %s has no board", projectKey), nil, nil
+	}
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "Boards of %s:\n", projectKey)
+	for _, b := range boards {
+		fmt.Fprintf(&sb, "- %d: %s (%s)\n", b.ID, b.Name, b.Type)
+	}
+	return textResult("%s", sb.String()), nil, nil
+}
+
+// GetBacklog lists a board's backlog in rank order.
+func (j *JiraMCPServer) GetBacklog(ctx context.Context, req *mcp.CallToolRequest, params *GetBacklogParams) (*mcp.CallToolResult, any, error) {
+	projectKey := j.projectKeyOrDefault(params.ProjectKey)
+	maxResults := params.MaxResults
+	if maxResults <= 0 || maxResults > searchPageSize {
+		maxResults = 50
+	}
+	b, err := j.findBoard(ctx, projectKey, params.BoardID)
+	if err != nil {
+		return textResult("Failed to find the board: %v", err), nil, nil
+	}
+	issues, total, err := j.backlog(ctx, b.ID, maxResults)
+	if err != nil {
+		return textResult("Failed to get the backlog of board %d: %v", b.ID, err), nil, nil
+	}
+	if len(issues) == 0 {
+		return textResult("The backlog of board %d (%s) is empty", b.ID, b.Name), nil, nil
+	}
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "Backlog of board %d (%s), top %d of %d:\n", b.ID, b.Name, len(issues), total)
+	for i := range issues {
+		fmt.Fprintf(&sb, "%d. %s\n", i+1, formatIssueLine(&issues[i]))
+	}
+	return textResult("%s", sb.String()), nil, nil
+}
+
+// RankIssue ranks an issue directly above or below another.
+func (j *JiraMCPServer) RankIssue(ctx context.Context, req *mcp.CallToolRequest, params *RankIssueParams) (*mcp.CallToolResult, any, error) {
+	issueKey := strings.ToUpper(params.IssueKey)
+	if (params.Above == "") == (params.Below == "") {
+		return textResult("Set exactly one of above or below"), nil, nil
+	}
+	r := rankRequest{Issues: []string{issueKey}, RankBeforeIssue: strings.ToUpper(params.Above), RankAfterIssue: strings.ToUpper(params.Below)}
+	if j.dryRun(params.DryRun) {
+		return dryRunResult("PUT", rankPath, r, nil), nil, nil
+	}
+	if err := j.rankIssues(ctx, r); err != nil {
+		return textResult("Failed to rank %s: %v", issueKey, err), nil, nil
+	}
+	if r.RankBeforeIssue != "" {
+		return textResult("Ranked %s above %s", issueKey, r.RankBeforeIssue), nil, nil
+	}
+	return textResult("Ranked %s below %s", issueKey, r.RankAfterIssue), nil, nil
+}
+
+// ReorderBacklog moves issues, in the given order, to the top or bottom of a
+// board's backlog.
+func (j *JiraMCPServer) ReorderBacklog(ctx context.Context, req *mcp.CallToolRequest, params *ReorderBacklogParams) (*mcp.CallToolResult, any, error) {
+	if len(params.IssueKeys) == 0 || len(params.IssueKeys) > maxRankIssues {
+		return textResult("issueKeys must list 1 to %d issues", maxRankIssues), nil, nil
+	}
+	position := strings.ToLower(params.Position)
+	if position == "" {
+		position = "top"
+	}
+	if position != "top" && position != "bottom" {
+		return textResult("position must be \"top\" or \"bottom\", got %q", params.Position), nil, nil
+	}
+	keys := make([]string, 0, len(params.IssueKeys))
+	for _, k := range params.IssueKeys {
+		keys = append(keys, strings.ToUpper(k))
+	}
+	projectKey := j.projectKeyOrDefault(params.ProjectKey)
+	b, err := j.findBoard(ctx, projectKey, params.BoardID)
+	if err != nil {
+		return textResult("Failed to find the board: %v", err), nil, nil
+	}
+
+	// The block is ranked against the first (or last) backlog issue that is
+	// not part of it. The backlog endpoint pages from the top, so the bottom
+	// needs the total first.
+	_, total, err := j.backlog(ctx, b.ID, 1)
+	if err != nil {
+		return textResult("Failed to get the backlog of board %d: %v", b.ID, err), nil, nil
+	}
+	var window []jira.Issue
+	if total > 0 {
+		path := fmt.Sprintf("rest/agile/1.0/board/%d/backlog?fields=summary&maxResults=%d", b.ID, len(keys)+1)
+		if position == "bottom" {
+			path += fmt.Sprintf("&startAt=%d", max(0, total-len(keys)-1))
+		}
+		var page struct {
+			Issues []jira.Issue `json:"issues"`
+		}
+		if _, err := j.jiraDo(ctx, "GET", path, nil, &page); err != nil {
+			return textResult("Failed to get the backlog of board %d: %v", b.ID, err), nil, nil
+		}
+		window = page.Issues
+	}
+	if position == "bottom" {
+		slices.Reverse(window)
+	}
+	anchor := ""
+	for _, issue := range window {
+		if !slices.Contains(keys, issue.Key) {
+			anchor = issue.Key
+			break
+		}
+	}
+
+	r := rankRequest{Issues: keys}
+	switch {
+	case anchor == "" && len(keys) == 1:
+		return textResult("%s is already the only issue of the backlog of board %d", keys[0], b.ID), nil, nil
+	case anchor == "":
+		// The backlog holds nothing but these issues: order them among
+		// themselves.
+		r = rankRequest{Issues: keys[1:], RankAfterIssue: keys[0]}
+	case position == "top":
+		r.RankBeforeIssue = anchor
+	default:
+		r.RankAfterIssue = anchor
+	}
+	if j.dryRun(params.DryRun) {
+		return dryRunResult("PUT", rankPath, r, nil), nil, nil
+	}
+	if err := j.rankIssues(ctx, r); err != nil {
+		return textResult("Failed to reorder the backlog of board %d: %v", b.ID, err), nil, nil
+	}
+	return textResult("Moved %s to the %s of the backlog of board %d (%s)", strings.Join(keys, ", "), position, b.ID, b.Name), nil, nil
+}
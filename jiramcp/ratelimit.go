@@ -0,0 +1,228 @@
+package jiramcp
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// mutationWindow is the sliding window the per-client mutation limit applies to.
+const mutationWindow = time.Hour
+
+// mutationBucket holds the mutation counts of clients when sessions are
+// resumable, so that every replica applies the same limit to a client.
+const mutationBucket = "mutations"
+
+// mutationGuard counts the Jira-modifying tool calls of each client so a
+// runaway agent loop cannot create or change tickets without bound. Counts
+// are kept per client, see clientKey, rather than per session, so that
+// reconnecting does not start a fresh hour, and expire with the window.
+type mutationGuard struct {
+	mu      sync.Mutex
+	clients map[string]*clientMutations
+	// store, when set, holds the counts as well, for replicas serving the
+	// same clients.
+	store Store
+}
+
+type clientMutations struct {
+	// Calls holds the times of the client's mutations inside the window.
+	Calls []time.Time `json:"calls,omitempty"`
+	// Limit and Until record an operator override of the configured limit;
+	// a limit of 0 lifts the cap until the override expires.
+	Limit int       `json:"limit,omitempty"`
+	Until time.Time `json:"until,omitempty"`
+}
+
+// expired reports whether the counters have nothing left to enforce.
+func (c *clientMutations) expired(now time.Time) bool {
+	return !now.Before(c.Until) && (len(c.Calls) == 0 || now.Sub(c.Calls[len(c.Calls)-1]) >= mutationWindow)
+}
+
+type OverrideMutationLimitParams struct {
+	// OverrideToken must match JIRA_MCP_MUTATION_OVERRIDE_TOKEN.
+	OverrideToken string `json:"overrideToken"`
+	// ClientKey is the client key shown in the limit error; it defaults to
+	// the calling client.
+	ClientKey string `json:"clientKey,omitempty"`
+	// Limit is the hourly limit to apply; 0 lifts the cap.
+	Limit int `json:"limit,omitempty"`
+	// Minutes is how long the override lasts (default 60).
+	Minutes int `json:"minutes,omitempty"`
+	// ResetCount forgets the client's mutations so far.
+	ResetCount bool `json:"resetCount,omitempty"`
+}
+
+// client returns the counters for id, from the store when there is one,
+// pruned to the current window. Counters of other clients that have
+// expired are dropped. The caller must hold g.mu.
+func (g *mutationGuard) client(id string, now time.Time) *clientMutations {
+	if g.clients == nil {
+		g.clients = make(map[string]*clientMutations)
+	}
+	for other, c := range g.clients {
+		if other != id && c.expired(now) {
+			delete(g.clients, other)
+		}
+	}
+	c := g.clients[id]
+	if g.store != nil {
+		var stored clientMutations
+		if found, err := g.store.Get(mutationBucket, id, &stored); err != nil {
+			slog.Warn("Failed to load mutation counts", "client", id, "error", err)
+		} else if found {
+			c = &stored
+		}
+	}
+	if c == nil {
+		c = &clientMutations{}
+	}
+	g.clients[id] = c
+	kept := c.Calls[:0]
+	for _, t := range c.Calls {
+		if now.Sub(t) < mutationWindow {
+			kept = append(kept, t)
+		}
+	}
+	c.Calls = kept
+	return c
+}
+
+// save writes the counters of id to the store, if there is one. The caller
+// must hold g.mu.
+func (g *mutationGuard) save(id string) {
+	if g.store == nil {
+		return
+	}
+	if err := g.store.Put(mutationBucket, id, g.clients[id]); err != nil {
+		slog.Warn("Failed to save mutation counts", "client", id, "error", err)
+	}
+}
+
+// allow records n mutations for client id if they all fit within limit,
+// returning the effective limit and, when refused, the time enough slots free
+// up, or the zero time when n exceeds the limit itself.
+func (g *mutationGuard) allow(id string, n, limit int, now time.Time) (bool, int, time.Time) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	c := g.client(id, now)
+	if now.Before(c.Until) {
+		limit = c.Limit
+	}
+	if limit > 0 && len(c.Calls)+n > limit {
+		if n > limit {
+			return false, limit, time.Time{}
+		}
+		return false, limit, c.Calls[len(c.Calls)+n-limit-1].Add(mutationWindow)
+	}
+	for i := 0; i < n; i++ {
+		c.Calls = append(c.Calls, now)
+	}
+	g.save(id)
+	return true, limit, time.Time{}
+}
+
+// pruneMutationCounts deletes the stored counters that have expired.
+func (j *JiraMCPServer) pruneMutationCounts() {
+	keys, err := j.store.Keys(mutationBucket)
+	if err != nil {
+		slog.Warn("Failed to list mutation counts", "error", err)
+		return
+	}
+	now := time.Now()
+	for _, key := range keys {
+		var c clientMutations
+		if found, err := j.store.Get(mutationBucket, key, &c); err == nil && found && c.expired(now) {
+			if err := j.store.Delete(mutationBucket, key); err != nil {
+				slog.Warn("Failed to delete mutation counts", "client", key, "error", err)
+			}
+		}
+	}
+}
+
+// mutationLimitMiddleware refuses calls to Jira-modifying tools once the
+// client has made JIRA_MCP_MAX_MUTATIONS_PER_HOUR of them in the last hour.
+// Dry runs do not count. A call counts once here; tools that change several
+// issues charge the rest before changing any, see runBulk. It is added
+// inside the project allowlist and role checks, so calls they refuse do not
+// count.
+func (j *JiraMCPServer) mutationLimitMiddleware(next mcp.MethodHandler) mcp.MethodHandler {
+	return func(ctx context.Context, method string, req mcp.Request) (mcp.Result, error) {
+		params, ok := req.GetParams().(*mcp.CallToolParamsRaw)
+		if !ok || !j.mutatingTools[params.Name] || j.config.DryRun {
+			return next(ctx, method, req)
+		}
+		var args struct {
+			DryRun bool `json:"dryRun"`
+		}
+		if len(params.Arguments) > 0 {
+			if err := json.Unmarshal(params.Arguments, &args); err != nil {
+				result := textResult("Invalid arguments: %v", err)
+				result.IsError = true
+				return result, nil
+			}
+		}
+		if args.DryRun {
+			return next(ctx, method, req)
+		}
+
+		client := requestClient(ctx)
+		allowed, limit, retry := j.mutations.allow(client, 1, j.config.MaxMutationsPerHour, time.Now())
+		if !allowed {
+			rateLimitHits.WithLabelValues("mutations").Inc()
+			logger(ctx).Warn("Mutation limit reached", "limit", limit)
+			result := textResult("Mutation limit reached: this client has made %d changes to Jira in the last hour, the maximum allowed. "+
+				"No further changes are possible until %s. If this is intended, ask an operator to raise the limit with override-mutation-limit for client %q.",
+				limit, retry.UTC().Format("15:04Z"), client)
+			result.IsError = true
+			return result, nil
+		}
+		return next(ctx, method, req)
+	}
+}
+
+// OverrideMutationLimit lets an operator raise or lift the mutation limit of a
+// client for a while. It is only registered when an override token is
+// configured, and the token must be supplied on every call.
+func (j *JiraMCPServer) OverrideMutationLimit(ctx context.Context, req *mcp.CallToolRequest, params *OverrideMutationLimitParams) (*mcp.CallToolResult, any, error) {
+	if subtle.ConstantTimeCompare([]byte(params.OverrideToken), []byte(j.config.MutationOverrideToken)) != 1 {
+		return textResult("Invalid override token"), nil, nil
+	}
+	if params.Limit < 0 {
+		return textResult("limit must be 0 (no limit) or positive"), nil, nil
+	}
+	client := strings.TrimSpace(params.ClientKey)
+	if client == "" {
+		client = requestClient(ctx)
+	}
+	minutes := params.Minutes
+	if minutes <= 0 {
+		minutes = 60
+	}
+
+	now := time.Now()
+	g := &j.mutations
+	g.mu.Lock()
+	c := g.client(client, now)
+	c.Limit = params.Limit
+	c.Until = now.Add(time.Duration(minutes) * time.Minute)
+	if params.ResetCount {
+		c.Calls = nil
+	}
+	g.save(client)
+	g.mu.Unlock()
+
+	limit := "no limit"
+	if params.Limit > 0 {
+		limit = fmt.Sprintf("%d changes per hour", params.Limit)
+	}
+	logger(ctx).Warn("Mutation limit overridden", "targetClient", client, "limit", limit, "minutes", minutes)
+	return textResult("Client %q may now make %s until %s", client, limit, c.Until.UTC().Format("15:04Z")), nil, nil
+}
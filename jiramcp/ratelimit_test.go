@@ -0,0 +1,112 @@
+package jiramcp
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/andygrunwald/go-jira"
+)
+
+func TestMutationGuardAllow(t *testing.T) {
+	var g mutationGuard
+	now := time.Now()
+	if ok, _, _ := g.allow("a", 2, 3, now); !ok {
+		t.Fatal("2 of 3 mutations refused")
+	}
+	// Two more do not fit, and neither is recorded.
+	ok, limit, retry := g.allow("a", 2, 3, now.Add(time.Minute))
+	if ok || limit != 3 || !retry.Equal(now.Add(mutationWindow)) {
+		t.Fatalf("allow = %v, %d, %v; want a refusal until the first mutation leaves the window", ok, limit, retry)
+	}
+	if ok, _, _ := g.allow("a", 1, 3, now.Add(time.Minute)); !ok {
+		t.Error("the third mutation was refused after a refused bulk charge")
+	}
+	if ok, _, retry := g.allow("b", 4, 3, now); ok || !retry.IsZero() {
+		t.Errorf("allow(4 of 3) = %v, %v; want a refusal with no retry time", ok, retry)
+	}
+	if ok, _, _ := g.allow("a", 3, 3, now.Add(mutationWindow+time.Minute)); !ok {
+		t.Error("mutations older than the window still count")
+	}
+}
+
+func TestMutationGuardExpiresClients(t *testing.T) {
+	var g mutationGuard
+	now := time.Now()
+	g.allow("a", 1, 5, now)
+	g.allow("b", 1, 5, now.Add(mutationWindow))
+	if _, ok := g.clients["a"]; ok {
+		t.Error("the counters of a client with no mutations in the window were kept")
+	}
+}
+
+// mutationTestServer builds a server with a mutation limit whose issues all
+// fail to load, so update calls reach Jira but change nothing.
+func mutationTestServer(t *testing.T, limit int, configure func(*JiraConfig)) (*JiraMCPServer, *MockJiraService) {
+	t.Helper()
+	mock := &MockJiraService{
+		DoFunc: jiraRoutes{}.do,
+		GetIssueFunc: func(context.Context, string, *jira.GetQueryOptions) (*jira.Issue, *jira.Response, error) {
+			return nil, nil, errors.New("not found")
+		},
+	}
+	return newTestServer(t, mock, func(c *JiraConfig) {
+		c.MaxMutationsPerHour = limit
+		if configure != nil {
+			configure(c)
+		}
+	}), mock
+}
+
+func TestMutationLimitOutlivesSessions(t *testing.T) {
+	j, _ := mutationTestServer(t, 2, nil)
+	update := map[string]interface{}{"issueKey": "SMS-1", "summary": "x"}
+	asA := context.WithValue(context.Background(), principalKey{}, tokenPrincipal("a"))
+
+	first := connect(t, asA, j)
+	for i := 0; i < 2; i++ {
+		if result := callTool(t, first, "update-jira-issue", update); strings.HasPrefix(resultText(result), "Mutation limit reached") {
+			t.Fatalf("call %d was refused: %s", i+1, resultText(result))
+		}
+	}
+	first.Close()
+
+	// A new session of the same client continues its count.
+	result := callTool(t, connect(t, asA, j), "update-jira-issue", update)
+	if !result.IsError || !strings.HasPrefix(resultText(result), "Mutation limit reached") {
+		t.Errorf("reconnected client: result = %q, want the limit error", resultText(result))
+	}
+	// Other clients have counts of their own.
+	asB := context.WithValue(context.Background(), principalKey{}, tokenPrincipal("b"))
+	if result := callTool(t, connect(t, asB, j), "update-jira-issue", update); strings.HasPrefix(resultText(result), "Mutation limit reached") {
+		t.Errorf("another client was refused: %s", resultText(result))
+	}
+}
+
+func TestMutationLimitIgnoresRefusedAndDryRunCalls(t *testing.T) {
+	j, _ := mutationTestServer(t, 1, func(c *JiraConfig) {
+		c.AllowedProjects = []string{"SMS"}
+	})
+	session := connect(t, context.Background(), j)
+
+	for i := 0; i < 3; i++ {
+		callTool(t, session, "update-jira-issue", map[string]interface{}{"issueKey": "OPS-1", "summary": "x"})
+		callTool(t, session, "update-jira-issue", map[string]interface{}{"issueKey": "SMS-1", "summary": "x", "dryRun": true})
+	}
+	if result := callTool(t, session, "update-jira-issue", map[string]interface{}{"issueKey": "SMS-1", "summary": "x"}); strings.HasPrefix(resultText(result), "Mutation limit reached") {
+		t.Errorf("refused and dry-run calls used up the limit: %s", resultText(result))
+	}
+}
+
+func TestMutationLimitRejectsInvalidArguments(t *testing.T) {
+	j, mock := mutationTestServer(t, 5, nil)
+	result := callTool(t, connect(t, context.Background(), j), "update-jira-issue", map[string]interface{}{"issueKey": "SMS-1", "dryRun": "yes"})
+	if !result.IsError || !strings.HasPrefix(resultText(result), "Invalid arguments") {
+		t.Errorf("result = %q, want an invalid arguments error", resultText(result))
+	}
+	if called(mock, "GetIssue") {
+		t.Error("a call with invalid arguments reached the handler")
+	}
+}
@@ -0,0 +1,279 @@
+package jiramcp
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// Roles of HTTP clients when JIRA_MCP_ROLES_FILE is set. Viewers may only use
+// read-only tools, editors every tool but the administrative ones, and admins
+// everything.
+const (
+	RoleViewer = "viewer"
+	RoleEditor = "editor"
+	RoleAdmin  = "admin"
+)
+
+// adminRoleTools are, besides the configuration and delete tools, the tools
+// only admins may use.
+var adminRoleTools = map[string]bool{
+	"override-mutation-limit":   true,
+	"audit-project-permissions": true,
+	"get-audit-records":         true,
+	"usage-report":              true,
+}
+
+// isAdminTool reports whether only admins may use the named tool.
+func isAdminTool(name string) bool {
+	return adminTools[name] || deleteTools[name] || adminRoleTools[name]
+}
+
+// roleGrant gives a role, optionally limited to some projects, to the clients
+// presenting a token or carrying a claim.
+type roleGrant struct {
+	// TokenSHA256 is the hex SHA-256 digest of an API token from
+	// JIRA_MCP_AUTH_TOKENS, so the roles file holds no secrets. Claim and
+	// Value match a claim of a verified OIDC token instead.
+	TokenSHA256 string `json:"tokenSha256,omitempty"`
+	// The claim must equal the value, or be a list containing it.
+	Claim string `json:"claim,omitempty"`
+	Value string `json:"value,omitempty"`
+	Role  string `json:"role"`
+	// Projects limits the grant to these projects; empty means all projects
+	// the server may access.
+	Projects []string `json:"projects,omitempty"`
+}
+
+// roleConfig is the content of JIRA_MCP_ROLES_FILE.
+type roleConfig struct {
+	// DefaultRole applies to authenticated clients that match no grant; when
+	// empty they may not use any tool.
+	DefaultRole string      `json:"defaultRole,omitempty"`
+	Grants      []roleGrant `json:"grants"`
+}
+
+// principal identifies an authenticated HTTP client.
+type principal struct {
+	// TokenSHA256 is the hex digest of the API token the client presented.
+	TokenSHA256 string
+	// Subject and Claims come from a verified identity token.
+	Subject string
+	Claims  map[string]interface{}
+}
+
+type principalKey struct{}
+
+// loadRoles reads and validates the roles file.
+func loadRoles(path string) (*roleConfig, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read roles file: %w", err)
+	}
+	var rc roleConfig
+	if err := json.Unmarshal(b, &rc); err != nil {
+		return nil, fmt.Errorf("failed to parse roles file %s: %w", path, err)
+	}
+	if rc.DefaultRole != "" && !validRole(rc.DefaultRole) {
+		return nil, fmt.Errorf("roles file %s: unknown default role %q", path, rc.DefaultRole)
+	}
+	for i, g := range rc.Grants {
+		if !validRole(g.Role) {
+			return nil, fmt.Errorf("grant %d in %s: unknown role %q (use %s, %s, or %s)", i+1, path, g.Role, RoleViewer, RoleEditor, RoleAdmin)
+		}
+		if (g.TokenSHA256 == "") == (g.Claim == "") {
+			return nil, fmt.Errorf("grant %d in %s needs either tokenSha256 or claim", i+1, path)
+		}
+		rc.Grants[i].TokenSHA256 = strings.ToLower(g.TokenSHA256)
+		for k, p := range g.Projects {
+			rc.Grants[i].Projects[k] = strings.ToUpper(p)
+		}
+	}
+	return &rc, nil
+}
+
+func validRole(role string) bool {
+	return role == RoleViewer || role == RoleEditor || role == RoleAdmin
+}
+
+// tokenFromHeader returns the API token sent as "Authorization: Bearer" or in
+// the X-API-Key header.
+func tokenFromHeader(h http.Header) string {
+	token := h.Get("X-API-Key")
+	if auth := h.Get("Authorization"); token == "" && len(auth) > 7 && strings.EqualFold(auth[:7], "Bearer ") {
+		token = strings.TrimSpace(auth[7:])
+	}
+	return token
+}
+
+// tokenPrincipal identifies a client by the digest of its API token.
+func tokenPrincipal(token string) *principal {
+	sum := sha256.Sum256([]byte(token))
+	return &principal{TokenSHA256: hex.EncodeToString(sum[:])}
+}
+
+// matches reports whether the grant applies to p.
+func (g *roleGrant) matches(p *principal) bool {
+	if g.TokenSHA256 != "" {
+		return g.TokenSHA256 == p.TokenSHA256
+	}
+	switch v := p.Claims[g.Claim].(type) {
+	case string:
+		return v == g.Value
+	case []interface{}:
+		for _, item := range v {
+			if s, ok := item.(string); ok && s == g.Value {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// grantFor returns the first grant matching p, the default role when none
+// does, or nil when p may do nothing.
+func (rc *roleConfig) grantFor(p *principal) *roleGrant {
+	if p != nil {
+		for i := range rc.Grants {
+			if rc.Grants[i].matches(p) {
+				return &rc.Grants[i]
+			}
+		}
+	}
+	if rc.DefaultRole != "" && p != nil {
+		return &roleGrant{Role: rc.DefaultRole}
+	}
+	return nil
+}
+
+// toolAllowedForRole reports whether role may use the named tool.
+func (j *JiraMCPServer) toolAllowedForRole(role, tool string) bool {
+	switch role {
+	case RoleAdmin:
+		return true
+	case RoleEditor:
+		return !isAdminTool(tool)
+	case RoleViewer:
+		return j.readOnlyTools[tool] && !isAdminTool(tool)
+	}
+	return false
+}
+
+// mayUseTool reports whether the client behind req may use the named tool,
+// for handlers that do on their own what the tool does. Without roles, every
+// client may.
+func (j *JiraMCPServer) mayUseTool(ctx context.Context, req mcp.Request, tool string) bool {
+	if j.roles == nil {
+		return true
+	}
+	grant := j.roles.grantFor(j.requestPrincipal(ctx, req))
+	return grant != nil && j.toolAllowedForRole(grant.Role, tool)
+}
+
+// projectAllowedForGrant reports whether the grant covers project.
+func projectAllowedForGrant(g *roleGrant, project string) bool {
+	return len(g.Projects) == 0 || slices.Contains(g.Projects, strings.ToUpper(project))
+}
+
+// grantProjectsKey carries the projects of the caller's grant, when it is
+// limited to some, in the request context; see projectScope.
+type grantProjectsKey struct{}
+
+// rememberGrant records the grant of a session's client, so that
+// notifications sent outside of requests only reach sessions that may see
+// them.
+func (j *JiraMCPServer) rememberGrant(session string, grant *roleGrant) {
+	j.sessionGrantsMu.Lock()
+	defer j.sessionGrantsMu.Unlock()
+	if grant == nil {
+		delete(j.sessionGrants, session)
+		return
+	}
+	j.sessionGrants[session] = grant
+}
+
+// sessionGrant returns the grant last seen for a session, or nil.
+func (j *JiraMCPServer) sessionGrant(session string) *roleGrant {
+	j.sessionGrantsMu.Lock()
+	defer j.sessionGrantsMu.Unlock()
+	return j.sessionGrants[session]
+}
+
+// rbacMiddleware enforces JIRA_MCP_ROLES_FILE: clients only see and call the
+// tools their role allows, and only on the projects their grant covers. The
+// projects of a call are found like those of the project allowlist, and JQL
+// searches are limited to the grant's projects when they run.
+func (j *JiraMCPServer) rbacMiddleware(next mcp.MethodHandler) mcp.MethodHandler {
+	return func(ctx context.Context, method string, req mcp.Request) (mcp.Result, error) {
+		grant := j.roles.grantFor(j.requestPrincipal(ctx, req))
+		if s := req.GetSession(); s != nil && s.ID() != "" {
+			j.rememberGrant(s.ID(), grant)
+		}
+		if grant != nil && len(grant.Projects) > 0 {
+			ctx = context.WithValue(ctx, grantProjectsKey{}, grant.Projects)
+		}
+		var key string
+		switch params := req.GetParams().(type) {
+		case *mcp.ListToolsParams:
+			result, err := next(ctx, method, req)
+			if list, ok := result.(*mcp.ListToolsResult); ok && err == nil {
+				allowed := make([]*mcp.Tool, 0, len(list.Tools))
+				for _, t := range list.Tools {
+					if grant != nil && j.toolAllowedForRole(grant.Role, t.Name) {
+						allowed = append(allowed, t)
+					}
+				}
+				list.Tools = allowed
+			}
+			return result, err
+		case *mcp.CallToolParamsRaw:
+			if grant == nil {
+				return nil, fmt.Errorf("this client has no role on this server")
+			}
+			if !j.toolAllowedForRole(grant.Role, params.Name) {
+				logger(ctx).Warn("Tool call denied by role", "role", grant.Role)
+				result := textResult("The %s role may not use %s", grant.Role, params.Name)
+				result.IsError = true
+				return result, nil
+			}
+			if len(grant.Projects) == 0 {
+				break
+			}
+			projects, err := j.toolCallProjects(ctx, params.Name, params.Arguments)
+			if err != nil {
+				result := textResult("Could not check the projects of this call: %v", err)
+				result.IsError = true
+				return result, nil
+			}
+			for _, project := range projects {
+				if !projectAllowedForGrant(grant, project) {
+					result := textResult("Your role does not cover project %s", project)
+					result.IsError = true
+					return result, nil
+				}
+			}
+		case *mcp.ReadResourceParams:
+			if grant == nil {
+				return nil, fmt.Errorf("this client has no role on this server")
+			}
+			key = resourceIssueKey(params.URI)
+		case *mcp.SubscribeParams:
+			if grant == nil {
+				return nil, fmt.Errorf("this client has no role on this server")
+			}
+			key = resourceIssueKey(params.URI)
+		}
+		if key != "" && !projectAllowedForGrant(grant, projectOfIssue(key)) {
+			return nil, fmt.Errorf("your role does not cover project %s", strings.ToUpper(projectOfIssue(key)))
+		}
+		return next(ctx, method, req)
+	}
+}
@@ -0,0 +1,140 @@
+package jiramcp
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// newRBACServer builds a server over HTTP, with the admin tools registered,
+// whose roles file grants viewer, editor on SMS, and admin to the tokens of
+// those names. configure may adjust the configuration further.
+func newRBACServer(t *testing.T, mock *MockJiraService, configure func(*JiraConfig)) *JiraMCPServer {
+	t.Helper()
+	rc := roleConfig{Grants: []roleGrant{
+		{TokenSHA256: tokenPrincipal("viewer").TokenSHA256, Role: RoleViewer},
+		{TokenSHA256: tokenPrincipal("editor").TokenSHA256, Role: RoleEditor, Projects: []string{"sms"}},
+		{TokenSHA256: tokenPrincipal("admin").TokenSHA256, Role: RoleAdmin},
+	}}
+	b, err := json.Marshal(rc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "roles.json")
+	if err := os.WriteFile(path, b, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return newTestServer(t, mock, func(c *JiraConfig) {
+		c.Transport = "sse"
+		c.AuthTokens = []string{"viewer", "editor", "admin", "nobody"}
+		c.RolesFile = path
+		c.AllowConfigUpdates = true
+		if configure != nil {
+			configure(c)
+		}
+	})
+}
+
+// connectAs connects to j as the client presenting token.
+func connectAs(t *testing.T, j *JiraMCPServer, token string) *mcp.ClientSession {
+	t.Helper()
+	return connect(t, context.WithValue(context.Background(), principalKey{}, tokenPrincipal(token)), j)
+}
+
+// toolNames lists the tools a session sees.
+func toolNames(t *testing.T, session *mcp.ClientSession) []string {
+	t.Helper()
+	list, err := session.ListTools(context.Background(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, tool := range list.Tools {
+		names = append(names, tool.Name)
+	}
+	return names
+}
+
+func TestRBACMiddlewareFiltersToolsByRole(t *testing.T) {
+	j := newRBACServer(t, &MockJiraService{DoFunc: jiraRoutes{}.do}, nil)
+
+	tests := []struct {
+		token       string
+		see, notSee []string
+	}{
+		{"viewer", []string{"search-jira-issues"}, []string{"create-jira-issue", "update-jira-issue", "update-server-config"}},
+		{"editor", []string{"search-jira-issues", "create-jira-issue"}, []string{"update-server-config"}},
+		{"admin", []string{"create-jira-issue", "update-server-config"}, nil},
+	}
+	for _, tt := range tests {
+		names := toolNames(t, connectAs(t, j, tt.token))
+		for _, name := range tt.see {
+			if !slices.Contains(names, name) {
+				t.Errorf("%s does not see %s", tt.token, name)
+			}
+		}
+		for _, name := range tt.notSee {
+			if slices.Contains(names, name) {
+				t.Errorf("%s sees %s", tt.token, name)
+			}
+		}
+	}
+
+	result := callTool(t, connectAs(t, j, "viewer"), "create-jira-issue", map[string]interface{}{"summary": "x", "issueType": "Task"})
+	if want := "The viewer role may not use create-jira-issue"; !result.IsError || resultText(result) != want {
+		t.Errorf("viewer creating an issue: result = %q, want %q", resultText(result), want)
+	}
+
+	if names := toolNames(t, connectAs(t, j, "nobody")); len(names) != 0 {
+		t.Errorf("a client without a grant sees %v", names)
+	}
+	_, err := connectAs(t, j, "nobody").CallTool(context.Background(), &mcp.CallToolParams{Name: "search-jira-issues", Arguments: map[string]interface{}{"jql": "project = SMS"}})
+	if err == nil {
+		t.Error("a client without a grant called a tool")
+	}
+}
+
+func TestRBACMiddlewareLimitsGrantToProjects(t *testing.T) {
+	mock := &MockJiraService{DoFunc: jiraRoutes{}.do}
+	j := newRBACServer(t, mock, nil)
+	session := connectAs(t, j, "editor")
+
+	tests := []struct {
+		tool string
+		args map[string]interface{}
+	}{
+		{"create-jira-issue", map[string]interface{}{"projectKey": "OPS", "summary": "x", "issueType": "Task"}},
+		{"bulk-update-issues", map[string]interface{}{"issueKeys": []string{"SMS-1", "OPS-2"}, "addLabels": []string{"x"}}},
+	}
+	for _, tt := range tests {
+		result := callTool(t, session, tt.tool, tt.args)
+		if want := "Your role does not cover project OPS"; !result.IsError || resultText(result) != want {
+			t.Errorf("%s %v: result = %q, want %q", tt.tool, tt.args, resultText(result), want)
+		}
+	}
+	if called(mock, "CreateIssue") || called(mock, "GetIssue") {
+		t.Errorf("refused calls reached Jira: %v", mock.Calls)
+	}
+
+	if _, err := session.ReadResource(context.Background(), &mcp.ReadResourceParams{URI: "jira://issue/OPS-1/discussion-summary"}); err == nil {
+		t.Error("the editor read a resource of project OPS")
+	}
+}
+
+func TestRBACGrantNarrowsSearches(t *testing.T) {
+	j := newRBACServer(t, &MockJiraService{DoFunc: jiraRoutes{}.do}, nil)
+	ctx := context.WithValue(context.Background(), grantProjectsKey{}, []string{"SMS"})
+
+	jql, err := j.scopeJQL(ctx, "status = Open")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `project in ("SMS") AND (status = Open)`; jql != want {
+		t.Errorf("scoped JQL = %q, want %q", jql, want)
+	}
+}
@@ -0,0 +1,215 @@
+package jiramcp
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/andygrunwald/go-jira"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// maxIssueReferences bounds the issues one extract-issue-references call
+// looks up.
+const maxIssueReferences = 100
+
+// issueKeyPattern matches issue keys in running text. Keys are upper case,
+// and a key glued to other letters, digits, or dashes is not one.
+var issueKeyPattern = regexp.MustCompile(`(?:^|[^A-Za-z0-9_-])([A-Z][A-Z0-9_]+-[1-9][0-9]*)(?:$|[^A-Za-z0-9_-])`)
+
+// urlPattern matches http and https URLs, without trailing punctuation.
+var urlPattern = regexp.MustCompile(`https?://[^\s<>"'|\[\]]+[^\s<>"'|\[\].,;:!?)]`)
+
+// issueURLKey matches the issue key of a Jira issue URL: /browse/KEY-1 or a
+// board or search view with ?selectedIssue=KEY-1.
+var issueURLKey = regexp.MustCompile(`(?i)(?:/browse/|[?&]selectedIssue=)([a-z][a-z0-9_]+-[1-9][0-9]*)`)
+
+type ExtractIssueReferencesParams struct {
+	// Text is any pasted text, such as meeting notes or a chat export.
+	Text string `json:"text"`
+	// ProjectKeys limits the references to these projects.
+	ProjectKeys []string `json:"projectKeys,omitempty"`
+}
+
+// issueReferences holds what extractIssueReferences found in a text.
+type issueReferences struct {
+	// keys are the candidate issue keys in order of first appearance.
+	keys []string
+	// otherSites are Jira issue URLs of other hosts, which are not checked.
+	otherSites []string
+}
+
+// extractIssueReferences finds the issue keys of a text, from bare keys and
+// from issue URLs on host. Issue URLs of other hosts are collected apart,
+// and other URLs are skipped so that keys in their paths are not picked up.
+func extractIssueReferences(text, host string) issueReferences {
+	var refs issueReferences
+	seen := make(map[string]bool)
+	add := func(key string) {
+		if key = strings.ToUpper(key); !seen[key] {
+			seen[key] = true
+			refs.keys = append(refs.keys, key)
+		}
+	}
+
+	// Adjacent keys share the character between them, so matching goes on
+	// from the end of each key rather than of each match.
+	scan := func(s string) {
+		for {
+			loc := issueKeyPattern.FindStringSubmatchIndex(s)
+			if loc == nil {
+				return
+			}
+			add(s[loc[2]:loc[3]])
+			s = s[loc[3]:]
+		}
+	}
+	last := 0
+	for _, loc := range urlPattern.FindAllStringIndex(text, -1) {
+		scan(text[last:loc[0]])
+		last = loc[1]
+		raw := text[loc[0]:loc[1]]
+		m := issueURLKey.FindStringSubmatch(raw)
+		if m == nil {
+			continue
+		}
+		if u, err := url.Parse(raw); err == nil && !strings.EqualFold(u.Host, host) {
+			refs.otherSites = append(refs.otherSites, raw)
+			continue
+		}
+		add(m[1])
+	}
+	scan(text[last:])
+	return refs
+}
+
+// issueReference is the outcome of looking up one referenced key.
+type issueReference struct {
+	key   string
+	issue *jira.Issue
+	// missing is set when the issue does not exist or cannot be seen.
+	missing bool
+	err     error
+}
+
+// ExtractIssueReferences finds the issue keys and issue URLs in a text,
+// checks which issues exist, and reports the current status of each.
+func (j *JiraMCPServer) ExtractIssueReferences(ctx context.Context, req *mcp.CallToolRequest, params *ExtractIssueReferencesParams) (*mcp.CallToolResult, any, error) {
+	host := ""
+	if u, err := url.Parse(j.config.BaseURL); err == nil {
+		host = u.Host
+	}
+	found := extractIssueReferences(params.Text, host)
+
+	// Tokens such as UTF-8 or SHA-256 look like keys; only keys of projects
+	// that exist are looked up. When the projects cannot be listed, every
+	// key is.
+	wanted := make(map[string]bool)
+	for _, key := range params.ProjectKeys {
+		wanted[strings.ToUpper(key)] = true
+	}
+	projects := make(map[string]bool)
+	if accessible, err := j.accessibleProjects(ctx); err == nil {
+		for _, p := range accessible {
+			projects[p.Key] = true
+		}
+	} else {
+		logger(ctx).Warn("Could not list projects to check issue references", "error", err)
+	}
+	var keys, ignored, outside []string
+	for _, key := range found.keys {
+		project := key[:strings.LastIndex(key, "-")]
+		switch {
+		case len(wanted) > 0 && !wanted[project]:
+		case len(projects) > 0 && !projects[project]:
+			ignored = append(ignored, key)
+		case !j.projectInScope(ctx, project):
+			outside = append(outside, key)
+		default:
+			keys = append(keys, key)
+		}
+	}
+	if len(keys) == 0 && len(outside) == 0 && len(found.otherSites) == 0 {
+		return textResult("No issue references found"), nil, nil
+	}
+	truncated := len(keys) > maxIssueReferences
+	if truncated {
+		keys = keys[:maxIssueReferences]
+	}
+
+	refs := make([]issueReference, len(keys))
+	for i, key := range keys {
+		// Keys the pool never reaches, because the request was cancelled,
+		// keep this error.
+		refs[i] = issueReference{key: key, err: fmt.Errorf("not looked up")}
+	}
+	// Lookups fail one by one, so the pool never stops early.
+	forEachParallel(ctx, j.fetchConcurrency(), len(keys), func(ctx context.Context, i int) error {
+		issue, resp, err := j.client(ctx).GetIssue(ctx, keys[i], &jira.GetQueryOptions{Fields: strings.Join(defaultSearchFields, ",")})
+		refs[i].err = nil
+		switch {
+		case err == nil:
+			refs[i].issue = issue
+			j.recentIssues.add(*issue)
+		case resp != nil && resp.StatusCode == http.StatusNotFound:
+			refs[i].missing = true
+		default:
+			refs[i].err = err
+		}
+		return nil
+	})
+
+	var sb strings.Builder
+	var missing, failed []string
+	statuses := make(map[string]*tally)
+	existing := 0
+	for _, r := range refs {
+		switch {
+		case r.issue != nil:
+			if existing == 0 {
+				sb.WriteString("Referenced issues:\n")
+			}
+			existing++
+			sb.WriteString("- ")
+			if r.issue.Key != r.key {
+				// Moved issues keep answering to their old key.
+				fmt.Fprintf(&sb, "%s (moved) → ", r.key)
+			}
+			sb.WriteString(formatIssueLine(r.issue) + "\n")
+			addTally(statuses, statusName(r.issue), 0)
+		case r.missing:
+			missing = append(missing, r.key)
+		default:
+			failed = append(failed, fmt.Sprintf("%s (%v)", r.key, r.err))
+		}
+	}
+	if existing > 1 {
+		var parts []string
+		for _, t := range sortedTallies(statuses) {
+			parts = append(parts, fmt.Sprintf("%s %d", t.name, t.issues))
+		}
+		fmt.Fprintf(&sb, "By status: %s\n", strings.Join(parts, ", "))
+	}
+	if len(missing) > 0 {
+		fmt.Fprintf(&sb, "Not found or not visible: %s\n", strings.Join(missing, ", "))
+	}
+	if len(failed) > 0 {
+		fmt.Fprintf(&sb, "Failed to look up: %s\n", strings.Join(failed, "; "))
+	}
+	if truncated {
+		fmt.Fprintf(&sb, "Only the first %d referenced keys were checked.\n", maxIssueReferences)
+	}
+	if len(ignored) > 0 {
+		fmt.Fprintf(&sb, "Ignored, no such project: %s\n", strings.Join(ignored, ", "))
+	}
+	if len(outside) > 0 {
+		fmt.Fprintf(&sb, "Not checked, outside the projects you may access: %s\n", strings.Join(outside, ", "))
+	}
+	if len(found.otherSites) > 0 {
+		fmt.Fprintf(&sb, "Issue links to other Jira sites, not checked:\n- %s\n", strings.Join(found.otherSites, "\n- "))
+	}
+	return textResult("%s", sb.String()), nil, nil
+}
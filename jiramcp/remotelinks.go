@@ -0,0 +1,101 @@
+package jiramcp
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/andygrunwald/go-jira"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+type ListRemoteLinksParams struct {
+	IssueKey string `json:"issueKey"`
+}
+
+type AddRemoteLinkParams struct {
+	IssueKey string `json:"issueKey"`
+	URL      string `json:"url"`
+	Title    string `json:"title"`
+	Summary  string `json:"summary,omitempty"`
+	// Relationship describes how the issue relates to the link, e.g. "mentioned in" or "fixed by".
+	Relationship string `json:"relationship,omitempty"`
+	// IconURL is a 16x16 icon shown next to the link, with IconTitle as its tooltip.
+	IconURL   string `json:"iconUrl,omitempty"`
+	IconTitle string `json:"iconTitle,omitempty"`
+	// GlobalID identifies the link across calls; adding a link with an existing
+	// global ID updates it instead of creating a duplicate. Defaults to the URL.
+	GlobalID string `json:"globalId,omitempty"`
+	DryRun   bool   `json:"dryRun,omitempty"`
+}
+
+// ListRemoteLinks returns the web and application links attached to an issue.
+func (j *JiraMCPServer) ListRemoteLinks(ctx context.Context, req *mcp.CallToolRequest, params *ListRemoteLinksParams) (*mcp.CallToolResult, any, error) {
+	var links []jira.RemoteLink
+	if _, err := j.jiraDo(ctx, "GET", fmt.Sprintf("rest/api/2/issue/%s/remotelink", params.IssueKey), nil, &links); err != nil {
+		return textResult("Failed to list remote links of %s: %v", params.IssueKey, err), nil, nil
+	}
+	if len(links) == 0 {
+		return textResult("%s has no remote links", params.IssueKey), nil, nil
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "Remote links of %s:\n", params.IssueKey)
+	for _, l := range links {
+		if l.Object == nil {
+			continue
+		}
+		sb.WriteString("- ")
+		if l.Relationship != "" {
+			fmt.Fprintf(&sb, "%s: ", l.Relationship)
+		}
+		fmt.Fprintf(&sb, "%s <%s> (id %d)", l.Object.Title, l.Object.URL, l.ID)
+		if l.Object.Summary != "" {
+			fmt.Fprintf(&sb, " — %s", l.Object.Summary)
+		}
+		sb.WriteString("\n")
+	}
+	return textResult("%s", sb.String()), nil, nil
+}
+
+// AddRemoteLink attaches a link to an external page, such as a pull request or
+// design document, to an issue.
+func (j *JiraMCPServer) AddRemoteLink(ctx context.Context, req *mcp.CallToolRequest, params *AddRemoteLinkParams) (*mcp.CallToolResult, any, error) {
+	u, err := url.Parse(params.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return textResult("url must be an absolute http(s) URL, got %q", params.URL), nil, nil
+	}
+	if strings.TrimSpace(params.Title) == "" {
+		return textResult("title is required"), nil, nil
+	}
+
+	link := &jira.RemoteLink{
+		GlobalID:     params.GlobalID,
+		Relationship: params.Relationship,
+		Object: &jira.RemoteLinkObject{
+			URL:     params.URL,
+			Title:   params.Title,
+			Summary: params.Summary,
+		},
+	}
+	if link.GlobalID == "" {
+		link.GlobalID = params.URL
+	}
+	if params.IconURL != "" {
+		link.Object.Icon = &jira.RemoteLinkIcon{Url16x16: params.IconURL, Title: params.IconTitle}
+	}
+
+	path := fmt.Sprintf("rest/api/2/issue/%s/remotelink", params.IssueKey)
+	if j.dryRun(params.DryRun) {
+		return dryRunResult("POST", path, link, j.issueProblems(ctx, params.IssueKey)), nil, nil
+	}
+
+	var created jira.RemoteLink
+	if _, err := j.jiraDo(ctx, "POST", path, link, &created); err != nil {
+		return textResult("Failed to add remote link to %s: %v", params.IssueKey, err), nil, nil
+	}
+	logger(ctx).Info("Added remote link", "linkId", created.ID, "url", params.URL)
+
+	return textResult("Linked %s to %s (remote link id %d)", params.IssueKey, params.URL, created.ID), nil, nil
+}
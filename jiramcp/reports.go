@@ -0,0 +1,314 @@
+package jiramcp
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/andygrunwald/go-jira"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// defaultReportIssues bounds how many issues the reporting tools scan by
+// default.
+const defaultReportIssues = 1000
+
+type SprintSummaryParams struct {
+	SprintID  int `json:"sprintId"`
+	MaxIssues int `json:"maxIssues,omitempty"`
+	// CapacityPoints is the team's capacity for the sprint; when set, the
+	// summary reports how much of it the sprint's points take.
+	CapacityPoints float64 `json:"capacityPoints,omitempty"`
+}
+
+type ProjectStatusReportParams struct {
+	ProjectKey string `json:"projectKey,omitempty"`
+	// From and To bound the period as YYYY-MM-DD; the default is the last
+	// 30 days.
+	From string `json:"from,omitempty"`
+	To   string `json:"to,omitempty"`
+	// GroupBy breaks the counts down by "assignee" (default) or "component".
+	GroupBy   string `json:"groupBy,omitempty"`
+	MaxIssues int    `json:"maxIssues,omitempty"`
+}
+
+// tally counts issues and points under a name.
+type tally struct {
+	name   string
+	issues int
+	points float64
+}
+
+// sortedTallies returns tallies by descending issue count, then name.
+func sortedTallies(m map[string]*tally) []*tally {
+	list := make([]*tally, 0, len(m))
+	for _, t := range m {
+		list = append(list, t)
+	}
+	sort.Slice(list, func(a, b int) bool {
+		if list[a].issues != list[b].issues {
+			return list[a].issues > list[b].issues
+		}
+		return list[a].name < list[b].name
+	})
+	return list
+}
+
+// addTally counts an issue under name.
+func addTally(m map[string]*tally, name string, points float64) {
+	if m[name] == nil {
+		m[name] = &tally{name: name}
+	}
+	m[name].issues++
+	m[name].points += points
+}
+
+// formatPoints renders story points without needless decimals.
+func formatPoints(p float64) string {
+	return strconv.FormatFloat(p, 'f', -1, 64)
+}
+
+// SprintSummary summarizes a sprint: issues by status, committed versus
+// completed story points, and scope added after the sprint started.
+func (j *JiraMCPServer) SprintSummary(ctx context.Context, req *mcp.CallToolRequest, params *SprintSummaryParams) (*mcp.CallToolResult, any, error) {
+	if params.SprintID <= 0 {
+		return textResult("sprintId is required"), nil, nil
+	}
+	maxIssues := params.MaxIssues
+	if maxIssues <= 0 {
+		maxIssues = defaultReportIssues
+	}
+	s, err := j.getSprint(ctx, params.SprintID)
+	if err != nil {
+		return textResult("Failed to get sprint %d: %v", params.SprintID, err), nil, nil
+	}
+	estimates, err := j.estimateFields(ctx)
+	if err != nil {
+		return textResult("Failed to find the estimate fields: %v", err), nil, nil
+	}
+	pointsID, pointsName := estimates.points, estimates.pointsName
+	fields := append([]string{"created", "status", "summary"}, estimates.ids()...)
+	issues, err := j.searchIssues(ctx, fmt.Sprintf("sprint = %d ORDER BY key ASC", s.ID), fields, "changelog", maxIssues)
+	if err != nil {
+		return textResult("Failed to load issues of sprint %d: %v", s.ID, err), nil, nil
+	}
+	categories, err := j.statusCategories(ctx)
+	if err != nil {
+		return textResult("Failed to load status categories: %v", err), nil, nil
+	}
+
+	byStatus := make(map[string]*tally)
+	byConfidence := make(map[string]*tally)
+	var total, completed, committed, committedDone, sized tally
+	var added []string
+	start, started := parseAgileTime(s.StartDate)
+	scope := burnupScope{sprintID: strconv.Itoa(s.ID)}
+	for _, issue := range issues {
+		// Issues estimated only with a T-shirt size count with the points
+		// the size maps to.
+		points, fromSize := j.estimatePoints(issue, estimates)
+		if fromSize {
+			sized.issues++
+			sized.points += points
+		}
+		addTally(byStatus, statusName(&issue), points)
+		if estimates.confidence != "" {
+			confidence := fieldText(issue.Fields.Unknowns[estimates.confidence])
+			if confidence == "" {
+				confidence = "(no confidence)"
+			}
+			addTally(byConfidence, confidence, points)
+		}
+		total.issues++
+		total.points += points
+		done := categories[strings.ToLower(statusName(&issue))] == "done"
+		if done {
+			completed.issues++
+			completed.points += points
+		}
+		if !started {
+			continue
+		}
+		if before := scope.stateAt(issue, start, pointsID, pointsName, categories); before.inScope {
+			committed.issues++
+			committed.points += before.points
+			if done {
+				committedDone.issues++
+				committedDone.points += points
+			}
+		} else {
+			added = append(added, fmt.Sprintf("%s (%s pts): %s", issue.Key, formatPoints(points), issue.Fields.Summary))
+		}
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "Sprint %d %q (%s)", s.ID, s.Name, s.State)
+	if s.Goal != "" {
+		fmt.Fprintf(&sb, "\nGoal: %s", s.Goal)
+	}
+	fmt.Fprintf(&sb, "\nIssues: %d, %s points", total.issues, formatPoints(total.points))
+	if pointsName == "" {
+		sb.WriteString(" (no story points field found)")
+	}
+	fmt.Fprintf(&sb, "\nCompleted: %d issues, %s points\n", completed.issues, formatPoints(completed.points))
+	if sized.issues > 0 {
+		fmt.Fprintf(&sb, "Counted from T-shirt sizes: %d issues without story points, %s points (%s)\n", sized.issues, formatPoints(sized.points), j.sizeNames())
+	}
+	if params.CapacityPoints > 0 {
+		fmt.Fprintf(&sb, "Capacity: %s points; the sprint holds %s points (%.0f%%), %s points remain open\n",
+			formatPoints(params.CapacityPoints), formatPoints(total.points), 100*total.points/params.CapacityPoints, formatPoints(total.points-completed.points))
+	}
+	if started {
+		fmt.Fprintf(&sb, "Committed at start: %d issues, %s points; %d of them (%s points) completed\n",
+			committed.issues, formatPoints(committed.points), committedDone.issues, formatPoints(committedDone.points))
+		fmt.Fprintf(&sb, "Added after start: %d issues\n", len(added))
+		for _, a := range added {
+			fmt.Fprintf(&sb, "  - %s\n", a)
+		}
+	} else {
+		sb.WriteString("The sprint has not started, so there is no commitment yet.\n")
+	}
+	sb.WriteString("By status:\n")
+	for _, t := range sortedTallies(byStatus) {
+		fmt.Fprintf(&sb, "- %s: %d issues, %s points\n", t.name, t.issues, formatPoints(t.points))
+	}
+	if len(byConfidence) > 0 {
+		sb.WriteString("By confidence:\n")
+		for _, t := range sortedTallies(byConfidence) {
+			fmt.Fprintf(&sb, "- %s: %d issues, %s points\n", t.name, t.issues, formatPoints(t.points))
+		}
+	}
+	if len(issues) == maxIssues {
+		fmt.Fprintf(&sb, "Only the first %d issues were counted; raise maxIssues.\n", maxIssues)
+	}
+	sb.WriteString("Issues moved out of the sprint are not included.\n")
+	return textResult("%s", sb.String()), nil, nil
+}
+
+// reportGroups returns the names an issue is counted under for groupBy.
+func reportGroups(issue jira.Issue, groupBy string) []string {
+	if groupBy == "component" {
+		if len(issue.Fields.Components) == 0 {
+			return []string{"(no component)"}
+		}
+		names := make([]string, 0, len(issue.Fields.Components))
+		for _, c := range issue.Fields.Components {
+			names = append(names, c.Name)
+		}
+		return names
+	}
+	if issue.Fields.Assignee == nil {
+		return []string{"(unassigned)"}
+	}
+	return []string{issue.Fields.Assignee.DisplayName}
+}
+
+// ProjectStatusReport counts the issues of a project created and resolved in
+// a period and those still open, broken down by assignee or component.
+func (j *JiraMCPServer) ProjectStatusReport(ctx context.Context, req *mcp.CallToolRequest, params *ProjectStatusReportParams) (*mcp.CallToolResult, any, error) {
+	projectKey := j.projectKeyOrDefault(params.ProjectKey)
+	groupBy := strings.ToLower(params.GroupBy)
+	if groupBy == "" {
+		groupBy = "assignee"
+	}
+	if groupBy != "assignee" && groupBy != "component" {
+		return textResult("groupBy must be \"assignee\" or \"component\", got %q", params.GroupBy), nil, nil
+	}
+	maxIssues := params.MaxIssues
+	if maxIssues <= 0 {
+		maxIssues = defaultReportIssues
+	}
+	// to is exclusive: the start of the day after the period.
+	now := time.Now()
+	to := time.Date(now.Year(), now.Month(), now.Day()+1, 0, 0, 0, 0, time.Local)
+	if params.To != "" {
+		t, err := time.ParseInLocation("2006-01-02", params.To, time.Local)
+		if err != nil {
+			return textResult("to must be YYYY-MM-DD, got %q", params.To), nil, nil
+		}
+		to = t.AddDate(0, 0, 1)
+	}
+	from := to.AddDate(0, 0, -30)
+	if params.From != "" {
+		t, err := time.ParseInLocation("2006-01-02", params.From, time.Local)
+		if err != nil {
+			return textResult("from must be YYYY-MM-DD, got %q", params.From), nil, nil
+		}
+		from = t
+	}
+	if !from.Before(to) {
+		return textResult("from must be before to"), nil, nil
+	}
+
+	const day = "2006-01-02"
+	jql := fmt.Sprintf(`project = %s AND (created >= "%s" OR resolved >= "%s" OR resolution = EMPTY) AND created < "%s" ORDER BY key ASC`,
+		projectKey, from.Format(day), from.Format(day), to.Format(day))
+	issues, err := j.searchIssues(ctx, jql, []string{"created", "resolutiondate", "assignee", "components", "status"}, "", maxIssues)
+	if err != nil {
+		return textResult("Failed to load issues of %s: %v", projectKey, err), nil, nil
+	}
+
+	created, resolved, open := make(map[string]*tally), make(map[string]*tally), make(map[string]*tally)
+	var nCreated, nResolved, nOpen int
+	for _, issue := range issues {
+		groups := reportGroups(issue, groupBy)
+		c := time.Time(issue.Fields.Created)
+		r := time.Time(issue.Fields.Resolutiondate)
+		if !c.Before(from) && c.Before(to) {
+			nCreated++
+			for _, g := range groups {
+				addTally(created, g, 0)
+			}
+		}
+		if !r.IsZero() && !r.Before(from) && r.Before(to) {
+			nResolved++
+			for _, g := range groups {
+				addTally(resolved, g, 0)
+			}
+		}
+		if r.IsZero() || !r.Before(to) {
+			nOpen++
+			for _, g := range groups {
+				addTally(open, g, 0)
+			}
+		}
+	}
+
+	names := make(map[string]bool)
+	for _, m := range []map[string]*tally{created, resolved, open} {
+		for name := range m {
+			names[name] = true
+		}
+	}
+	rows := make(map[string]*tally, len(names))
+	for name := range names {
+		rows[name] = &tally{name: name}
+		if t := open[name]; t != nil {
+			rows[name].issues = t.issues
+		}
+	}
+	count := func(m map[string]*tally, name string) int {
+		if t := m[name]; t != nil {
+			return t.issues
+		}
+		return 0
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "%s from %s to %s: %d created, %d resolved, %d open at the end (net %+d)\n",
+		projectKey, from.Format(day), to.AddDate(0, 0, -1).Format(day), nCreated, nResolved, nOpen, nCreated-nResolved)
+	fmt.Fprintf(&sb, "\nBy %s (created / resolved / open):\n", groupBy)
+	for _, t := range sortedTallies(rows) {
+		fmt.Fprintf(&sb, "- %s: %d / %d / %d\n", t.name, count(created, t.name), count(resolved, t.name), count(open, t.name))
+	}
+	if groupBy == "component" {
+		sb.WriteString("Issues with several components are counted under each.\n")
+	}
+	if len(issues) == maxIssues {
+		fmt.Fprintf(&sb, "Only the first %d issues were counted; raise maxIssues or shorten the period.\n", maxIssues)
+	}
+	return textResult("%s", sb.String()), nil, nil
+}
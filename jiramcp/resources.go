@@ -0,0 +1,178 @@
+package jiramcp
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/andygrunwald/go-jira"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// jiraTimeLayout is the timestamp format used by the Jira REST API for
+// created/updated fields on comments and changelog entries.
+const jiraTimeLayout = "2006-01-02T15:04:05.000-0700"
+
+// commentPageSize is the number of comments requested per page when paging
+// through an issue's comment thread.
+const commentPageSize = 100
+
+type commentPage struct {
+	StartAt    int             `json:"startAt"`
+	MaxResults int             `json:"maxResults"`
+	Total      int             `json:"total"`
+	Comments   []*jira.Comment `json:"comments"`
+}
+
+func (j *JiraMCPServer) addResources() {
+	j.server.AddResourceTemplate(&mcp.ResourceTemplate{
+		Name:        "issue-discussion-summary",
+		URITemplate: "jira://issue/{key}/discussion-summary",
+		Description: "Compact, normalized transcript of an issue's comment thread (one line per comment with author and timestamp). " +
+			"Optional query parameters: last=N keeps only the N most recent comments, since=YYYY-MM-DD drops older comments.",
+		MIMEType: "text/plain",
+	}, j.DiscussionSummary)
+	j.server.AddResourceTemplate(&mcp.ResourceTemplate{
+		Name:        "issue-link-graph",
+		URITemplate: "jira://issue/{key}/link-graph",
+		Description: "Nodes/edges JSON of the issues reachable from an issue through links, subtasks, and parents, for rendering dependency diagrams. " +
+			"Optional query parameters: depth (default 2, max 5) and maxNodes (default 50, max 200).",
+		MIMEType: "application/json",
+	}, j.LinkGraph)
+	j.server.AddResource(&mcp.Resource{
+		Name:        "daily-digest",
+		URI:         digestURI,
+		Description: "The most recent daily digest of new, overdue, and SLA-risk issues. Subscribe to be notified when a new one is published.",
+		MIMEType:    "text/markdown",
+	}, j.DigestResource)
+	j.server.AddResourceTemplate(&mcp.ResourceTemplate{
+		Name:        "issue-export",
+		URITemplate: exportURIPrefix + "{name}",
+		Description: "A CSV or JSON file of search results written by the export-issues tool. Exports are removed after a day.",
+	}, j.ExportResource)
+}
+
+// acceptSubscription allows clients to subscribe to any resource; the SDK keeps
+// track of subscribers and only delivers updates for subscribed URIs.
+func acceptSubscription(context.Context, *mcp.SubscribeRequest) error { return nil }
+
+func acceptUnsubscription(context.Context, *mcp.UnsubscribeRequest) error { return nil }
+
+// parseIssueResourceURI splits a jira://issue/{key}/{suffix} URI into the issue key
+// and its query parameters. It returns an error if the URI does not address
+// the expected suffix.
+func parseIssueResourceURI(uri, suffix string) (string, url.Values, error) {
+	u, err := url.Parse(uri)
+	if err != nil {
+		return "", nil, fmt.Errorf("invalid resource URI %q: %w", uri, err)
+	}
+	parts := strings.Split(strings.Trim(u.Path, "/"), "/")
+	if u.Scheme != "jira" || u.Host != "issue" || len(parts) != 2 || parts[0] == "" || parts[1] != suffix {
+		return "", nil, fmt.Errorf("unsupported resource URI %q", uri)
+	}
+	return strings.ToUpper(parts[0]), u.Query(), nil
+}
+
+// fetchAllComments pages through the comment endpoint for an issue and returns
+// every comment in chronological order.
+func (j *JiraMCPServer) fetchAllComments(ctx context.Context, issueKey string) ([]*jira.Comment, error) {
+	var comments []*jira.Comment
+	for startAt := 0; ; {
+		var page commentPage
+		path := fmt.Sprintf("rest/api/2/issue/%s/comment?orderBy=created&startAt=%d&maxResults=%d", issueKey, startAt, commentPageSize)
+		if _, err := j.jiraDo(ctx, "GET", path, nil, &page); err != nil {
+			return nil, fmt.Errorf("failed to fetch comments for %s: %w", issueKey, err)
+		}
+		comments = append(comments, page.Comments...)
+		startAt += len(page.Comments)
+		if len(page.Comments) == 0 || startAt >= page.Total {
+			return comments, nil
+		}
+	}
+}
+
+// DiscussionSummary serves jira://issue/{key}/discussion-summary. Each comment is
+// rendered on a single line as "[timestamp] author: body" with whitespace
+// collapsed, which keeps long threads cheap to feed to the model.
+func (j *JiraMCPServer) DiscussionSummary(ctx context.Context, req *mcp.ReadResourceRequest) (*mcp.ReadResourceResult, error) {
+	issueKey, query, err := parseIssueResourceURI(req.Params.URI, "discussion-summary")
+	if err != nil {
+		return nil, err
+	}
+
+	last := 0
+	if v := query.Get("last"); v != "" {
+		last, err = strconv.Atoi(v)
+		if err != nil || last < 0 {
+			return nil, fmt.Errorf("invalid 'last' parameter %q: must be a non-negative integer", v)
+		}
+	}
+	var since time.Time
+	if v := query.Get("since"); v != "" {
+		since, err = time.Parse("2006-01-02", v)
+		if err != nil {
+			return nil, fmt.Errorf("invalid 'since' parameter %q: expected YYYY-MM-DD", v)
+		}
+	}
+
+	comments, err := j.fetchAllComments(ctx, issueKey)
+	if err != nil {
+		return nil, err
+	}
+	total := len(comments)
+
+	if !since.IsZero() {
+		kept := comments[:0]
+		for _, c := range comments {
+			created, err := time.Parse(jiraTimeLayout, c.Created)
+			if err != nil || !created.Before(since) {
+				kept = append(kept, c)
+			}
+		}
+		comments = kept
+	}
+	if last > 0 && len(comments) > last {
+		comments = comments[len(comments)-last:]
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "%s discussion: %d of %d comments\n", issueKey, len(comments), total)
+	for _, c := range comments {
+		fmt.Fprintf(&sb, "[%s] %s: %s\n", formatJiraTimestamp(c.Created), commentAuthor(c), normalizeWhitespace(c.Body))
+	}
+
+	return &mcp.ReadResourceResult{
+		Contents: []*mcp.ResourceContents{
+			{URI: req.Params.URI, MIMEType: "text/plain", Text: sb.String()},
+		},
+	}, nil
+}
+
+// formatJiraTimestamp shortens a Jira API timestamp to minute precision in UTC,
+// falling back to the raw value if it cannot be parsed.
+func formatJiraTimestamp(ts string) string {
+	t, err := time.Parse(jiraTimeLayout, ts)
+	if err != nil {
+		return ts
+	}
+	return t.UTC().Format("2006-01-02 15:04Z")
+}
+
+func commentAuthor(c *jira.Comment) string {
+	if c.Author.DisplayName != "" {
+		return c.Author.DisplayName
+	}
+	if c.Author.Name != "" {
+		return c.Author.Name
+	}
+	return "unknown"
+}
+
+// normalizeWhitespace collapses all runs of whitespace (including newlines) into
+// a single space so multi-paragraph comments fit on one line.
+func normalizeWhitespace(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
@@ -0,0 +1,158 @@
+package jiramcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// rotationBucket holds the persisted position of each rotation.
+const rotationBucket = "rotations"
+
+// rotation distributes issues of a project, or of one component in it, over
+// its members in turn.
+type rotation struct {
+	Name      string `json:"name"`
+	Project   string `json:"project"`
+	Component string `json:"component,omitempty"`
+	// Members are the accountIds taking turns, or the usernames on Server
+	// and Data Center.
+	Members []string `json:"members"`
+}
+
+// rotationState is the persisted position of a rotation.
+type rotationState struct {
+	Next         int    `json:"next"`
+	LastAssignee string `json:"lastAssignee,omitempty"`
+	LastIssue    string `json:"lastIssue,omitempty"`
+}
+
+type AssignNextInRotationParams struct {
+	IssueKey string `json:"issueKey"`
+	// Rotation names the rotation to use; by default it is chosen by the
+	// issue's project and components.
+	Rotation string `json:"rotation,omitempty"`
+	DryRun   bool   `json:"dryRun,omitempty"`
+}
+
+// loadRotations reads the rotations defined in JIRA_MCP_ROTATIONS_FILE. The
+// file is read on every call so edits apply without a restart.
+func (j *JiraMCPServer) loadRotations() ([]rotation, error) {
+	if j.config.RotationsFile == "" {
+		return nil, fmt.Errorf("no rotations are configured (set JIRA_MCP_ROTATIONS_FILE)")
+	}
+	b, err := os.ReadFile(j.config.RotationsFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read rotations file: %w", err)
+	}
+	var rotations []rotation
+	if err := json.Unmarshal(b, &rotations); err != nil {
+		return nil, fmt.Errorf("failed to parse rotations file %s: %w", j.config.RotationsFile, err)
+	}
+	for i, r := range rotations {
+		if r.Name == "" || len(r.Members) == 0 {
+			return nil, fmt.Errorf("rotation %d in %s needs a name and at least one member", i+1, j.config.RotationsFile)
+		}
+	}
+	return rotations, nil
+}
+
+// matchRotation picks the rotation for an issue: one for any of its components
+// if defined, otherwise one for the whole project.
+func matchRotation(rotations []rotation, project string, components []string) *rotation {
+	var projectWide *rotation
+	for i, r := range rotations {
+		if !strings.EqualFold(r.Project, project) {
+			continue
+		}
+		if r.Component == "" {
+			if projectWide == nil {
+				projectWide = &rotations[i]
+			}
+			continue
+		}
+		for _, c := range components {
+			if strings.EqualFold(r.Component, c) {
+				return &rotations[i]
+			}
+		}
+	}
+	return projectWide
+}
+
+// AssignNextInRotation assigns an issue to the next member of its rotation.
+// Members that cannot be assigned (for example because they left the project)
+// are skipped.
+func (j *JiraMCPServer) AssignNextInRotation(ctx context.Context, req *mcp.CallToolRequest, params *AssignNextInRotationParams) (*mcp.CallToolResult, any, error) {
+	rotations, err := j.loadRotations()
+	if err != nil {
+		return textResult("%v", err), nil, nil
+	}
+	issue, _, err := j.client(ctx).GetIssue(ctx, params.IssueKey, nil)
+	if err != nil {
+		return textResult("Failed to get JIRA issue %s: %v", params.IssueKey, err), nil, nil
+	}
+
+	var r *rotation
+	if params.Rotation != "" {
+		for i := range rotations {
+			if strings.EqualFold(rotations[i].Name, params.Rotation) {
+				r = &rotations[i]
+			}
+		}
+		if r == nil {
+			return textResult("Unknown rotation %q", params.Rotation), nil, nil
+		}
+	} else {
+		var components []string
+		for _, c := range issue.Fields.Components {
+			components = append(components, c.Name)
+		}
+		if r = matchRotation(rotations, issue.Fields.Project.Key, components); r == nil {
+			return textResult("No rotation is configured for %s or its components", issue.Fields.Project.Key), nil, nil
+		}
+	}
+
+	// The lock keeps concurrent calls from handing out the same turn.
+	j.rotationMu.Lock()
+	defer j.rotationMu.Unlock()
+	var state rotationState
+	if _, err := j.store.Get(rotationBucket, r.Name, &state); err != nil {
+		return textResult("Failed to load state of rotation %s: %v", r.Name, err), nil, nil
+	}
+
+	path := fmt.Sprintf("rest/api/2/issue/%s/assignee", issue.Key)
+	if j.dryRun(params.DryRun) {
+		member := r.Members[state.Next%len(r.Members)]
+		return dryRunResult("PUT", path, j.userRef(member), nil), nil, nil
+	}
+
+	var failures []string
+	for attempt := 0; attempt < len(r.Members); attempt++ {
+		member := r.Members[(state.Next+attempt)%len(r.Members)]
+		if _, err := j.jiraDo(ctx, "PUT", path, j.userRef(member), nil); err != nil {
+			failures = append(failures, fmt.Sprintf("%s: %v", member, err))
+			continue
+		}
+		state = rotationState{
+			Next:         (state.Next + attempt + 1) % len(r.Members),
+			LastAssignee: member,
+			LastIssue:    issue.Key,
+		}
+		if err := j.store.Put(rotationBucket, r.Name, state); err != nil {
+			logger(ctx).Error("Failed to save rotation state", "rotation", r.Name, "error", err)
+		}
+		logger(ctx).Info("Assigned issue from rotation", "assignee", member, "rotation", r.Name)
+
+		result := fmt.Sprintf("Assigned %s to %s (rotation %s); next up: %s", issue.Key, member, r.Name, r.Members[state.Next])
+		if len(failures) > 0 {
+			result += "\nSkipped members that could not be assigned:\n- " + strings.Join(failures, "\n- ")
+		}
+		return textResult("%s", result), nil, nil
+	}
+	return textResult("No member of rotation %s could be assigned %s:\n- %s", r.Name, issue.Key, strings.Join(failures, "\n- ")), nil, nil
+}
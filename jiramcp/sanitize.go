@@ -0,0 +1,280 @@
+package jiramcp
+
+import (
+	"html"
+	"net/url"
+	"strings"
+)
+
+// sanitizedTags are the HTML elements kept by sanitizeHTML. Other elements
+// are dropped but their text is kept, except for droppedTags.
+var sanitizedTags = map[string]bool{
+	"a": true, "b": true, "blockquote": true, "br": true, "cite": true, "code": true,
+	"dd": true, "del": true, "div": true, "dl": true, "dt": true, "em": true,
+	"h1": true, "h2": true, "h3": true, "h4": true, "h5": true, "h6": true,
+	"hr": true, "i": true, "img": true, "ins": true, "li": true, "ol": true,
+	"p": true, "pre": true, "q": true, "s": true, "small": true, "span": true,
+	"strong": true, "sub": true, "sup": true, "table": true, "tbody": true,
+	"td": true, "tfoot": true, "th": true, "thead": true, "tr": true, "tt": true,
+	"u": true, "ul": true,
+}
+
+// droppedTags are removed together with their content.
+var droppedTags = map[string]bool{
+	"script": true, "style": true, "iframe": true, "object": true, "embed": true,
+	"noscript": true, "template": true, "textarea": true, "select": true,
+	"svg": true, "math": true, "head": true, "title": true, "frameset": true,
+}
+
+// voidTags have no closing tag.
+var voidTags = map[string]bool{"br": true, "hr": true, "img": true}
+
+// sanitizedAttrs are the attributes kept per element. Event handlers and
+// style are never kept.
+var sanitizedAttrs = map[string][]string{
+	"a":   {"href", "title"},
+	"img": {"src", "alt", "title", "width", "height"},
+	"td":  {"colspan", "rowspan"},
+	"th":  {"colspan", "rowspan"},
+	"ol":  {"start"},
+}
+
+// numericAttrs only keep values made of digits.
+var numericAttrs = map[string]bool{"width": true, "height": true, "colspan": true, "rowspan": true, "start": true}
+
+// htmlTag is a parsed start or end tag.
+type htmlTag struct {
+	name        string
+	end         bool
+	selfClosing bool
+	attrs       [][2]string
+}
+
+// sanitizeHTML reduces HTML, such as Jira's rendered descriptions, to a small
+// allowlist of formatting elements and attributes that are safe to display
+// in a web page. Nothing of the input is copied through verbatim: tags are
+// parsed and written back from the allowlist, and text is re-escaped, so
+// malformed input can lose formatting but never smuggle markup. Relative
+// links and images are resolved against base.
+func sanitizeHTML(s string, base *url.URL) string {
+	var sb strings.Builder
+	var open []string
+	text := func(t string) {
+		sb.WriteString(html.EscapeString(html.UnescapeString(t)))
+	}
+	for i := 0; i < len(s); {
+		lt := strings.IndexByte(s[i:], '<')
+		if lt < 0 {
+			text(s[i:])
+			break
+		}
+		text(s[i : i+lt])
+		i += lt
+		rest := s[i:]
+		if strings.HasPrefix(rest, "<!--") {
+			end := strings.Index(rest[4:], "-->")
+			if end < 0 {
+				break
+			}
+			i += 4 + end + 3
+			continue
+		}
+		if strings.HasPrefix(rest, "<!") || strings.HasPrefix(rest, "<?") {
+			end := strings.IndexByte(rest, '>')
+			if end < 0 {
+				break
+			}
+			i += end + 1
+			continue
+		}
+		tag, n, ok := parseHTMLTag(rest)
+		if !ok {
+			text("<")
+			i++
+			continue
+		}
+		i += n
+
+		switch {
+		case droppedTags[tag.name] && !tag.end && !tag.selfClosing:
+			// Skip to the matching end tag, as browsers treat the content of
+			// script and style as raw text.
+			end := findEndTag(s[i:], tag.name)
+			if end < 0 {
+				i = len(s)
+				break
+			}
+			i += end
+			if gt := strings.IndexByte(s[i:], '>'); gt >= 0 {
+				i += gt + 1
+			} else {
+				i = len(s)
+			}
+		case !sanitizedTags[tag.name]:
+		case tag.end:
+			for k := len(open) - 1; k >= 0; k-- {
+				if open[k] == tag.name {
+					for len(open) > k {
+						sb.WriteString("</" + open[len(open)-1] + ">")
+						open = open[:len(open)-1]
+					}
+					break
+				}
+			}
+		default:
+			sb.WriteString("<" + tag.name)
+			for _, attr := range tag.attrs {
+				if value, ok := sanitizedAttr(tag.name, attr[0], html.UnescapeString(attr[1]), base); ok {
+					sb.WriteString(" " + attr[0] + `="` + html.EscapeString(value) + `"`)
+				}
+			}
+			if tag.name == "a" {
+				sb.WriteString(` rel="noopener noreferrer nofollow"`)
+			}
+			sb.WriteString(">")
+			if !voidTags[tag.name] {
+				open = append(open, tag.name)
+			}
+		}
+	}
+	for k := len(open) - 1; k >= 0; k-- {
+		sb.WriteString("</" + open[k] + ">")
+	}
+	return sb.String()
+}
+
+// sanitizedAttr returns the value to keep for an attribute, or false when it
+// is dropped.
+func sanitizedAttr(tag, name, value string, base *url.URL) (string, bool) {
+	allowed := false
+	for _, a := range sanitizedAttrs[tag] {
+		allowed = allowed || a == name
+	}
+	switch {
+	case !allowed:
+		return "", false
+	case numericAttrs[name]:
+		if value == "" || strings.Trim(value, "0123456789") != "" {
+			return "", false
+		}
+		return value, true
+	case name == "href":
+		return safeURL(value, base, "http", "https", "mailto")
+	case name == "src":
+		return safeURL(value, base, "http", "https")
+	}
+	return value, true
+}
+
+// safeURL resolves a link against base and keeps it only when it uses one
+// of schemes. Whitespace and control characters, which browsers ignore
+// inside schemes, are removed first so "java\tscript:" is caught.
+func safeURL(raw string, base *url.URL, schemes ...string) (string, bool) {
+	cleaned := strings.Map(func(r rune) rune {
+		if r <= ' ' || r == 0x7f {
+			return -1
+		}
+		return r
+	}, raw)
+	u, err := url.Parse(cleaned)
+	if err != nil {
+		return "", false
+	}
+	if base != nil {
+		u = base.ResolveReference(u)
+	}
+	for _, scheme := range schemes {
+		if strings.EqualFold(u.Scheme, scheme) {
+			return u.String(), true
+		}
+	}
+	return "", false
+}
+
+// parseHTMLTag parses the tag at the start of s and returns it with its
+// length. It reports false when s does not start with a well-formed tag.
+func parseHTMLTag(s string) (htmlTag, int, bool) {
+	var tag htmlTag
+	i := 1
+	if i < len(s) && s[i] == '/' {
+		tag.end = true
+		i++
+	}
+	start := i
+	for i < len(s) && (isASCIILetter(s[i]) || (i > start && (s[i] >= '0' && s[i] <= '9' || s[i] == '-'))) {
+		i++
+	}
+	if i == start {
+		return tag, 0, false
+	}
+	tag.name = strings.ToLower(s[start:i])
+	for {
+		for i < len(s) && (isHTMLSpace(s[i]) || s[i] == '/') {
+			tag.selfClosing = s[i] == '/'
+			i++
+		}
+		if i >= len(s) {
+			return tag, 0, false
+		}
+		if s[i] == '>' {
+			return tag, i + 1, true
+		}
+		tag.selfClosing = false
+		nameStart := i
+		for i < len(s) && !isHTMLSpace(s[i]) && s[i] != '=' && s[i] != '>' && s[i] != '/' {
+			i++
+		}
+		name := strings.ToLower(s[nameStart:i])
+		for i < len(s) && isHTMLSpace(s[i]) {
+			i++
+		}
+		value := ""
+		if i < len(s) && s[i] == '=' {
+			i++
+			for i < len(s) && isHTMLSpace(s[i]) {
+				i++
+			}
+			if i < len(s) && (s[i] == '"' || s[i] == '\'') {
+				end := strings.IndexByte(s[i+1:], s[i])
+				if end < 0 {
+					return tag, 0, false
+				}
+				value = s[i+1 : i+1+end]
+				i += end + 2
+			} else {
+				valueStart := i
+				for i < len(s) && !isHTMLSpace(s[i]) && s[i] != '>' {
+					i++
+				}
+				value = s[valueStart:i]
+			}
+		}
+		tag.attrs = append(tag.attrs, [2]string{name, value})
+	}
+}
+
+// findEndTag returns the offset of the first end tag of the named element in
+// s, or -1. The name is compared in place, ignoring ASCII case, as offsets
+// into a lower-cased copy of s need not hold in s.
+func findEndTag(s, name string) int {
+	for i := 0; ; {
+		lt := strings.Index(s[i:], "</")
+		if lt < 0 {
+			return -1
+		}
+		i += lt
+		n := i + 2 + len(name)
+		if n <= len(s) && strings.EqualFold(s[i+2:n], name) && (n == len(s) || isHTMLSpace(s[n]) || s[n] == '/' || s[n] == '>') {
+			return i
+		}
+		i += 2
+	}
+}
+
+func isASCIILetter(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z'
+}
+
+func isHTMLSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == '\f'
+}
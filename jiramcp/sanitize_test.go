@@ -0,0 +1,44 @@
+package jiramcp
+
+import (
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func TestSanitizeHTML(t *testing.T) {
+	base, _ := url.Parse("https://jira.example.com/")
+	const rel = ` rel="noopener noreferrer nofollow"`
+	tests := []struct {
+		name, in, want string
+	}{
+		{"formatting", `<p>a <b>b</b><br/>c</p>`, `<p>a <b>b</b><br>c</p>`},
+		{"script", `<p>a</p><script>alert("</p>")</script><p>b</p>`, `<p>a</p><p>b</p>`},
+		{"script in mixed case", `<SCRIPT>x</ScRiPt >y`, `y`},
+		{"longer end tag name", `<script>x</scripts>y</script>z`, `z`},
+		{"script with non-ASCII", "<script>" + strings.Repeat("Ⱥ", 10) + "</script>after", `after`},
+		{"unclosed script", `a<script>alert(1)`, `a`},
+		{"style", `<style>p { color: red }</style>text`, `text`},
+		{"comment", `a<!-- <script> -->b`, `ab`},
+		{"unknown tag keeps text", `<font color="red">x</font>`, `x`},
+		{"event handler", `<b onclick="alert(1)">y</b>`, `<b>y</b>`},
+		{"javascript href", `<a href="javascript:alert(1)">x</a>`, `<a` + rel + `>x</a>`},
+		{"javascript href with tab", "<a href=\"java\tscript:alert(1)\">x</a>", `<a` + rel + `>x</a>`},
+		{"javascript href with entity", `<a href="java&#9;script:alert(1)">x</a>`, `<a` + rel + `>x</a>`},
+		{"relative href", `<a href="/browse/SMS-1">SMS-1</a>`, `<a href="https://jira.example.com/browse/SMS-1"` + rel + `>SMS-1</a>`},
+		{"data src", `<img src="data:image/png;base64,AAAA">`, `<img>`},
+		{"attribute quoting", `<img src=x.png alt='a"b' width="10px" height=20>`, `<img src="https://jira.example.com/x.png" alt="a&#34;b" height="20">`},
+		{"quoted greater-than", `<a title="x>y">t</a>`, `<a title="x&gt;y"` + rel + `>t</a>`},
+		{"unterminated attribute", `<a href="x`, `&lt;a href=&#34;x`},
+		{"unclosed tags", `<b>bold <i>both`, `<b>bold <i>both</i></b>`},
+		{"stray end tag", `</b>x</i>`, `x`},
+		{"misnested tags", `<b><i>x</b>y</i>`, `<b><i>x</i></b>y`},
+		{"bare less-than", `a < b & c`, `a &lt; b &amp; c`},
+		{"non-ASCII text", `<p>Größe – 日本 &amp; Ⱥ</p>`, `<p>Größe – 日本 &amp; Ⱥ</p>`},
+	}
+	for _, tt := range tests {
+		if got := sanitizeHTML(tt.in, base); got != tt.want {
+			t.Errorf("%s: sanitizeHTML(%q) = %q, want %q", tt.name, tt.in, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,322 @@
+package jiramcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// projectArgs and issueArgs are the tool arguments that name projects and
+// issues, as single values or lists. They are collected at any depth of a
+// call's arguments, so every project a call touches is checked, not only
+// its projectKey.
+var (
+	projectArgs = map[string]bool{
+		"projectKey":       true,
+		"projectKeys":      true,
+		"sourceProject":    true,
+		"targetProjectKey": true,
+	}
+	issueArgs = map[string]bool{
+		"issueKey":       true,
+		"issueKeys":      true,
+		"issueIdsOrKeys": true,
+		"epicKey":        true,
+		"testExecKey":    true,
+		"above":          true,
+		"below":          true,
+	}
+)
+
+// defaultProjectTools work on JIRA_PROJECT_KEY when called without a project,
+// so such a call addresses the default project.
+var defaultProjectTools = map[string]bool{
+	"create-jira-issue":          true,
+	"create-work-breakdown":      true,
+	"create-issue-from-template": true,
+	"list-overdue-issues":        true,
+	"transition-heatmap":         true,
+	"burnup":                     true,
+	"list-components":            true,
+	"create-component":           true,
+	"snapshot-project-setup":     true,
+	"check-my-permissions":       true,
+	"audit-project-permissions":  true,
+	"list-security-levels":       true,
+	"list-boards":                true,
+	"get-backlog":                true,
+	"reorder-backlog":            true,
+	"project-status-report":      true,
+	"most-voted-issues":          true,
+	"issue-numbering-report":     true,
+	"field-normalization-report": true,
+}
+
+// issueKeyLike matches issue keys, as opposed to numeric issue IDs.
+var issueKeyLike = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9_]*-[0-9]+$`)
+
+// argStrings returns the string or strings of an argument value. Numbers,
+// such as board IDs, are returned in their decimal form, and 0 as unset.
+func argStrings(v interface{}) []string {
+	switch v := v.(type) {
+	case string:
+		if v = strings.TrimSpace(v); v != "" {
+			return []string{v}
+		}
+	case float64:
+		if v != 0 {
+			return []string{strconv.FormatFloat(v, 'f', -1, 64)}
+		}
+	case []interface{}:
+		var values []string
+		for _, e := range v {
+			values = append(values, argStrings(e)...)
+		}
+		return values
+	}
+	return nil
+}
+
+// toolCallProjects returns the projects a tool call addresses: the projects
+// it names, those of the issues it names, and those of the boards and
+// service desks it names, which are looked up in Jira along with issues
+// given by ID. A call of a defaultProjectTools tool naming none addresses
+// the default project.
+func (j *JiraMCPServer) toolCallProjects(ctx context.Context, tool string, arguments json.RawMessage) ([]string, error) {
+	var args interface{}
+	if len(arguments) > 0 {
+		if err := json.Unmarshal(arguments, &args); err != nil {
+			return nil, fmt.Errorf("invalid arguments: %w", err)
+		}
+	}
+
+	var projects []string
+	add := func(project string) {
+		if project = strings.ToUpper(project); project != "" && !slices.Contains(projects, project) {
+			projects = append(projects, project)
+		}
+	}
+	var walk func(v interface{}) error
+	walk = func(v interface{}) error {
+		switch v := v.(type) {
+		case []interface{}:
+			for _, e := range v {
+				if err := walk(e); err != nil {
+					return err
+				}
+			}
+		case map[string]interface{}:
+			for name, value := range v {
+				var lookup func(context.Context, string) (string, error)
+				switch {
+				case projectArgs[name]:
+					for _, project := range argStrings(value) {
+						add(project)
+					}
+					continue
+				case issueArgs[name]:
+					lookup = j.issueProject
+				case name == "boardId":
+					lookup = j.boardProject
+				case name == "serviceDeskId":
+					lookup = j.serviceDeskProject
+				default:
+					if err := walk(value); err != nil {
+						return err
+					}
+					continue
+				}
+				for _, id := range argStrings(value) {
+					project, err := lookup(ctx, id)
+					if err != nil {
+						return err
+					}
+					add(project)
+				}
+			}
+		}
+		return nil
+	}
+	if err := walk(args); err != nil {
+		return nil, err
+	}
+	if len(projects) == 0 && defaultProjectTools[tool] {
+		add(j.config.ProjectKey)
+	}
+	return projects, nil
+}
+
+// issueProject returns the project of an issue given by key or ID. Keys are
+// read directly; IDs are looked up.
+func (j *JiraMCPServer) issueProject(ctx context.Context, idOrKey string) (string, error) {
+	if issueKeyLike.MatchString(idOrKey) {
+		return projectOfIssue(idOrKey), nil
+	}
+	return cached(ctx, j.cache, "metadata", "issueproject:"+idOrKey, func() (string, error) {
+		var issue struct {
+			Fields struct {
+				Project struct {
+					Key string `json:"key"`
+				} `json:"project"`
+			} `json:"fields"`
+		}
+		if _, err := j.jiraDo(ctx, "GET", fmt.Sprintf("rest/api/2/issue/%s?fields=project", url.PathEscape(idOrKey)), nil, &issue); err != nil {
+			return "", fmt.Errorf("failed to look up the project of issue %s: %w", idOrKey, err)
+		}
+		return issue.Fields.Project.Key, nil
+	})
+}
+
+// boardProject returns the project a board belongs to.
+func (j *JiraMCPServer) boardProject(ctx context.Context, boardID string) (string, error) {
+	return cached(ctx, j.cache, "metadata", "boardproject:"+boardID, func() (string, error) {
+		var board struct {
+			Location struct {
+				ProjectKey string `json:"projectKey"`
+			} `json:"location"`
+		}
+		if _, err := j.jiraDo(ctx, "GET", "rest/agile/1.0/board/"+url.PathEscape(boardID), nil, &board); err != nil {
+			return "", fmt.Errorf("failed to look up board %s: %w", boardID, err)
+		}
+		if board.Location.ProjectKey == "" {
+			return "", fmt.Errorf("board %s belongs to no project", boardID)
+		}
+		return board.Location.ProjectKey, nil
+	})
+}
+
+// serviceDeskProject returns the project of a service desk.
+func (j *JiraMCPServer) serviceDeskProject(ctx context.Context, serviceDeskID string) (string, error) {
+	return cached(ctx, j.cache, "metadata", "servicedeskproject:"+serviceDeskID, func() (string, error) {
+		var desk struct {
+			ProjectKey string `json:"projectKey"`
+		}
+		if _, err := j.jiraDo(ctx, "GET", "rest/servicedeskapi/servicedesk/"+url.PathEscape(serviceDeskID), nil, &desk); err != nil {
+			return "", fmt.Errorf("failed to look up service desk %s: %w", serviceDeskID, err)
+		}
+		return desk.ProjectKey, nil
+	})
+}
+
+// resourceIssueKey returns the issue key of a jira://issue/{key}/... URI, or
+// "" for other URIs.
+func resourceIssueKey(uri string) string {
+	u, err := url.Parse(uri)
+	if err != nil || u.Scheme != "jira" || u.Host != "issue" {
+		return ""
+	}
+	key, _, _ := strings.Cut(strings.Trim(u.Path, "/"), "/")
+	return key
+}
+
+// projectScope returns the projects a request may address, upper case, and
+// whether it is restricted to them at all: the allowed projects of the
+// runtime settings, narrowed to the projects of the caller's role grant.
+func (j *JiraMCPServer) projectScope(ctx context.Context) ([]string, bool) {
+	var projects []string
+	restricted := false
+	if allowed := j.currentSettings().AllowedProjects; len(allowed) > 0 {
+		restricted = true
+		for _, p := range allowed {
+			projects = append(projects, strings.ToUpper(p))
+		}
+	}
+	if granted, ok := ctx.Value(grantProjectsKey{}).([]string); ok {
+		if restricted {
+			projects = slices.DeleteFunc(projects, func(p string) bool { return !slices.Contains(granted, p) })
+		} else {
+			projects = slices.Clone(granted)
+		}
+		restricted = true
+	}
+	return projects, restricted
+}
+
+// projectInScope reports whether the request may address project.
+func (j *JiraMCPServer) projectInScope(ctx context.Context, project string) bool {
+	projects, restricted := j.projectScope(ctx)
+	return !restricted || slices.Contains(projects, strings.ToUpper(project))
+}
+
+// scopeJQL limits a JQL query to the projects the request may address, so
+// that searches, filters, and bulk changes stay inside them whatever the
+// query says.
+func (j *JiraMCPServer) scopeJQL(ctx context.Context, jql string) (string, error) {
+	projects, restricted := j.projectScope(ctx)
+	if !restricted {
+		return jql, nil
+	}
+	if len(projects) == 0 {
+		return "", fmt.Errorf("you may not access any project")
+	}
+	quoted := make([]string, len(projects))
+	for i, p := range projects {
+		quoted[i] = strconv.Quote(p)
+	}
+	scope := fmt.Sprintf("project in (%s)", strings.Join(quoted, ", "))
+	where, orderBy, err := splitOrderBy(jql)
+	if err != nil {
+		return "", err
+	}
+	if where = strings.TrimSpace(where); where == "" {
+		return scope + orderBy, nil
+	}
+	return fmt.Sprintf("%s AND (%s)%s", scope, where, orderBy), nil
+}
+
+// splitOrderBy splits a JQL query into its condition and its ORDER BY
+// clause, including the leading space. An "order by" inside a quoted string
+// or parentheses is not a clause. It fails when the parentheses outside
+// quoted strings do not balance, as the condition could then close the
+// parentheses scopeJQL puts around it and escape the project scope.
+func splitOrderBy(jql string) (string, string, error) {
+	var quote rune
+	escaped := false
+	depth := 0
+	at := -1
+	for i, r := range jql {
+		switch {
+		case escaped:
+			escaped = false
+		case quote != 0 && r == '\\':
+			escaped = true
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			}
+		case r == '"' || r == '\'':
+			quote = r
+		case r == '(':
+			depth++
+		case r == ')':
+			if depth--; depth < 0 {
+				return "", "", fmt.Errorf("the JQL query closes a parenthesis it did not open")
+			}
+		case depth == 0 && hasWordFold(jql, i, "order") && (i == 0 || unicode.IsSpace(rune(jql[i-1])) || jql[i-1] == ')'):
+			rest := strings.TrimLeftFunc(jql[i+len("order"):], unicode.IsSpace)
+			if len(rest) < len(jql)-i-len("order") && hasWordFold(rest, 0, "by") {
+				at = i
+			}
+		}
+	}
+	if depth != 0 {
+		return "", "", fmt.Errorf("the JQL query leaves a parenthesis open")
+	}
+	if at < 0 {
+		return jql, "", nil
+	}
+	return jql[:at], " " + jql[at:], nil
+}
+
+// hasWordFold reports whether s has the ASCII word at byte offset i, ignoring
+// case. It compares bytes of s itself, as lower-casing can change the length
+// of other text and with it the offsets.
+func hasWordFold(s string, i int, word string) bool {
+	return len(s)-i >= len(word) && strings.EqualFold(s[i:i+len(word)], word)
+}
@@ -0,0 +1,51 @@
+package jiramcp
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestScopeJQL(t *testing.T) {
+	j := newTestServer(t, &MockJiraService{DoFunc: jiraRoutes{}.do}, func(c *JiraConfig) {
+		c.AllowedProjects = []string{"A"}
+	})
+	ctx := context.Background()
+
+	tests := []struct {
+		query, want string
+	}{
+		// Lower-casing the Kelvin sign (U+212A) shortens it, which once
+		// shifted the offsets of the ORDER BY clause.
+		{"summary ~ \"\u212a\" ORDER BY key", "project in (\"A\") AND (summary ~ \"\u212a\") ORDER BY key"},
+		{
+			"text ~ \"" + strings.Repeat("\u212a", 10) + "\" OR project = SECRET order by \"Story point estimate\"",
+			"project in (\"A\") AND (text ~ \"" + strings.Repeat("\u212a", 10) + "\" OR project = SECRET) order by \"Story point estimate\"",
+		},
+		{`summary ~ 'it\'s ORDER BY' OR key = A-1`, `project in ("A") AND (summary ~ 'it\'s ORDER BY' OR key = A-1)`},
+		{`(status = Open) ORDER BY rank`, `project in ("A") AND ((status = Open)) ORDER BY rank`},
+		{`key in (A-1, A-2) ORDERS = 1`, `project in ("A") AND (key in (A-1, A-2) ORDERS = 1)`},
+		{`  `, `project in ("A")`},
+	}
+	for _, tt := range tests {
+		got, err := j.scopeJQL(ctx, tt.query)
+		if err != nil || got != tt.want {
+			t.Errorf("scopeJQL(%q) = %q, %v; want %q", tt.query, got, err, tt.want)
+		}
+	}
+
+	// A condition that closes the parentheses around it would escape them.
+	for _, query := range []string{`status = Open) OR (project = SECRET`, `(status = Open`, `summary ~ ")" OR project = B)`} {
+		if got, err := j.scopeJQL(ctx, query); err == nil {
+			t.Errorf("scopeJQL(%q) = %q, want an error", query, got)
+		}
+	}
+}
+
+func TestScopeJQLUnrestricted(t *testing.T) {
+	j := newTestServer(t, &MockJiraService{DoFunc: jiraRoutes{}.do}, nil)
+	query := "status = Open) OR (project = SECRET"
+	if got, err := j.scopeJQL(context.Background(), query); err != nil || got != query {
+		t.Errorf("scopeJQL(%q) = %q, %v; want it unchanged", query, got, err)
+	}
+}
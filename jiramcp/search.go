@@ -0,0 +1,407 @@
+package jiramcp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/andygrunwald/go-jira"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// searchPageSize is the number of issues requested per search page.
+const searchPageSize = 100
+
+// Page tokens returned to clients carry a prefix recording which search API
+// produced them, so a token stays valid if the server falls back between APIs.
+const (
+	jqlTokenPrefix    = "jql:"
+	offsetTokenPrefix = "offset:"
+)
+
+// defaultSearchFields is requested when a caller does not name fields; the
+// enhanced search API otherwise returns only issue IDs.
+var defaultSearchFields = []string{"summary", "status", "issuetype", "priority", "assignee", "updated"}
+
+type SearchIssuesParams struct {
+	JQL string `json:"jql,omitempty"`
+	// Query names a saved JQL query from the server's runtime settings and is
+	// used instead of jql.
+	Query      string `json:"query,omitempty"`
+	MaxResults int    `json:"maxResults,omitempty"`
+	// PageToken continues a previous search; pass the nextPageToken it returned.
+	PageToken string `json:"pageToken,omitempty"`
+	// Verbosity is "minimal", "standard", or "full"; defaults to the server setting.
+	Verbosity string `json:"verbosity,omitempty"`
+}
+
+// issueSearchPage is one page of search results. NextPageToken is empty on the
+// last page; Total is -1 when the API does not report it.
+type issueSearchPage struct {
+	Issues        []jira.Issue
+	NextPageToken string
+	Total         int
+}
+
+type enhancedSearchRequest struct {
+	JQL           string   `json:"jql"`
+	NextPageToken string   `json:"nextPageToken,omitempty"`
+	MaxResults    int      `json:"maxResults"`
+	Fields        []string `json:"fields"`
+	Expand        string   `json:"expand,omitempty"`
+}
+
+type enhancedSearchResponse struct {
+	Issues        []jira.Issue `json:"issues"`
+	NextPageToken string       `json:"nextPageToken"`
+	IsLast        bool         `json:"isLast"`
+}
+
+type legacySearchResponse struct {
+	StartAt    int          `json:"startAt"`
+	MaxResults int          `json:"maxResults"`
+	Total      int          `json:"total"`
+	Issues     []jira.Issue `json:"issues"`
+}
+
+// searchPage fetches a single page of JQL results. It uses Jira Cloud's
+// enhanced search endpoint (token-based pagination) and falls back to the
+// legacy offset-based /search endpoint on instances that do not provide it,
+// such as Server and Data Center. The v2 flavour of the enhanced endpoint is
+// used so descriptions keep their wiki markup instead of ADF.
+func (j *JiraMCPServer) searchPage(ctx context.Context, jql string, fields []string, expand string, pageSize int, pageToken string) (*issueSearchPage, error) {
+	if len(fields) == 0 {
+		fields = defaultSearchFields
+	}
+	jql, err := j.scopeJQL(ctx, jql)
+	if err != nil {
+		return nil, err
+	}
+
+	if !j.legacySearch.Load() && !strings.HasPrefix(pageToken, offsetTokenPrefix) {
+		var result enhancedSearchResponse
+		resp, err := j.jiraDo(ctx, "POST", "rest/api/2/search/jql", enhancedSearchRequest{
+			JQL:           jql,
+			NextPageToken: strings.TrimPrefix(pageToken, jqlTokenPrefix),
+			MaxResults:    pageSize,
+			Fields:        fields,
+			Expand:        expand,
+		}, &result)
+		if err == nil {
+			j.recentIssues.add(result.Issues...)
+			page := &issueSearchPage{Issues: result.Issues, Total: -1}
+			if !result.IsLast && result.NextPageToken != "" {
+				page.NextPageToken = jqlTokenPrefix + result.NextPageToken
+			}
+			return page, nil
+		}
+		if resp == nil || (resp.StatusCode != http.StatusNotFound && resp.StatusCode != http.StatusGone) {
+			return nil, fmt.Errorf("search failed: %w", err)
+		}
+		if pageToken != "" {
+			return nil, fmt.Errorf("search failed: page token %q is not supported by this Jira instance", pageToken)
+		}
+		slog.Warn("Enhanced JQL search is unavailable, falling back to /rest/api/2/search", "status", resp.StatusCode)
+		j.legacySearch.Store(true)
+	}
+
+	startAt := 0
+	if pageToken != "" {
+		n, err := strconv.Atoi(strings.TrimPrefix(pageToken, offsetTokenPrefix))
+		if err != nil || n < 0 || !strings.HasPrefix(pageToken, offsetTokenPrefix) {
+			return nil, fmt.Errorf("invalid page token %q", pageToken)
+		}
+		startAt = n
+	}
+	query := url.Values{}
+	query.Set("jql", jql)
+	query.Set("startAt", strconv.Itoa(startAt))
+	query.Set("maxResults", strconv.Itoa(pageSize))
+	query.Set("fields", strings.Join(fields, ","))
+	if expand != "" {
+		query.Set("expand", expand)
+	}
+	var result legacySearchResponse
+	if _, err := j.jiraDo(ctx, "GET", "rest/api/2/search?"+query.Encode(), nil, &result); err != nil {
+		return nil, fmt.Errorf("search failed: %w", err)
+	}
+	j.recentIssues.add(result.Issues...)
+	page := &issueSearchPage{Issues: result.Issues, Total: result.Total}
+	if next := result.StartAt + len(result.Issues); len(result.Issues) > 0 && next < result.Total {
+		page.NextPageToken = offsetTokenPrefix + strconv.Itoa(next)
+	}
+	return page, nil
+}
+
+// searchIssues runs a JQL query and pages through the results until maxResults
+// issues have been collected or the result set is exhausted.
+func (j *JiraMCPServer) searchIssues(ctx context.Context, jql string, fields []string, expand string, maxResults int) ([]jira.Issue, error) {
+	var issues []jira.Issue
+	err := j.searchAll(ctx, jql, fields, expand, maxResults, func(page []jira.Issue) error {
+		issues = append(issues, page...)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return issues, nil
+}
+
+// searchAll runs a JQL query and passes up to maxResults issues to emit, a
+// page at a time and in result order. Pages after the first are fetched
+// JIRA_MCP_FETCH_CONCURRENCY at a time: by offset with the legacy search,
+// and with the enhanced search by listing the remaining keys, which Jira
+// returns in large pages, and fetching the issues in bulk.
+func (j *JiraMCPServer) searchAll(ctx context.Context, jql string, fields []string, expand string, maxResults int, emit func([]jira.Issue) error) error {
+	first, err := j.searchPage(ctx, jql, fields, expand, min(searchPageSize, maxResults), "")
+	if err != nil {
+		return err
+	}
+	if err := emit(first.Issues); err != nil {
+		return err
+	}
+	count := len(first.Issues)
+	if first.NextPageToken == "" || count == 0 || count >= maxResults {
+		return nil
+	}
+	if strings.HasPrefix(first.NextPageToken, offsetTokenPrefix) {
+		return j.searchOffsets(ctx, jql, fields, expand, count, min(first.Total, maxResults), emit)
+	}
+
+	keys, err := j.searchKeys(ctx, jql, count, maxResults)
+	if err != nil {
+		return err
+	}
+	err = j.bulkFetchIssues(ctx, keys, fields, expand, emit)
+	if !errors.Is(err, errNoBulkFetch) {
+		return err
+	}
+	// Without the bulk fetch API the pages can only be read one by one.
+	for token := first.NextPageToken; token != "" && count < maxResults; {
+		page, err := j.searchPage(ctx, jql, fields, expand, min(searchPageSize, maxResults-count), token)
+		if err != nil {
+			return err
+		}
+		if err := emit(page.Issues); err != nil {
+			return err
+		}
+		count += len(page.Issues)
+		if len(page.Issues) == 0 {
+			break
+		}
+		token = page.NextPageToken
+	}
+	return nil
+}
+
+// searchOffsets fetches the legacy search pages from offset start up to end
+// concurrently, a batch of pages at a time so that large exports are not
+// held in memory, and emits them in order.
+func (j *JiraMCPServer) searchOffsets(ctx context.Context, jql string, fields []string, expand string, start, end int, emit func([]jira.Issue) error) error {
+	// Jira may return fewer issues per page than requested, so pages step by
+	// what the first page held.
+	step := start
+	workers := j.fetchConcurrency()
+	for start < end {
+		var offsets []int
+		for o := start; o < end && len(offsets) < 2*workers; o += step {
+			offsets = append(offsets, o)
+		}
+		pages := make([][]jira.Issue, len(offsets))
+		err := forEachParallel(ctx, workers, len(offsets), func(ctx context.Context, i int) error {
+			page, err := j.searchPage(ctx, jql, fields, expand, min(step, end-offsets[i]), offsetTokenPrefix+strconv.Itoa(offsets[i]))
+			if err != nil {
+				return err
+			}
+			pages[i] = page.Issues
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		for _, page := range pages {
+			if err := emit(page); err != nil {
+				return err
+			}
+		}
+		start = offsets[len(offsets)-1] + step
+		if len(pages[len(pages)-1]) == 0 {
+			// Issues left the results since the first page.
+			break
+		}
+	}
+	return nil
+}
+
+// maxKeyPageSize is the page size of the enhanced search when only keys are
+// requested.
+const maxKeyPageSize = 5000
+
+// searchKeys lists the keys of the results of a JQL query from offset skip
+// up to maxResults.
+func (j *JiraMCPServer) searchKeys(ctx context.Context, jql string, skip, maxResults int) ([]string, error) {
+	var keys []string
+	seen := 0
+	for token := ""; seen < maxResults; {
+		page, err := j.searchPage(ctx, jql, []string{"key"}, "", min(maxKeyPageSize, maxResults-seen), token)
+		if err != nil {
+			return nil, err
+		}
+		for _, issue := range page.Issues {
+			if seen >= skip && seen < maxResults {
+				keys = append(keys, issue.Key)
+			}
+			seen++
+		}
+		if page.NextPageToken == "" || len(page.Issues) == 0 {
+			break
+		}
+		token = page.NextPageToken
+	}
+	return keys, nil
+}
+
+// bulkFetchSize is how many issues one bulk fetch request returns.
+const bulkFetchSize = 100
+
+// errNoBulkFetch reports that the instance has no bulk fetch API.
+var errNoBulkFetch = errors.New("bulk fetch is unavailable")
+
+type bulkFetchRequest struct {
+	IssueIDsOrKeys []string `json:"issueIdsOrKeys"`
+	Fields         []string `json:"fields"`
+	Expand         []string `json:"expand,omitempty"`
+}
+
+type bulkFetchResponse struct {
+	Issues []jira.Issue `json:"issues"`
+}
+
+// bulkFetchIssues fetches issues by key in batches of bulkFetchSize,
+// JIRA_MCP_FETCH_CONCURRENCY batches at a time, and emits them in the order
+// of keys. Issues that no longer exist or cannot be seen are left out.
+func (j *JiraMCPServer) bulkFetchIssues(ctx context.Context, keys []string, fields []string, expand string, emit func([]jira.Issue) error) error {
+	if len(fields) == 0 {
+		fields = defaultSearchFields
+	}
+	var expands []string
+	if expand != "" {
+		expands = strings.Split(expand, ",")
+	}
+	workers := j.fetchConcurrency()
+	batches := (len(keys) + bulkFetchSize - 1) / bulkFetchSize
+	for first := 0; first < batches; first += 2 * workers {
+		group := make([][]jira.Issue, min(2*workers, batches-first))
+		err := forEachParallel(ctx, workers, len(group), func(ctx context.Context, i int) error {
+			batch := keys[(first+i)*bulkFetchSize : min((first+i+1)*bulkFetchSize, len(keys))]
+			var result bulkFetchResponse
+			resp, err := j.jiraDo(ctx, "POST", "rest/api/2/issue/bulkfetch", bulkFetchRequest{
+				IssueIDsOrKeys: batch,
+				Fields:         fields,
+				Expand:         expands,
+			}, &result)
+			if err != nil {
+				if first == 0 && resp != nil && (resp.StatusCode == http.StatusNotFound || resp.StatusCode == http.StatusMethodNotAllowed) {
+					return errNoBulkFetch
+				}
+				return fmt.Errorf("bulk fetch failed: %w", err)
+			}
+			// The response is not in request order.
+			byKey := make(map[string]jira.Issue, len(result.Issues))
+			for _, issue := range result.Issues {
+				byKey[issue.Key] = issue
+			}
+			for _, key := range batch {
+				if issue, ok := byKey[key]; ok {
+					group[i] = append(group[i], issue)
+				}
+			}
+			j.recentIssues.add(group[i]...)
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		for _, issues := range group {
+			if err := emit(issues); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// SearchJiraIssues runs a JQL query and returns one page of results along with
+// the token for the next page.
+func (j *JiraMCPServer) SearchJiraIssues(ctx context.Context, req *mcp.CallToolRequest, params *SearchIssuesParams) (*mcp.CallToolResult, any, error) {
+	if params.Query != "" {
+		jql, ok := j.currentSettings().NamedQueries[params.Query]
+		if !ok {
+			return textResult("Unknown named query %q; get-server-config lists the configured queries", params.Query), nil, nil
+		}
+		params.JQL = jql
+	}
+	if strings.TrimSpace(params.JQL) == "" {
+		return textResult("jql or query is required"), nil, nil
+	}
+	pageSize := params.MaxResults
+	if pageSize <= 0 || pageSize > searchPageSize {
+		pageSize = 50
+	}
+
+	verbosity := j.verbosity(params.Verbosity)
+	page, err := j.searchPage(ctx, params.JQL, issueFieldsFor(verbosity), "", pageSize, params.PageToken)
+	if err != nil {
+		return textResult("Failed to search issues: %v", err), nil, nil
+	}
+	if len(page.Issues) == 0 {
+		return textResult("No issues match: %s", params.JQL), nil, nil
+	}
+
+	var sb strings.Builder
+	if page.Total >= 0 {
+		fmt.Fprintf(&sb, "%d issue(s) on this page, %d total:\n", len(page.Issues), page.Total)
+	} else {
+		fmt.Fprintf(&sb, "%d issue(s) on this page:\n", len(page.Issues))
+	}
+	for _, issue := range page.Issues {
+		sb.WriteString(formatIssue(&issue, verbosity))
+		sb.WriteString("\n")
+	}
+	if page.NextPageToken != "" {
+		fmt.Fprintf(&sb, "\nMore results available. nextPageToken: %s\n", page.NextPageToken)
+	}
+	return textResult("%s", sb.String()), nil, nil
+}
+
+// formatIssueLine renders a one-line summary of an issue from the fields
+// requested by defaultSearchFields.
+func formatIssueLine(issue *jira.Issue) string {
+	f := issue.Fields
+	if f == nil {
+		return issue.Key
+	}
+	status := ""
+	if f.Status != nil {
+		status = f.Status.Name
+	}
+	line := fmt.Sprintf("%s [%s] %s", issue.Key, status, f.Summary)
+	var details []string
+	if f.Type.Name != "" {
+		details = append(details, f.Type.Name)
+	}
+	if f.Priority != nil && f.Priority.Name != "" {
+		details = append(details, f.Priority.Name)
+	}
+	if f.Assignee != nil {
+		details = append(details, "assignee: "+f.Assignee.DisplayName)
+	} else {
+		details = append(details, "unassigned")
+	}
+	return line + " (" + strings.Join(details, ", ") + ")"
+}
@@ -0,0 +1,137 @@
+package jiramcp
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/andygrunwald/go-jira"
+)
+
+// testIssues returns issues SMS-first to SMS-(first+n-1).
+func testIssues(first, n int) []jira.Issue {
+	issues := make([]jira.Issue, n)
+	for i := range issues {
+		issues[i] = jira.Issue{Key: "SMS-" + strconv.Itoa(first+i), Fields: &jira.IssueFields{Summary: "Issue", Status: &jira.Status{Name: "Open"}}}
+	}
+	return issues
+}
+
+func TestSearchJiraIssuesPagesWithTokens(t *testing.T) {
+	var requests []enhancedSearchRequest
+	routes := jiraRoutes{
+		"POST rest/api/2/search/jql": func(r *http.Request) (int, interface{}) {
+			var req enhancedSearchRequest
+			decodeBody(t, r, &req)
+			requests = append(requests, req)
+			if req.NextPageToken == "" {
+				return http.StatusOK, enhancedSearchResponse{Issues: testIssues(1, 2), NextPageToken: "page2"}
+			}
+			return http.StatusOK, enhancedSearchResponse{Issues: testIssues(3, 1), IsLast: true}
+		},
+	}
+	j := newTestServer(t, &MockJiraService{DoFunc: routes.do}, nil)
+
+	first, _, _ := j.SearchJiraIssues(context.Background(), nil, &SearchIssuesParams{JQL: "project = SMS", MaxResults: 2})
+	text := resultText(first)
+	if !strings.Contains(text, "SMS-1") || !strings.Contains(text, "SMS-2") || !strings.Contains(text, "nextPageToken: jql:page2") {
+		t.Fatalf("first page = %q, want SMS-1, SMS-2, and the next page token", text)
+	}
+	second, _, _ := j.SearchJiraIssues(context.Background(), nil, &SearchIssuesParams{JQL: "project = SMS", MaxResults: 2, PageToken: "jql:page2"})
+	text = resultText(second)
+	if !strings.Contains(text, "SMS-3") || strings.Contains(text, "nextPageToken") {
+		t.Fatalf("last page = %q, want SMS-3 and no next page token", text)
+	}
+	if len(requests) != 2 || requests[0].MaxResults != 2 || requests[1].NextPageToken != "page2" {
+		t.Errorf("requests = %+v, want two pages of 2, the second with token page2", requests)
+	}
+}
+
+func TestSearchJiraIssuesFallsBackToOffsets(t *testing.T) {
+	var startAts []string
+	routes := jiraRoutes{
+		"GET rest/api/2/search": func(r *http.Request) (int, interface{}) {
+			startAt := r.URL.Query().Get("startAt")
+			startAts = append(startAts, startAt)
+			n, _ := strconv.Atoi(startAt)
+			return http.StatusOK, legacySearchResponse{StartAt: n, MaxResults: 2, Total: 3, Issues: testIssues(n+1, min(2, 3-n))}
+		},
+	}
+	j := newTestServer(t, &MockJiraService{DoFunc: routes.do}, nil)
+
+	first, _, _ := j.SearchJiraIssues(context.Background(), nil, &SearchIssuesParams{JQL: "project = SMS", MaxResults: 2})
+	text := resultText(first)
+	if !strings.Contains(text, "2 issue(s) on this page, 3 total") || !strings.Contains(text, "nextPageToken: offset:2") {
+		t.Fatalf("first page = %q, want 2 of 3 issues and an offset token", text)
+	}
+	if !j.legacySearch.Load() {
+		t.Error("the fallback to the legacy search was not remembered")
+	}
+	second, _, _ := j.SearchJiraIssues(context.Background(), nil, &SearchIssuesParams{JQL: "project = SMS", MaxResults: 2, PageToken: "offset:2"})
+	text = resultText(second)
+	if !strings.Contains(text, "SMS-3") || strings.Contains(text, "nextPageToken") {
+		t.Fatalf("last page = %q, want SMS-3 and no next page token", text)
+	}
+	if strings.Join(startAts, ",") != "0,2" {
+		t.Errorf("startAt = %v, want 0 then 2", startAts)
+	}
+
+	bad, _, _ := j.SearchJiraIssues(context.Background(), nil, &SearchIssuesParams{JQL: "project = SMS", PageToken: "offset:x"})
+	if text := resultText(bad); !strings.Contains(text, "invalid page token") {
+		t.Errorf("bad token result = %q, want an invalid page token error", text)
+	}
+}
+
+func TestSearchJiraIssuesStaysInAllowedProjects(t *testing.T) {
+	var jql string
+	routes := jiraRoutes{
+		"POST rest/api/2/search/jql": func(r *http.Request) (int, interface{}) {
+			var req enhancedSearchRequest
+			decodeBody(t, r, &req)
+			jql = req.JQL
+			return http.StatusOK, enhancedSearchResponse{IsLast: true}
+		},
+	}
+	j := newTestServer(t, &MockJiraService{DoFunc: routes.do}, func(c *JiraConfig) {
+		c.AllowedProjects = []string{"SMS", "ops"}
+	})
+
+	const scope = `project in ("SMS", "OPS")`
+	tests := []struct {
+		query, want string
+	}{
+		{
+			`assignee = currentUser() OR summary ~ "order by" ORDER BY updated DESC`,
+			scope + ` AND (assignee = currentUser() OR summary ~ "order by") ORDER BY updated DESC`,
+		},
+		{
+			`status = Open OR project = SECRET`,
+			scope + ` AND (status = Open OR project = SECRET)`,
+		},
+		{
+			`status = Open order   by rank`,
+			scope + ` AND (status = Open) order   by rank`,
+		},
+		{
+			"text ~ \"\u212a\u212a\u212a\u212a\u212a\" OR project = SECRET order by \"Story point estimate\"",
+			scope + " AND (text ~ \"\u212a\u212a\u212a\u212a\u212a\" OR project = SECRET) order by \"Story point estimate\"",
+		},
+		{
+			`summary ~ "Größe ORDER BY Ⱥ" ORDER BY key`,
+			scope + ` AND (summary ~ "Größe ORDER BY Ⱥ") ORDER BY key`,
+		},
+		{
+			`ORDER BY created`,
+			scope + ` ORDER BY created`,
+		},
+	}
+	for _, tt := range tests {
+		jql = ""
+		result, _, _ := j.SearchJiraIssues(context.Background(), nil, &SearchIssuesParams{JQL: tt.query})
+		if jql != tt.want {
+			t.Errorf("query %q: JQL sent = %q, want %q (result %q)", tt.query, jql, tt.want, resultText(result))
+		}
+	}
+}
@@ -0,0 +1,314 @@
+package jiramcp
+
+import (
+	"bufio"
+	"context"
+	"crypto/rand"
+	"encoding/csv"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/andygrunwald/go-jira"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+const (
+	exportURIPrefix = "jira://export/"
+	// defaultExportIssues and maxExportIssues bound the size of an export.
+	defaultExportIssues = 10000
+	maxExportIssues     = 100000
+	// exportRetention is how long exports are kept before they are removed.
+	exportRetention = 24 * time.Hour
+)
+
+// defaultExportFields are exported when the caller does not name fields.
+var defaultExportFields = []string{"summary", "issuetype", "status", "priority", "assignee", "reporter", "created", "updated", "resolution", "labels", "components"}
+
+type ExportIssuesParams struct {
+	JQL string `json:"jql,omitempty"`
+	// Query names a saved JQL query from the server's runtime settings and is
+	// used instead of jql.
+	Query string `json:"query,omitempty"`
+	// Fields are the field IDs to export, after the issue key.
+	Fields []string `json:"fields,omitempty"`
+	// Format is "csv" (default) or "json".
+	Format    string `json:"format,omitempty"`
+	MaxIssues int    `json:"maxIssues,omitempty"`
+}
+
+// exportMIMETypes maps export formats to their MIME types.
+var exportMIMETypes = map[string]string{"csv": "text/csv", "json": "application/json"}
+
+// exportDir is where export-issues writes its files.
+func (j *JiraMCPServer) exportDir() string {
+	return filepath.Join(j.config.StateDir, "exports")
+}
+
+// exportWriter writes issues to an export file in one format.
+type exportWriter interface {
+	write(key string, fields map[string]json.RawMessage) error
+	close() error
+}
+
+type csvExport struct {
+	w      *csv.Writer
+	fields []string
+}
+
+func newCSVExport(w *bufio.Writer, fields []string) (*csvExport, error) {
+	e := &csvExport{w: csv.NewWriter(w), fields: fields}
+	return e, e.w.Write(append([]string{"key"}, fields...))
+}
+
+func (e *csvExport) write(key string, fields map[string]json.RawMessage) error {
+	row := []string{key}
+	for _, f := range e.fields {
+		row = append(row, exportValue(fields[f]))
+	}
+	return e.w.Write(row)
+}
+
+func (e *csvExport) close() error {
+	e.w.Flush()
+	return e.w.Error()
+}
+
+// jsonExport writes a JSON array of {"key": ..., "fields": {...}} objects, one
+// per line, keeping Jira's own field values.
+type jsonExport struct {
+	w      *bufio.Writer
+	fields []string
+	n      int
+}
+
+func (e *jsonExport) write(key string, fields map[string]json.RawMessage) error {
+	selected := make(map[string]json.RawMessage, len(e.fields))
+	for _, f := range e.fields {
+		if v, ok := fields[f]; ok {
+			selected[f] = v
+		}
+	}
+	b, err := json.Marshal(map[string]interface{}{"key": key, "fields": selected})
+	if err != nil {
+		return err
+	}
+	sep := ",\n"
+	if e.n == 0 {
+		sep = "[\n"
+	}
+	e.n++
+	e.w.WriteString(sep)
+	_, err = e.w.Write(b)
+	return err
+}
+
+func (e *jsonExport) close() error {
+	if e.n == 0 {
+		e.w.WriteString("[")
+	}
+	_, err := e.w.WriteString("\n]\n")
+	return err
+}
+
+// exportValue flattens a field value into a spreadsheet cell: names of
+// objects such as users and statuses, and lists joined with "; ".
+func exportValue(raw json.RawMessage) string {
+	var v interface{}
+	if len(raw) == 0 || json.Unmarshal(raw, &v) != nil {
+		return ""
+	}
+	return flattenExportValue(v)
+}
+
+func flattenExportValue(v interface{}) string {
+	switch v := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(v)
+	case []interface{}:
+		parts := make([]string, 0, len(v))
+		for _, item := range v {
+			parts = append(parts, flattenExportValue(item))
+		}
+		return strings.Join(parts, "; ")
+	case map[string]interface{}:
+		for _, k := range []string{"displayName", "name", "value", "key"} {
+			if s, ok := v[k].(string); ok {
+				return s
+			}
+		}
+	}
+	b, _ := json.Marshal(v)
+	return string(b)
+}
+
+// issueFieldValues returns the fields of a search result by ID, custom
+// fields included.
+func issueFieldValues(issue jira.Issue) map[string]json.RawMessage {
+	var fields map[string]json.RawMessage
+	if issue.Fields != nil {
+		if b, err := json.Marshal(issue.Fields); err == nil {
+			json.Unmarshal(b, &fields)
+		}
+	}
+	return fields
+}
+
+// removeOldExports deletes exports older than exportRetention.
+func (j *JiraMCPServer) removeOldExports() {
+	entries, err := os.ReadDir(j.exportDir())
+	if err != nil {
+		return
+	}
+	for _, e := range entries {
+		info, err := e.Info()
+		if err != nil || !strings.HasPrefix(e.Name(), "search-") || time.Since(info.ModTime()) < exportRetention {
+			continue
+		}
+		if err := os.Remove(filepath.Join(j.exportDir(), e.Name())); err != nil {
+			slog.Warn("Failed to remove old export", "file", e.Name(), "error", err)
+		}
+	}
+}
+
+// ExportIssues runs a JQL query and writes every matching issue, page by
+// page, to a CSV or JSON file served as a jira://export/ resource, so result
+// sets too large for a tool result can be handed to other programs.
+func (j *JiraMCPServer) ExportIssues(ctx context.Context, req *mcp.CallToolRequest, params *ExportIssuesParams) (*mcp.CallToolResult, any, error) {
+	if params.Query != "" {
+		jql, ok := j.currentSettings().NamedQueries[params.Query]
+		if !ok {
+			return textResult("Unknown named query %q; get-server-config lists the configured queries", params.Query), nil, nil
+		}
+		params.JQL = jql
+	}
+	if strings.TrimSpace(params.JQL) == "" {
+		return textResult("jql or query is required"), nil, nil
+	}
+	format := strings.ToLower(params.Format)
+	if format == "" {
+		format = "csv"
+	}
+	mimeType, ok := exportMIMETypes[format]
+	if !ok {
+		return textResult("format must be \"csv\" or \"json\", got %q", params.Format), nil, nil
+	}
+	maxIssues := params.MaxIssues
+	if maxIssues <= 0 {
+		maxIssues = defaultExportIssues
+	}
+	maxIssues = min(maxIssues, maxExportIssues)
+	fields := params.Fields
+	if len(fields) == 0 {
+		fields = defaultExportFields
+	}
+
+	j.removeOldExports()
+	if err := os.MkdirAll(j.exportDir(), 0o700); err != nil {
+		return textResult("Failed to create the export directory: %v", err), nil, nil
+	}
+	id := make([]byte, 8)
+	rand.Read(id)
+	name := fmt.Sprintf("search-%s-%s.%s", time.Now().UTC().Format("20060102T150405Z"), hex.EncodeToString(id), format)
+	path := filepath.Join(j.exportDir(), name)
+	// The export is written under a temporary name and renamed when complete,
+	// so a failed export never shows up as a truncated resource.
+	f, err := os.OpenFile(path+".tmp", os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o600)
+	if err != nil {
+		return textResult("Failed to create the export file: %v", err), nil, nil
+	}
+	defer os.Remove(path + ".tmp")
+	defer f.Close()
+
+	buf := bufio.NewWriter(f)
+	var out exportWriter
+	if format == "csv" {
+		if out, err = newCSVExport(buf, fields); err != nil {
+			return textResult("Failed to write the export: %v", err), nil, nil
+		}
+	} else {
+		out = &jsonExport{w: buf, fields: fields}
+	}
+	count := 0
+	var writeErr error
+	err = j.searchAll(ctx, params.JQL, fields, "", maxIssues, func(page []jira.Issue) error {
+		for _, issue := range page {
+			if writeErr = out.write(issue.Key, issueFieldValues(issue)); writeErr != nil {
+				return writeErr
+			}
+			count++
+		}
+		return nil
+	})
+	if writeErr != nil {
+		return textResult("Failed to write the export: %v", writeErr), nil, nil
+	}
+	if err != nil {
+		return textResult("Failed to search issues after exporting %d: %v", count, err), nil, nil
+	}
+	if err := out.close(); err != nil {
+		return textResult("Failed to write the export: %v", err), nil, nil
+	}
+	if err := buf.Flush(); err != nil {
+		return textResult("Failed to write the export: %v", err), nil, nil
+	}
+	if err := f.Close(); err != nil {
+		return textResult("Failed to write the export: %v", err), nil, nil
+	}
+	if err := os.Rename(path+".tmp", path); err != nil {
+		return textResult("Failed to write the export: %v", err), nil, nil
+	}
+	info, _ := os.Stat(path)
+	size := info.Size()
+	logger(ctx).Info("Exported issues", "file", name, "issues", count)
+
+	text := fmt.Sprintf("Exported %d issue(s) as %s (%d bytes) to %s%s; the file is kept for %s.", count, strings.ToUpper(format), size, exportURIPrefix, name, exportRetention)
+	if count == maxIssues {
+		text += fmt.Sprintf(" Only the first %d issues were exported; raise maxIssues (up to %d).", maxIssues, maxExportIssues)
+	}
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			&mcp.TextContent{Text: text},
+			&mcp.ResourceLink{URI: exportURIPrefix + name, Name: name, MIMEType: mimeType, Size: &size},
+		},
+	}, nil, nil
+}
+
+// ExportResource serves jira://export/{name}, a file written by
+// export-issues.
+func (j *JiraMCPServer) ExportResource(ctx context.Context, req *mcp.ReadResourceRequest) (*mcp.ReadResourceResult, error) {
+	u, err := url.Parse(req.Params.URI)
+	if err != nil || u.Scheme != "jira" || u.Host != "export" {
+		return nil, fmt.Errorf("unsupported resource URI %q", req.Params.URI)
+	}
+	name := strings.Trim(u.Path, "/")
+	format := strings.TrimPrefix(filepath.Ext(name), ".")
+	if !strings.HasPrefix(name, "search-") || strings.ContainsAny(name, `/\`) || exportMIMETypes[format] == "" {
+		return nil, mcp.ResourceNotFoundError(req.Params.URI)
+	}
+	b, err := os.ReadFile(filepath.Join(j.exportDir(), name))
+	if os.IsNotExist(err) {
+		return nil, mcp.ResourceNotFoundError(req.Params.URI)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read export %s: %w", name, err)
+	}
+	return &mcp.ReadResourceResult{
+		Contents: []*mcp.ResourceContents{
+			{URI: req.Params.URI, MIMEType: exportMIMETypes[format], Text: string(b)},
+		},
+	}, nil
+}
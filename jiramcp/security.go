@@ -0,0 +1,77 @@
+package jiramcp
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+type ListSecurityLevelsParams struct {
+	ProjectKey string `json:"projectKey,omitempty"`
+}
+
+// securityLevel is a level of an issue security scheme.
+type securityLevel struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+// projectSecurityLevels returns the security levels the current user may set
+// on issues of a project. A project without an issue security scheme has
+// none.
+func (j *JiraMCPServer) projectSecurityLevels(ctx context.Context, projectKey string) ([]securityLevel, error) {
+	return cached(ctx, j.cache, "metadata", "securitylevels:"+strings.ToUpper(projectKey), func() ([]securityLevel, error) {
+		var result struct {
+			Levels []securityLevel `json:"levels"`
+		}
+		_, err := j.jiraDo(ctx, "GET", fmt.Sprintf("rest/api/2/project/%s/securitylevel", projectKey), nil, &result)
+		return result.Levels, err
+	})
+}
+
+// securityLevelValue resolves a security level name or ID against a
+// project's security scheme and returns the field value to write. When the
+// level cannot be used it returns a problem describing why instead.
+func (j *JiraMCPServer) securityLevelValue(ctx context.Context, projectKey, level string) (map[string]string, string) {
+	levels, err := j.projectSecurityLevels(ctx, projectKey)
+	if err != nil {
+		return nil, fmt.Sprintf("could not list the security levels of %s: %v", projectKey, err)
+	}
+	if len(levels) == 0 {
+		return nil, fmt.Sprintf("security level %q cannot be set: %s has no issue security scheme, or you may set none of its levels", level, projectKey)
+	}
+	names := make([]string, 0, len(levels))
+	for _, l := range levels {
+		if strings.EqualFold(l.Name, level) || l.ID == level {
+			return map[string]string{"id": l.ID}, ""
+		}
+		names = append(names, l.Name)
+	}
+	return nil, unknownNameProblem("security level", level, "is not in "+projectKey+"'s security scheme", names, nil)
+}
+
+// ListSecurityLevels lists the issue security levels available in a project.
+func (j *JiraMCPServer) ListSecurityLevels(ctx context.Context, req *mcp.CallToolRequest, params *ListSecurityLevelsParams) (*mcp.CallToolResult, any, error) {
+	projectKey := j.projectKeyOrDefault(params.ProjectKey)
+	levels, err := j.projectSecurityLevels(ctx, projectKey)
+	if err != nil {
+		return textResult("Failed to list the security levels of %s: %v", projectKey, err), nil, nil
+	}
+	if len(levels) == 0 {
+		return textResult("%s has no issue security scheme, or you may set none of its levels; its issues are visible to everyone who can browse the project", projectKey), nil, nil
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "Security levels available in %s:\n", projectKey)
+	for _, l := range levels {
+		fmt.Fprintf(&sb, "- %s (ID: %s)", l.Name, l.ID)
+		if l.Description != "" {
+			fmt.Fprintf(&sb, ": %s", l.Description)
+		}
+		sb.WriteString("\n")
+	}
+	return textResult("%s", sb.String()), nil, nil
+}
@@ -0,0 +1,105 @@
+package jiramcp
+
+import (
+	"context"
+	"log/slog"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/andygrunwald/go-jira"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// Server returns the underlying MCP server, for programs that run it on a
+// transport of their own.
+func (j *JiraMCPServer) Server() *mcp.Server {
+	return j.server
+}
+
+// CurrentUser returns the Jira user of the configured service account, which
+// also verifies that Jira is reachable and accepts the credentials.
+func (j *JiraMCPServer) CurrentUser(ctx context.Context) (*jira.User, error) {
+	user, _, err := j.service.GetSelf(ctx)
+	return user, err
+}
+
+// Close saves pending tool usage and releases the storage backend.
+func (j *JiraMCPServer) Close() error {
+	j.flushUsage()
+	return j.store.Close()
+}
+
+// ServeStdio serves a single client over stdin and stdout until ctx is
+// cancelled or the client disconnects.
+func (j *JiraMCPServer) ServeStdio(ctx context.Context) error {
+	slog.Info("Starting MCP server with STDIO transport")
+	return j.server.Run(ctx, &mcp.StdioTransport{})
+}
+
+// Handler returns the HTTP handler of the HTTP transports: the SSE endpoint at
+// /sse, the streamable HTTP endpoint at /mcp, health probes, the status page,
+// and, when enabled, metrics and the Jira webhook.
+func (j *JiraMCPServer) Handler() http.Handler {
+	config := j.config
+	handler := mcp.NewSSEHandler(func(request *http.Request) *mcp.Server {
+		url := request.URL.Path
+		slog.Debug("Handling SSE request", "path", url)
+		switch url {
+		case "/sse":
+			return j.server
+		default:
+			return nil
+		}
+	})
+	mux := http.NewServeMux()
+	// Probes are unauthenticated so orchestrators can reach them.
+	mux.HandleFunc("/healthz", j.healthzHandler)
+	mux.HandleFunc("/readyz", j.readyzHandler)
+	if config.Metrics {
+		mux.Handle("/metrics", j.requireAuth(metricsHandler()))
+	}
+	mux.Handle("/status", j.requireAuth(http.HandlerFunc(j.statusHandler)))
+	if config.WebhookSecret != "" {
+		// Webhooks are verified with their own secret, since Jira cannot
+		// send an API token.
+		slog.Info("Accepting Jira webhooks", "path", config.WebhookPath)
+		mux.HandleFunc(config.WebhookPath, j.webhookHandler)
+	}
+	// Streamable HTTP sessions live in this process unless they are
+	// resumable, in which case any replica accepts a session ID and restores
+	// the session's state from the store.
+	var streamable http.Handler = mcp.NewStreamableHTTPHandler(func(*http.Request) *mcp.Server {
+		return j.server
+	}, &mcp.StreamableHTTPOptions{Stateless: config.ResumableSessions})
+	var mcpHandler http.Handler = handler
+	if config.SessionCredentials {
+		mcpHandler = withRequestCredentials(mcpHandler)
+		streamable = withRequestCredentials(streamable)
+	}
+	mux.Handle("/mcp", j.requireAuth(streamable))
+	mux.Handle("/", j.requireAuth(mcpHandler))
+	return mux
+}
+
+// ServeSSE serves the SSE transport on port until ctx is cancelled, then
+// drains running tool calls and shuts down.
+func (j *JiraMCPServer) ServeSSE(ctx context.Context, port string) error {
+	config := j.config
+	addr := net.JoinHostPort(config.BindAddress, port)
+	tlsCfg, err := tlsConfig(config)
+	if err != nil {
+		return err
+	}
+	slog.Info("Starting MCP server with SSE transport", "addr", addr, "tls", tlsCfg != nil)
+	if len(config.AuthTokens) == 0 && config.OIDCIssuer == "" && config.TLSClientCAFile == "" {
+		slog.Warn("The SSE endpoint has no authentication; set JIRA_MCP_AUTH_TOKENS, JIRA_MCP_OIDC_ISSUER, or JIRA_MCP_TLS_CLIENT_CA, or bind to localhost with JIRA_MCP_BIND_ADDRESS")
+	}
+	srv := &http.Server{
+		Addr:              addr,
+		Handler:           j.Handler(),
+		TLSConfig:         tlsCfg,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	return j.serveHTTP(ctx, srv, config.TLSCertFile, config.TLSKeyFile)
+}
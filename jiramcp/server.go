@@ -0,0 +1,1294 @@
+package jiramcp
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/andygrunwald/go-jira"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/trivago/tgo/tcontainer"
+)
+
+const (
+	ServerName    = "jira-mcp-server"
+	ServerVersion = "1.0.0"
+)
+
+type JiraMCPServer struct {
+	server *mcp.Server
+	config *JiraConfig
+	// service uses the configured service account. Handlers call
+	// j.client(ctx), which prefers the session's own credentials.
+	service JiraService
+	// sessionClients holds the clients of sessions that supplied their own
+	// Jira credentials, by session ID.
+	sessionClientsMu sync.Mutex
+	sessionClients   map[string]*sessionClient
+	// pseudonyms is non-nil when anonymization mode is enabled.
+	pseudonyms *pseudonymizer
+	// sessionKeys holds the key of each session's state, see
+	// sessionKeyMiddleware, by session ID, for notifications sent outside of
+	// requests.
+	sessionKeysMu sync.Mutex
+	sessionKeys   map[string]string
+	// toolNames records every tool offered to addTool, whether or not the
+	// policy allowed it, so configuration typos can be reported.
+	toolNames map[string]bool
+	// registeredTools names the tools the policy allowed, and readOnlyTools
+	// those of them that never modify anything.
+	registeredTools map[string]bool
+	readOnlyTools   map[string]bool
+	// roles is the role configuration of HTTP clients, or nil, and
+	// sessionGrants the grant of each session's client, by session ID.
+	roles           *roleConfig
+	sessionGrantsMu sync.Mutex
+	sessionGrants   map[string]*roleGrant
+	// oidc verifies OIDC bearer tokens when JIRA_MCP_OIDC_ISSUER is set.
+	oidc *oidcAuth
+	// assets serves templates, canned responses, and the status page from the
+	// override directory or the embedded defaults.
+	assets  fs.FS
+	store   Store
+	started time.Time
+	// cache holds Jira metadata such as projects and users; limiter, when
+	// set, paces requests to Jira. Both are shared through Redis when
+	// JIRA_MCP_REDIS_URL is set.
+	cache   Cache
+	limiter Limiter
+	// transport carries Jira requests: the network, or a cassette recorder
+	// or player; see cassette.go.
+	transport http.RoundTripper
+	// sites holds a client for each site of JIRA_MCP_SITES_FILE.
+	sites map[string]JiraService
+	// legacySearch is set once the enhanced JQL search endpoint turned out to
+	// be unavailable, so later searches go straight to /rest/api/2/search.
+	legacySearch atomic.Bool
+	// settings holds the runtime-tunable settings; see settings.go.
+	settingsMu sync.RWMutex
+	settings   runtimeSettings
+	// rotationMu serializes turns of assignment rotations.
+	rotationMu sync.Mutex
+	// inFlight counts running tool calls; draining is set once shutdown
+	// begins. ready caches the readiness check.
+	inFlight sync.WaitGroup
+	draining atomic.Bool
+	ready    readiness
+	// recentIssues and completions back argument completions.
+	recentIssues recentIssues
+	completions  completionLookups
+	// mutatingTools names the registered tools that modify Jira, which count
+	// towards the per-session mutation limit.
+	mutatingTools map[string]bool
+	mutations     mutationGuard
+	// journal records the changes of each session for undo-last-change.
+	journal operationJournal
+	// activeSessions counts the running calls of each resumable session, by
+	// session key; see sessionStateMiddleware.
+	activeSessionsMu sync.Mutex
+	activeSessions   map[string]int
+	// usage counts tool calls for usage-report.
+	usage usageTracker
+	// unavailableTools maps the tools hidden because their API is out of
+	// reach to the reason; see probe.go.
+	unavailableTools map[string]string
+	// instance is the deployment type and version of Jira, detected at
+	// startup.
+	instance jiraInstance
+	// xray signs in to Xray Cloud for get-test-status.
+	xray xraySession
+}
+
+type JiraConfig struct {
+	BaseURL    string
+	Username   string
+	APIToken   string
+	ProjectKey string
+	// DryRun makes every mutating tool validate and report its payload
+	// instead of writing to Jira.
+	DryRun bool
+	// Anonymize replaces user names and emails in all outputs with
+	// pseudonyms that are consistent within a session.
+	Anonymize bool
+	// AssetsDir optionally overrides the embedded templates, canned
+	// responses, and status page assets file by file.
+	AssetsDir string
+	// ReadOnly registers only tools that never modify Jira.
+	ReadOnly bool
+	// Mode selects a tool persona: "full" (default) or "commenter", which
+	// exposes read tools plus the comment tools only.
+	Mode string
+	// EnabledTools, when non-empty, is the allowlist of tool names to
+	// register. DisabledTools is a denylist applied on top of it.
+	EnabledTools  []string
+	DisabledTools []string
+	// WebhookSecret enables the Jira webhook endpoint in SSE mode and is used
+	// to verify incoming webhook requests.
+	WebhookSecret string
+	WebhookPath   string
+	// WebhookQuerySecret also accepts the secret as a "secret" query
+	// parameter, for Jira Server instances that cannot sign webhooks.
+	WebhookQuerySecret bool
+	// BulkConfirmThreshold is the number of issues a bulk update,
+	// transition, or delete may change without the user confirming it; a
+	// negative value turns confirmation off.
+	BulkConfirmThreshold int
+	// PollInterval, when set, polls Jira for updated issues of PollProjects
+	// (default the allowed projects, or ProjectKey) and notifies clients as
+	// webhooks do, for when Jira cannot reach the server.
+	PollInterval time.Duration
+	PollProjects []string
+	// StateDir holds locally persisted server state such as issue snapshots.
+	StateDir string
+	// Store selects the storage backend: "file" (default), "bolt", or
+	// "postgres", which uses the StoreDSN connection string.
+	Store    string
+	StoreDSN string
+	// TemplatesFile optionally names a JSON file with issue templates that
+	// add to or replace the bundled ones.
+	TemplatesFile string
+	// ConfirmStatuses lists statuses the service account cannot move issues
+	// out of; transitions into them require a confirmation phrase.
+	ConfirmStatuses []string
+	// RotationsFile names a JSON file defining assignment rotations.
+	RotationsFile string
+	// OnCallProvider selects the on-call integration ("opsgenie",
+	// "pagerduty", or "webhook"); empty disables it. OnCallURL overrides the
+	// provider's API base URL and is the endpoint for the webhook provider.
+	OnCallProvider string
+	OnCallURL      string
+	OnCallToken    string
+	OnCallSchedule string
+	// AllowedProjects is the initial allowed project list of the runtime
+	// settings; empty allows every project.
+	AllowedProjects []string
+	// AllowConfigUpdates registers the update-server-config tool.
+	AllowConfigUpdates bool
+	// AllowDelete registers the delete and archive tools.
+	AllowDelete bool
+	// Transport is the MCP transport the server runs on ("stdio" or "sse").
+	// Tools that write local files are only available over stdio.
+	Transport string
+	// SessionCredentials lets HTTP/SSE clients act as their own Jira user by
+	// sending credentials headers; RequireSessionCredentials refuses tool
+	// calls from sessions that did not, instead of using the service account.
+	SessionCredentials        bool
+	RequireSessionCredentials bool
+	// BindAddress is the interface the HTTP listener binds to in SSE mode;
+	// empty listens on all interfaces.
+	BindAddress string
+	// AuthTokens, when non-empty, are the API tokens HTTP clients must
+	// present to use the MCP endpoint and status page.
+	AuthTokens []string
+	// TLSCertFile and TLSKeyFile enable HTTPS in SSE mode; TLSClientCAFile
+	// additionally requires client certificates signed by that CA.
+	TLSCertFile     string
+	TLSKeyFile      string
+	TLSClientCAFile string
+	// DigestTime ("HH:MM" in DigestLocation) schedules the daily digest;
+	// empty disables it. DigestProjects defaults to ProjectKey, and
+	// DigestSLAField names the SLA whose at-risk issues are listed.
+	DigestTime       string
+	DigestLocation   *time.Location
+	DigestProjects   []string
+	DigestSLAField   string
+	DigestWebhookURL string
+	// ClassifierURL, when set, is called with new JSM comments to tag them
+	// with urgency and sentiment; ClassifierToken is sent as a bearer token.
+	ClassifierURL   string
+	ClassifierToken string
+	// TestProvider selects the test management app get-test-status reads
+	// ("xray", "xray-server", or "zephyr"); empty disables it. TestAPIURL
+	// overrides the app's cloud API address. Xray Cloud signs in with
+	// TestClientID and TestAPIToken as the client secret; Zephyr Scale takes
+	// TestAPIToken as its API token. Xray Server uses the Jira credentials.
+	TestProvider string
+	TestAPIURL   string
+	TestClientID string
+	TestAPIToken string
+	// ProbeAPIs checks at startup which optional Jira APIs (Agile, Service
+	// Management, administration) the service account can reach and hides
+	// the tools of the others. It is skipped with per-session credentials,
+	// whose access may differ from the service account's.
+	ProbeAPIs bool
+	// Deployment is DeploymentCloud or DeploymentServer to skip detecting
+	// the deployment type from serverInfo at startup.
+	Deployment string
+	// SkipStartupChecks builds the server without contacting Jira: the
+	// deployment is taken from Deployment or guessed from BaseURL, and
+	// optional APIs are not probed. It is meant for tests and embedders
+	// using NewJiraMCPServerWithService; there is no environment variable.
+	SkipStartupChecks bool
+	// StoryPointsField is the ID or name of the story points field; by
+	// default the field Jira Software creates is used.
+	StoryPointsField string
+	// TShirtSizes maps T-shirt sizes to story points, smallest first.
+	// SizeField and ConfidenceField are the IDs or names of the custom
+	// fields holding an issue's size and estimate confidence, if any.
+	TShirtSizes     []TShirtSize
+	SizeField       string
+	ConfidenceField string
+	// Metrics serves Prometheus metrics at /metrics in SSE mode.
+	Metrics bool
+	// ShutdownTimeout bounds how long shutdown waits for running tool calls.
+	ShutdownTimeout time.Duration
+	// MaxMutationsPerHour caps the Jira-modifying tool calls each client
+	// may make per hour; 0 disables the limit. MutationOverrideToken
+	// enables the override-mutation-limit tool.
+	MaxMutationsPerHour   int
+	MutationOverrideToken string
+	// RolesFile names a JSON file assigning viewer, editor, and admin roles
+	// to HTTP clients by API token or identity claim.
+	RolesFile string
+	// OIDCIssuer enables OIDC bearer tokens on the HTTP transports; tokens
+	// must be issued for OIDCAudience. OIDCJWKSURL overrides the signing
+	// keys found through discovery. OIDCImpersonationFile maps users, by the
+	// OIDCUserClaim claim, to Jira credentials they act with.
+	OIDCIssuer            string
+	OIDCAudience          string
+	OIDCJWKSURL           string
+	OIDCUserClaim         string
+	OIDCImpersonationFile string
+	// ResumableSessions serves the streamable HTTP endpoint without
+	// process-local sessions and keeps each session's state in the store, so
+	// clients can reconnect to any replica and carry on.
+	ResumableSessions bool
+	// RedisURL, when set, shares the metadata cache and the Jira rate budget
+	// between replicas through Redis. JiraRateLimit caps requests to Jira per
+	// second across all replicas sharing the Redis; 0 disables the cap.
+	RedisURL      string
+	JiraRateLimit int
+	// RecordFile, when set, records every Jira request and response to a
+	// cassette file; ReplayFile serves a recorded cassette back instead of
+	// contacting Jira.
+	RecordFile string
+	ReplayFile string
+	// SitesFile names a JSON file of other Jira sites, by name, that
+	// migrate-issue can copy issues to and from.
+	SitesFile string
+	Sites     map[string]JiraSite
+	// Profile names the site of SitesFile to use as the default site, in
+	// place of JIRA_BASE_URL and its credentials.
+	Profile string
+	// ProjectsFile names a JSON file of the projects issues are created in,
+	// with the issue type, labels, and components each uses by default.
+	ProjectsFile string
+	Projects     map[string]ProjectDefaults
+	// ProxyURL sends Jira requests through a proxy; when empty, HTTPS_PROXY,
+	// HTTP_PROXY, and NO_PROXY apply. CAFile names a PEM bundle of CAs
+	// trusted in addition to the system ones. InsecureSkipVerify disables
+	// TLS certificate verification altogether.
+	ProxyURL           string
+	CAFile             string
+	InsecureSkipVerify bool
+	// ConnectTimeout bounds connecting to Jira, TLS handshake included, and
+	// ResponseTimeout how long Jira may take to start answering; 0 leaves
+	// the latter unbounded.
+	ConnectTimeout  time.Duration
+	ResponseTimeout time.Duration
+	// FetchConcurrency bounds the Jira reads a tool runs at once when it
+	// needs many, such as search pages of large reports and exports.
+	FetchConcurrency int
+	// Verbosity is the default output level of read tools: "minimal",
+	// "standard", or "full". Callers can override it per call.
+	Verbosity string
+}
+
+type CreateJiraIssueParams struct {
+	Summary      string                 `json:"summary"`
+	Description  string                 `json:"description"`
+	IssueType    string                 `json:"issueType"`
+	Priority     string                 `json:"priority"`
+	ProjectKey   string                 `json:"projectKey,omitempty"`
+	Labels       []string               `json:"labels,omitempty"`
+	Components   []string               `json:"components,omitempty"`
+	CustomFields map[string]interface{} `json:"customFields,omitempty"`
+	Assignee     *jira.User             `json:"assignee,omitempty"`
+	// DueDate is YYYY-MM-DD or a relative date such as "in 2 weeks" or
+	// "next friday".
+	DueDate string `json:"dueDate,omitempty"`
+	// SecurityLevel restricts who can see the issue; it is a level name or
+	// ID of the project's issue security scheme.
+	SecurityLevel string `json:"securityLevel,omitempty"`
+	// LiteralMentions keeps @name and @email text as typed instead of
+	// turning it into mentions that notify the users.
+	LiteralMentions bool `json:"literalMentions,omitempty"`
+	// DryRun validates the request and returns the payload without creating the issue.
+	DryRun bool `json:"dryRun,omitempty"`
+}
+
+type UpdateIssueArgs struct {
+	IssueKey    string `json:"issueKey"`
+	Summary     string `json:"summary,omitempty"`
+	Description string `json:"description,omitempty"`
+	// Status moves the issue, after the field edits, through the transition
+	// whose name or target status matches it.
+	Status string `json:"status,omitempty"`
+	// Resolution and TransitionFields are set on the transition's screen, for
+	// example {"fixVersions": [{"name": "1.2"}]}.
+	Resolution       string                 `json:"resolution,omitempty"`
+	TransitionFields map[string]interface{} `json:"transitionFields,omitempty"`
+	// ConfirmationPhrase must repeat the issue key when Status is one the
+	// server is configured to treat as irreversible.
+	ConfirmationPhrase string `json:"confirmationPhrase,omitempty"`
+	Priority           string `json:"priority,omitempty"`
+	IssueType          string `json:"issueType,omitempty"`
+	// Assignee is an email address or name to look up, or "none" to unassign.
+	Assignee string `json:"assignee,omitempty"`
+	// DueDate is YYYY-MM-DD or a relative date such as "in 2 weeks" or
+	// "next friday", or "none" to clear it.
+	DueDate string `json:"dueDate,omitempty"`
+	// SecurityLevel is a level name or ID of the project's issue security
+	// scheme, or "none" to make the issue visible to everyone in the project.
+	SecurityLevel string `json:"securityLevel,omitempty"`
+	// Components and FixVersions replace the issue's values; an empty (but
+	// present) list clears them. The Add and Remove lists change single
+	// values and leave the rest untouched.
+	Components        []string `json:"components,omitempty"`
+	AddComponents     []string `json:"addComponents,omitempty"`
+	RemoveComponents  []string `json:"removeComponents,omitempty"`
+	FixVersions       []string `json:"fixVersions,omitempty"`
+	AddFixVersions    []string `json:"addFixVersions,omitempty"`
+	RemoveFixVersions []string `json:"removeFixVersions,omitempty"`
+	// CustomFields sets fields by name or ID, e.g. {"Story Points": 3}.
+	CustomFields map[string]interface{} `json:"customFields,omitempty"`
+	// AddLabels and RemoveLabels are applied as incremental label operations,
+	// leaving the issue's other labels untouched.
+	AddLabels    []string `json:"addLabels,omitempty"`
+	RemoveLabels []string `json:"removeLabels,omitempty"`
+	// NotifyUsers set to false suppresses Jira's email notifications for the
+	// edit (requires project admin permission).
+	NotifyUsers *bool `json:"notifyUsers,omitempty"`
+	// LiteralMentions keeps @name and @email text as typed instead of
+	// turning it into mentions that notify the users.
+	LiteralMentions bool `json:"literalMentions,omitempty"`
+	// DryRun validates the request and returns the payload without updating the issue.
+	DryRun bool `json:"dryRun,omitempty"`
+}
+
+// textResult builds a tool result holding a single formatted text block.
+func textResult(format string, args ...interface{}) *mcp.CallToolResult {
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			&mcp.TextContent{Text: fmt.Sprintf(format, args...)},
+		},
+	}
+}
+
+// issueEditPath returns the edit endpoint for an issue, adding the notifyUsers
+// query parameter when notifications were explicitly requested or suppressed.
+func issueEditPath(issueKey string, notifyUsers *bool) string {
+	path := fmt.Sprintf("rest/api/2/issue/%s", issueKey)
+	if notifyUsers != nil {
+		path += fmt.Sprintf("?notifyUsers=%t", *notifyUsers)
+	}
+	return path
+}
+
+// componentRefs converts component names into the reference objects Jira
+// expects on create and in update operations. It returns nil for an empty
+// list so the field is omitted from create payloads entirely.
+func componentRefs(names []string) []*jira.Component {
+	if len(names) == 0 {
+		return nil
+	}
+	components := make([]*jira.Component, 0, len(names))
+	for _, name := range names {
+		components = append(components, &jira.Component{Name: name})
+	}
+	return components
+}
+
+func (j *JiraMCPServer) UpdateJiraIssue(ctx context.Context, req *mcp.CallToolRequest, params *UpdateIssueArgs) (*mcp.CallToolResult, any, error) {
+
+	issue, _, err := j.client(ctx).GetIssue(ctx, params.IssueKey, nil)
+	if err != nil {
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{
+				&mcp.TextContent{Text: fmt.Sprintf("Failed to get JIRA issue %s: %v", params.IssueKey, err)},
+			},
+		}, nil, nil
+	}
+
+	updateFields := make(map[string]interface{})
+
+	if params.Summary != "" {
+		updateFields["summary"] = []map[string]interface{}{
+			{"set": params.Summary},
+		}
+
+	}
+	mentioned := &mentions{}
+	if params.Description != "" {
+		var description string
+		description, mentioned = j.resolveMentions(ctx, params.Description, params.LiteralMentions)
+		updateFields["description"] = []map[string]interface{}{
+			{"set": description},
+		}
+
+	}
+	if params.Components != nil || len(params.AddComponents) > 0 || len(params.RemoveComponents) > 0 {
+		updateFields["components"] = nameOperations(params.Components, params.AddComponents, params.RemoveComponents)
+	}
+	if params.FixVersions != nil || len(params.AddFixVersions) > 0 || len(params.RemoveFixVersions) > 0 {
+		updateFields["fixVersions"] = nameOperations(params.FixVersions, params.AddFixVersions, params.RemoveFixVersions)
+	}
+
+	// Problems with the values below are caught before anything is written.
+	var problems []string
+	if params.Priority != "" {
+		updateFields["priority"] = setOperation(map[string]string{"name": params.Priority})
+		if problem, err := j.priorityProblem(ctx, issue.Fields.Project.Key, params.Priority); err == nil && problem != "" {
+			problems = append(problems, problem)
+		}
+	}
+	if params.IssueType != "" {
+		updateFields["issuetype"] = setOperation(map[string]string{"name": params.IssueType})
+		if project, err := j.getProject(ctx, issue.Fields.Project.Key); err == nil {
+			if problem := issueTypeProblem(project, params.IssueType); problem != "" {
+				problems = append(problems, problem)
+			}
+		}
+	}
+	if params.Assignee != "" {
+		if value, err := j.assigneeValue(ctx, params.Assignee); err != nil {
+			problems = append(problems, fmt.Sprintf("assignee: %v", err))
+		} else {
+			updateFields["assignee"] = setOperation(value)
+		}
+	}
+	if params.DueDate != "" {
+		if value, err := dueDateValue(params.DueDate); err != nil {
+			problems = append(problems, err.Error())
+		} else {
+			updateFields["duedate"] = setOperation(value)
+		}
+	}
+	if params.SecurityLevel != "" {
+		if strings.EqualFold(params.SecurityLevel, "none") {
+			updateFields["security"] = setOperation(nil)
+		} else if value, problem := j.securityLevelValue(ctx, issue.Fields.Project.Key, params.SecurityLevel); problem != "" {
+			problems = append(problems, problem)
+		} else {
+			updateFields["security"] = setOperation(value)
+		}
+	}
+	if len(params.CustomFields) > 0 {
+		ops, fieldProblems, err := j.customFieldOperations(ctx, issue.Key, params.CustomFields)
+		if err != nil {
+			return textResult("Failed to get the editable fields of %s: %v", issue.Key, err), nil, nil
+		}
+		for id, op := range ops {
+			updateFields[id] = op
+		}
+		problems = append(problems, fieldProblems...)
+	}
+	if len(params.AddLabels) > 0 || len(params.RemoveLabels) > 0 {
+		var labelOps []map[string]interface{}
+		for _, label := range params.AddLabels {
+			labelOps = append(labelOps, map[string]interface{}{"add": label})
+		}
+		for _, label := range params.RemoveLabels {
+			labelOps = append(labelOps, map[string]interface{}{"remove": label})
+		}
+		updateFields["labels"] = labelOps
+	}
+	// A status change is a workflow transition, performed with its resolution
+	// and screen fields after the field edits.
+	var transition *jira.Transition
+	var transitionBody map[string]interface{}
+	if params.Status != "" && !strings.EqualFold(params.Status, statusName(issue)) {
+		var available []string
+		transition, available, err = j.findTransition(ctx, issue.Key, params.Status)
+		if err != nil {
+			return textResult("Failed to get transitions of %s: %v", issue.Key, err), nil, nil
+		}
+		if transition == nil {
+			return textResult("%s has no transition to %q; nothing was changed. Available: %s", issue.Key, params.Status, strings.Join(available, ", ")), nil, nil
+		}
+		transitionBody = transitionRequest(transition, params.Resolution, params.TransitionFields)
+	} else if params.Resolution != "" || len(params.TransitionFields) > 0 {
+		return textResult("resolution and transitionFields are only applied with a status change; nothing was changed"), nil, nil
+	}
+
+	confirmation := ""
+	if transition != nil && j.requiresConfirmation(transition.To.Name) {
+		confirmation = confirmationProblem("Moving "+issue.Key+" to "+transition.To.Name, issue.Key, params.ConfirmationPhrase)
+	}
+
+	update := map[string]interface{}{
+		"update": updateFields,
+	}
+	if j.dryRun(params.DryRun) {
+		if components := append(append([]string(nil), params.Components...), params.AddComponents...); len(components) > 0 {
+			project, err := j.getProject(ctx, issue.Fields.Project.Key)
+			if err != nil {
+				problems = append(problems, fmt.Sprintf("could not load project %s to verify components: %v", issue.Fields.Project.Key, err))
+			} else {
+				problems = append(problems, componentProblems(project, componentRefs(components))...)
+			}
+		}
+		if confirmation != "" {
+			problems = append(problems, confirmation)
+		}
+		switch {
+		case transition != nil && len(updateFields) > 0:
+			result := dryRunResult("PUT", issueEditPath(issue.Key, params.NotifyUsers), update, problems)
+			result.Content = append(result.Content, dryRunResult("POST", transitionPath(issue.Key), transitionBody, nil).Content...)
+			return result, nil, nil
+		case transition != nil:
+			return dryRunResult("POST", transitionPath(issue.Key), transitionBody, problems), nil, nil
+		case len(updateFields) > 0 || len(problems) > 0:
+			return dryRunResult("PUT", issueEditPath(issue.Key, params.NotifyUsers), update, problems), nil, nil
+		}
+		return textResult("Dry run: no field changes were requested for %s", issue.Key), nil, nil
+	}
+	if confirmation != "" {
+		return textResult("%s", confirmation), nil, nil
+	}
+	if len(problems) > 0 {
+		return textResult("Failed to update JIRA issue %s; nothing was changed: %s", issue.Key, strings.Join(problems, "; ")), nil, nil
+	}
+
+	issueUrl := fmt.Sprintf("%s/browse/%s", j.config.BaseURL, issue.Key)
+	var done []string
+	if len(updateFields) > 0 {
+		before := j.journalBefore(ctx, issue.Key, updateFields)
+		_, err = j.jiraDo(ctx, "PUT", issueEditPath(issue.Key, params.NotifyUsers), update, nil)
+		if err != nil {
+			return &mcp.CallToolResult{
+				Content: []mcp.Content{
+					&mcp.TextContent{Text: fmt.Sprintf("Failed to update JIRA issue %s: %v", params.IssueKey, err)},
+				},
+			}, nil, nil
+		}
+		note := ""
+		if transition != nil {
+			note = "the status change is not undone"
+		}
+		j.journalUpdate(ctx, req, "update-jira-issue", issue.Key, before, note)
+		done = append(done, "updated fields")
+		if due, ok := updateFields["duedate"].([]map[string]interface{}); ok {
+			if value, _ := due[0]["set"].(string); value != "" {
+				done = append(done, "due "+value)
+			}
+		}
+	}
+	if transition != nil {
+		if _, err := j.jiraDo(ctx, "POST", transitionPath(issue.Key), transitionBody, nil); err != nil {
+			if len(done) > 0 {
+				return textResult("Updated the fields of %s, but failed to move it to %s via %q: %v", issueUrl, transition.To.Name, transition.Name, err), nil, nil
+			}
+			return textResult("Failed to move %s to %s via %q: %v", issue.Key, transition.To.Name, transition.Name, err), nil, nil
+		}
+		logger(ctx).Info("Transitioned issue", "status", transition.To.Name)
+		done = append(done, fmt.Sprintf("moved to %s", transition.To.Name))
+	}
+	if len(done) == 0 {
+		return textResult("No changes were requested for %s", issueUrl), nil, nil
+	}
+
+	logger(ctx).Info("Updated issue", "url", issueUrl)
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			&mcp.TextContent{Text: fmt.Sprintf("Updated JIRA issue: %s (%s)%s", issueUrl, strings.Join(done, ", "), mentioned.note())},
+		},
+	}, nil, nil
+}
+func (j *JiraMCPServer) assignIssueToUser(ctx context.Context, req *mcp.CallToolRequest, params *UpdateIssueArgs) {
+
+}
+
+// CreateJiraIssue creates a new Jira issue using the provided parameters.
+// Without a project key it uses the configured JIRA_PROJECT_KEY, and it returns the created issue key.
+//
+// Parameters:
+//   - ctx: context for request cancellation and deadlines
+//   - req: MCP CallToolRequest containing tool invocation details
+//   - params: CreateJiraIssueParams with issue fields and metadata
+//
+// Returns:
+//   - *mcp.CallToolResult: result containing the created issue key or error message
+//   - any: additional data (always nil)
+//   - error: always nil (errors are returned in the result content)
+func (j *JiraMCPServer) CreateJiraIssue(ctx context.Context, req *mcp.CallToolRequest, params *CreateJiraIssueParams) (*mcp.CallToolResult, any, error) {
+	projectKey := j.projectKeyOrDefault(params.ProjectKey)
+	// Templates name their own project, which the middleware does not see.
+	if !j.projectInScope(ctx, projectKey) {
+		return textResult("Project %s is outside the projects you may access", projectKey), nil, nil
+	}
+	defaults := j.projectDefaults(projectKey)
+	issueType := params.IssueType
+	if issueType == "" {
+		issueType = defaults.IssueType
+	}
+	labels := params.Labels
+	if len(labels) == 0 {
+		labels = defaults.Labels
+	}
+	components := params.Components
+	if len(components) == 0 {
+		components = defaults.Components
+	}
+	securityLevel := params.SecurityLevel
+	if securityLevel == "" {
+		securityLevel = defaults.SecurityLevel
+	}
+
+	var assignee *jira.User
+	// Look for "assign to: <user>" in the description to assign the issue.
+	if strings.Contains(strings.ToLower(params.Description), "assign to:") {
+		parts := strings.SplitN(params.Description, "assign to:", 2)
+		if len(parts) > 1 {
+			assigneeQuery := strings.TrimSpace(strings.Split(parts[1], "\n")[0])
+			logger(ctx).Debug("Looking up assignee", "query", assigneeQuery)
+			foundUser, err := j.findJiraUser(ctx, assigneeQuery)
+			if err != nil {
+				logger(ctx).Warn("Could not find assignee", "error", err)
+				// Optionally, you could return an error message to the user here.
+			} else if foundUser != nil {
+				logger(ctx).Debug("Found assignee", "assignee", foundUser.DisplayName)
+				assignee = j.assignableUser(foundUser)
+			} else {
+				logger(ctx).Warn("Assignee not found", "query", assigneeQuery)
+			}
+		}
+	} else if a := j.assignableUser(params.Assignee); a != nil {
+		logger(ctx).Debug("Assigning user from assignee parameter", "user", j.userID(a))
+		assignee = a
+	} else {
+		// Default to assigning the issue to the current user if no assignee is specified.
+		currentUser, _, err := j.client(ctx).GetSelf(ctx)
+		if err != nil {
+			logger(ctx).Warn("Could not get current user to self-assign", "error", err)
+		} else if currentUser != nil {
+			logger(ctx).Debug("Defaulting assignee to current user", "assignee", currentUser.DisplayName)
+			assignee = j.assignableUser(currentUser)
+		}
+	}
+
+	description, mentioned := j.resolveMentions(ctx, params.Description, params.LiteralMentions)
+	issue := &jira.Issue{
+		Fields: &jira.IssueFields{
+			Project:     jira.Project{Key: projectKey},
+			Summary:     params.Summary,
+			Description: description,
+			Type:        jira.IssueType{Name: issueType},
+			Labels:      labels,
+			Components:  componentRefs(components),
+			Assignee:    assignee,
+		},
+	}
+	if params.Priority != "" {
+		issue.Fields.Priority = &jira.Priority{Name: params.Priority}
+	}
+	if len(params.CustomFields) > 0 {
+		issue.Fields.Unknowns = tcontainer.MarshalMap(params.CustomFields)
+	}
+	if params.DueDate != "" {
+		due, err := resolveDate(params.DueDate, time.Now())
+		if err != nil {
+			return textResult("Failed to create JIRA issue: due date: %v", err), nil, nil
+		}
+		issue.Fields.Duedate = jira.Date(due)
+	}
+	// go-jira has no security field, so the level goes in with the custom
+	// fields.
+	securityProblem := ""
+	if securityLevel != "" {
+		var value map[string]string
+		if value, securityProblem = j.securityLevelValue(ctx, projectKey, securityLevel); securityProblem == "" {
+			if issue.Fields.Unknowns == nil {
+				issue.Fields.Unknowns = tcontainer.NewMarshalMap()
+			}
+			issue.Fields.Unknowns["security"] = value
+		}
+	}
+
+	if j.dryRun(params.DryRun) {
+		problems := j.validateIssueFields(ctx, issue.Fields)
+		if securityProblem != "" {
+			problems = append(problems, securityProblem)
+		}
+		return dryRunResult("POST", "rest/api/2/issue", issue, problems), nil, nil
+	}
+
+	// A mistyped project key would otherwise surface as a generic field
+	// error from Jira.
+	if problem := j.projectKeyProblem(ctx, projectKey); problem != "" {
+		return textResult("Failed to create JIRA issue: %s", problem), nil, nil
+	}
+	// Issue types and priority schemes differ between projects, so check them
+	// up front rather than letting Jira reject them with a generic field
+	// error. When the project metadata cannot be read, Jira has the last word.
+	if project, err := j.getProject(ctx, projectKey); err == nil {
+		if problem := issueTypeProblem(project, issueType); problem != "" {
+			return textResult("Failed to create JIRA issue: %s", problem), nil, nil
+		}
+	}
+	if params.Priority != "" {
+		if problem, err := j.priorityProblem(ctx, projectKey, params.Priority); err == nil && problem != "" {
+			return textResult("Failed to create JIRA issue: %s", problem), nil, nil
+		}
+	}
+	// An issue meant to be restricted is never created visible to everyone.
+	if securityProblem != "" {
+		return textResult("Failed to create JIRA issue: %s", securityProblem), nil, nil
+	}
+
+	createdIssue, _, err := j.client(ctx).CreateIssue(ctx, issue)
+	if err != nil {
+		//return nil, nil, fmt.Errorf("failed to create JIRA issue: %w", err)
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{
+				&mcp.TextContent{Text: fmt.Sprintf("Failed to create JIRA issue: %v", err)},
+			},
+		}, nil, nil
+	}
+	issueUrl := fmt.Sprintf("%s/browse/%s", j.config.BaseURL, createdIssue.Key)
+	logger(ctx).Info("Created issue", "url", issueUrl)
+	j.journalCreate(ctx, "create-jira-issue", createdIssue.Key)
+	if securityLevel != "" {
+		issueUrl += fmt.Sprintf(" (security level %s)", securityLevel)
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			&mcp.TextContent{Text: fmt.Sprintf("Created JIRA issue: %s%s%s", issueUrl, dueDateNote(issue.Fields), mentioned.note())},
+		},
+	}, nil, nil
+}
+
+// NewJiraMCPServer creates and initializes a new JiraMCPServer instance.
+// It sets up the MCP server implementation, registers Jira tools, and returns the configured server.
+//
+// Parameters:
+//
+//	config - pointer to JiraConfig containing Jira connection details
+//
+// Returns:
+//
+//	*JiraMCPServer - pointer to the initialized JiraMCPServer
+//	error - error if initialization fails
+func NewJiraMCPServer(config *JiraConfig) (*JiraMCPServer, error) {
+	return newJiraMCPServer(config, nil)
+}
+
+// NewJiraMCPServerWithService creates a server that reaches Jira through
+// service, such as a MockJiraService, instead of a go-jira client for the
+// configured service account. Clients built for per-session credentials still
+// use go-jira, and anonymization only learns identities from go-jira clients.
+func NewJiraMCPServerWithService(config *JiraConfig, service JiraService) (*JiraMCPServer, error) {
+	if service == nil {
+		return nil, fmt.Errorf("a JiraService is required")
+	}
+	return newJiraMCPServer(config, service)
+}
+
+func newJiraMCPServer(config *JiraConfig, service JiraService) (*JiraMCPServer, error) {
+	var pseudonyms *pseudonymizer
+	if config.Anonymize {
+		pseudonyms = newPseudonymizer()
+	}
+
+	cache, limiter, err := newCacheAndLimiter(config)
+	if err != nil {
+		return nil, err
+	}
+
+	transport, err := jiraTransport(config)
+	if err != nil {
+		return nil, err
+	}
+	if service == nil {
+		jiraClient, err := newJiraClient(config.BaseURL, jiraCredentials{Username: config.Username, Token: config.APIToken}, pseudonyms, limiter, transport)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create JIRA client: %w", err)
+		}
+		service = NewGoJiraService(jiraClient)
+	}
+
+	sites := make(map[string]JiraService, len(config.Sites))
+	for name, site := range config.Sites {
+		client, err := newJiraClient(site.BaseURL, jiraCredentials{Username: site.Username, Token: site.Token}, pseudonyms, nil, transport)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create JIRA client for site %s: %w", name, err)
+		}
+		sites[name] = NewGoJiraService(client)
+	}
+
+	store, err := openStore(config)
+	if err != nil {
+		return nil, err
+	}
+
+	// Initialize JiraMCPServer struct with config; the MCP server is created
+	// below because its options refer back to jcmp.
+	jcmp := &JiraMCPServer{
+		config:          config,
+		service:         service,
+		pseudonyms:      pseudonyms,
+		toolNames:       make(map[string]bool),
+		registeredTools: make(map[string]bool),
+		readOnlyTools:   make(map[string]bool),
+		mutatingTools:   make(map[string]bool),
+		sessionClients:  make(map[string]*sessionClient),
+		sessionGrants:   make(map[string]*roleGrant),
+		sessionKeys:     make(map[string]string),
+		activeSessions:  make(map[string]int),
+		assets:          newAssetFS(config.AssetsDir),
+		store:           store,
+		started:         time.Now(),
+		cache:           cache,
+		limiter:         limiter,
+		transport:       transport,
+		sites:           sites,
+		completions:     completionLookups{cache: cache},
+	}
+
+	// Capabilities are declared from what is registered: tools and
+	// resources always, prompts when at least one is available, plus logging
+	// and argument completions.
+	server := mcp.NewServer(&mcp.Implementation{
+		Name:    ServerName,
+		Version: ServerVersion,
+	}, &mcp.ServerOptions{
+		HasTools:     true,
+		HasResources: true,
+		// Subscriptions are tracked by the SDK; updates are pushed from the
+		// webhook listener.
+		SubscribeHandler:   acceptSubscription,
+		UnsubscribeHandler: acceptUnsubscription,
+		CompletionHandler:  jcmp.complete,
+		InitializedHandler: jcmp.sessionInitialized,
+	})
+	jcmp.server = server
+	if err := jcmp.loadSettings(); err != nil {
+		return nil, err
+	}
+	server.AddReceivingMiddleware(jcmp.loggingMiddleware)
+	server.AddReceivingMiddleware(jcmp.metricsMiddleware)
+	if config.SessionCredentials && config.Transport != "stdio" {
+		server.AddReceivingMiddleware(jcmp.sessionCredentialsMiddleware)
+	}
+	server.AddReceivingMiddleware(jcmp.inFlightMiddleware)
+	if config.MaxMutationsPerHour > 0 {
+		if config.ResumableSessions {
+			jcmp.mutations.store = store
+			jcmp.pruneMutationCounts()
+		}
+		server.AddReceivingMiddleware(jcmp.mutationLimitMiddleware)
+	}
+	server.AddReceivingMiddleware(jcmp.allowedProjectsMiddleware)
+	if config.OIDCIssuer != "" && config.Transport != "stdio" {
+		jcmp.oidc, err = newOIDCAuth(context.Background(), config)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if config.RolesFile != "" && config.Transport != "stdio" {
+		roles, err := loadRoles(config.RolesFile)
+		if err != nil {
+			return nil, err
+		}
+		jcmp.roles = roles
+		server.AddReceivingMiddleware(jcmp.rbacMiddleware)
+	}
+	if pseudonyms != nil {
+		server.AddReceivingMiddleware(jcmp.anonymizeMiddleware)
+	}
+	if config.ResumableSessions {
+		server.AddReceivingMiddleware(jcmp.sessionStateMiddleware)
+		jcmp.pruneSessionStates()
+	}
+	server.AddReceivingMiddleware(jcmp.sessionKeyMiddleware)
+
+	if config.SkipStartupChecks {
+		jcmp.instance = jiraInstance{Deployment: config.Deployment, Source: "JIRA_MCP_DEPLOYMENT"}
+		if config.Deployment == "" {
+			jcmp.instance = jiraInstance{Deployment: guessDeployment(config.BaseURL), Source: "url"}
+		}
+	} else {
+		jcmp.instance = jcmp.detectInstance(context.Background())
+	}
+	if config.ProbeAPIs && !config.SessionCredentials && !config.SkipStartupChecks {
+		jcmp.probeAPIs(context.Background())
+	}
+
+	// Register Jira-related tools and resources to the MCP server.
+	jcmp.addTools()
+	jcmp.checkToolConfig()
+	jcmp.addResources()
+	jcmp.addAssetResources()
+	jcmp.addPrompts()
+
+	// Return the configured JiraMCPServer instance.
+	return jcmp, nil
+}
+
+func (j *JiraMCPServer) addTools() {
+	addTool(j, &mcp.Tool{Name: "create-jira-issue", Description: "Create a new Jira issue", Annotations: additiveHints(false)}, j.CreateJiraIssue)
+	addTool(j, &mcp.Tool{Name: "create-work-breakdown", Description: "Create an epic, its stories, and their sub-tasks in one call, linking each issue to its parent. The tree is validated first, and a failure part-way deletes the issues already created unless keepPartial is set", Annotations: additiveHints(false)}, j.CreateWorkBreakdown)
+	addTool(j, &mcp.Tool{Name: "list-session-changes", Description: "List the changes this session made that undo-last-change can revert, newest first", Annotations: &mcp.ToolAnnotations{ReadOnlyHint: true, OpenWorldHint: boolPtr(false)}}, j.ListSessionChanges)
+	addTool(j, &mcp.Tool{Name: "undo-last-change", Description: "Revert the newest change this session made: delete the issues a create, clone, or work breakdown call created, or restore the fields an update or estimate changed. Fields edited again since are left alone unless force is set", Annotations: destructiveHints(false)}, j.UndoLastChange)
+	addTool(j, &mcp.Tool{Name: "update-jira-issue", Description: "Update an existing Jira issue: summary, description, priority, issue type, assignee, due date, components, fix versions, labels, and custom fields by name. Setting status also performs the matching workflow transition, with resolution and transitionFields for its screen", Annotations: destructiveHints(true)}, j.UpdateJiraIssue)
+	addTool(j, &mcp.Tool{Name: "list-issue-templates", Description: "List the issue templates defined for this server and the variables each one expects", Annotations: &mcp.ToolAnnotations{ReadOnlyHint: true, OpenWorldHint: boolPtr(false)}}, j.ListIssueTemplates)
+	addTool(j, &mcp.Tool{Name: "create-issue-from-template", Description: "Create a JIRA issue from a named template, filling in its {{variable}} placeholders", Annotations: additiveHints(false)}, j.CreateIssueFromTemplate)
+	addTool(j, &mcp.Tool{Name: "clone-jira-issue", Description: "Clone a JIRA issue into the same or another project, optionally copying attachments and links and linking the clone to the original", Annotations: additiveHints(false)}, j.CloneJiraIssue)
+	addTool(j, &mcp.Tool{Name: "migrate-issue", Description: "Copy an issue with its comments and attachments to a project on another configured Jira site, linking the original and the copy to each other", Annotations: additiveHints(false)}, j.MigrateIssue)
+	addTool(j, &mcp.Tool{Name: "transition-jira-issue", Description: "Move a JIRA issue to another status by transition or target status name. Irreversible transitions require confirmationPhrase set to the issue key", Annotations: destructiveHints(false)}, j.TransitionJiraIssue)
+	addTool(j, &mcp.Tool{Name: "bulk-transition-issues", Description: "Move the issues matching a JQL query, or listed by key, through the matching transition. Changes to many issues are confirmed by the user first", Annotations: destructiveHints(false)}, j.BulkTransitionIssues)
+	addTool(j, &mcp.Tool{Name: "bulk-update-issues", Description: "Apply the same priority, assignee, due date, label, or component edits to the issues matching a JQL query or listed by key. Changes to many issues are confirmed by the user first", Annotations: destructiveHints(true)}, j.BulkUpdateIssues)
+	addTool(j, &mcp.Tool{Name: "delete-jira-issue", Description: "Permanently delete a JIRA issue, optionally with its subtasks. Requires confirm: true and confirmationPhrase set to the issue key", Annotations: destructiveHints(true)}, j.DeleteJiraIssue)
+	addTool(j, &mcp.Tool{Name: "archive-jira-issue", Description: "Archive a JIRA issue (Data Center or Cloud Premium). Requires confirm: true", Annotations: destructiveHints(true)}, j.ArchiveJiraIssue)
+	addTool(j, &mcp.Tool{Name: "add-comment", Description: "Add a comment to a Jira issue, optionally restricted to a project role or group", Annotations: additiveHints(false)}, j.AddComment)
+	addTool(j, &mcp.Tool{Name: "search-jira-issues", Description: "Search Jira issues with JQL; returns one page of results and a nextPageToken for the following page", Annotations: readOnlyHints()}, j.SearchJiraIssues)
+	addTool(j, &mcp.Tool{Name: "extract-issue-references", Description: "Find the issue keys and Jira issue URLs in pasted text such as meeting notes or a chat export, check which issues exist, and report the current status of each", Annotations: readOnlyHints()}, j.ExtractIssueReferences)
+	addTool(j, &mcp.Tool{Name: "list-overdue-issues", Description: "List a project's unresolved issues past their due date, most overdue first, optionally with those due in the next days", Annotations: readOnlyHints()}, j.ListOverdueIssues)
+	addTool(j, &mcp.Tool{Name: "get-my-issues", Description: "List open issues assigned to you, grouped by status", Annotations: readOnlyHints()}, j.GetMyIssues)
+	addTool(j, &mcp.Tool{Name: "get-my-reported-issues", Description: "List open issues you reported, grouped by status", Annotations: readOnlyHints()}, j.GetMyReportedIssues)
+	addTool(j, &mcp.Tool{Name: "get-recent-issues", Description: "List the issues you viewed most recently", Annotations: readOnlyHints()}, j.GetRecentIssues)
+	addTool(j, &mcp.Tool{Name: "get-issue-content", Description: "Get an issue's description and recent comments as Markdown, or as sanitized HTML for clients that render it (format: markdown, html, or both)", Annotations: readOnlyHints()}, j.GetIssueContent)
+	addTool(j, &mcp.Tool{Name: "get-issue-history", Description: "Get an issue's changelog (who changed which field, from and to, and when), optionally filtered by field or date range", Annotations: readOnlyHints()}, j.GetIssueHistory)
+	addTool(j, &mcp.Tool{Name: "list-filters", Description: "List saved Jira filters visible to you, optionally by name", Annotations: readOnlyHints()}, j.ListFilters)
+	addTool(j, &mcp.Tool{Name: "get-filter", Description: "Get a saved Jira filter's name, owner, and JQL", Annotations: readOnlyHints()}, j.GetFilter)
+	addTool(j, &mcp.Tool{Name: "run-filter", Description: "Run a saved Jira filter's JQL and return one page of matching issues", Annotations: readOnlyHints()}, j.RunFilter)
+	addTool(j, &mcp.Tool{Name: "create-filter", Description: "Save a JQL query as a new Jira filter", Annotations: additiveHints(false)}, j.CreateFilter)
+	addTool(j, &mcp.Tool{Name: "list-service-desks", Description: "List Jira Service Management service desks", Annotations: readOnlyHints()}, j.ListServiceDesks)
+	addTool(j, &mcp.Tool{Name: "list-request-types", Description: "List the request types of a Jira Service Management service desk", Annotations: readOnlyHints()}, j.ListRequestTypes)
+	addTool(j, &mcp.Tool{Name: "create-customer-request", Description: "Raise a customer request in a Jira Service Management service desk", Annotations: additiveHints(false)}, j.CreateCustomerRequest)
+	addTool(j, &mcp.Tool{Name: "add-request-comment", Description: "Comment on a Jira Service Management request; internal unless public is true", Annotations: additiveHints(false)}, j.AddRequestComment)
+	addTool(j, &mcp.Tool{Name: "get-request-sla", Description: "Get the SLA status (remaining time, breaches) of a Jira Service Management request", Annotations: readOnlyHints()}, j.GetRequestSLA)
+	addTool(j, &mcp.Tool{Name: "get-request-comments", Description: "Get the comments of a Jira Service Management request, tagged with urgency and sentiment when a classifier is configured", Annotations: readOnlyHints()}, j.GetRequestComments)
+	addTool(j, &mcp.Tool{Name: "list-queues", Description: "List the queues of a Jira Service Management service desk", Annotations: readOnlyHints()}, j.ListQueues)
+	addTool(j, &mcp.Tool{Name: "get-queue-comments", Description: "Collect new comments on the requests of a service desk queue, most urgent and most negative threads first when a classifier is configured", Annotations: readOnlyHints()}, j.GetQueueComments)
+	addTool(j, &mcp.Tool{Name: "list-security-levels", Description: "List the issue security levels of a project that can be set on its issues to restrict who can see them", Annotations: readOnlyHints()}, j.ListSecurityLevels)
+	addTool(j, &mcp.Tool{Name: "list-priorities", Description: "List issue priorities, optionally only those in a project's priority scheme", Annotations: readOnlyHints()}, j.ListPriorities)
+	addTool(j, &mcp.Tool{Name: "list-issue-types", Description: "List issue types, optionally only those available in a project", Annotations: readOnlyHints()}, j.ListIssueTypes)
+	addTool(j, &mcp.Tool{Name: "find-jira-user", Description: "Find Jira users by name, username, or email and return their account IDs, emails, and whether they are active", Annotations: readOnlyHints()}, j.FindJiraUser)
+	addTool(j, &mcp.Tool{Name: "list-statuses", Description: "List workflow statuses with their categories, or a project's statuses by issue type", Annotations: readOnlyHints()}, j.ListStatuses)
+	addTool(j, &mcp.Tool{Name: "list-components", Description: "List the components of a Jira project (defaults to the configured project)", Annotations: readOnlyHints()}, j.ListComponents)
+	addTool(j, &mcp.Tool{Name: "create-component", Description: "Create a component in a Jira project (defaults to the configured project)", Annotations: additiveHints(false)}, j.CreateComponent)
+	addTool(j, &mcp.Tool{Name: "snapshot-project-setup", Description: "Save a project's components, versions, and filters, with the server's issue templates, as a portable project setup", Annotations: additiveHints(true)}, j.SnapshotProjectSetup)
+	addTool(j, &mcp.Tool{Name: "apply-project-setup", Description: "Create the components, versions, filters, and issue templates of a saved project setup that a project lacks", Annotations: additiveHints(true)}, j.ApplyProjectSetup)
+	addTool(j, &mcp.Tool{Name: "list-labels", Description: "List labels in use on the Jira instance, optionally filtered by prefix", Annotations: readOnlyHints()}, j.ListLabels)
+	addTool(j, &mcp.Tool{Name: "check-my-permissions", Description: "Check which operations (create, edit, transition, assign, delete, ...) the server's Jira account may perform in a project or on an issue", Annotations: readOnlyHints()}, j.CheckMyPermissions)
+	addTool(j, &mcp.Tool{Name: "audit-project-permissions", Description: "Report a project's permission scheme grants and flag risky ones (requires Jira admin)", Annotations: readOnlyHints()}, j.AuditProjectPermissions)
+	addTool(j, &mcp.Tool{Name: "get-audit-records", Description: "Query Jira's audit log by date range, category, and user (requires Jira site admin)", Annotations: readOnlyHints()}, j.GetAuditRecords)
+	addTool(j, &mcp.Tool{Name: "get-test-status", Description: "List the Xray or Zephyr Scale tests covering an issue with the status of their latest run, and whether the issue is fully tested", Annotations: readOnlyHints()}, j.GetTestStatus)
+	addTool(j, &mcp.Tool{Name: "assign-to-oncall", Description: "Assign an issue to whoever is currently on call according to the configured Opsgenie, PagerDuty, or webhook schedule", Annotations: destructiveHints(true)}, j.AssignToOnCall)
+	addTool(j, &mcp.Tool{Name: "assign-next-in-rotation", Description: "Assign an issue to the next person in the round-robin rotation configured for its project or component", Annotations: destructiveHints(false)}, j.AssignNextInRotation)
+	addTool(j, &mcp.Tool{Name: "list-remote-links", Description: "List the web links (pull requests, docs, incident pages) attached to an issue", Annotations: readOnlyHints()}, j.ListRemoteLinks)
+	addTool(j, &mcp.Tool{Name: "add-remote-link", Description: "Attach a web link such as a pull request, design doc, or incident page to an issue, with title, icon, and relationship", Annotations: additiveHints(true)}, j.AddRemoteLink)
+	addTool(j, &mcp.Tool{Name: "list-watchers", Description: "List the users watching a Jira issue", Annotations: readOnlyHints()}, j.ListWatchers)
+	addTool(j, &mcp.Tool{Name: "add-watcher", Description: "Add a user (by name, email, or accountId) as a watcher of a Jira issue", Annotations: additiveHints(true)}, j.AddWatcher)
+	addTool(j, &mcp.Tool{Name: "remove-watcher", Description: "Remove a user (by name, email, or accountId) from the watchers of a Jira issue", Annotations: destructiveHints(true)}, j.RemoveWatcher)
+	addTool(j, &mcp.Tool{Name: "list-voters", Description: "Show how many votes a Jira issue has and who voted", Annotations: readOnlyHints()}, j.ListVoters)
+	addTool(j, &mcp.Tool{Name: "vote-for-issue", Description: "Vote for a Jira issue as the account the server acts as", Annotations: additiveHints(true)}, j.VoteForIssue)
+	addTool(j, &mcp.Tool{Name: "remove-vote", Description: "Withdraw the vote of the account the server acts as from a Jira issue", Annotations: destructiveHints(true)}, j.RemoveVote)
+	addTool(j, &mcp.Tool{Name: "most-voted-issues", Description: "List the issues of a project with the most votes, optionally narrowed by JQL", Annotations: readOnlyHints()}, j.MostVotedIssues)
+	addTool(j, &mcp.Tool{Name: "get-daily-digest", Description: "Get the daily digest of new, overdue, and SLA-risk issues; refresh compiles a new one now", Annotations: readOnlyHints()}, j.GetDailyDigest)
+	addTool(j, &mcp.Tool{Name: "transition-heatmap", Description: "Show how often each workflow transition is used in a project over a period, the average dwell time before it, and bounce-backs out of done", Annotations: readOnlyHints()}, j.TransitionHeatmap)
+	addTool(j, &mcp.Tool{Name: "burnup", Description: "Return daily burnup data (scope vs completed story points and issue counts) of a sprint or fix version as JSON arrays for charting", Annotations: readOnlyHints()}, j.Burnup)
+	addTool(j, &mcp.Tool{Name: "list-boards", Description: "List the agile boards of a project", Annotations: readOnlyHints()}, j.ListBoards)
+	addTool(j, &mcp.Tool{Name: "get-backlog", Description: "List a board's backlog in rank order", Annotations: readOnlyHints()}, j.GetBacklog)
+	addTool(j, &mcp.Tool{Name: "rank-issue", Description: "Rank an issue directly above or below another issue", Annotations: destructiveHints(true)}, j.RankIssue)
+	addTool(j, &mcp.Tool{Name: "reorder-backlog", Description: "Move issues, in the given order, to the top or bottom of a board's backlog", Annotations: destructiveHints(true)}, j.ReorderBacklog)
+	addTool(j, &mcp.Tool{Name: "sprint-summary", Description: "Summarize a sprint: issues and story points by status, committed vs completed points, and scope added after the start", Annotations: readOnlyHints()}, j.SprintSummary)
+	addTool(j, &mcp.Tool{Name: "project-status-report", Description: "Count a project's issues created, resolved, and open over a date range, by assignee or component", Annotations: readOnlyHints()}, j.ProjectStatusReport)
+	addTool(j, &mcp.Tool{Name: "set-estimate", Description: "Estimate an issue with a T-shirt size, mapped to story points, or with story points, and set the estimate's confidence", Annotations: destructiveHints(true)}, j.SetEstimate)
+	addTool(j, &mcp.Tool{Name: "get-estimates", Description: "List the T-shirt size, story points, and confidence of issues, converting sizes to points", Annotations: readOnlyHints()}, j.GetEstimates)
+	addTool(j, &mcp.Tool{Name: "issue-numbering-report", Description: "Report the latest issue key and number of projects and their weekly issue creation rate, with a projection, for pre-allocating references", Annotations: readOnlyHints()}, j.IssueNumberingReport)
+	addTool(j, &mcp.Tool{Name: "field-normalization-report", Description: "Scan a project for labels differing only by case, unused components, and issues without a priority, and propose update-jira-issue calls that normalize them", Annotations: readOnlyHints()}, j.FieldNormalizationReport)
+	addTool(j, &mcp.Tool{Name: "export-issue-bundle", Description: "Export an issue's fields, comments, changelog, and attachment metadata as one JSON document, optionally zipped with its attachments (stdio only)", Annotations: readOnlyHints()}, j.ExportIssueBundle)
+	addTool(j, &mcp.Tool{Name: "export-issues", Description: "Export every issue matching a JQL query as a CSV or JSON file, served as a jira://export/ resource, for result sets too large for a tool result", Annotations: readOnlyHints()}, j.ExportIssues)
+	addTool(j, &mcp.Tool{Name: "usage-report", Description: "Report how often each tool was called over the last days, its failure rate and average duration, and which tools were never used", Annotations: &mcp.ToolAnnotations{ReadOnlyHint: true, OpenWorldHint: boolPtr(false)}}, j.UsageReport)
+	addTool(j, &mcp.Tool{Name: "get-server-config", Description: "Show the server's configuration and runtime settings (allowed projects, named queries, templates) with secrets removed", Annotations: &mcp.ToolAnnotations{ReadOnlyHint: true, OpenWorldHint: boolPtr(false)}}, j.GetServerConfig)
+	addTool(j, &mcp.Tool{Name: "update-server-config", Description: "Change runtime settings: allowed projects, named queries, and issue templates. Changes are persisted", Annotations: &mcp.ToolAnnotations{DestructiveHint: boolPtr(true), IdempotentHint: true, OpenWorldHint: boolPtr(false)}}, j.UpdateServerConfig)
+	addTool(j, &mcp.Tool{Name: "override-mutation-limit", Description: "Operator override: raise or lift a client's hourly limit on changes to Jira for a while. Requires the operator's override token", Annotations: &mcp.ToolAnnotations{DestructiveHint: boolPtr(false), IdempotentHint: true, OpenWorldHint: boolPtr(false)}}, j.OverrideMutationLimit)
+	addTool(j, &mcp.Tool{Name: "snapshot-issue", Description: "Save a snapshot of all editable fields of an issue (locally or as an issue property) before making large edits", Annotations: additiveHints(false)}, j.SnapshotIssue)
+	addTool(j, &mcp.Tool{Name: "list-issue-snapshots", Description: "List the saved snapshots of an issue", Annotations: readOnlyHints()}, j.ListIssueSnapshots)
+	addTool(j, &mcp.Tool{Name: "restore-issue-from-snapshot", Description: "Restore an issue's editable fields from a saved snapshot (defaults to the most recent)", Annotations: destructiveHints(true)}, j.RestoreIssueFromSnapshot)
+}
+
+func getEnv(key, defaultValue string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return defaultValue
+}
+
+// getEnvBool parses a boolean environment variable, returning defaultValue
+// when it is unset or not a valid boolean.
+func getEnvBool(key string, defaultValue bool) bool {
+	if value, err := strconv.ParseBool(os.Getenv(key)); err == nil {
+		return value
+	}
+	return defaultValue
+}
+
+// getEnvInt parses an integer environment variable, returning defaultValue
+// when it is unset or not a valid integer.
+func getEnvInt(key string, defaultValue int) int {
+	if value, err := strconv.Atoi(os.Getenv(key)); err == nil {
+		return value
+	}
+	return defaultValue
+}
+
+// getEnvList splits a comma-separated environment variable into its trimmed,
+// non-empty elements.
+func getEnvList(key string) []string {
+	var values []string
+	for _, v := range strings.Split(os.Getenv(key), ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			values = append(values, v)
+		}
+	}
+	return values
+}
+
+// Secrets returns the configured credentials, which are redacted from logs.
+func (c *JiraConfig) Secrets() []string {
+	secrets := append([]string{c.APIToken, c.WebhookSecret, c.OnCallToken, c.MutationOverrideToken, c.StoreDSN, c.RedisURL, c.ClassifierToken, c.TestAPIToken}, c.AuthTokens...)
+	for _, site := range c.Sites {
+		secrets = append(secrets, site.Token)
+	}
+	if proxy, err := url.Parse(c.ProxyURL); err == nil && proxy.User != nil {
+		if password, ok := proxy.User.Password(); ok {
+			secrets = append(secrets, password)
+		}
+	}
+	return secrets
+}
+
+// LoadConfig reads the server configuration from the environment and validates
+// it.
+func LoadConfig() (*JiraConfig, error) {
+	config := &JiraConfig{
+		BaseURL:                   getEnv("JIRA_BASE_URL", ""),
+		Username:                  getEnv("JIRA_USERNAME", ""),
+		APIToken:                  getEnv("JIRA_API_TOKEN", ""),
+		ProjectKey:                getEnv("JIRA_PROJECT_KEY", ""),
+		Anonymize:                 getEnvBool("JIRA_MCP_ANONYMIZE", false),
+		AssetsDir:                 getEnv("JIRA_MCP_ASSETS_DIR", ""),
+		ReadOnly:                  getEnvBool("JIRA_MCP_READ_ONLY", false),
+		Mode:                      strings.ToLower(getEnv("JIRA_MODE", ModeFull)),
+		EnabledTools:              getEnvList("JIRA_MCP_ENABLED_TOOLS"),
+		DisabledTools:             getEnvList("JIRA_MCP_DISABLED_TOOLS"),
+		StateDir:                  getEnv("JIRA_MCP_STATE_DIR", defaultStateDir()),
+		Store:                     strings.ToLower(getEnv("JIRA_MCP_STORE", StoreFile)),
+		StoreDSN:                  getEnv("JIRA_MCP_STORE_DSN", ""),
+		WebhookSecret:             getEnv("JIRA_WEBHOOK_SECRET", ""),
+		WebhookPath:               getEnv("JIRA_WEBHOOK_PATH", "/webhooks/jira"),
+		WebhookQuerySecret:        getEnvBool("JIRA_WEBHOOK_QUERY_SECRET", false),
+		BulkConfirmThreshold:      getEnvInt("JIRA_MCP_BULK_CONFIRM_THRESHOLD", 5),
+		PollInterval:              time.Duration(getEnvInt("JIRA_MCP_POLL_INTERVAL", 0)) * time.Second,
+		PollProjects:              getEnvList("JIRA_MCP_POLL_PROJECTS"),
+		Verbosity:                 strings.ToLower(getEnv("JIRA_MCP_VERBOSITY", VerbosityStandard)),
+		ConfirmStatuses:           getEnvList("JIRA_MCP_CONFIRM_STATUSES"),
+		TemplatesFile:             getEnv("JIRA_MCP_TEMPLATES_FILE", ""),
+		AllowedProjects:           getEnvList("JIRA_MCP_ALLOWED_PROJECTS"),
+		AllowConfigUpdates:        getEnvBool("JIRA_MCP_ALLOW_CONFIG_UPDATES", false),
+		RotationsFile:             getEnv("JIRA_MCP_ROTATIONS_FILE", ""),
+		AllowDelete:               getEnvBool("JIRA_MCP_ALLOW_DELETE", false),
+		ProbeAPIs:                 getEnvBool("JIRA_MCP_PROBE_APIS", true),
+		Deployment:                strings.ToLower(getEnv("JIRA_MCP_DEPLOYMENT", "")),
+		OnCallProvider:            strings.ToLower(getEnv("JIRA_MCP_ONCALL_PROVIDER", "")),
+		OnCallURL:                 getEnv("JIRA_MCP_ONCALL_URL", ""),
+		OnCallToken:               getEnv("JIRA_MCP_ONCALL_TOKEN", ""),
+		OnCallSchedule:            getEnv("JIRA_MCP_ONCALL_SCHEDULE", ""),
+		MaxMutationsPerHour:       getEnvInt("JIRA_MCP_MAX_MUTATIONS_PER_HOUR", 0),
+		MutationOverrideToken:     getEnv("JIRA_MCP_MUTATION_OVERRIDE_TOKEN", ""),
+		SessionCredentials:        getEnvBool("JIRA_MCP_SESSION_CREDENTIALS", false),
+		RequireSessionCredentials: getEnvBool("JIRA_MCP_REQUIRE_SESSION_CREDENTIALS", false),
+		BindAddress:               getEnv("JIRA_MCP_BIND_ADDRESS", ""),
+		AuthTokens:                getEnvList("JIRA_MCP_AUTH_TOKENS"),
+		TLSCertFile:               getEnv("JIRA_MCP_TLS_CERT", ""),
+		TLSKeyFile:                getEnv("JIRA_MCP_TLS_KEY", ""),
+		TLSClientCAFile:           getEnv("JIRA_MCP_TLS_CLIENT_CA", ""),
+		ShutdownTimeout:           time.Duration(getEnvInt("JIRA_MCP_SHUTDOWN_TIMEOUT", 30)) * time.Second,
+		DigestTime:                getEnv("JIRA_MCP_DIGEST_TIME", ""),
+		DigestProjects:            getEnvList("JIRA_MCP_DIGEST_PROJECTS"),
+		DigestSLAField:            getEnv("JIRA_MCP_DIGEST_SLA_FIELD", ""),
+		DigestWebhookURL:          getEnv("JIRA_MCP_DIGEST_WEBHOOK_URL", ""),
+		ClassifierURL:             getEnv("JIRA_MCP_CLASSIFIER_URL", ""),
+		ClassifierToken:           getEnv("JIRA_MCP_CLASSIFIER_TOKEN", ""),
+		TestProvider:              strings.ToLower(getEnv("JIRA_MCP_TEST_PROVIDER", "")),
+		TestAPIURL:                getEnv("JIRA_MCP_TEST_API_URL", ""),
+		TestClientID:              getEnv("JIRA_MCP_TEST_CLIENT_ID", ""),
+		TestAPIToken:              getEnv("JIRA_MCP_TEST_API_TOKEN", ""),
+		StoryPointsField:          getEnv("JIRA_MCP_STORY_POINTS_FIELD", ""),
+		SizeField:                 getEnv("JIRA_MCP_SIZE_FIELD", ""),
+		ConfidenceField:           getEnv("JIRA_MCP_CONFIDENCE_FIELD", ""),
+		Metrics:                   getEnvBool("JIRA_MCP_METRICS", false),
+		RedisURL:                  getEnv("JIRA_MCP_REDIS_URL", ""),
+		ResumableSessions:         getEnvBool("JIRA_MCP_RESUMABLE_SESSIONS", false),
+		RolesFile:                 getEnv("JIRA_MCP_ROLES_FILE", ""),
+		OIDCIssuer:                getEnv("JIRA_MCP_OIDC_ISSUER", ""),
+		OIDCAudience:              getEnv("JIRA_MCP_OIDC_AUDIENCE", ""),
+		OIDCJWKSURL:               getEnv("JIRA_MCP_OIDC_JWKS_URL", ""),
+		OIDCUserClaim:             getEnv("JIRA_MCP_OIDC_USER_CLAIM", "email"),
+		OIDCImpersonationFile:     getEnv("JIRA_MCP_OIDC_IMPERSONATION_FILE", ""),
+		JiraRateLimit:             getEnvInt("JIRA_MCP_JIRA_RATE_LIMIT", 0),
+		RecordFile:                getEnv("JIRA_MCP_RECORD_FILE", ""),
+		ReplayFile:                getEnv("JIRA_MCP_REPLAY_FILE", ""),
+		SitesFile:                 getEnv("JIRA_MCP_SITES_FILE", ""),
+		Profile:                   getEnv("JIRA_PROFILE", ""),
+		ProjectsFile:              getEnv("JIRA_MCP_PROJECTS_FILE", ""),
+		ProxyURL:                  getEnv("JIRA_MCP_PROXY_URL", ""),
+		CAFile:                    getEnv("JIRA_MCP_CA_FILE", ""),
+		InsecureSkipVerify:        getEnvBool("JIRA_MCP_INSECURE_SKIP_VERIFY", false),
+		ConnectTimeout:            time.Duration(getEnvInt("JIRA_MCP_CONNECT_TIMEOUT", 30)) * time.Second,
+		ResponseTimeout:           time.Duration(getEnvInt("JIRA_MCP_RESPONSE_TIMEOUT", 0)) * time.Second,
+		FetchConcurrency:          getEnvInt("JIRA_MCP_FETCH_CONCURRENCY", defaultFetchConcurrency),
+	}
+	if config.SitesFile != "" {
+		sites, err := loadSites(config.SitesFile)
+		if err != nil {
+			return nil, err
+		}
+		config.Sites = sites
+	}
+	if config.Profile != "" {
+		if err := applyProfile(config); err != nil {
+			return nil, err
+		}
+	}
+	// Validate required fields. There is deliberately no default site, so
+	// that a token is never sent to a site it was not configured for.
+	if config.BaseURL == "" {
+		return nil, fmt.Errorf("JIRA_BASE_URL environment variable is required, or JIRA_PROFILE naming a site of JIRA_MCP_SITES_FILE")
+	}
+	// Replay never contacts Jira, so it needs no credentials. A profile's
+	// site may leave out the username to use a personal access token.
+	if config.Username == "" && config.ReplayFile == "" && config.Profile == "" {
+		return nil, fmt.Errorf("JIRA_USERNAME environment variable is required")
+	}
+	if config.APIToken == "" && config.ReplayFile == "" {
+		return nil, fmt.Errorf("JIRA_API_TOKEN environment variable is required")
+	}
+	if config.RecordFile != "" && config.ReplayFile != "" {
+		return nil, fmt.Errorf("JIRA_MCP_RECORD_FILE and JIRA_MCP_REPLAY_FILE cannot be used together")
+	}
+	if config.ProjectsFile != "" {
+		projects, err := loadProjects(config.ProjectsFile)
+		if err != nil {
+			return nil, err
+		}
+		config.Projects = projects
+	}
+	if config.ProjectKey == "" {
+		return nil, fmt.Errorf("JIRA_PROJECT_KEY environment variable is required, or JIRA_PROFILE naming a site with a projectKey")
+	}
+	switch config.Deployment {
+	case "", DeploymentCloud, DeploymentServer:
+	case "datacenter", "dc":
+		config.Deployment = DeploymentServer
+	default:
+		return nil, fmt.Errorf("JIRA_MCP_DEPLOYMENT must be %q or %q (Server and Data Center), got %q", DeploymentCloud, DeploymentServer, config.Deployment)
+	}
+	if config.Mode != ModeFull && config.Mode != ModeCommenter {
+		return nil, fmt.Errorf("JIRA_MODE must be %q or %q, got %q", ModeFull, ModeCommenter, config.Mode)
+	}
+	switch config.OnCallProvider {
+	case "", OnCallOpsgenie, OnCallPagerDuty:
+	case OnCallWebhook:
+		if config.OnCallURL == "" {
+			return nil, fmt.Errorf("JIRA_MCP_ONCALL_URL is required for the webhook on-call provider")
+		}
+	default:
+		return nil, fmt.Errorf("JIRA_MCP_ONCALL_PROVIDER must be %q, %q, or %q, got %q", OnCallOpsgenie, OnCallPagerDuty, OnCallWebhook, config.OnCallProvider)
+	}
+	switch config.TestProvider {
+	case "", TestsXrayServer:
+	case TestsXray, TestsZephyr:
+		if config.TestAPIToken == "" || (config.TestProvider == TestsXray && config.TestClientID == "") {
+			return nil, fmt.Errorf("JIRA_MCP_TEST_PROVIDER %q needs credentials: set JIRA_MCP_TEST_API_TOKEN, and JIRA_MCP_TEST_CLIENT_ID for Xray", config.TestProvider)
+		}
+	default:
+		return nil, fmt.Errorf("JIRA_MCP_TEST_PROVIDER must be %q, %q, or %q, got %q", TestsXray, TestsXrayServer, TestsZephyr, config.TestProvider)
+	}
+	if (config.TLSCertFile == "") != (config.TLSKeyFile == "") {
+		return nil, fmt.Errorf("JIRA_MCP_TLS_CERT and JIRA_MCP_TLS_KEY must be set together")
+	}
+	if config.TLSClientCAFile != "" && config.TLSCertFile == "" {
+		return nil, fmt.Errorf("JIRA_MCP_TLS_CLIENT_CA requires JIRA_MCP_TLS_CERT and JIRA_MCP_TLS_KEY")
+	}
+	if config.OIDCIssuer != "" && config.OIDCAudience == "" {
+		return nil, fmt.Errorf("JIRA_MCP_OIDC_AUDIENCE is required with JIRA_MCP_OIDC_ISSUER")
+	}
+	if config.OIDCImpersonationFile != "" {
+		if config.OIDCIssuer == "" {
+			return nil, fmt.Errorf("JIRA_MCP_OIDC_IMPERSONATION_FILE requires JIRA_MCP_OIDC_ISSUER")
+		}
+		config.SessionCredentials = true
+	}
+	if config.RolesFile != "" && len(config.AuthTokens) == 0 && config.OIDCIssuer == "" {
+		return nil, fmt.Errorf("JIRA_MCP_ROLES_FILE requires client authentication: set JIRA_MCP_AUTH_TOKENS or JIRA_MCP_OIDC_ISSUER")
+	}
+	if config.RequireSessionCredentials {
+		config.SessionCredentials = true
+	}
+	if config.FetchConcurrency < 1 {
+		return nil, fmt.Errorf("JIRA_MCP_FETCH_CONCURRENCY must be at least 1, got %d", config.FetchConcurrency)
+	}
+	sizes, err := parseTShirtSizes(getEnv("JIRA_MCP_TSHIRT_SIZES", defaultTShirtSizes))
+	if err != nil {
+		return nil, err
+	}
+	config.TShirtSizes = sizes
+	if config.PollInterval != 0 && config.PollInterval < 10*time.Second {
+		return nil, fmt.Errorf("JIRA_MCP_POLL_INTERVAL must be at least 10 seconds, got %s", config.PollInterval)
+	}
+	if config.DigestTime != "" {
+		if _, err := time.Parse("15:04", config.DigestTime); err != nil {
+			return nil, fmt.Errorf("JIRA_MCP_DIGEST_TIME must be HH:MM, got %q", config.DigestTime)
+		}
+	}
+	loc, err := time.LoadLocation(getEnv("JIRA_MCP_DIGEST_TIMEZONE", "Local"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid JIRA_MCP_DIGEST_TIMEZONE: %w", err)
+	}
+	config.DigestLocation = loc
+	switch config.Store {
+	case StoreFile, StoreBolt:
+	case StorePostgres:
+		if config.StoreDSN == "" {
+			return nil, fmt.Errorf("JIRA_MCP_STORE_DSN is required for the postgres store")
+		}
+	default:
+		return nil, fmt.Errorf("JIRA_MCP_STORE must be %q, %q, or %q, got %q", StoreFile, StoreBolt, StorePostgres, config.Store)
+	}
+	if config.ShutdownTimeout <= 0 {
+		return nil, fmt.Errorf("JIRA_MCP_SHUTDOWN_TIMEOUT must be a positive number of seconds")
+	}
+	if config.MaxMutationsPerHour < 0 {
+		return nil, fmt.Errorf("JIRA_MCP_MAX_MUTATIONS_PER_HOUR must not be negative")
+	}
+	if config.JiraRateLimit < 0 {
+		return nil, fmt.Errorf("JIRA_MCP_JIRA_RATE_LIMIT must not be negative")
+	}
+	if !validVerbosity(config.Verbosity) {
+		return nil, fmt.Errorf("JIRA_MCP_VERBOSITY must be %q, %q, or %q, got %q", VerbosityMinimal, VerbosityStandard, VerbosityFull, config.Verbosity)
+	}
+
+	// Ensure BaseURL has proper format
+	if !strings.HasPrefix(config.BaseURL, "http://") && !strings.HasPrefix(config.BaseURL, "https://") {
+		config.BaseURL = "https://" + config.BaseURL
+	}
+
+	// Remove trailing slash if present
+	config.BaseURL = strings.TrimSuffix(config.BaseURL, "/")
+
+	return config, nil
+}
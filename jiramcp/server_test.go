@@ -0,0 +1,290 @@
+package jiramcp
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/andygrunwald/go-jira"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// jiraRoutes answers the raw requests of a MockJiraService, keyed by method
+// and path as in "GET rest/api/2/project". A route returns the status and
+// the value sent back as JSON; requests without a route get 404.
+type jiraRoutes map[string]func(r *http.Request) (int, interface{})
+
+func (routes jiraRoutes) do(req *http.Request, v interface{}) (*jira.Response, error) {
+	status, body := http.StatusNotFound, interface{}(map[string]interface{}{"errorMessages": []string{"no route"}})
+	if route := routes[req.Method+" "+strings.TrimPrefix(req.URL.Path, "/")]; route != nil {
+		status, body = route(req)
+	}
+	b, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+	resp := &jira.Response{Response: &http.Response{
+		StatusCode: status,
+		Header:     http.Header{"Content-Type": {"application/json"}},
+		Body:       io.NopCloser(bytes.NewReader(b)),
+		Request:    req,
+	}}
+	if status >= http.StatusMultipleChoices {
+		return resp, fmt.Errorf("request failed. Please analyze the request body for more details. Status code: %d", status)
+	}
+	if v != nil {
+		return resp, json.Unmarshal(b, v)
+	}
+	return resp, nil
+}
+
+// decodeBody decodes the JSON body of a request sent to a route.
+func decodeBody(t *testing.T, r *http.Request, v interface{}) {
+	t.Helper()
+	if err := json.NewDecoder(r.Body).Decode(v); err != nil {
+		t.Fatalf("decoding %s %s: %v", r.Method, r.URL.Path, err)
+	}
+}
+
+// newTestServer builds a server that talks to mock, without contacting Jira
+// at startup. configure may adjust the configuration first.
+func newTestServer(t *testing.T, mock *MockJiraService, configure func(*JiraConfig)) *JiraMCPServer {
+	t.Helper()
+	config := &JiraConfig{
+		BaseURL:              "https://jira.example.com",
+		Username:             "bot",
+		APIToken:             "secret",
+		ProjectKey:           "SMS",
+		Transport:            "stdio",
+		Mode:                 ModeFull,
+		StateDir:             t.TempDir(),
+		Store:                StoreFile,
+		Verbosity:            VerbosityStandard,
+		BulkConfirmThreshold: 5,
+		FetchConcurrency:     1,
+		Deployment:           DeploymentServer,
+		SkipStartupChecks:    true,
+	}
+	if configure != nil {
+		configure(config)
+	}
+	j, err := NewJiraMCPServerWithService(config, mock)
+	if err != nil {
+		t.Fatalf("NewJiraMCPServerWithService: %v", err)
+	}
+	return j
+}
+
+// connect opens an in-memory MCP session to j, so calls pass through the
+// server's middleware. Values of ctx reach the handlers, as those of an
+// authenticated HTTP request do.
+func connect(t *testing.T, ctx context.Context, j *JiraMCPServer) *mcp.ClientSession {
+	t.Helper()
+	return connectWith(t, ctx, j, nil)
+}
+
+// connectWith is connect for a client with options, such as handlers of
+// notifications.
+func connectWith(t *testing.T, ctx context.Context, j *JiraMCPServer, opts *mcp.ClientOptions) *mcp.ClientSession {
+	t.Helper()
+	clientTransport, serverTransport := mcp.NewInMemoryTransports()
+	if _, err := j.server.Connect(ctx, serverTransport, nil); err != nil {
+		t.Fatalf("server connect: %v", err)
+	}
+	client := mcp.NewClient(&mcp.Implementation{Name: "test", Version: "1.0.0"}, opts)
+	session, err := client.Connect(ctx, clientTransport, nil)
+	if err != nil {
+		t.Fatalf("client connect: %v", err)
+	}
+	t.Cleanup(func() { session.Close() })
+	return session
+}
+
+// callTool calls a tool over session and returns its result.
+func callTool(t *testing.T, session *mcp.ClientSession, name string, args map[string]interface{}) *mcp.CallToolResult {
+	t.Helper()
+	result, err := session.CallTool(context.Background(), &mcp.CallToolParams{Name: name, Arguments: args})
+	if err != nil {
+		t.Fatalf("calling %s: %v", name, err)
+	}
+	return result
+}
+
+// resultText joins the text content of a tool result.
+func resultText(r *mcp.CallToolResult) string {
+	var parts []string
+	for _, c := range r.Content {
+		if tc, ok := c.(*mcp.TextContent); ok {
+			parts = append(parts, tc.Text)
+		}
+	}
+	return strings.Join(parts, "\n")
+}
+
+// called reports whether the mock's method was called.
+func called(m *MockJiraService, method string) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, c := range m.Calls {
+		if c == method {
+			return true
+		}
+	}
+	return false
+}
+
+// projectRoutes serves the SMS project, which has Task and Bug issue types.
+func projectRoutes() jiraRoutes {
+	return jiraRoutes{
+		"GET rest/api/2/project": func(*http.Request) (int, interface{}) {
+			return http.StatusOK, []jira.Project{{Key: "SMS", Name: "Support"}}
+		},
+		"GET rest/api/2/project/SMS": func(*http.Request) (int, interface{}) {
+			return http.StatusOK, jira.Project{Key: "SMS", Name: "Support", IssueTypes: []jira.IssueType{{Name: "Task"}, {Name: "Bug"}}}
+		},
+	}
+}
+
+func TestCreateJiraIssue(t *testing.T) {
+	var created *jira.Issue
+	mock := &MockJiraService{
+		DoFunc: projectRoutes().do,
+		GetSelfFunc: func(context.Context) (*jira.User, *jira.Response, error) {
+			return &jira.User{Name: "bot", DisplayName: "Bot"}, nil, nil
+		},
+		CreateIssueFunc: func(_ context.Context, issue *jira.Issue) (*jira.Issue, *jira.Response, error) {
+			created = issue
+			return &jira.Issue{Key: "SMS-7"}, nil, nil
+		},
+	}
+	j := newTestServer(t, mock, nil)
+
+	result, _, err := j.CreateJiraIssue(context.Background(), nil, &CreateJiraIssueParams{
+		Summary:     "Login fails",
+		Description: "Steps to reproduce",
+		IssueType:   "Bug",
+		Labels:      []string{"auth"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := resultText(result), "Created JIRA issue: https://jira.example.com/browse/SMS-7"; !strings.HasPrefix(got, want) {
+		t.Fatalf("result = %q, want prefix %q", got, want)
+	}
+	if created == nil {
+		t.Fatal("CreateIssue was not called")
+	}
+	f := created.Fields
+	if f.Project.Key != "SMS" || f.Summary != "Login fails" || f.Type.Name != "Bug" || strings.Join(f.Labels, ",") != "auth" {
+		t.Errorf("created fields = project %q, summary %q, type %q, labels %v", f.Project.Key, f.Summary, f.Type.Name, f.Labels)
+	}
+	// Without an assignee the issue goes to the caller, by username on
+	// Server and Data Center.
+	if f.Assignee == nil || f.Assignee.Name != "bot" {
+		t.Errorf("assignee = %+v, want the current user", f.Assignee)
+	}
+	entries := j.journal.entries("")
+	if len(entries) != 1 || strings.Join(entries[0].Created, ",") != "SMS-7" {
+		t.Errorf("journal = %+v, want the creation of SMS-7", entries)
+	}
+}
+
+func TestCreateJiraIssueRejectsUnknownProjectAndType(t *testing.T) {
+	mock := &MockJiraService{
+		DoFunc: projectRoutes().do,
+		GetSelfFunc: func(context.Context) (*jira.User, *jira.Response, error) {
+			return &jira.User{Name: "bot"}, nil, nil
+		},
+	}
+	j := newTestServer(t, mock, nil)
+
+	tests := []struct {
+		params CreateJiraIssueParams
+		want   string
+	}{
+		{CreateJiraIssueParams{ProjectKey: "SMX", Summary: "x", IssueType: "Task"}, `Did you mean "SMS"?`},
+		{CreateJiraIssueParams{Summary: "x", IssueType: "Epic"}, "Epic"},
+	}
+	for _, tt := range tests {
+		result, _, err := j.CreateJiraIssue(context.Background(), nil, &tt.params)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := resultText(result); !strings.HasPrefix(got, "Failed to create JIRA issue") || !strings.Contains(got, tt.want) {
+			t.Errorf("project %q, type %q: result = %q, want a failure mentioning %q", tt.params.ProjectKey, tt.params.IssueType, got, tt.want)
+		}
+	}
+	if called(mock, "CreateIssue") {
+		t.Error("CreateIssue was called for an invalid issue")
+	}
+}
+
+func TestUpdateJiraIssue(t *testing.T) {
+	var update map[string]map[string][]map[string]interface{}
+	routes := jiraRoutes{
+		"GET rest/api/2/issue/SMS-1": func(*http.Request) (int, interface{}) {
+			return http.StatusOK, map[string]interface{}{"fields": map[string]interface{}{"summary": "Old", "labels": []string{}}}
+		},
+		"PUT rest/api/2/issue/SMS-1": func(r *http.Request) (int, interface{}) {
+			decodeBody(t, r, &update)
+			return http.StatusNoContent, nil
+		},
+	}
+	mock := &MockJiraService{
+		DoFunc: routes.do,
+		GetIssueFunc: func(_ context.Context, key string, _ *jira.GetQueryOptions) (*jira.Issue, *jira.Response, error) {
+			return &jira.Issue{Key: key, Fields: &jira.IssueFields{Project: jira.Project{Key: "SMS"}, Summary: "Old"}}, nil, nil
+		},
+	}
+	j := newTestServer(t, mock, nil)
+
+	result, _, err := j.UpdateJiraIssue(context.Background(), nil, &UpdateIssueArgs{
+		IssueKey:  "SMS-1",
+		Summary:   "New",
+		AddLabels: []string{"triaged"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := resultText(result), "Updated JIRA issue: https://jira.example.com/browse/SMS-1 (updated fields)"; got != want {
+		t.Fatalf("result = %q, want %q", got, want)
+	}
+	if got := update["update"]["summary"]; len(got) != 1 || got[0]["set"] != "New" {
+		t.Errorf("summary operations = %v, want a set to New", got)
+	}
+	if got := update["update"]["labels"]; len(got) != 1 || got[0]["add"] != "triaged" {
+		t.Errorf("label operations = %v, want an add of triaged", got)
+	}
+	if entries := j.journal.entries(""); len(entries) != 1 || entries[0].IssueKey != "SMS-1" {
+		t.Errorf("journal = %+v, want the edit of SMS-1", entries)
+	}
+}
+
+func TestUpdateJiraIssueDryRunChangesNothing(t *testing.T) {
+	routes := jiraRoutes{
+		"PUT rest/api/2/issue/SMS-1": func(*http.Request) (int, interface{}) {
+			t.Error("dry run sent the update")
+			return http.StatusNoContent, nil
+		},
+	}
+	mock := &MockJiraService{
+		DoFunc: routes.do,
+		GetIssueFunc: func(_ context.Context, key string, _ *jira.GetQueryOptions) (*jira.Issue, *jira.Response, error) {
+			return &jira.Issue{Key: key, Fields: &jira.IssueFields{Project: jira.Project{Key: "SMS"}}}, nil, nil
+		},
+	}
+	j := newTestServer(t, mock, nil)
+
+	result, _, err := j.UpdateJiraIssue(context.Background(), nil, &UpdateIssueArgs{IssueKey: "SMS-1", Summary: "New", DryRun: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := resultText(result); !strings.Contains(got, "Request: PUT /rest/api/2/issue/SMS-1") {
+		t.Errorf("result = %q, want the request that would be sent", got)
+	}
+}
@@ -0,0 +1,143 @@
+package jiramcp
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+type (
+	sessionKeyKey struct{}
+	clientKeyKey  struct{}
+)
+
+// anonymousClient is the client key of clients that present no identity.
+const anonymousClient = "anonymous"
+
+// sessionKeyMiddleware names the per-session state of each request after
+// both the session ID and the client that owns it: the authenticated
+// principal and the Jira credentials it sent. The session ID alone is chosen
+// by whoever sends it, so a client presenting another client's session ID
+// reaches none of that session's journal or pseudonyms. It also records the
+// client's key, see clientKey. It is added last so that all other middleware
+// and the handlers see the keys.
+func (j *JiraMCPServer) sessionKeyMiddleware(next mcp.MethodHandler) mcp.MethodHandler {
+	return func(ctx context.Context, method string, req mcp.Request) (mcp.Result, error) {
+		owner := j.ownerHash(ctx, req)
+		if s := req.GetSession(); s != nil && s.ID() != "" {
+			key := s.ID()
+			if owner != "" {
+				key += "." + owner
+			}
+			j.sessionKeysMu.Lock()
+			j.sessionKeys[s.ID()] = key
+			j.sessionKeysMu.Unlock()
+			ctx = context.WithValue(ctx, sessionKeyKey{}, key)
+		}
+		client := anonymousClient
+		if owner != "" {
+			client = "client:" + owner
+		}
+		ctx = context.WithValue(ctx, clientKeyKey{}, client)
+		return next(ctx, method, req)
+	}
+}
+
+// sessionStateKey returns the key of a session's state as last seen in a
+// request, or "" for none, as requestSession does within requests.
+func (j *JiraMCPServer) sessionStateKey(id string) string {
+	j.sessionKeysMu.Lock()
+	defer j.sessionKeysMu.Unlock()
+	return j.sessionKeys[id]
+}
+
+// ownerHash identifies the client behind req by its principal and the Jira
+// credentials it sent, or is "" for clients that present no identity, such
+// as stdio clients.
+func (j *JiraMCPServer) ownerHash(ctx context.Context, req mcp.Request) string {
+	h := sha256.New()
+	owned := false
+	if who := principalID(j.requestPrincipal(ctx, req)); who != "" {
+		fmt.Fprintf(h, "principal\x00%s\x00", who)
+		owned = true
+	}
+	if creds, ok := requestCredentials(ctx, req); ok {
+		fmt.Fprintf(h, "credentials\x00%s\x00%s\x00", creds.Username, creds.Token)
+		owned = true
+	}
+	if !owned {
+		return ""
+	}
+	return hex.EncodeToString(h.Sum(nil))[:16]
+}
+
+// principalID identifies an authenticated client across requests, or is ""
+// for none. The subject of an identity token outlives the token itself.
+func principalID(p *principal) string {
+	if p == nil {
+		return ""
+	}
+	if p.Subject != "" {
+		return "sub:" + p.Subject
+	}
+	return "token:" + p.TokenSHA256
+}
+
+// requestSession returns the key of the calling session's state, or "" for
+// none.
+func requestSession(ctx context.Context) string {
+	key, _ := ctx.Value(sessionKeyKey{}).(string)
+	return key
+}
+
+// requestClient returns the key of the calling client across its sessions:
+// "client:" and the hash of its identity, or anonymousClient for clients
+// that present none, which share it.
+func requestClient(ctx context.Context) string {
+	if key, ok := ctx.Value(clientKeyKey{}).(string); ok {
+		return key
+	}
+	return anonymousClient
+}
+
+// sessionInitialized watches a new session and forgets its per-session state
+// once it ends, so long-running servers do not collect state of sessions
+// that are gone.
+func (j *JiraMCPServer) sessionInitialized(ctx context.Context, req *mcp.InitializedRequest) {
+	session := req.Session
+	if session == nil {
+		return
+	}
+	go func() {
+		session.Wait()
+		j.forgetSession(session.ID())
+	}()
+}
+
+// forgetSession drops the per-session state kept in memory for a session,
+// under any of its keys.
+func (j *JiraMCPServer) forgetSession(id string) {
+	j.rememberGrant(id, nil)
+	j.sessionKeysMu.Lock()
+	delete(j.sessionKeys, id)
+	j.sessionKeysMu.Unlock()
+	j.sessionClientsMu.Lock()
+	delete(j.sessionClients, id)
+	j.sessionClientsMu.Unlock()
+	j.dropSessionState(func(key string) bool {
+		return key == id || strings.HasPrefix(key, id+".")
+	})
+}
+
+// dropSessionState drops the pseudonyms and journals of the session keys
+// matching match. Mutation counts are kept per client and outlive sessions.
+func (j *JiraMCPServer) dropSessionState(match func(key string) bool) {
+	if j.pseudonyms != nil {
+		j.pseudonyms.forget(match)
+	}
+	j.journal.forget(match)
+}
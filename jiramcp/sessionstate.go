@@ -0,0 +1,119 @@
+package jiramcp
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// sessionBucket is the store bucket holding the state of resumable sessions.
+const sessionBucket = "sessions"
+
+// sessionStateTTL is how long the state of an idle session is kept.
+const sessionStateTTL = 24 * time.Hour
+
+// sessionState is the per-session working context that outlives a dropped
+// connection, a restart, or a request served by another replica.
+type sessionState struct {
+	UpdatedAt time.Time `json:"updatedAt"`
+	// Pseudonyms and PseudonymCount are the session's anonymization
+	// pseudonyms, by real name.
+	Pseudonyms     map[string]string `json:"pseudonyms,omitempty"`
+	PseudonymCount int               `json:"pseudonymCount,omitempty"`
+	// Journal is the session's changes that undo-last-change can revert.
+	Journal []journalEntry `json:"journal,omitempty"`
+}
+
+// captureSession collects the in-memory state of session.
+func (j *JiraMCPServer) captureSession(session string) *sessionState {
+	state := &sessionState{UpdatedAt: time.Now()}
+	if p := j.pseudonyms; p != nil {
+		p.mu.Lock()
+		if names := p.sessions[session]; len(names) > 0 {
+			state.Pseudonyms = make(map[string]string, len(names))
+			for name, alias := range names {
+				state.Pseudonyms[name] = alias
+			}
+		}
+		state.PseudonymCount = p.counters[session]
+		p.mu.Unlock()
+	}
+	state.Journal = j.journal.entries(session)
+	return state
+}
+
+// restoreSession replaces the in-memory state of session with state.
+func (j *JiraMCPServer) restoreSession(session string, state *sessionState) {
+	if p := j.pseudonyms; p != nil {
+		p.mu.Lock()
+		names := make(map[string]string, len(state.Pseudonyms))
+		for name, alias := range state.Pseudonyms {
+			names[name] = alias
+		}
+		p.sessions[session] = names
+		p.counters[session] = state.PseudonymCount
+		p.mu.Unlock()
+	}
+	j.journal.replace(session, state.Journal)
+}
+
+// sessionStateMiddleware makes sessions resumable: before a tool call the
+// session's state is loaded from the store, and afterwards it is saved back,
+// so any replica, or this one after a restart, continues where the session
+// left off. Resumable sessions never close, so once no call of a session is
+// running here its state is dropped from memory; the store keeps it. It is
+// added last so that it wraps the middleware whose state it restores.
+func (j *JiraMCPServer) sessionStateMiddleware(next mcp.MethodHandler) mcp.MethodHandler {
+	return func(ctx context.Context, method string, req mcp.Request) (mcp.Result, error) {
+		session := requestSession(ctx)
+		if method != "tools/call" || session == "" {
+			return next(ctx, method, req)
+		}
+
+		j.activeSessionsMu.Lock()
+		j.activeSessions[session]++
+		if j.activeSessions[session] == 1 {
+			var state sessionState
+			found, err := j.store.Get(sessionBucket, session, &state)
+			if err != nil {
+				logger(ctx).Warn("Failed to load session state", "error", err)
+			} else if found && time.Since(state.UpdatedAt) < sessionStateTTL {
+				j.restoreSession(session, &state)
+			}
+		}
+		j.activeSessionsMu.Unlock()
+
+		result, err := next(ctx, method, req)
+
+		if err := j.store.Put(sessionBucket, session, j.captureSession(session)); err != nil {
+			logger(ctx).Warn("Failed to save session state", "error", err)
+		}
+		j.activeSessionsMu.Lock()
+		defer j.activeSessionsMu.Unlock()
+		if j.activeSessions[session]--; j.activeSessions[session] == 0 {
+			delete(j.activeSessions, session)
+			j.dropSessionState(func(key string) bool { return key == session })
+		}
+		return result, err
+	}
+}
+
+// pruneSessionStates deletes the stored state of sessions idle for longer
+// than sessionStateTTL.
+func (j *JiraMCPServer) pruneSessionStates() {
+	keys, err := j.store.Keys(sessionBucket)
+	if err != nil {
+		slog.Warn("Failed to list session states", "error", err)
+		return
+	}
+	for _, key := range keys {
+		var state sessionState
+		if found, err := j.store.Get(sessionBucket, key, &state); err == nil && found && time.Since(state.UpdatedAt) >= sessionStateTTL {
+			if err := j.store.Delete(sessionBucket, key); err != nil {
+				slog.Warn("Failed to delete session state", "session", key, "error", err)
+			}
+		}
+	}
+}
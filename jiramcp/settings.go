@@ -0,0 +1,279 @@
+package jiramcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// settingsBucket and settingsKey locate the persisted runtime settings.
+const (
+	settingsBucket = "settings"
+	settingsKey    = "runtime"
+)
+
+// runtimeSettings are the settings operators can change while the server is
+// running with update-server-config. Changes are persisted in the store and
+// take precedence over the environment on the next start.
+type runtimeSettings struct {
+	// AllowedProjects, when non-empty, restricts every project a tool call
+	// addresses, the issues addressed by resources, JQL queries, and the
+	// issues clients are notified about.
+	AllowedProjects []string `json:"allowedProjects,omitempty"`
+	// NamedQueries maps short names to JQL usable as search-jira-issues' query.
+	NamedQueries map[string]string `json:"namedQueries,omitempty"`
+	// Templates add to or replace the bundled and file-based issue templates.
+	Templates []issueTemplate `json:"templates,omitempty"`
+}
+
+type GetServerConfigParams struct{}
+
+type UpdateServerConfigParams struct {
+	// AllowedProjects replaces the allowed project list; an empty list allows all projects.
+	AllowedProjects *[]string `json:"allowedProjects,omitempty"`
+	// NamedQueries are merged into the existing ones; an empty JQL removes the query.
+	NamedQueries map[string]string `json:"namedQueries,omitempty"`
+	// Templates are added, replacing existing runtime templates of the same name.
+	Templates       []issueTemplate `json:"templates,omitempty"`
+	RemoveTemplates []string        `json:"removeTemplates,omitempty"`
+}
+
+// loadSettings initializes the runtime settings from the environment and any
+// previously persisted changes.
+func (j *JiraMCPServer) loadSettings() error {
+	j.settings = runtimeSettings{AllowedProjects: j.config.AllowedProjects}
+	if _, err := j.store.Get(settingsBucket, settingsKey, &j.settings); err != nil {
+		return fmt.Errorf("failed to load runtime settings: %w", err)
+	}
+	return nil
+}
+
+// currentSettings returns a copy of the runtime settings.
+func (j *JiraMCPServer) currentSettings() runtimeSettings {
+	j.settingsMu.RLock()
+	defer j.settingsMu.RUnlock()
+	s := j.settings
+	s.AllowedProjects = slices.Clone(s.AllowedProjects)
+	s.Templates = slices.Clone(s.Templates)
+	s.NamedQueries = make(map[string]string, len(j.settings.NamedQueries))
+	for name, jql := range j.settings.NamedQueries {
+		s.NamedQueries[name] = jql
+	}
+	return s
+}
+
+// projectAllowed reports whether the runtime settings permit access to project.
+func (j *JiraMCPServer) projectAllowed(project string) bool {
+	j.settingsMu.RLock()
+	defer j.settingsMu.RUnlock()
+	if len(j.settings.AllowedProjects) == 0 {
+		return true
+	}
+	for _, p := range j.settings.AllowedProjects {
+		if strings.EqualFold(p, project) {
+			return true
+		}
+	}
+	return false
+}
+
+// projectOfIssue returns the project part of an issue key.
+func projectOfIssue(issueKey string) string {
+	project, _, _ := strings.Cut(issueKey, "-")
+	return project
+}
+
+// allowedProjectsMiddleware rejects tool calls, resource reads, and
+// subscriptions addressing a project outside the allowed list. Every project
+// a call addresses is checked, see toolCallProjects; JQL queries are limited
+// to the allowed projects when they run, see scopeJQL.
+func (j *JiraMCPServer) allowedProjectsMiddleware(next mcp.MethodHandler) mcp.MethodHandler {
+	return func(ctx context.Context, method string, req mcp.Request) (mcp.Result, error) {
+		if len(j.currentSettings().AllowedProjects) == 0 {
+			return next(ctx, method, req)
+		}
+		var key string
+		switch params := req.GetParams().(type) {
+		case *mcp.CallToolParamsRaw:
+			projects, err := j.toolCallProjects(ctx, params.Name, params.Arguments)
+			if err != nil {
+				result := textResult("Could not check the projects of this call: %v", err)
+				result.IsError = true
+				return result, nil
+			}
+			for _, project := range projects {
+				if !j.projectAllowed(project) {
+					result := textResult("Project %s is not in the list of projects this server may access", project)
+					result.IsError = true
+					return result, nil
+				}
+			}
+		case *mcp.ReadResourceParams:
+			key = resourceIssueKey(params.URI)
+		case *mcp.SubscribeParams:
+			key = resourceIssueKey(params.URI)
+		}
+		if key != "" && !j.projectAllowed(projectOfIssue(key)) {
+			return nil, fmt.Errorf("project %s is not in the list of projects this server may access", strings.ToUpper(projectOfIssue(key)))
+		}
+		return next(ctx, method, req)
+	}
+}
+
+// GetServerConfig reports the server's configuration with credentials and
+// secrets removed.
+func (j *JiraMCPServer) GetServerConfig(ctx context.Context, req *mcp.CallToolRequest, params *GetServerConfigParams) (*mcp.CallToolResult, any, error) {
+	c := j.config
+	settings := j.currentSettings()
+	sanitized := map[string]interface{}{
+		"version":             ServerVersion,
+		"baseUrl":             c.BaseURL,
+		"username":            c.Username,
+		"projectKey":          c.ProjectKey,
+		"profile":             c.Profile,
+		"instance":            j.instance,
+		"transport":           c.Transport,
+		"mode":                c.Mode,
+		"dryRun":              c.DryRun,
+		"readOnly":            c.ReadOnly,
+		"anonymize":           c.Anonymize,
+		"verbosity":           c.Verbosity,
+		"enabledTools":        c.EnabledTools,
+		"disabledTools":       c.DisabledTools,
+		"unavailableTools":    j.unavailableTools,
+		"confirmStatuses":     c.ConfirmStatuses,
+		"webhooksEnabled":     c.WebhookSecret != "",
+		"webhookQuerySecret":  c.WebhookQuerySecret,
+		"bulkConfirmation":    c.BulkConfirmThreshold,
+		"pollInterval":        c.PollInterval.String(),
+		"pollProjects":        c.PollProjects,
+		"configUpdates":       c.AllowConfigUpdates,
+		"maxMutationsPerHour": c.MaxMutationsPerHour,
+		"sessionCredentials":  c.SessionCredentials,
+		"bindAddress":         c.BindAddress,
+		"authTokens":          len(c.AuthTokens),
+		"tls":                 c.TLSCertFile != "",
+		"mutualTls":           c.TLSClientCAFile != "",
+		"templatesFile":       c.TemplatesFile,
+		"assetsDir":           c.AssetsDir,
+		"stateDir":            c.StateDir,
+		"redis":               c.RedisURL != "",
+		"resumableSessions":   c.ResumableSessions,
+		"roles":               c.RolesFile != "",
+		"oidcIssuer":          c.OIDCIssuer,
+		"oidcImpersonation":   c.OIDCImpersonationFile != "",
+		"jiraRateLimit":       c.JiraRateLimit,
+		"recordFile":          c.RecordFile,
+		"classifier":          c.ClassifierURL != "",
+		"testProvider":        c.TestProvider,
+		"storyPointsField":    c.StoryPointsField,
+		"tshirtSizes":         c.TShirtSizes,
+		"sizeField":           c.SizeField,
+		"confidenceField":     c.ConfidenceField,
+		"replayFile":          c.ReplayFile,
+		"sites":               len(c.Sites),
+		"projects":            len(c.Projects),
+		"proxy":               proxyForDisplay(c.ProxyURL),
+		"caFile":              c.CAFile,
+		"insecureSkipVerify":  c.InsecureSkipVerify,
+		"connectTimeout":      c.ConnectTimeout.String(),
+		"responseTimeout":     c.ResponseTimeout.String(),
+		"fetchConcurrency":    c.FetchConcurrency,
+		"runtimeSettings":     settings,
+	}
+	b, err := json.MarshalIndent(sanitized, "", "  ")
+	if err != nil {
+		return textResult("Failed to encode server configuration: %v", err), nil, nil
+	}
+	return textResult("%s", b), nil, nil
+}
+
+// sortTemplates orders templates by name.
+func sortTemplates(templates []issueTemplate) {
+	sort.Slice(templates, func(a, b int) bool { return templates[a].Name < templates[b].Name })
+}
+
+// addRuntimeTemplates adds templates to the runtime settings and persists
+// them, as update-server-config does.
+func (j *JiraMCPServer) addRuntimeTemplates(templates []issueTemplate) error {
+	j.settingsMu.Lock()
+	defer j.settingsMu.Unlock()
+	updated := j.settings
+	updated.Templates = append(slices.Clone(updated.Templates), templates...)
+	sortTemplates(updated.Templates)
+	if err := j.store.Put(settingsBucket, settingsKey, updated); err != nil {
+		return fmt.Errorf("failed to save settings: %w", err)
+	}
+	j.settings = updated
+	return nil
+}
+
+// UpdateServerConfig changes the runtime settings and persists them. It is
+// only registered when JIRA_MCP_ALLOW_CONFIG_UPDATES is enabled.
+func (j *JiraMCPServer) UpdateServerConfig(ctx context.Context, req *mcp.CallToolRequest, params *UpdateServerConfigParams) (*mcp.CallToolResult, any, error) {
+	for _, t := range params.Templates {
+		if t.Name == "" || t.Summary == "" || t.IssueType == "" {
+			return textResult("Templates need at least a name, summary, and issueType"), nil, nil
+		}
+	}
+
+	j.settingsMu.Lock()
+	defer j.settingsMu.Unlock()
+	updated := j.settings
+	var changes []string
+	if params.AllowedProjects != nil {
+		updated.AllowedProjects = nil
+		for _, p := range *params.AllowedProjects {
+			updated.AllowedProjects = append(updated.AllowedProjects, strings.ToUpper(strings.TrimSpace(p)))
+		}
+		changes = append(changes, fmt.Sprintf("allowed projects: %v", updated.AllowedProjects))
+	}
+	if len(params.NamedQueries) > 0 {
+		queries := make(map[string]string, len(updated.NamedQueries)+len(params.NamedQueries))
+		for name, jql := range updated.NamedQueries {
+			queries[name] = jql
+		}
+		for name, jql := range params.NamedQueries {
+			if jql == "" {
+				delete(queries, name)
+				changes = append(changes, "removed query "+name)
+			} else {
+				queries[name] = jql
+				changes = append(changes, "set query "+name)
+			}
+		}
+		updated.NamedQueries = queries
+	}
+	if len(params.Templates) > 0 || len(params.RemoveTemplates) > 0 {
+		var templates []issueTemplate
+		for _, t := range updated.Templates {
+			replaced := slices.Contains(params.RemoveTemplates, t.Name) || slices.ContainsFunc(params.Templates, func(n issueTemplate) bool { return n.Name == t.Name })
+			if !replaced {
+				templates = append(templates, t)
+			}
+		}
+		updated.Templates = append(templates, params.Templates...)
+		sortTemplates(updated.Templates)
+		for _, t := range params.Templates {
+			changes = append(changes, "set template "+t.Name)
+		}
+		for _, name := range params.RemoveTemplates {
+			changes = append(changes, "removed template "+name)
+		}
+	}
+	if len(changes) == 0 {
+		return textResult("No settings were changed"), nil, nil
+	}
+
+	if err := j.store.Put(settingsBucket, settingsKey, updated); err != nil {
+		return textResult("Failed to save settings: %v", err), nil, nil
+	}
+	j.settings = updated
+	logger(ctx).Warn("Runtime settings updated", "changes", strings.Join(changes, "; "))
+	return textResult("Updated server settings:\n- %s", strings.Join(changes, "\n- ")), nil, nil
+}
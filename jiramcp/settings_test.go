@@ -0,0 +1,54 @@
+package jiramcp
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/andygrunwald/go-jira"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+func TestAllowedProjectsMiddleware(t *testing.T) {
+	mock := &MockJiraService{
+		DoFunc: jiraRoutes{}.do,
+		GetIssueFunc: func(_ context.Context, key string, _ *jira.GetQueryOptions) (*jira.Issue, *jira.Response, error) {
+			return &jira.Issue{Key: key, Fields: &jira.IssueFields{Project: jira.Project{Key: projectOfIssue(key)}}}, nil, nil
+		},
+	}
+	j := newTestServer(t, mock, func(c *JiraConfig) {
+		c.AllowedProjects = []string{"SMS"}
+	})
+	session := connect(t, context.Background(), j)
+
+	tests := []struct {
+		tool    string
+		args    map[string]interface{}
+		project string
+	}{
+		{"update-jira-issue", map[string]interface{}{"issueKey": "OPS-1", "summary": "x"}, "OPS"},
+		{"bulk-update-issues", map[string]interface{}{"issueKeys": []string{"SMS-1", "ops-2"}, "addLabels": []string{"x"}}, "OPS"},
+		{"create-jira-issue", map[string]interface{}{"projectKey": "OPS", "summary": "x", "issueType": "Task"}, "OPS"},
+	}
+	for _, tt := range tests {
+		result := callTool(t, session, tt.tool, tt.args)
+		want := "Project " + tt.project + " is not in the list of projects this server may access"
+		if !result.IsError || resultText(result) != want {
+			t.Errorf("%s %v: result = %q (error %v), want %q", tt.tool, tt.args, resultText(result), result.IsError, want)
+		}
+	}
+	if called(mock, "GetIssue") || called(mock, "CreateIssue") {
+		t.Errorf("refused calls reached Jira: %v", mock.Calls)
+	}
+
+	// A call on an allowed project reaches the handler.
+	callTool(t, session, "update-jira-issue", map[string]interface{}{"issueKey": "SMS-1", "summary": "x", "dryRun": true})
+	if !called(mock, "GetIssue") {
+		t.Error("a call on an allowed project did not reach the handler")
+	}
+
+	_, err := session.ReadResource(context.Background(), &mcp.ReadResourceParams{URI: "jira://issue/OPS-1/discussion-summary"})
+	if err == nil || !strings.Contains(err.Error(), "project OPS is not in the list") {
+		t.Errorf("reading an OPS resource: err = %v, want a refusal", err)
+	}
+}
@@ -0,0 +1,148 @@
+package jiramcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/andygrunwald/go-jira"
+)
+
+// DefaultSite names the Jira site of JIRA_BASE_URL among the configured sites.
+const DefaultSite = "default"
+
+// JiraSite is another Jira site the server can reach, configured in
+// JIRA_MCP_SITES_FILE.
+type JiraSite struct {
+	BaseURL  string `json:"baseUrl"`
+	Username string `json:"username,omitempty"`
+	Token    string `json:"token"`
+	// ProjectKey is the default project when the site is chosen with
+	// JIRA_PROFILE.
+	ProjectKey string `json:"projectKey,omitempty"`
+}
+
+// loadSites reads the sites file, a JSON object of sites by name.
+func loadSites(path string) (map[string]JiraSite, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read sites file: %w", err)
+	}
+	var sites map[string]JiraSite
+	if err := json.Unmarshal(b, &sites); err != nil {
+		return nil, fmt.Errorf("failed to parse sites file %s: %w", path, err)
+	}
+	for name, s := range sites {
+		if strings.EqualFold(name, DefaultSite) {
+			return nil, fmt.Errorf("sites file %s: %q is reserved for JIRA_BASE_URL", path, DefaultSite)
+		}
+		if !strings.HasPrefix(s.BaseURL, "http://") && !strings.HasPrefix(s.BaseURL, "https://") {
+			return nil, fmt.Errorf("sites file %s: site %s needs an http(s) baseUrl", path, name)
+		}
+		if s.Token == "" {
+			return nil, fmt.Errorf("sites file %s: site %s has no token", path, name)
+		}
+		s.BaseURL = strings.TrimSuffix(s.BaseURL, "/")
+		sites[name] = s
+	}
+	return sites, nil
+}
+
+// applyProfile makes the site JIRA_PROFILE names the default site. The
+// profile supplies the URL and credentials, so setting JIRA_BASE_URL,
+// JIRA_USERNAME, or JIRA_API_TOKEN as well is refused rather than mixing a
+// token with the wrong site.
+func applyProfile(config *JiraConfig) error {
+	if config.SitesFile == "" {
+		return fmt.Errorf("JIRA_PROFILE %q needs JIRA_MCP_SITES_FILE to define its site", config.Profile)
+	}
+	var names []string
+	for name, site := range config.Sites {
+		if !strings.EqualFold(name, config.Profile) {
+			names = append(names, name)
+			continue
+		}
+		for _, env := range []string{"JIRA_BASE_URL", "JIRA_USERNAME", "JIRA_API_TOKEN"} {
+			if os.Getenv(env) != "" {
+				return fmt.Errorf("JIRA_PROFILE and %s cannot be used together; the profile's site supplies the URL and credentials", env)
+			}
+		}
+		config.BaseURL, config.Username, config.APIToken = site.BaseURL, site.Username, site.Token
+		if config.ProjectKey == "" {
+			config.ProjectKey = site.ProjectKey
+		}
+		// The site is now the default one.
+		delete(config.Sites, name)
+		return nil
+	}
+	sort.Strings(names)
+	return fmt.Errorf("JIRA_PROFILE %q is not a site of %s (sites: %s)", config.Profile, config.SitesFile, strings.Join(names, ", "))
+}
+
+// VerifySite checks that the service account belongs to the site of
+// JIRA_BASE_URL: its credentials authenticate there as the configured user,
+// and it can browse at least one project. A token for one site is accepted by
+// every Atlassian Cloud site the account can log in to, so authenticating
+// alone does not show that the site is the intended one.
+func (j *JiraMCPServer) VerifySite(ctx context.Context) (*jira.User, error) {
+	user, _, err := j.service.GetSelf(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to authenticate with %s: %w", j.config.BaseURL, err)
+	}
+	if j.config.ReplayFile != "" {
+		return user, nil
+	}
+	// Users may hide their email address; the username is only compared
+	// when Jira shows something to compare it with.
+	if u := j.config.Username; u != "" && (user.EmailAddress != "" || user.Name != "") &&
+		!strings.EqualFold(u, user.EmailAddress) && !strings.EqualFold(u, user.Name) {
+		return nil, fmt.Errorf("%s authenticated the credentials as %s, not as the configured username %s", j.config.BaseURL, user.DisplayName, u)
+	}
+	var perms myPermissions
+	if _, err := j.jiraDo(ctx, "GET", "rest/api/2/mypermissions?permissions=BROWSE_PROJECTS", nil, &perms); err != nil {
+		slog.Warn("Could not check the service account's permissions", "error", err)
+	} else if !perms.Permissions["BROWSE_PROJECTS"].HavePermission {
+		return nil, fmt.Errorf("%s is not a member of %s: it cannot browse any project there; check JIRA_BASE_URL", user.DisplayName, j.config.BaseURL)
+	}
+	if self, err := url.Parse(user.Self); err == nil && self.Host != "" {
+		if base, err := url.Parse(j.config.BaseURL); err == nil && !strings.EqualFold(self.Host, base.Host) {
+			// Custom domains and proxies can explain this, so it is only
+			// logged.
+			slog.Warn("Jira reports a different base URL than JIRA_BASE_URL", "baseUrl", j.config.BaseURL, "reported", self.Scheme+"://"+self.Host)
+		}
+	}
+	return user, nil
+}
+
+// siteNames lists the configured sites, the default one first.
+func (j *JiraMCPServer) siteNames() []string {
+	names := make([]string, 0, len(j.sites))
+	for name := range j.sites {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return append([]string{DefaultSite}, names...)
+}
+
+// onSite returns a context whose Jira client talks to the named site, and
+// the site's base URL. The default site keeps the request's own client.
+func (j *JiraMCPServer) onSite(ctx context.Context, name string) (context.Context, string, error) {
+	if name == "" || strings.EqualFold(name, DefaultSite) {
+		return ctx, j.config.BaseURL, nil
+	}
+	service, ok := j.sites[name]
+	if !ok {
+		return nil, "", fmt.Errorf("unknown site %q (configured: %s)", name, strings.Join(j.siteNames(), ", "))
+	}
+	site := j.config.Sites[name]
+	ctx = context.WithValue(ctx, jiraClientKey{}, service)
+	// Cached lookups on the site must not mix with those of the default one.
+	account := jiraCredentials{Username: site.Username, Token: site.Token}
+	ctx = context.WithValue(ctx, jiraAccountKey{}, site.BaseURL+"\x00"+account.hash())
+	return ctx, site.BaseURL, nil
+}
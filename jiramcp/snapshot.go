@@ -0,0 +1,352 @@
+package jiramcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// snapshotBucket is the store bucket holding local issue snapshots.
+const snapshotBucket = "snapshots"
+
+// snapshotPropertyPrefix prefixes issue property keys used for snapshots kept
+// in Jira itself.
+const snapshotPropertyPrefix = "mcp-snapshot."
+
+// nonRestorableFields are fields that cannot be written back with a plain edit,
+// or that would duplicate content if they were.
+var nonRestorableFields = map[string]bool{
+	"comment":      true,
+	"attachment":   true,
+	"issuelinks":   true,
+	"worklog":      true,
+	"timetracking": true,
+	"issuetype":    true,
+	"project":      true,
+	"parent":       true,
+	"status":       true,
+}
+
+type SnapshotIssueParams struct {
+	IssueKey string `json:"issueKey"`
+	// Storage is "local" (default, server state directory) or "property" (an
+	// issue property on the Jira issue itself).
+	Storage string `json:"storage,omitempty"`
+	Note    string `json:"note,omitempty"`
+	DryRun  bool   `json:"dryRun,omitempty"`
+}
+
+type ListIssueSnapshotsParams struct {
+	IssueKey  string `json:"issueKey"`
+	Storage   string `json:"storage,omitempty"`
+	Verbosity string `json:"verbosity,omitempty"`
+}
+
+type RestoreIssueParams struct {
+	IssueKey string `json:"issueKey"`
+	// SnapshotID selects the snapshot to restore; defaults to the most recent.
+	SnapshotID string `json:"snapshotId,omitempty"`
+	Storage    string `json:"storage,omitempty"`
+	DryRun     bool   `json:"dryRun,omitempty"`
+}
+
+// issueSnapshot captures the editable fields of an issue at a point in time.
+type issueSnapshot struct {
+	ID       string                     `json:"id"`
+	IssueKey string                     `json:"issueKey"`
+	TakenAt  time.Time                  `json:"takenAt"`
+	Note     string                     `json:"note,omitempty"`
+	Status   string                     `json:"status,omitempty"`
+	Fields   map[string]json.RawMessage `json:"fields"`
+}
+
+type rawIssue struct {
+	Key    string                     `json:"key"`
+	Fields map[string]json.RawMessage `json:"fields"`
+}
+
+type editMeta struct {
+	Fields map[string]json.RawMessage `json:"fields"`
+}
+
+// editableFields returns the IDs of the fields the current user may edit on the issue.
+func (j *JiraMCPServer) editableFields(ctx context.Context, issueKey string) (map[string]bool, error) {
+	var meta editMeta
+	if _, err := j.jiraDo(ctx, "GET", fmt.Sprintf("rest/api/2/issue/%s/editmeta", issueKey), nil, &meta); err != nil {
+		return nil, err
+	}
+	editable := make(map[string]bool, len(meta.Fields))
+	for id := range meta.Fields {
+		editable[id] = true
+	}
+	return editable, nil
+}
+
+func validSnapshotStorage(storage string) (string, error) {
+	switch storage {
+	case "", "local":
+		return "local", nil
+	case "property":
+		return "property", nil
+	default:
+		return "", fmt.Errorf("unknown storage %q: use \"local\" or \"property\"", storage)
+	}
+}
+
+// SnapshotIssue records the current value of every editable field of an issue.
+func (j *JiraMCPServer) SnapshotIssue(ctx context.Context, req *mcp.CallToolRequest, params *SnapshotIssueParams) (*mcp.CallToolResult, any, error) {
+	storage, err := validSnapshotStorage(params.Storage)
+	if err != nil {
+		return textResult("%v", err), nil, nil
+	}
+
+	var issue rawIssue
+	if _, err := j.jiraDo(ctx, "GET", fmt.Sprintf("rest/api/2/issue/%s?fields=*all", params.IssueKey), nil, &issue); err != nil {
+		return textResult("Failed to get JIRA issue %s: %v", params.IssueKey, err), nil, nil
+	}
+	editable, err := j.editableFields(ctx, issue.Key)
+	if err != nil {
+		return textResult("Failed to get editable fields of %s: %v", issue.Key, err), nil, nil
+	}
+
+	now := time.Now().UTC()
+	snapshot := issueSnapshot{
+		ID:       now.Format("20060102T150405Z"),
+		IssueKey: issue.Key,
+		TakenAt:  now,
+		Note:     params.Note,
+		Fields:   make(map[string]json.RawMessage),
+	}
+	for id, value := range issue.Fields {
+		if editable[id] && !nonRestorableFields[id] {
+			snapshot.Fields[id] = value
+		}
+	}
+	var status struct {
+		Name string `json:"name"`
+	}
+	if json.Unmarshal(issue.Fields["status"], &status) == nil {
+		snapshot.Status = status.Name
+	}
+
+	if storage == "property" {
+		path := fmt.Sprintf("rest/api/2/issue/%s/properties/%s%s", issue.Key, snapshotPropertyPrefix, snapshot.ID)
+		if j.dryRun(params.DryRun) {
+			return dryRunResult("PUT", path, snapshot, nil), nil, nil
+		}
+		if _, err := j.jiraDo(ctx, "PUT", path, snapshot, nil); err != nil {
+			return textResult("Failed to store snapshot on %s: %v", issue.Key, err), nil, nil
+		}
+	} else if err := j.store.Put(snapshotBucket, issue.Key+"/"+snapshot.ID, snapshot); err != nil {
+		return textResult("Failed to store snapshot of %s: %v", issue.Key, err), nil, nil
+	}
+	logger(ctx).Info("Stored snapshot", "storage", storage, "snapshot", snapshot.ID, "fields", len(snapshot.Fields))
+
+	return textResult("Saved snapshot %s of %s (%d fields, storage: %s)", snapshot.ID, issue.Key, len(snapshot.Fields), storage), nil, nil
+}
+
+// snapshotIDs lists the IDs of an issue's snapshots, oldest first.
+func (j *JiraMCPServer) snapshotIDs(ctx context.Context, issueKey, storage string) ([]string, error) {
+	var ids []string
+	if storage == "property" {
+		var props struct {
+			Keys []struct {
+				Key string `json:"key"`
+			} `json:"keys"`
+		}
+		if _, err := j.jiraDo(ctx, "GET", fmt.Sprintf("rest/api/2/issue/%s/properties", issueKey), nil, &props); err != nil {
+			return nil, err
+		}
+		for _, k := range props.Keys {
+			if id, ok := strings.CutPrefix(k.Key, snapshotPropertyPrefix); ok {
+				ids = append(ids, id)
+			}
+		}
+	} else {
+		keys, err := j.store.Keys(snapshotBucket)
+		if err != nil {
+			return nil, err
+		}
+		for _, k := range keys {
+			if id, ok := strings.CutPrefix(k, issueKey+"/"); ok {
+				ids = append(ids, id)
+			}
+		}
+	}
+	// IDs are UTC timestamps, so lexical order is chronological.
+	sort.Strings(ids)
+	return ids, nil
+}
+
+// loadSnapshot fetches a single snapshot from the chosen storage.
+func (j *JiraMCPServer) loadSnapshot(ctx context.Context, issueKey, id, storage string) (*issueSnapshot, error) {
+	var snapshot issueSnapshot
+	if storage == "property" {
+		var prop struct {
+			Value issueSnapshot `json:"value"`
+		}
+		path := fmt.Sprintf("rest/api/2/issue/%s/properties/%s%s", issueKey, snapshotPropertyPrefix, id)
+		if _, err := j.jiraDo(ctx, "GET", path, nil, &prop); err != nil {
+			return nil, err
+		}
+		return &prop.Value, nil
+	}
+	found, err := j.store.Get(snapshotBucket, issueKey+"/"+id, &snapshot)
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return nil, fmt.Errorf("snapshot %s of %s not found", id, issueKey)
+	}
+	return &snapshot, nil
+}
+
+// ListIssueSnapshots lists the snapshots available for an issue.
+func (j *JiraMCPServer) ListIssueSnapshots(ctx context.Context, req *mcp.CallToolRequest, params *ListIssueSnapshotsParams) (*mcp.CallToolResult, any, error) {
+	storage, err := validSnapshotStorage(params.Storage)
+	if err != nil {
+		return textResult("%v", err), nil, nil
+	}
+	issueKey := strings.ToUpper(params.IssueKey)
+	ids, err := j.snapshotIDs(ctx, issueKey, storage)
+	if err != nil {
+		return textResult("Failed to list snapshots of %s: %v", issueKey, err), nil, nil
+	}
+	if len(ids) == 0 {
+		return textResult("No %s snapshots found for %s", storage, issueKey), nil, nil
+	}
+
+	verbosity := j.verbosity(params.Verbosity)
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "Snapshots of %s (%s storage):\n", issueKey, storage)
+	for _, id := range ids {
+		if verbosity == VerbosityMinimal {
+			fmt.Fprintf(&sb, "- %s\n", id)
+			continue
+		}
+		snapshot, err := j.loadSnapshot(ctx, issueKey, id, storage)
+		if err != nil {
+			fmt.Fprintf(&sb, "- %s (unreadable: %v)\n", id, err)
+			continue
+		}
+		fmt.Fprintf(&sb, "- %s: %d fields, status %s", id, len(snapshot.Fields), snapshot.Status)
+		if snapshot.Note != "" {
+			fmt.Fprintf(&sb, " — %s", snapshot.Note)
+		}
+		if verbosity == VerbosityFull {
+			names := make([]string, 0, len(snapshot.Fields))
+			for name := range snapshot.Fields {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+			fmt.Fprintf(&sb, "\n    taken %s; fields: %s", snapshot.TakenAt.UTC().Format("2006-01-02 15:04Z"), strings.Join(names, ", "))
+		}
+		sb.WriteString("\n")
+	}
+	return textResult("%s", sb.String()), nil, nil
+}
+
+// restorableValue reduces a field value as returned by the GET issue API to the
+// form accepted by the edit API: objects are referenced by id or accountId.
+func restorableValue(raw json.RawMessage) interface{} {
+	var v interface{}
+	if err := json.Unmarshal(raw, &v); err != nil {
+		return nil
+	}
+	return toReference(v)
+}
+
+func toReference(v interface{}) interface{} {
+	switch t := v.(type) {
+	case map[string]interface{}:
+		if id, ok := t["accountId"]; ok {
+			return map[string]interface{}{"accountId": id}
+		}
+		if id, ok := t["id"]; ok {
+			return map[string]interface{}{"id": id}
+		}
+		if name, ok := t["name"]; ok {
+			return map[string]interface{}{"name": name}
+		}
+		return t
+	case []interface{}:
+		refs := make([]interface{}, len(t))
+		for i, item := range t {
+			refs[i] = toReference(item)
+		}
+		return refs
+	default:
+		return v
+	}
+}
+
+// RestoreIssueFromSnapshot writes the field values of a snapshot back to the
+// issue. Fields that are no longer editable are skipped and reported; the
+// status is reported but not changed, since that requires a transition.
+func (j *JiraMCPServer) RestoreIssueFromSnapshot(ctx context.Context, req *mcp.CallToolRequest, params *RestoreIssueParams) (*mcp.CallToolResult, any, error) {
+	storage, err := validSnapshotStorage(params.Storage)
+	if err != nil {
+		return textResult("%v", err), nil, nil
+	}
+	issueKey := strings.ToUpper(params.IssueKey)
+
+	id := params.SnapshotID
+	if id == "" {
+		ids, err := j.snapshotIDs(ctx, issueKey, storage)
+		if err != nil {
+			return textResult("Failed to list snapshots of %s: %v", issueKey, err), nil, nil
+		}
+		if len(ids) == 0 {
+			return textResult("No %s snapshots found for %s", storage, issueKey), nil, nil
+		}
+		id = ids[len(ids)-1]
+	}
+	snapshot, err := j.loadSnapshot(ctx, issueKey, id, storage)
+	if err != nil {
+		return textResult("Failed to load snapshot %s of %s: %v", id, issueKey, err), nil, nil
+	}
+
+	editable, err := j.editableFields(ctx, issueKey)
+	if err != nil {
+		return textResult("Failed to get editable fields of %s: %v", issueKey, err), nil, nil
+	}
+	fields := make(map[string]interface{})
+	var skipped []string
+	for fieldID, raw := range snapshot.Fields {
+		if !editable[fieldID] {
+			skipped = append(skipped, fieldID)
+			continue
+		}
+		fields[fieldID] = restorableValue(raw)
+	}
+	sort.Strings(skipped)
+
+	path := fmt.Sprintf("rest/api/2/issue/%s", issueKey)
+	payload := map[string]interface{}{"fields": fields}
+	if j.dryRun(params.DryRun) {
+		var problems []string
+		if len(skipped) > 0 {
+			problems = append(problems, fmt.Sprintf("fields no longer editable and would be skipped: %s", strings.Join(skipped, ", ")))
+		}
+		return dryRunResult("PUT", path, payload, problems), nil, nil
+	}
+	if _, err := j.jiraDo(ctx, "PUT", path, payload, nil); err != nil {
+		return textResult("Failed to restore %s from snapshot %s: %v", issueKey, id, err), nil, nil
+	}
+	logger(ctx).Info("Restored issue from snapshot", "snapshot", id, "fields", len(fields))
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "Restored %d fields of %s from snapshot %s", len(fields), issueKey, id)
+	if len(skipped) > 0 {
+		fmt.Fprintf(&sb, "\nSkipped fields that are no longer editable: %s", strings.Join(skipped, ", "))
+	}
+	if snapshot.Status != "" {
+		fmt.Fprintf(&sb, "\nStatus at snapshot time was %q; status is not restored automatically.", snapshot.Status)
+	}
+	return textResult("%s", sb.String()), nil, nil
+}
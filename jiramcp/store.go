@@ -0,0 +1,147 @@
+package jiramcp
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+)
+
+// Storage backends selectable with JIRA_MCP_STORE.
+const (
+	StoreFile     = "file"
+	StoreBolt     = "bolt"
+	StorePostgres = "postgres"
+)
+
+// Store is a small persistent key/value store for server-side state such as
+// issue snapshots, runtime settings, and rotation positions. Values are
+// grouped into buckets and encoded as JSON. The file and bbolt backends keep
+// state on local disk; the Postgres backend lets several replicas share it.
+type Store interface {
+	// Put stores v under key in bucket.
+	Put(bucket, key string, v interface{}) error
+	// Get decodes the value stored under key into v. It reports whether the
+	// key was found.
+	Get(bucket, key string, v interface{}) (bool, error)
+	// Delete removes key from bucket.
+	Delete(bucket, key string) error
+	// Keys returns the sorted keys in bucket.
+	Keys(bucket string) ([]string, error)
+	Close() error
+}
+
+// defaultStateDir returns the directory used for local state when
+// JIRA_MCP_STATE_DIR is not set.
+func defaultStateDir() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		dir = os.TempDir()
+	}
+	return filepath.Join(dir, ServerName)
+}
+
+// openStore opens the storage backend selected by the configuration.
+func openStore(config *JiraConfig) (Store, error) {
+	switch config.Store {
+	case StoreBolt:
+		return openBoltStore(config.StateDir)
+	case StorePostgres:
+		return openPostgresStore(config.StoreDSN)
+	default:
+		return openFileStore(config.StateDir)
+	}
+}
+
+// fileStore keeps all state in a single JSON file that is rewritten
+// atomically on every change. It is the default backend.
+type fileStore struct {
+	mu   sync.Mutex
+	path string
+	data map[string]map[string]json.RawMessage
+}
+
+// openFileStore loads the store file in dir, creating the directory if needed.
+func openFileStore(dir string) (*fileStore, error) {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, fmt.Errorf("failed to create state directory %s: %w", dir, err)
+	}
+	s := &fileStore{
+		path: filepath.Join(dir, "state.json"),
+		data: make(map[string]map[string]json.RawMessage),
+	}
+	b, err := os.ReadFile(s.path)
+	if os.IsNotExist(err) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read state file %s: %w", s.path, err)
+	}
+	if err := json.Unmarshal(b, &s.data); err != nil {
+		return nil, fmt.Errorf("failed to parse state file %s: %w", s.path, err)
+	}
+	return s, nil
+}
+
+func (s *fileStore) Put(bucket, key string, v interface{}) error {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.data[bucket] == nil {
+		s.data[bucket] = make(map[string]json.RawMessage)
+	}
+	s.data[bucket][key] = b
+	return s.save()
+}
+
+func (s *fileStore) Get(bucket, key string, v interface{}) (bool, error) {
+	s.mu.Lock()
+	b, ok := s.data[bucket][key]
+	s.mu.Unlock()
+	if !ok {
+		return false, nil
+	}
+	return true, json.Unmarshal(b, v)
+}
+
+func (s *fileStore) Delete(bucket, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.data[bucket][key]; !ok {
+		return nil
+	}
+	delete(s.data[bucket], key)
+	return s.save()
+}
+
+func (s *fileStore) Keys(bucket string) ([]string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	keys := make([]string, 0, len(s.data[bucket]))
+	for k := range s.data[bucket] {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys, nil
+}
+
+func (s *fileStore) Close() error { return nil }
+
+// save writes the store to disk via a temporary file so a crash never leaves a
+// truncated state file behind. The caller must hold s.mu.
+func (s *fileStore) save() error {
+	b, err := json.MarshalIndent(s.data, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp := s.path + ".tmp"
+	if err := os.WriteFile(tmp, b, 0o600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, s.path)
+}
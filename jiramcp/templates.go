@@ -0,0 +1,285 @@
+package jiramcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/andygrunwald/go-jira"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// templateVar matches {{name}} placeholders in issue templates.
+var templateVar = regexp.MustCompile(`\{\{\s*([A-Za-z0-9_.-]+)\s*\}\}`)
+
+// issueTemplate is a named recipe for creating issues that follow team
+// conventions. String values may contain {{variable}} placeholders.
+type issueTemplate struct {
+	Name         string                 `json:"name"`
+	Description  string                 `json:"description,omitempty"`
+	IssueType    string                 `json:"issueType"`
+	Priority     string                 `json:"priority,omitempty"`
+	Summary      string                 `json:"summary"`
+	Body         string                 `json:"body,omitempty"`
+	Labels       []string               `json:"labels,omitempty"`
+	Components   []string               `json:"components,omitempty"`
+	CustomFields map[string]interface{} `json:"customFields,omitempty"`
+	// Defaults supplies values for variables the caller does not set.
+	Defaults map[string]string `json:"defaults,omitempty"`
+	// AssignOnCall assigns issues created from the template to the current
+	// on-call of OnCallSchedule (or the default schedule) when an on-call
+	// provider is configured.
+	AssignOnCall   bool   `json:"assignOnCall,omitempty"`
+	OnCallSchedule string `json:"onCallSchedule,omitempty"`
+}
+
+type ListIssueTemplatesParams struct{}
+
+type CreateIssueFromTemplateParams struct {
+	Template string `json:"template"`
+	// Variables fills the template's {{variable}} placeholders.
+	Variables  map[string]string `json:"variables,omitempty"`
+	ProjectKey string            `json:"projectKey,omitempty"`
+	// Labels are added to the template's labels.
+	Labels []string `json:"labels,omitempty"`
+	DryRun bool     `json:"dryRun,omitempty"`
+}
+
+// issueTemplates loads the templates bundled in the assets directory, those
+// defined in JIRA_MCP_TEMPLATES_FILE, and those added at runtime with
+// update-server-config. Later sources take precedence by name.
+func (j *JiraMCPServer) issueTemplates() (map[string]*issueTemplate, error) {
+	templates := make(map[string]*issueTemplate)
+	names, err := j.listAssets("templates", ".json")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list templates: %w", err)
+	}
+	for _, name := range names {
+		b, err := fs.ReadFile(j.assets, path.Join("templates", name+".json"))
+		if err != nil {
+			return nil, err
+		}
+		var t issueTemplate
+		if err := json.Unmarshal(b, &t); err != nil {
+			return nil, fmt.Errorf("invalid template %s: %w", name, err)
+		}
+		if t.Name == "" {
+			t.Name = name
+		}
+		templates[t.Name] = &t
+	}
+
+	if j.config.TemplatesFile != "" {
+		b, err := os.ReadFile(j.config.TemplatesFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read templates file: %w", err)
+		}
+		var configured []issueTemplate
+		if err := json.Unmarshal(b, &configured); err != nil {
+			return nil, fmt.Errorf("failed to parse templates file %s: %w", j.config.TemplatesFile, err)
+		}
+		for i := range configured {
+			if configured[i].Name == "" {
+				return nil, fmt.Errorf("template %d in %s has no name", i+1, j.config.TemplatesFile)
+			}
+			templates[configured[i].Name] = &configured[i]
+		}
+	}
+
+	added := j.currentSettings().Templates
+	for i := range added {
+		templates[added[i].Name] = &added[i]
+	}
+	return templates, nil
+}
+
+// variables returns the sorted names of the placeholders used by t.
+func (t *issueTemplate) variables() []string {
+	seen := make(map[string]bool)
+	collect := func(s string) {
+		for _, m := range templateVar.FindAllStringSubmatch(s, -1) {
+			seen[m[1]] = true
+		}
+	}
+	collect(t.Summary)
+	collect(t.Body)
+	for _, l := range t.Labels {
+		collect(l)
+	}
+	for _, v := range t.CustomFields {
+		if s, ok := v.(string); ok {
+			collect(s)
+		}
+	}
+	names := make([]string, 0, len(seen))
+	for name := range seen {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// expand substitutes variables into s. Placeholders without a value are
+// collected in missing and left in place.
+func expand(s string, vars map[string]string, missing map[string]bool) string {
+	return templateVar.ReplaceAllStringFunc(s, func(m string) string {
+		name := templateVar.FindStringSubmatch(m)[1]
+		if v, ok := vars[name]; ok {
+			return v
+		}
+		missing[name] = true
+		return m
+	})
+}
+
+// ListIssueTemplates describes the available issue templates and the
+// variables each one expects.
+func (j *JiraMCPServer) ListIssueTemplates(ctx context.Context, req *mcp.CallToolRequest, params *ListIssueTemplatesParams) (*mcp.CallToolResult, any, error) {
+	templates, err := j.issueTemplates()
+	if err != nil {
+		return textResult("Failed to load issue templates: %v", err), nil, nil
+	}
+	if len(templates) == 0 {
+		return textResult("No issue templates are defined"), nil, nil
+	}
+	names := make([]string, 0, len(templates))
+	for name := range templates {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "%d issue template(s):\n", len(names))
+	for _, name := range names {
+		t := templates[name]
+		fmt.Fprintf(&sb, "- %s (%s)", t.Name, t.IssueType)
+		if t.Description != "" {
+			fmt.Fprintf(&sb, ": %s", t.Description)
+		}
+		sb.WriteString("\n")
+		if vars := t.variables(); len(vars) > 0 {
+			optional := make([]string, 0, len(vars))
+			for i, v := range vars {
+				if _, ok := t.Defaults[v]; ok {
+					optional = append(optional, v)
+					vars[i] = v + "?"
+				}
+			}
+			fmt.Fprintf(&sb, "    variables: %s", strings.Join(vars, ", "))
+			if len(optional) > 0 {
+				sb.WriteString(" (? = has a default)")
+			}
+			sb.WriteString("\n")
+		}
+	}
+	return textResult("%s", sb.String()), nil, nil
+}
+
+// CreateIssueFromTemplate fills in a template's variables and creates the
+// resulting issue through create-jira-issue.
+func (j *JiraMCPServer) CreateIssueFromTemplate(ctx context.Context, req *mcp.CallToolRequest, params *CreateIssueFromTemplateParams) (*mcp.CallToolResult, any, error) {
+	templates, err := j.issueTemplates()
+	if err != nil {
+		return textResult("Failed to load issue templates: %v", err), nil, nil
+	}
+	t, ok := templates[params.Template]
+	if !ok {
+		return textResult("Unknown issue template %q; use list-issue-templates to see the available templates", params.Template), nil, nil
+	}
+
+	vars := make(map[string]string, len(t.Defaults)+len(params.Variables))
+	for k, v := range t.Defaults {
+		vars[k] = v
+	}
+	for k, v := range params.Variables {
+		vars[k] = v
+	}
+	missing := make(map[string]bool)
+	create := &CreateJiraIssueParams{
+		Summary:     expand(t.Summary, vars, missing),
+		Description: expand(t.Body, vars, missing),
+		IssueType:   t.IssueType,
+		Priority:    t.Priority,
+		ProjectKey:  params.ProjectKey,
+		Components:  t.Components,
+		DryRun:      params.DryRun,
+	}
+	for _, l := range append(append([]string(nil), t.Labels...), params.Labels...) {
+		create.Labels = append(create.Labels, expand(l, vars, missing))
+	}
+	if len(t.CustomFields) > 0 {
+		create.CustomFields = make(map[string]interface{}, len(t.CustomFields))
+		for id, v := range t.CustomFields {
+			if s, ok := v.(string); ok {
+				v = expand(s, vars, missing)
+			}
+			create.CustomFields[id] = v
+		}
+	}
+	if len(missing) > 0 {
+		names := make([]string, 0, len(missing))
+		for name := range missing {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		return textResult("Template %q needs values for: %s", t.Name, strings.Join(names, ", ")), nil, nil
+	}
+
+	if t.AssignOnCall && j.config.OnCallProvider != "" {
+		user, err := j.onCallAssignee(ctx, t.OnCallSchedule)
+		if err != nil {
+			// Creating the ticket matters more than who gets it first.
+			logger(ctx).Warn("Could not assign issue from template to on-call", "template", t.Name, "error", err)
+		} else {
+			create.Assignee = &jira.User{AccountID: user.AccountID, Name: user.Name}
+		}
+	}
+
+	return j.CreateJiraIssue(ctx, req, create)
+}
+
+// TemplateIndex serves jira://templates, the names of all issue templates.
+func (j *JiraMCPServer) TemplateIndex(ctx context.Context, req *mcp.ReadResourceRequest) (*mcp.ReadResourceResult, error) {
+	templates, err := j.issueTemplates()
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(templates))
+	for name := range templates {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return &mcp.ReadResourceResult{
+		Contents: []*mcp.ResourceContents{
+			{URI: req.Params.URI, MIMEType: "text/plain", Text: strings.Join(names, "\n")},
+		},
+	}, nil
+}
+
+// TemplateResource serves jira://templates/{name}, the definition of one
+// issue template.
+func (j *JiraMCPServer) TemplateResource(ctx context.Context, req *mcp.ReadResourceRequest) (*mcp.ReadResourceResult, error) {
+	templates, err := j.issueTemplates()
+	if err != nil {
+		return nil, err
+	}
+	t, ok := templates[strings.TrimPrefix(req.Params.URI, "jira://templates/")]
+	if !ok {
+		return nil, mcp.ResourceNotFoundError(req.Params.URI)
+	}
+	b, err := json.MarshalIndent(t, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return &mcp.ReadResourceResult{
+		Contents: []*mcp.ResourceContents{
+			{URI: req.Params.URI, MIMEType: "application/json", Text: string(b)},
+		},
+	}, nil
+}
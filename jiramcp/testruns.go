@@ -0,0 +1,412 @@
+package jiramcp
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/andygrunwald/go-jira"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// Test management apps selectable with JIRA_MCP_TEST_PROVIDER.
+const (
+	// TestsXray is Xray Cloud, reached through its GraphQL API.
+	TestsXray = "xray"
+	// TestsXrayServer is Xray on Jira Server or Data Center, whose REST API
+	// is part of Jira.
+	TestsXrayServer = "xray-server"
+	// TestsZephyr is Zephyr Scale Cloud.
+	TestsZephyr = "zephyr"
+)
+
+// maxLinkedTests bounds the tests get-test-status reports for one issue.
+const maxLinkedTests = 100
+
+type GetTestStatusParams struct {
+	IssueKey string `json:"issueKey"`
+}
+
+// linkedTest is a test covering an issue, with its most recent run.
+type linkedTest struct {
+	Key     string
+	Summary string
+	// issueID is the Jira issue ID of Xray tests.
+	issueID string
+	// Status is that of the latest run, "" when the test never ran;
+	// Execution is the test execution or cycle of that run.
+	Status    string
+	Execution string
+	Finished  time.Time
+}
+
+// passed reports whether the test's latest run passed.
+func (t *linkedTest) passed() bool {
+	switch strings.ToUpper(t.Status) {
+	case "PASS", "PASSED":
+		return true
+	}
+	return false
+}
+
+// xraySession holds the Xray Cloud API token, which is valid for a day.
+type xraySession struct {
+	mu      sync.Mutex
+	token   string
+	expires time.Time
+}
+
+// testAPI calls the test management API at endpoint and decodes the answer
+// into v.
+func testAPI(ctx context.Context, method, endpoint string, header http.Header, body, v interface{}) error {
+	var reader io.Reader
+	if body != nil {
+		b, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(b)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, endpoint, reader)
+	if err != nil {
+		return err
+	}
+	if header != nil {
+		req.Header = header.Clone()
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	req.Header.Set("Accept", "application/json")
+	resp, err := outboundHTTPClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	b, err := io.ReadAll(io.LimitReader(resp.Body, 4<<20))
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("%s: %s", resp.Status, strings.TrimSpace(string(b)))
+	}
+	return json.Unmarshal(b, v)
+}
+
+// testAPIURL returns the base URL of the test management API.
+func (j *JiraMCPServer) testAPIURL() string {
+	if j.config.TestAPIURL != "" {
+		return strings.TrimSuffix(j.config.TestAPIURL, "/")
+	}
+	if j.config.TestProvider == TestsZephyr {
+		return "https://api.zephyrscale.smartbear.com/v2"
+	}
+	return "https://xray.cloud.getxray.app"
+}
+
+// xrayAuth returns the header authenticating Xray Cloud requests, signing
+// in with the client ID and secret when the last token expired.
+func (j *JiraMCPServer) xrayAuth(ctx context.Context) (http.Header, error) {
+	j.xray.mu.Lock()
+	defer j.xray.mu.Unlock()
+	if j.xray.token == "" || time.Now().After(j.xray.expires) {
+		var token string
+		err := testAPI(ctx, "POST", j.testAPIURL()+"/api/v2/authenticate", nil, map[string]string{
+			"client_id":     j.config.TestClientID,
+			"client_secret": j.config.TestAPIToken,
+		}, &token)
+		if err != nil {
+			return nil, fmt.Errorf("Xray sign-in failed: %w", err)
+		}
+		j.xray.token, j.xray.expires = token, time.Now().Add(23*time.Hour)
+	}
+	header := http.Header{}
+	header.Set("Authorization", "Bearer "+j.xray.token)
+	return header, nil
+}
+
+// xrayLinkedTests returns the Xray tests linked to an issue. Xray links
+// tests to the issues they cover with Jira issue links, so they are found
+// among the issue's links by their Test issue type. A Test itself is its own
+// only test.
+func (j *JiraMCPServer) xrayLinkedTests(ctx context.Context, issueKey string) ([]*linkedTest, error) {
+	var issue jira.Issue
+	if _, err := j.jiraDo(ctx, "GET", fmt.Sprintf("rest/api/2/issue/%s?fields=summary,issuetype,issuelinks", issueKey), nil, &issue); err != nil {
+		return nil, err
+	}
+	if issue.Fields == nil {
+		return nil, nil
+	}
+	if strings.EqualFold(issue.Fields.Type.Name, "Test") {
+		return []*linkedTest{{Key: issue.Key, Summary: issue.Fields.Summary, issueID: issue.ID}}, nil
+	}
+	var tests []*linkedTest
+	seen := make(map[string]bool)
+	for _, link := range issue.Fields.IssueLinks {
+		other := link.OutwardIssue
+		if other == nil {
+			other = link.InwardIssue
+		}
+		if other == nil || other.Fields == nil || !strings.EqualFold(other.Fields.Type.Name, "Test") || seen[other.Key] {
+			continue
+		}
+		seen[other.Key] = true
+		tests = append(tests, &linkedTest{Key: other.Key, Summary: other.Fields.Summary, issueID: other.ID})
+	}
+	return tests, nil
+}
+
+// xrayCloudRuns fills in the latest Xray Cloud run of each test.
+func (j *JiraMCPServer) xrayCloudRuns(ctx context.Context, tests []*linkedTest) error {
+	header, err := j.xrayAuth(ctx)
+	if err != nil {
+		return err
+	}
+	byID := make(map[string]*linkedTest, len(tests))
+	ids := make([]string, 0, len(tests))
+	for _, t := range tests {
+		byID[t.issueID] = t
+		ids = append(ids, t.issueID)
+	}
+	const query = `query($ids: [String], $limit: Int!, $start: Int) {
+  getTestRuns(testIssueIds: $ids, limit: $limit, start: $start) {
+    total
+    results {
+      status { name }
+      finishedOn
+      test { issueId }
+      testExecution { jira(fields: ["key"]) }
+    }
+  }
+}`
+	for start := 0; ; {
+		var result struct {
+			Data struct {
+				GetTestRuns struct {
+					Total   int `json:"total"`
+					Results []struct {
+						Status struct {
+							Name string `json:"name"`
+						} `json:"status"`
+						FinishedOn string `json:"finishedOn"`
+						Test       struct {
+							IssueID string `json:"issueId"`
+						} `json:"test"`
+						TestExecution struct {
+							Jira struct {
+								Key string `json:"key"`
+							} `json:"jira"`
+						} `json:"testExecution"`
+					} `json:"results"`
+				} `json:"getTestRuns"`
+			} `json:"data"`
+			Errors []struct {
+				Message string `json:"message"`
+			} `json:"errors"`
+		}
+		err := testAPI(ctx, "POST", j.testAPIURL()+"/api/v2/graphql", header, map[string]interface{}{
+			"query":     query,
+			"variables": map[string]interface{}{"ids": ids, "limit": 100, "start": start},
+		}, &result)
+		if err != nil {
+			return fmt.Errorf("Xray query failed: %w", err)
+		}
+		if len(result.Errors) > 0 {
+			return fmt.Errorf("Xray query failed: %s", result.Errors[0].Message)
+		}
+		runs := result.Data.GetTestRuns
+		for _, r := range runs.Results {
+			finished, _ := time.Parse(time.RFC3339, r.FinishedOn)
+			recordRun(byID[r.Test.IssueID], r.Status.Name, r.TestExecution.Jira.Key, finished)
+		}
+		start += len(runs.Results)
+		if len(runs.Results) == 0 || start >= runs.Total {
+			return nil
+		}
+	}
+}
+
+// xrayServerRuns fills in the latest Xray Server or Data Center run of each
+// test.
+func (j *JiraMCPServer) xrayServerRuns(ctx context.Context, tests []*linkedTest) error {
+	return forEachParallel(ctx, j.fetchConcurrency(), len(tests), func(ctx context.Context, i int) error {
+		var runs []struct {
+			Status      string `json:"status"`
+			TestExecKey string `json:"testExecKey"`
+			FinishedOn  string `json:"finishedOn"`
+		}
+		if _, err := j.jiraDo(ctx, "GET", "rest/raven/1.0/testruns?testKey="+url.QueryEscape(tests[i].Key), nil, &runs); err != nil {
+			return fmt.Errorf("runs of %s: %w", tests[i].Key, err)
+		}
+		for _, r := range runs {
+			finished, _ := time.Parse("2006-01-02T15:04:05-0700", r.FinishedOn)
+			if finished.IsZero() {
+				finished, _ = time.Parse(time.RFC3339, r.FinishedOn)
+			}
+			recordRun(tests[i], r.Status, r.TestExecKey, finished)
+		}
+		return nil
+	})
+}
+
+// zephyrTests returns the Zephyr Scale test cases linked to an issue, with
+// their latest executions.
+func (j *JiraMCPServer) zephyrTests(ctx context.Context, issueKey string) ([]*linkedTest, error) {
+	header := http.Header{}
+	header.Set("Authorization", "Bearer "+j.config.TestAPIToken)
+	base := j.testAPIURL()
+
+	var links []struct {
+		Key string `json:"key"`
+	}
+	if err := testAPI(ctx, "GET", fmt.Sprintf("%s/issuelinks/%s/testcases", base, url.PathEscape(issueKey)), header, nil, &links); err != nil {
+		return nil, fmt.Errorf("Zephyr Scale test cases: %w", err)
+	}
+	if len(links) == 0 {
+		return nil, nil
+	}
+	var statuses struct {
+		Values []struct {
+			ID   int    `json:"id"`
+			Name string `json:"name"`
+		} `json:"values"`
+	}
+	if err := testAPI(ctx, "GET", base+"/statuses?statusType=TEST_EXECUTION&maxResults=1000", header, nil, &statuses); err != nil {
+		return nil, fmt.Errorf("Zephyr Scale statuses: %w", err)
+	}
+	statusNames := make(map[int]string, len(statuses.Values))
+	for _, s := range statuses.Values {
+		statusNames[s.ID] = s.Name
+	}
+
+	tests := make([]*linkedTest, min(len(links), maxLinkedTests))
+	err := forEachParallel(ctx, j.fetchConcurrency(), len(tests), func(ctx context.Context, i int) error {
+		t := &linkedTest{Key: links[i].Key}
+		tests[i] = t
+		var testCase struct {
+			Name string `json:"name"`
+		}
+		if err := testAPI(ctx, "GET", fmt.Sprintf("%s/testcases/%s", base, url.PathEscape(t.Key)), header, nil, &testCase); err != nil {
+			return fmt.Errorf("Zephyr Scale test case %s: %w", t.Key, err)
+		}
+		t.Summary = testCase.Name
+		var executions struct {
+			Values []struct {
+				Key                 string `json:"key"`
+				ActualEndDate       string `json:"actualEndDate"`
+				TestExecutionStatus struct {
+					ID int `json:"id"`
+				} `json:"testExecutionStatus"`
+			} `json:"values"`
+		}
+		if err := testAPI(ctx, "GET", fmt.Sprintf("%s/testexecutions?testCase=%s&maxResults=1000", base, url.QueryEscape(t.Key)), header, nil, &executions); err != nil {
+			return fmt.Errorf("Zephyr Scale executions of %s: %w", t.Key, err)
+		}
+		for _, e := range executions.Values {
+			finished, _ := time.Parse(time.RFC3339, e.ActualEndDate)
+			recordRun(t, statusNames[e.TestExecutionStatus.ID], e.Key, finished)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return tests, nil
+}
+
+// recordRun keeps a run as the test's latest when it finished after the
+// latest seen so far. Runs that never finished only count when there is no
+// other run.
+func recordRun(t *linkedTest, status, execution string, finished time.Time) {
+	if t == nil || status == "" {
+		return
+	}
+	if t.Status == "" || finished.After(t.Finished) {
+		t.Status, t.Execution, t.Finished = status, execution, finished
+	}
+}
+
+// GetTestStatus lists the tests covering an issue in Xray or Zephyr Scale
+// with the status of their latest run, and says whether the issue is fully
+// tested: it has tests and the latest run of each passed.
+func (j *JiraMCPServer) GetTestStatus(ctx context.Context, req *mcp.CallToolRequest, params *GetTestStatusParams) (*mcp.CallToolResult, any, error) {
+	issueKey := strings.ToUpper(strings.TrimSpace(params.IssueKey))
+	if issueKey == "" {
+		return textResult("issueKey is required"), nil, nil
+	}
+	var (
+		tests    []*linkedTest
+		err      error
+		provider string
+	)
+	switch j.config.TestProvider {
+	case "":
+		return textResult("No test management app is configured (set JIRA_MCP_TEST_PROVIDER)"), nil, nil
+	case TestsXray, TestsXrayServer:
+		provider = "Xray"
+		tests, err = j.xrayLinkedTests(ctx, issueKey)
+		if err == nil && len(tests) > maxLinkedTests {
+			tests = tests[:maxLinkedTests]
+		}
+		if err == nil && len(tests) > 0 {
+			if j.config.TestProvider == TestsXray {
+				err = j.xrayCloudRuns(ctx, tests)
+			} else {
+				err = j.xrayServerRuns(ctx, tests)
+			}
+		}
+	case TestsZephyr:
+		provider = "Zephyr Scale"
+		tests, err = j.zephyrTests(ctx, issueKey)
+	}
+	if err != nil {
+		return textResult("Failed to get the tests of %s: %v", issueKey, err), nil, nil
+	}
+	if len(tests) == 0 {
+		return textResult("%s has no tests linked in %s, so it is not tested.", issueKey, provider), nil, nil
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "%s has %d linked test(s) in %s:\n", issueKey, len(tests), provider)
+	passed, failed, notRun := 0, 0, 0
+	for _, t := range tests {
+		fmt.Fprintf(&sb, "- %s", t.Key)
+		if t.Summary != "" {
+			fmt.Fprintf(&sb, " %s", t.Summary)
+		}
+		switch {
+		case t.Status == "":
+			notRun++
+			sb.WriteString(": never run\n")
+			continue
+		case t.passed():
+			passed++
+		default:
+			failed++
+		}
+		fmt.Fprintf(&sb, ": %s", t.Status)
+		if t.Execution != "" {
+			fmt.Fprintf(&sb, " in %s", t.Execution)
+		}
+		if !t.Finished.IsZero() {
+			fmt.Fprintf(&sb, " on %s", t.Finished.Format("2006-01-02"))
+		}
+		sb.WriteString("\n")
+	}
+	if passed == len(tests) {
+		fmt.Fprintf(&sb, "Fully tested: the latest run of all %d test(s) passed.\n", passed)
+	} else {
+		fmt.Fprintf(&sb, "Not fully tested: %d passed, %d did not pass, %d never run.\n", passed, failed, notRun)
+	}
+	if len(tests) == maxLinkedTests {
+		fmt.Fprintf(&sb, "Only the first %d tests were checked.\n", maxLinkedTests)
+	}
+	return textResult("%s", sb.String()), nil, nil
+}
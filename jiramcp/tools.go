@@ -0,0 +1,117 @@
+package jiramcp
+
+import (
+	"log/slog"
+	"slices"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// Tool personas selectable with JIRA_MODE.
+const (
+	ModeFull      = "full"
+	ModeCommenter = "commenter"
+)
+
+// commenterTools are the mutating tools still available in commenter mode.
+var commenterTools = map[string]bool{
+	"add-comment":         true,
+	"add-request-comment": true,
+}
+
+// adminTools are only registered when JIRA_MCP_ALLOW_CONFIG_UPDATES is set.
+var adminTools = map[string]bool{
+	"update-server-config": true,
+}
+
+// deleteTools are only registered when JIRA_MCP_ALLOW_DELETE is set.
+var deleteTools = map[string]bool{
+	"delete-jira-issue":  true,
+	"archive-jira-issue": true,
+}
+
+// addTool registers a tool on the server unless the configured policy excludes
+// it. Tools are considered mutating unless annotated with ReadOnlyHint.
+func addTool[In any](j *JiraMCPServer, t *mcp.Tool, h mcp.ToolHandlerFor[In, any]) {
+	j.toolNames[t.Name] = true
+	if !j.toolAllowed(t) {
+		slog.Debug("Tool is disabled by configuration", "tool", t.Name)
+		return
+	}
+	if reason, ok := j.unavailableTools[t.Name]; ok {
+		slog.Debug("Tool is hidden", "tool", t.Name, "reason", reason)
+		return
+	}
+	j.registeredTools[t.Name] = true
+	if isReadOnlyTool(t) {
+		j.readOnlyTools[t.Name] = true
+	}
+	if !isReadOnlyTool(t) && (t.Annotations == nil || t.Annotations.OpenWorldHint == nil || *t.Annotations.OpenWorldHint) {
+		j.mutatingTools[t.Name] = true
+	}
+	mcp.AddTool(j.server, t, h)
+}
+
+// isReadOnlyTool reports whether a tool is annotated as never modifying Jira.
+func isReadOnlyTool(t *mcp.Tool) bool {
+	return t.Annotations != nil && t.Annotations.ReadOnlyHint
+}
+
+// toolAllowed applies the server's tool policy to t.
+func (j *JiraMCPServer) toolAllowed(t *mcp.Tool) bool {
+	if adminTools[t.Name] && !j.config.AllowConfigUpdates {
+		return false
+	}
+	if deleteTools[t.Name] && !j.config.AllowDelete {
+		return false
+	}
+	if t.Name == "override-mutation-limit" && j.config.MutationOverrideToken == "" {
+		return false
+	}
+	if j.config.ReadOnly && !isReadOnlyTool(t) {
+		return false
+	}
+	if j.config.Mode == ModeCommenter && !isReadOnlyTool(t) && !commenterTools[t.Name] {
+		return false
+	}
+	if len(j.config.EnabledTools) > 0 && !slices.Contains(j.config.EnabledTools, t.Name) {
+		return false
+	}
+	return !slices.Contains(j.config.DisabledTools, t.Name)
+}
+
+// checkToolConfig warns about allow/deny list entries that do not name a tool,
+// which usually indicates a typo in the configuration.
+func (j *JiraMCPServer) checkToolConfig() {
+	for _, name := range append(slices.Clone(j.config.EnabledTools), j.config.DisabledTools...) {
+		if !j.toolNames[name] {
+			slog.Warn("Tool in the tool allow/deny list does not exist", "tool", name)
+		}
+	}
+}
+
+// Annotation presets for tools that act on Jira. Clients use the hints to
+// decide when to ask the user for confirmation. The MCP spec treats a tool that
+// is not read-only as destructive unless it says otherwise, so the additive
+// preset sets DestructiveHint explicitly.
+
+// readOnlyHints marks a tool that only reads from Jira.
+func readOnlyHints() *mcp.ToolAnnotations {
+	return &mcp.ToolAnnotations{ReadOnlyHint: true, OpenWorldHint: boolPtr(true)}
+}
+
+// additiveHints marks a tool that creates things in Jira without changing or
+// removing existing data.
+func additiveHints(idempotent bool) *mcp.ToolAnnotations {
+	return &mcp.ToolAnnotations{DestructiveHint: boolPtr(false), IdempotentHint: idempotent, OpenWorldHint: boolPtr(true)}
+}
+
+// destructiveHints marks a tool that overwrites or removes existing Jira data.
+func destructiveHints(idempotent bool) *mcp.ToolAnnotations {
+	return &mcp.ToolAnnotations{DestructiveHint: boolPtr(true), IdempotentHint: idempotent, OpenWorldHint: boolPtr(true)}
+}
+
+// boolPtr returns a pointer to b, for the optional hints of tool annotations.
+func boolPtr(b bool) *bool {
+	return &b
+}
@@ -0,0 +1,119 @@
+package jiramcp
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/andygrunwald/go-jira"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+type TransitionIssueParams struct {
+	IssueKey string `json:"issueKey"`
+	// Transition is the name of the transition or of the target status.
+	Transition string `json:"transition"`
+	Resolution string `json:"resolution,omitempty"`
+	Comment    string `json:"comment,omitempty"`
+	// LiteralMentions keeps @name and @email text as typed instead of
+	// turning it into mentions that notify the users.
+	LiteralMentions bool `json:"literalMentions,omitempty"`
+	// ConfirmationPhrase must repeat the issue key when the target status is
+	// one the server is configured to treat as irreversible.
+	ConfirmationPhrase string `json:"confirmationPhrase,omitempty"`
+	DryRun             bool   `json:"dryRun,omitempty"`
+}
+
+// findTransition returns the available transition of an issue matching name,
+// either by transition name or by target status name.
+func (j *JiraMCPServer) findTransition(ctx context.Context, issueKey, name string) (*jira.Transition, []string, error) {
+	transitions, _, err := j.client(ctx).GetTransitions(ctx, issueKey)
+	if err != nil {
+		return nil, nil, err
+	}
+	available := make([]string, 0, len(transitions))
+	for i, t := range transitions {
+		if strings.EqualFold(t.Name, name) || strings.EqualFold(t.To.Name, name) {
+			return &transitions[i], nil, nil
+		}
+		available = append(available, fmt.Sprintf("%s (→ %s)", t.Name, t.To.Name))
+	}
+	return nil, available, nil
+}
+
+// statusName returns the current status of issue, or "" when it was not
+// fetched.
+func statusName(issue *jira.Issue) string {
+	if issue.Fields == nil || issue.Fields.Status == nil {
+		return ""
+	}
+	return issue.Fields.Status.Name
+}
+
+// transitionPath is the endpoint performing transitions of an issue.
+func transitionPath(issueKey string) string {
+	return fmt.Sprintf("rest/api/2/issue/%s/transitions", issueKey)
+}
+
+// transitionRequest builds the body of a transition that also sets a
+// resolution and other fields on the transition screen.
+func transitionRequest(t *jira.Transition, resolution string, fields map[string]interface{}) map[string]interface{} {
+	body := map[string]interface{}{"transition": map[string]string{"id": t.ID}}
+	screen := make(map[string]interface{}, len(fields)+1)
+	for name, value := range fields {
+		screen[name] = value
+	}
+	if resolution != "" {
+		screen["resolution"] = map[string]string{"name": resolution}
+	}
+	if len(screen) > 0 {
+		body["fields"] = screen
+	}
+	return body
+}
+
+// TransitionJiraIssue moves an issue through its workflow.
+func (j *JiraMCPServer) TransitionJiraIssue(ctx context.Context, req *mcp.CallToolRequest, params *TransitionIssueParams) (*mcp.CallToolResult, any, error) {
+	issueKey := strings.ToUpper(params.IssueKey)
+	transition, available, err := j.findTransition(ctx, issueKey, params.Transition)
+	if err != nil {
+		return textResult("Failed to get transitions of %s: %v", issueKey, err), nil, nil
+	}
+	if transition == nil {
+		return textResult("%s has no transition %q. Available: %s", issueKey, params.Transition, strings.Join(available, ", ")), nil, nil
+	}
+
+	payload := jira.CreateTransitionPayload{Transition: jira.TransitionPayload{ID: transition.ID}}
+	if params.Resolution != "" {
+		payload.Fields.Resolution = &jira.Resolution{Name: params.Resolution}
+	}
+	mentioned := &mentions{}
+	if params.Comment != "" {
+		var comment string
+		comment, mentioned = j.resolveMentions(ctx, params.Comment, params.LiteralMentions)
+		payload.Update.Comment = []jira.TransitionPayloadComment{{Add: jira.TransitionPayloadCommentBody{Body: comment}}}
+	}
+
+	confirmation := ""
+	if j.requiresConfirmation(transition.To.Name) {
+		confirmation = confirmationProblem("Moving "+issueKey+" to "+transition.To.Name, issueKey, params.ConfirmationPhrase)
+	}
+
+	if j.dryRun(params.DryRun) {
+		var problems []string
+		if confirmation != "" {
+			problems = append(problems, confirmation)
+		}
+		return dryRunResult("POST", transitionPath(issueKey), payload, problems), nil, nil
+	}
+	if confirmation != "" {
+		return textResult("%s", confirmation), nil, nil
+	}
+
+	if resp, err := j.client(ctx).DoTransitionWithPayload(ctx, issueKey, payload); err != nil {
+		return textResult("Failed to transition %s via %q: %v", issueKey, transition.Name, jira.NewJiraError(resp, err)), nil, nil
+	}
+	logger(ctx).Info("Transitioned issue", "status", transition.To.Name)
+
+	return textResult("Moved %s to %s (transition %q)%s", issueKey, transition.To.Name, transition.Name, mentioned.note()), nil, nil
+}
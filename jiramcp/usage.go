@@ -0,0 +1,221 @@
+package jiramcp
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+const (
+	// usageBucket holds tool usage counters, one key per UTC day.
+	usageBucket = "usage"
+	// usageFlushInterval is how often counters are written to the store.
+	usageFlushInterval = time.Minute
+	// usageRetention is how long daily counters are kept.
+	usageRetention = 90 * 24 * time.Hour
+)
+
+type UsageReportParams struct {
+	// Days is the number of days covered, today included (default 7).
+	Days int `json:"days,omitempty"`
+}
+
+// toolUsage counts the calls of a tool. Failures are calls whose result
+// reports that the tool failed or was refused; errors are protocol errors.
+type toolUsage struct {
+	Calls      int   `json:"calls"`
+	Failures   int   `json:"failures,omitempty"`
+	Errors     int   `json:"errors,omitempty"`
+	DurationMs int64 `json:"durationMs"`
+}
+
+func (u *toolUsage) add(o toolUsage) {
+	u.Calls += o.Calls
+	u.Failures += o.Failures
+	u.Errors += o.Errors
+	u.DurationMs += o.DurationMs
+}
+
+// usageTracker accumulates tool usage in memory and adds it to the store's
+// daily counters at most every usageFlushInterval, so calls do not each
+// rewrite the store.
+type usageTracker struct {
+	mu        sync.Mutex
+	pending   map[string]map[string]*toolUsage
+	lastFlush time.Time
+}
+
+// toolFailed reports whether a tool result describes a failure. Tools report
+// Jira errors as text starting with "Failed", and refusals with IsError.
+func toolFailed(r *mcp.CallToolResult) bool {
+	if r.IsError {
+		return true
+	}
+	for _, c := range r.Content {
+		if tc, ok := c.(*mcp.TextContent); ok {
+			return strings.HasPrefix(tc.Text, "Failed")
+		}
+	}
+	return false
+}
+
+// recordUsage counts a tool call.
+func (j *JiraMCPServer) recordUsage(tool string, result mcp.Result, err error, d time.Duration) {
+	u := toolUsage{Calls: 1, DurationMs: d.Milliseconds()}
+	if r, ok := result.(*mcp.CallToolResult); err != nil {
+		u.Errors = 1
+	} else if ok && toolFailed(r) {
+		u.Failures = 1
+	}
+
+	t := &j.usage
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	day := time.Now().UTC().Format("2006-01-02")
+	if t.pending == nil {
+		t.pending = make(map[string]map[string]*toolUsage)
+	}
+	if t.pending[day] == nil {
+		t.pending[day] = make(map[string]*toolUsage)
+	}
+	if t.pending[day][tool] == nil {
+		t.pending[day][tool] = &toolUsage{}
+	}
+	t.pending[day][tool].add(u)
+	if time.Since(t.lastFlush) >= usageFlushInterval {
+		j.flushUsageLocked()
+	}
+}
+
+// flushUsage writes the pending counters to the store.
+func (j *JiraMCPServer) flushUsage() {
+	j.usage.mu.Lock()
+	defer j.usage.mu.Unlock()
+	j.flushUsageLocked()
+}
+
+func (j *JiraMCPServer) flushUsageLocked() {
+	t := &j.usage
+	t.lastFlush = time.Now()
+	for day, tools := range t.pending {
+		counts := make(map[string]*toolUsage)
+		if _, err := j.store.Get(usageBucket, day, &counts); err != nil {
+			slog.Warn("Failed to load tool usage", "day", day, "error", err)
+			continue
+		}
+		for tool, u := range tools {
+			if counts[tool] == nil {
+				counts[tool] = &toolUsage{}
+			}
+			counts[tool].add(*u)
+		}
+		if err := j.store.Put(usageBucket, day, counts); err != nil {
+			slog.Warn("Failed to save tool usage", "day", day, "error", err)
+			continue
+		}
+		delete(t.pending, day)
+	}
+
+	days, err := j.store.Keys(usageBucket)
+	if err != nil {
+		return
+	}
+	oldest := time.Now().UTC().Add(-usageRetention).Format("2006-01-02")
+	for _, day := range days {
+		if day < oldest {
+			j.store.Delete(usageBucket, day)
+		}
+	}
+}
+
+// UsageReport summarizes which tools were called over the last days, how
+// often they failed, and which registered tools were never used.
+func (j *JiraMCPServer) UsageReport(ctx context.Context, req *mcp.CallToolRequest, params *UsageReportParams) (*mcp.CallToolResult, any, error) {
+	days := params.Days
+	if days <= 0 {
+		days = 7
+	}
+	j.flushUsage()
+	now := time.Now().UTC()
+	since := now.AddDate(0, 0, 1-days).Format("2006-01-02")
+	keys, err := j.store.Keys(usageBucket)
+	if err != nil {
+		return textResult("Failed to load tool usage: %v", err), nil, nil
+	}
+	totals := make(map[string]*toolUsage)
+	var all toolUsage
+	for _, day := range keys {
+		if day < since {
+			continue
+		}
+		counts := make(map[string]*toolUsage)
+		if _, err := j.store.Get(usageBucket, day, &counts); err != nil {
+			return textResult("Failed to load tool usage of %s: %v", day, err), nil, nil
+		}
+		for tool, u := range counts {
+			if totals[tool] == nil {
+				totals[tool] = &toolUsage{}
+			}
+			totals[tool].add(*u)
+			all.add(*u)
+		}
+	}
+
+	names := make([]string, 0, len(totals))
+	for name := range totals {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(a, b int) bool {
+		if totals[names[a]].Calls != totals[names[b]].Calls {
+			return totals[names[a]].Calls > totals[names[b]].Calls
+		}
+		return names[a] < names[b]
+	})
+	rate := func(u *toolUsage) float64 {
+		return 100 * float64(u.Failures+u.Errors) / float64(u.Calls)
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "Tool usage from %s to %s (UTC): %d calls", since, now.Format("2006-01-02"), all.Calls)
+	if all.Calls == 0 {
+		sb.WriteString("\n")
+	} else {
+		fmt.Fprintf(&sb, ", %.1f%% failed\n", rate(&all))
+	}
+	for _, name := range names {
+		u := totals[name]
+		fmt.Fprintf(&sb, "- %s: %d calls, %d failed, %d errors (%.1f%%), avg %dms\n",
+			name, u.Calls, u.Failures, u.Errors, rate(u), u.DurationMs/int64(u.Calls))
+	}
+
+	var failing []string
+	for _, name := range names {
+		if totals[name].Failures+totals[name].Errors > 0 {
+			failing = append(failing, name)
+		}
+	}
+	sort.SliceStable(failing, func(a, b int) bool { return rate(totals[failing[a]]) > rate(totals[failing[b]]) })
+	if len(failing) > 0 {
+		sb.WriteString("\nHighest failure rates:\n")
+		for _, name := range failing[:min(5, len(failing))] {
+			fmt.Fprintf(&sb, "- %s: %.1f%% of %d calls\n", name, rate(totals[name]), totals[name].Calls)
+		}
+	}
+	var unused []string
+	for name := range j.registeredTools {
+		if totals[name] == nil {
+			unused = append(unused, name)
+		}
+	}
+	sort.Strings(unused)
+	if len(unused) > 0 {
+		fmt.Fprintf(&sb, "\nNever called: %s\n", strings.Join(unused, ", "))
+	}
+	return textResult("%s", sb.String()), nil, nil
+}
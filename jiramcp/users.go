@@ -0,0 +1,172 @@
+package jiramcp
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/andygrunwald/go-jira"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// Match strategies of find-jira-user.
+const (
+	MatchExactEmail = "exact-email"
+	MatchExactName  = "exact-name"
+	MatchFuzzyFirst = "fuzzy-first"
+)
+
+// defaultUserResults is how many users find-jira-user returns by default.
+const defaultUserResults = 10
+
+type FindJiraUserParams struct {
+	// Query is a name, username, or email address.
+	Query string `json:"query"`
+	// MatchStrategy is exact-email, exact-name, or fuzzy-first. It defaults
+	// to exact-email for queries that look like an email address and to
+	// fuzzy-first otherwise.
+	MatchStrategy string `json:"matchStrategy,omitempty"`
+	// MaxResults caps the users returned (default 10).
+	MaxResults int `json:"maxResults,omitempty"`
+}
+
+// searchUsers runs Jira's user search, which matches names, usernames, and
+// email addresses by prefix. Cloud takes the search as query; Server and
+// Data Center reject that and take it as username.
+func (j *JiraMCPServer) searchUsers(ctx context.Context, query string) ([]jira.User, error) {
+	return cached(ctx, j.cache, "user", "user:"+strings.ToLower(query), func() ([]jira.User, error) {
+		if !j.isCloud() {
+			var users []jira.User
+			_, err := j.jiraDo(ctx, "GET", "rest/api/2/user/search?maxResults=50&username="+url.QueryEscape(query), nil, &users)
+			return users, err
+		}
+		users, _, err := j.client(ctx).FindUsers(ctx, query)
+		return users, err
+	})
+}
+
+// exactUserMatch reports whether u is exactly the user query names.
+func exactUserMatch(u jira.User, query, strategy string) bool {
+	switch strategy {
+	case MatchExactEmail:
+		return strings.EqualFold(u.EmailAddress, query)
+	case MatchExactName:
+		return strings.EqualFold(u.DisplayName, query) || strings.EqualFold(u.Name, query)
+	default:
+		return strings.EqualFold(u.EmailAddress, query) || strings.EqualFold(u.DisplayName, query) || strings.EqualFold(u.Name, query)
+	}
+}
+
+// matchUsers filters the search results for query by strategy. The exact
+// strategies keep only exact matches; fuzzy-first keeps every result, exact
+// matches first.
+func matchUsers(users []jira.User, query, strategy string) []jira.User {
+	var exact, rest []jira.User
+	for _, u := range users {
+		if exactUserMatch(u, query, strategy) {
+			exact = append(exact, u)
+		} else {
+			rest = append(rest, u)
+		}
+	}
+	if strategy == MatchFuzzyFirst {
+		return append(exact, rest...)
+	}
+	return exact
+}
+
+// describeUser renders a user with the fields needed to tell people apart.
+func describeUser(u jira.User) string {
+	id := "accountId " + u.AccountID
+	if u.AccountID == "" {
+		id = "username " + u.Name
+	}
+	status := "active"
+	if !u.Active {
+		status = "inactive"
+	}
+	email := u.EmailAddress
+	if email == "" {
+		email = "hidden"
+	}
+	return fmt.Sprintf("%s (%s, email %s, %s)", u.DisplayName, id, email, status)
+}
+
+// findJiraUser resolves query (a name, username, or email) to exactly one
+// Jira user. A user whose email, display name, or username equals the query
+// wins, as does the only result of the search; otherwise the query is
+// ambiguous and the candidates are listed in the error rather than guessing.
+func (j *JiraMCPServer) findJiraUser(ctx context.Context, query string) (*jira.User, error) {
+	if query == "" {
+		return nil, nil // No query, no user to find.
+	}
+
+	users, err := j.searchUsers(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("error searching for user '%s': %w", query, err)
+	}
+
+	if len(users) == 0 {
+		return nil, fmt.Errorf("no user found for query '%s'", query)
+	}
+
+	candidates := matchUsers(users, query, "")
+	if len(candidates) == 0 {
+		candidates = users
+	}
+	if len(candidates) == 1 {
+		return &candidates[0], nil
+	}
+	names := make([]string, 0, len(candidates))
+	for _, u := range candidates {
+		names = append(names, describeUser(u))
+	}
+	return nil, fmt.Errorf("%d users match '%s': %s; use find-jira-user and pass an accountId or exact email", len(candidates), query, strings.Join(names, "; "))
+}
+
+// FindJiraUser searches for users and returns their account IDs, display
+// names, email addresses, and whether they are active.
+func (j *JiraMCPServer) FindJiraUser(ctx context.Context, req *mcp.CallToolRequest, params *FindJiraUserParams) (*mcp.CallToolResult, any, error) {
+	query := strings.TrimSpace(params.Query)
+	if query == "" {
+		return textResult("query is required"), nil, nil
+	}
+	strategy := params.MatchStrategy
+	if strategy == "" {
+		strategy = MatchFuzzyFirst
+		if strings.Contains(query, "@") {
+			strategy = MatchExactEmail
+		}
+	}
+	if strategy != MatchExactEmail && strategy != MatchExactName && strategy != MatchFuzzyFirst {
+		return textResult("matchStrategy must be %q, %q, or %q", MatchExactEmail, MatchExactName, MatchFuzzyFirst), nil, nil
+	}
+	limit := params.MaxResults
+	if limit <= 0 {
+		limit = defaultUserResults
+	}
+
+	users, err := j.searchUsers(ctx, query)
+	if err != nil {
+		return textResult("Failed to search for users: %v", err), nil, nil
+	}
+	matches := matchUsers(users, query, strategy)
+	if len(matches) == 0 {
+		if len(users) > 0 && strategy != MatchFuzzyFirst {
+			return textResult("No user matches %q exactly (%s); %d users match partially, use matchStrategy %q to list them.", query, strategy, len(users), MatchFuzzyFirst), nil, nil
+		}
+		return textResult("No users found for %q.", query), nil, nil
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "Users matching %q (%s):\n", query, strategy)
+	for i, u := range matches {
+		if i == limit {
+			fmt.Fprintf(&sb, "... and %d more; raise maxResults or refine the query\n", len(matches)-limit)
+			break
+		}
+		fmt.Fprintf(&sb, "- %s\n", describeUser(u))
+	}
+	return textResult("%s", sb.String()), nil, nil
+}
@@ -0,0 +1,99 @@
+package jiramcp
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/andygrunwald/go-jira"
+)
+
+// Verbosity levels accepted by the read tools' verbosity parameter and by
+// JIRA_MCP_VERBOSITY. Minimal keeps only identifiers and titles, standard is
+// the default one-line-per-item output, and full adds descriptions and
+// secondary fields.
+const (
+	VerbosityMinimal  = "minimal"
+	VerbosityStandard = "standard"
+	VerbosityFull     = "full"
+)
+
+// fullSearchFields is requested for issue lists at full verbosity.
+var fullSearchFields = append([]string{"description", "labels", "components", "reporter", "created", "fixVersions"}, defaultSearchFields...)
+
+func validVerbosity(v string) bool {
+	return v == VerbosityMinimal || v == VerbosityStandard || v == VerbosityFull
+}
+
+// verbosity resolves the level for a call: the requested level if it is
+// valid, otherwise the configured default.
+func (j *JiraMCPServer) verbosity(requested string) string {
+	if v := strings.ToLower(requested); validVerbosity(v) {
+		return v
+	}
+	return j.config.Verbosity
+}
+
+// issueFieldsFor returns the search fields needed to render issues at the
+// given verbosity.
+func issueFieldsFor(verbosity string) []string {
+	switch verbosity {
+	case VerbosityMinimal:
+		return []string{"summary", "status"}
+	case VerbosityFull:
+		return fullSearchFields
+	default:
+		return defaultSearchFields
+	}
+}
+
+// formatIssue renders an issue at the given verbosity. Full output spans
+// several lines, indented under the standard summary line.
+func formatIssue(issue *jira.Issue, verbosity string) string {
+	f := issue.Fields
+	switch {
+	case f == nil:
+		return issue.Key
+	case verbosity == VerbosityMinimal:
+		return fmt.Sprintf("%s: %s", issue.Key, f.Summary)
+	case verbosity != VerbosityFull:
+		return formatIssueLine(issue)
+	}
+
+	var sb strings.Builder
+	sb.WriteString(formatIssueLine(issue))
+	var details []string
+	if f.Reporter != nil {
+		details = append(details, "reporter: "+f.Reporter.DisplayName)
+	}
+	if created := time.Time(f.Created); !created.IsZero() {
+		details = append(details, "created: "+created.UTC().Format("2006-01-02 15:04Z"))
+	}
+	if updated := time.Time(f.Updated); !updated.IsZero() {
+		details = append(details, "updated: "+updated.UTC().Format("2006-01-02 15:04Z"))
+	}
+	if len(f.Labels) > 0 {
+		details = append(details, "labels: "+strings.Join(f.Labels, ", "))
+	}
+	if len(f.Components) > 0 {
+		names := make([]string, 0, len(f.Components))
+		for _, c := range f.Components {
+			names = append(names, c.Name)
+		}
+		details = append(details, "components: "+strings.Join(names, ", "))
+	}
+	if len(f.FixVersions) > 0 {
+		names := make([]string, 0, len(f.FixVersions))
+		for _, v := range f.FixVersions {
+			names = append(names, v.Name)
+		}
+		details = append(details, "fix versions: "+strings.Join(names, ", "))
+	}
+	if len(details) > 0 {
+		sb.WriteString("\n    " + strings.Join(details, "; "))
+	}
+	if desc := strings.TrimSpace(f.Description); desc != "" {
+		sb.WriteString("\n    " + strings.ReplaceAll(desc, "\n", "\n    "))
+	}
+	return sb.String()
+}
@@ -0,0 +1,142 @@
+package jiramcp
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/andygrunwald/go-jira"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// defaultMostVotedResults caps most-voted-issues output when maxResults is
+// not given.
+const defaultMostVotedResults = 20
+
+type VoteParams struct {
+	IssueKey string `json:"issueKey"`
+	DryRun   bool   `json:"dryRun,omitempty"`
+}
+
+type ListVotersParams struct {
+	IssueKey  string `json:"issueKey"`
+	Verbosity string `json:"verbosity,omitempty"`
+}
+
+type MostVotedIssuesParams struct {
+	ProjectKey string `json:"projectKey,omitempty"`
+	// JQL narrows the issues further, e.g. "statusCategory != Done".
+	JQL        string `json:"jql,omitempty"`
+	MaxResults int    `json:"maxResults,omitempty"`
+}
+
+type votesResponse struct {
+	Votes    int         `json:"votes"`
+	HasVoted bool        `json:"hasVoted"`
+	Voters   []jira.User `json:"voters"`
+}
+
+// issueVotes returns the vote count of an issue from its votes field, which
+// go-jira does not model.
+func issueVotes(issue jira.Issue) int {
+	if issue.Fields == nil {
+		return 0
+	}
+	votes, _ := issue.Fields.Unknowns["votes"].(map[string]interface{})
+	n, _ := votes["votes"].(float64)
+	return int(n)
+}
+
+// VoteForIssue adds the vote of the account the server acts as. Jira does
+// not let users vote for issues they reported or that are resolved.
+func (j *JiraMCPServer) VoteForIssue(ctx context.Context, req *mcp.CallToolRequest, params *VoteParams) (*mcp.CallToolResult, any, error) {
+	path := fmt.Sprintf("rest/api/2/issue/%s/votes", params.IssueKey)
+	if j.dryRun(params.DryRun) {
+		return dryRunResult("POST", path, nil, j.issueProblems(ctx, params.IssueKey)), nil, nil
+	}
+	if _, err := j.jiraDo(ctx, "POST", path, nil, nil); err != nil {
+		return textResult("Failed to vote for %s: %v", params.IssueKey, err), nil, nil
+	}
+	logger(ctx).Info("Voted for issue")
+
+	return textResult("Voted for %s", params.IssueKey), nil, nil
+}
+
+// RemoveVote withdraws the vote of the account the server acts as.
+func (j *JiraMCPServer) RemoveVote(ctx context.Context, req *mcp.CallToolRequest, params *VoteParams) (*mcp.CallToolResult, any, error) {
+	path := fmt.Sprintf("rest/api/2/issue/%s/votes", params.IssueKey)
+	if j.dryRun(params.DryRun) {
+		return dryRunResult("DELETE", path, nil, j.issueProblems(ctx, params.IssueKey)), nil, nil
+	}
+	if _, err := j.jiraDo(ctx, "DELETE", path, nil, nil); err != nil {
+		return textResult("Failed to remove the vote from %s: %v", params.IssueKey, err), nil, nil
+	}
+	logger(ctx).Info("Removed vote")
+
+	return textResult("Removed the vote from %s", params.IssueKey), nil, nil
+}
+
+// ListVoters returns the vote count of an issue and who voted. Jira only
+// lists voters to users with the "View voters and watchers" permission.
+func (j *JiraMCPServer) ListVoters(ctx context.Context, req *mcp.CallToolRequest, params *ListVotersParams) (*mcp.CallToolResult, any, error) {
+	var resp votesResponse
+	if _, err := j.jiraDo(ctx, "GET", fmt.Sprintf("rest/api/2/issue/%s/votes", params.IssueKey), nil, &resp); err != nil {
+		return textResult("Failed to list voters of %s: %v", params.IssueKey, err), nil, nil
+	}
+	if resp.Votes == 0 {
+		return textResult("%s has no votes", params.IssueKey), nil, nil
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "%s has %d vote(s)", params.IssueKey, resp.Votes)
+	if resp.HasVoted {
+		sb.WriteString(", including yours")
+	}
+	sb.WriteString(":\n")
+	verbosity := j.verbosity(params.Verbosity)
+	for _, v := range resp.Voters {
+		switch verbosity {
+		case VerbosityMinimal:
+			fmt.Fprintf(&sb, "- %s\n", v.DisplayName)
+		case VerbosityFull:
+			fmt.Fprintf(&sb, "- %s (accountId %s", v.DisplayName, v.AccountID)
+			if v.EmailAddress != "" {
+				fmt.Fprintf(&sb, ", %s", v.EmailAddress)
+			}
+			sb.WriteString(")\n")
+		default:
+			fmt.Fprintf(&sb, "- %s (accountId %s)\n", v.DisplayName, v.AccountID)
+		}
+	}
+	if len(resp.Voters) < resp.Votes {
+		fmt.Fprintf(&sb, "%d voter(s) are not visible to this account.\n", resp.Votes-len(resp.Voters))
+	}
+	return textResult("%s", sb.String()), nil, nil
+}
+
+// MostVotedIssues lists the issues of a project with the most votes.
+func (j *JiraMCPServer) MostVotedIssues(ctx context.Context, req *mcp.CallToolRequest, params *MostVotedIssuesParams) (*mcp.CallToolResult, any, error) {
+	projectKey := j.projectKeyOrDefault(params.ProjectKey)
+	maxResults := params.MaxResults
+	if maxResults <= 0 {
+		maxResults = defaultMostVotedResults
+	}
+	jql := fmt.Sprintf("project = %s AND votes > 0", projectKey)
+	if params.JQL != "" {
+		jql = fmt.Sprintf("%s AND (%s)", jql, params.JQL)
+	}
+	issues, err := j.searchIssues(ctx, jql+" ORDER BY votes DESC, created ASC", []string{"summary", "status", "votes"}, "", maxResults)
+	if err != nil {
+		return textResult("Failed to search for voted issues in %s: %v", projectKey, err), nil, nil
+	}
+	if len(issues) == 0 {
+		return textResult("No issues in %s have votes", projectKey), nil, nil
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "Most voted issues in %s:\n", projectKey)
+	for _, issue := range issues {
+		fmt.Fprintf(&sb, "- %s (%d votes, %s): %s\n", issue.Key, issueVotes(issue), statusName(&issue), issue.Fields.Summary)
+	}
+	return textResult("%s", sb.String()), nil, nil
+}
@@ -0,0 +1,119 @@
+package jiramcp
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/andygrunwald/go-jira"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+type ListWatchersParams struct {
+	IssueKey  string `json:"issueKey"`
+	Verbosity string `json:"verbosity,omitempty"`
+}
+
+type WatcherParams struct {
+	IssueKey string `json:"issueKey"`
+	// User is a display name, email address, or accountId.
+	User   string `json:"user"`
+	DryRun bool   `json:"dryRun,omitempty"`
+}
+
+type watchersResponse struct {
+	WatchCount int         `json:"watchCount"`
+	IsWatching bool        `json:"isWatching"`
+	Watchers   []jira.User `json:"watchers"`
+}
+
+// ListWatchers returns the users currently watching an issue.
+func (j *JiraMCPServer) ListWatchers(ctx context.Context, req *mcp.CallToolRequest, params *ListWatchersParams) (*mcp.CallToolResult, any, error) {
+	var resp watchersResponse
+	if _, err := j.jiraDo(ctx, "GET", fmt.Sprintf("rest/api/2/issue/%s/watchers", params.IssueKey), nil, &resp); err != nil {
+		return textResult("Failed to list watchers of %s: %v", params.IssueKey, err), nil, nil
+	}
+	if len(resp.Watchers) == 0 {
+		return textResult("%s has no watchers", params.IssueKey), nil, nil
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "%s has %d watcher(s):\n", params.IssueKey, resp.WatchCount)
+	verbosity := j.verbosity(params.Verbosity)
+	for _, w := range resp.Watchers {
+		switch verbosity {
+		case VerbosityMinimal:
+			fmt.Fprintf(&sb, "- %s\n", w.DisplayName)
+		case VerbosityFull:
+			fmt.Fprintf(&sb, "- %s (accountId %s", w.DisplayName, w.AccountID)
+			if w.EmailAddress != "" {
+				fmt.Fprintf(&sb, ", %s", w.EmailAddress)
+			}
+			if !w.Active {
+				sb.WriteString(", inactive")
+			}
+			sb.WriteString(")\n")
+		default:
+			fmt.Fprintf(&sb, "- %s (accountId %s)\n", w.DisplayName, w.AccountID)
+		}
+	}
+	return textResult("%s", sb.String()), nil, nil
+}
+
+// resolveWatcher looks up the user named in a watcher request.
+func (j *JiraMCPServer) resolveWatcher(ctx context.Context, query string) (*jira.User, error) {
+	if strings.TrimSpace(query) == "" {
+		return nil, fmt.Errorf("user is required")
+	}
+	user, err := j.findJiraUser(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("could not resolve user %q: %w", query, err)
+	}
+	return user, nil
+}
+
+// AddWatcher resolves the user and adds them as a watcher of the issue.
+func (j *JiraMCPServer) AddWatcher(ctx context.Context, req *mcp.CallToolRequest, params *WatcherParams) (*mcp.CallToolResult, any, error) {
+	user, err := j.resolveWatcher(ctx, params.User)
+	if err != nil {
+		return textResult("%v", err), nil, nil
+	}
+
+	path := fmt.Sprintf("rest/api/2/issue/%s/watchers", params.IssueKey)
+	if j.dryRun(params.DryRun) {
+		return dryRunResult("POST", path, j.userID(user), j.issueProblems(ctx, params.IssueKey)), nil, nil
+	}
+
+	// The watchers endpoint takes the accountId, or the username on Server
+	// and Data Center, as a bare JSON string.
+	if _, err := j.jiraDo(ctx, "POST", path, j.userID(user), nil); err != nil {
+		return textResult("Failed to add %s as a watcher of %s: %v", user.DisplayName, params.IssueKey, err), nil, nil
+	}
+	logger(ctx).Info("Added watcher", "watcher", user.DisplayName)
+
+	return textResult("Added %s as a watcher of %s", user.DisplayName, params.IssueKey), nil, nil
+}
+
+// RemoveWatcher resolves the user and removes them from the issue's watchers.
+func (j *JiraMCPServer) RemoveWatcher(ctx context.Context, req *mcp.CallToolRequest, params *WatcherParams) (*mcp.CallToolResult, any, error) {
+	user, err := j.resolveWatcher(ctx, params.User)
+	if err != nil {
+		return textResult("%v", err), nil, nil
+	}
+
+	param := "accountId"
+	if !j.isCloud() {
+		param = "username"
+	}
+	path := fmt.Sprintf("rest/api/2/issue/%s/watchers?%s=%s", params.IssueKey, param, url.QueryEscape(j.userID(user)))
+	if j.dryRun(params.DryRun) {
+		return dryRunResult("DELETE", path, nil, j.issueProblems(ctx, params.IssueKey)), nil, nil
+	}
+	if _, err := j.jiraDo(ctx, "DELETE", path, nil, nil); err != nil {
+		return textResult("Failed to remove %s from the watchers of %s: %v", user.DisplayName, params.IssueKey, err), nil, nil
+	}
+	logger(ctx).Info("Removed watcher", "watcher", user.DisplayName)
+
+	return textResult("Removed %s from the watchers of %s", user.DisplayName, params.IssueKey), nil, nil
+}
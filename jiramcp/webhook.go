@@ -0,0 +1,208 @@
+package jiramcp
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// maxWebhookBody bounds the size of accepted webhook payloads.
+const maxWebhookBody = 5 << 20
+
+// jiraWebhookEvent is the subset of a Jira webhook payload used to build
+// notifications.
+type jiraWebhookEvent struct {
+	WebhookEvent string `json:"webhookEvent"`
+	User         *struct {
+		DisplayName string `json:"displayName"`
+	} `json:"user"`
+	Issue *struct {
+		Key    string `json:"key"`
+		Fields struct {
+			Summary string `json:"summary"`
+			Status  *struct {
+				Name string `json:"name"`
+			} `json:"status"`
+		} `json:"fields"`
+	} `json:"issue"`
+	Comment *struct {
+		Author struct {
+			DisplayName string `json:"displayName"`
+		} `json:"author"`
+	} `json:"comment"`
+	Changelog *struct {
+		Items []struct {
+			Field      string `json:"field"`
+			FromString string `json:"fromString"`
+			ToString   string `json:"toString"`
+		} `json:"items"`
+	} `json:"changelog"`
+}
+
+// verifyWebhook checks the request against the configured secret. Jira Cloud
+// signs payloads with an HMAC-SHA256 X-Hub-Signature header when a secret is
+// set on the webhook. Only with JIRA_WEBHOOK_QUERY_SECRET may older instances
+// pass the secret as a "secret" query parameter instead, since it then shows
+// up in Jira's webhook configuration and in proxy access logs. Without a
+// secret nothing passes.
+func (j *JiraMCPServer) verifyWebhook(r *http.Request, body []byte) bool {
+	secret := []byte(j.config.WebhookSecret)
+	if len(secret) == 0 {
+		return false
+	}
+	if sig := r.Header.Get("X-Hub-Signature"); sig != "" {
+		mac := hmac.New(sha256.New, secret)
+		mac.Write(body)
+		expected := "sha256=" + hex.EncodeToString(mac.Sum(nil))
+		return hmac.Equal([]byte(sig), []byte(expected))
+	}
+	if !j.config.WebhookQuerySecret {
+		return false
+	}
+	token := r.URL.Query().Get("secret")
+	return token != "" && subtle.ConstantTimeCompare([]byte(token), secret) == 1
+}
+
+// webhookHandler receives Jira webhooks and forwards them to connected MCP
+// clients as resource-updated and logging notifications.
+func (j *JiraMCPServer) webhookHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxWebhookBody))
+	if err != nil {
+		http.Error(w, "failed to read body", http.StatusBadRequest)
+		return
+	}
+	if !j.verifyWebhook(r, body) {
+		slog.Warn("Rejected Jira webhook with invalid secret", "remoteAddr", r.RemoteAddr)
+		http.Error(w, "invalid webhook secret", http.StatusUnauthorized)
+		return
+	}
+
+	var event jiraWebhookEvent
+	if err := json.Unmarshal(body, &event); err != nil {
+		http.Error(w, "invalid payload", http.StatusBadRequest)
+		return
+	}
+	if j.pseudonyms != nil {
+		// Webhooks do not pass through the Jira clients, whose responses
+		// teach the pseudonymizer who is who, so learn their users here.
+		var doc interface{}
+		if json.Unmarshal(body, &doc) == nil {
+			j.pseudonyms.learnFromJSON(doc)
+		}
+	}
+	if event.Issue == nil || event.Issue.Key == "" {
+		// Not an issue-level event (e.g. project or version changes); nothing to forward.
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	j.publishWebhookEvent(r.Context(), &event)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// publishWebhookEvent notifies subscribers of the resources affected by the
+// event and sends a one-line description to every connected session.
+func (j *JiraMCPServer) publishWebhookEvent(ctx context.Context, event *jiraWebhookEvent) {
+	issueKey := event.Issue.Key
+	message := describeWebhookEvent(event)
+	slog.Info("Jira webhook", "event", event.WebhookEvent, "issueKey", issueKey, "message", message)
+
+	if strings.HasPrefix(event.WebhookEvent, "comment_") || event.Comment != nil {
+		uri := fmt.Sprintf("jira://issue/%s/discussion-summary", issueKey)
+		if err := j.server.ResourceUpdated(ctx, &mcp.ResourceUpdatedNotificationParams{URI: uri}); err != nil {
+			slog.Warn("Failed to send resource update", "uri", uri, "error", err)
+		}
+	}
+
+	j.broadcastLog(ctx, "info", "jira-webhook", []string{projectOfIssue(issueKey)}, map[string]interface{}{
+		"event":    event.WebhookEvent,
+		"issueKey": issueKey,
+		"message":  message,
+	})
+}
+
+// broadcastLog sends a logging notification about the issues of projects to
+// every connected session that may see them. The SDK drops messages below
+// the level each client asked for. With anonymization, each session gets
+// the data rewritten with its own pseudonyms.
+func (j *JiraMCPServer) broadcastLog(ctx context.Context, level mcp.LoggingLevel, logger string, projects []string, data interface{}) {
+	for session := range j.server.Sessions() {
+		if !j.sessionSees(session, projects) {
+			continue
+		}
+		sessionData := data
+		if j.pseudonyms != nil {
+			sessionData = j.pseudonyms.rewriteData(j.sessionStateKey(session.ID()), data)
+		}
+		if err := session.Log(ctx, &mcp.LoggingMessageParams{Level: level, Logger: logger, Data: sessionData}); err != nil {
+			slog.Warn("Failed to send log notification", "session", session.ID(), "error", err)
+		}
+	}
+}
+
+// sessionSees reports whether a session may be told about the issues of
+// projects. With roles, a session whose client has not been seen with a
+// grant is told nothing.
+func (j *JiraMCPServer) sessionSees(session *mcp.ServerSession, projects []string) bool {
+	var grant *roleGrant
+	if j.roles != nil {
+		if grant = j.sessionGrant(session.ID()); grant == nil {
+			return false
+		}
+	}
+	for _, project := range projects {
+		if !j.projectAllowed(project) || (grant != nil && !projectAllowedForGrant(grant, project)) {
+			return false
+		}
+	}
+	return true
+}
+
+// describeWebhookEvent renders a short human-readable summary of a webhook.
+func describeWebhookEvent(event *jiraWebhookEvent) string {
+	issue := event.Issue
+	actor := "someone"
+	if event.User != nil && event.User.DisplayName != "" {
+		actor = event.User.DisplayName
+	} else if event.Comment != nil && event.Comment.Author.DisplayName != "" {
+		actor = event.Comment.Author.DisplayName
+	}
+
+	switch event.WebhookEvent {
+	case "jira:issue_created":
+		return fmt.Sprintf("%s created %s: %s", actor, issue.Key, issue.Fields.Summary)
+	case "jira:issue_deleted":
+		return fmt.Sprintf("%s deleted %s: %s", actor, issue.Key, issue.Fields.Summary)
+	case "comment_created":
+		return fmt.Sprintf("%s commented on %s: %s", actor, issue.Key, issue.Fields.Summary)
+	case "comment_updated":
+		return fmt.Sprintf("%s edited a comment on %s", actor, issue.Key)
+	case "jira:issue_updated":
+		var changes []string
+		if event.Changelog != nil {
+			for _, item := range event.Changelog.Items {
+				changes = append(changes, fmt.Sprintf("%s: %q → %q", item.Field, item.FromString, item.ToString))
+			}
+		}
+		if len(changes) == 0 {
+			return fmt.Sprintf("%s updated %s", actor, issue.Key)
+		}
+		return fmt.Sprintf("%s updated %s (%s)", actor, issue.Key, strings.Join(changes, ", "))
+	default:
+		return fmt.Sprintf("%s on %s by %s", event.WebhookEvent, issue.Key, actor)
+	}
+}
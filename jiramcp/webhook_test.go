@@ -0,0 +1,80 @@
+package jiramcp
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// signWebhook returns the X-Hub-Signature Jira sends for body.
+func signWebhook(secret, body string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(body))
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+func TestVerifyWebhook(t *testing.T) {
+	const body = `{"webhookEvent":"jira:issue_updated","issue":{"key":"SMS-1"}}`
+	tests := []struct {
+		name        string
+		secret      string
+		querySecret bool
+		signature   string
+		query       string
+		want        bool
+	}{
+		{"valid signature", "hook", false, signWebhook("hook", body), "", true},
+		{"signature with another secret", "hook", false, signWebhook("other", body), "", false},
+		{"signature of another body", "hook", false, signWebhook("hook", body+" "), "", false},
+		{"signature without prefix", "hook", false, strings.TrimPrefix(signWebhook("hook", body), "sha256="), "", false},
+		{"no signature", "hook", false, "", "", false},
+		{"query secret not enabled", "hook", false, "", "hook", false},
+		{"query secret", "hook", true, "", "hook", true},
+		{"wrong query secret", "hook", true, "", "hoo", false},
+		{"bad signature beside a good query secret", "hook", true, signWebhook("other", body), "hook", false},
+		{"no secret configured", "", true, signWebhook("", body), "", false},
+	}
+	for _, tt := range tests {
+		j := &JiraMCPServer{config: &JiraConfig{WebhookSecret: tt.secret, WebhookQuerySecret: tt.querySecret}}
+		target := "/webhook"
+		if tt.query != "" {
+			target += "?secret=" + tt.query
+		}
+		r := httptest.NewRequest(http.MethodPost, target, strings.NewReader(body))
+		if tt.signature != "" {
+			r.Header.Set("X-Hub-Signature", tt.signature)
+		}
+		if got := j.verifyWebhook(r, []byte(body)); got != tt.want {
+			t.Errorf("%s: verifyWebhook = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestWebhookHandlerRejectsUnverifiedRequests(t *testing.T) {
+	j := newTestServer(t, &MockJiraService{DoFunc: jiraRoutes{}.do}, func(c *JiraConfig) {
+		c.WebhookSecret = "hook"
+	})
+	const body = `{"webhookEvent":"jira:issue_updated","issue":{"key":"SMS-1"}}`
+
+	tests := []struct {
+		method, signature string
+		want              int
+	}{
+		{http.MethodGet, signWebhook("hook", body), http.StatusMethodNotAllowed},
+		{http.MethodPost, signWebhook("other", body), http.StatusUnauthorized},
+		{http.MethodPost, signWebhook("hook", body), http.StatusNoContent},
+	}
+	for _, tt := range tests {
+		r := httptest.NewRequest(tt.method, "/webhook", strings.NewReader(body))
+		r.Header.Set("X-Hub-Signature", tt.signature)
+		w := httptest.NewRecorder()
+		j.webhookHandler(w, r)
+		if w.Code != tt.want {
+			t.Errorf("%s with signature %s: status = %d, want %d", tt.method, tt.signature, w.Code, tt.want)
+		}
+	}
+}
@@ -0,0 +1,65 @@
+package jiramcp
+
+import (
+	"context"
+	"sync"
+)
+
+// defaultFetchConcurrency is how many Jira reads a tool runs at once when
+// JIRA_MCP_FETCH_CONCURRENCY is not set.
+const defaultFetchConcurrency = 4
+
+// fetchConcurrency returns how many Jira reads a tool may run at once.
+func (j *JiraMCPServer) fetchConcurrency() int {
+	return max(j.config.FetchConcurrency, 1)
+}
+
+// forEachParallel calls fn for every index below n on a pool of at most
+// workers goroutines and waits for all of them. After the first error it
+// starts no further calls, cancels the context of those still running, and
+// returns that error. Callers collect results by index, so their order does
+// not depend on which call finishes first.
+//
+// Jira requests made by fn still go through the client's transport, so the
+// JIRA_MCP_JIRA_RATE_LIMIT budget and the retries on 429 apply to each of
+// them; the pool only bounds how many wait at once.
+func forEachParallel(ctx context.Context, workers, n int, fn func(ctx context.Context, i int) error) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	var (
+		wg       sync.WaitGroup
+		once     sync.Once
+		firstErr error
+	)
+	next := make(chan int)
+	for range min(max(workers, 1), n) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range next {
+				if err := fn(ctx, i); err != nil {
+					once.Do(func() {
+						firstErr = err
+						cancel()
+					})
+				}
+			}
+		}()
+	}
+	started := 0
+feed:
+	for ; started < n; started++ {
+		select {
+		case next <- started:
+		case <-ctx.Done():
+			break feed
+		}
+	}
+	close(next)
+	wg.Wait()
+	if firstErr == nil && started < n {
+		// The caller's context ended before every call started.
+		return ctx.Err()
+	}
+	return firstErr
+}